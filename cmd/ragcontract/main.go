@@ -0,0 +1,45 @@
+// Command ragcontract verifies a RAG server against the contract embedded in
+// internal/client/contracts/rag_api.json and exits non-zero on any violation.
+//
+// Usage:
+//
+//	go run ./cmd/ragcontract -target http://localhost:8080 [-mutating]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"llm/internal/client"
+	"llm/internal/config"
+)
+
+func main() {
+	target := flag.String("target", "http://localhost:8080", "base URL of the RAG server")
+	userID := flag.String("user", "contract-check", "probe user ID substituted into requests")
+	mutating := flag.Bool("mutating", false, "also exercise endpoints that write data")
+	timeout := flag.Duration("timeout", 10*time.Second, "per-request timeout")
+	flag.Parse()
+
+	ragClient := client.NewRAGClient(&config.Config{
+		RAGServerURL:     *target,
+		RAGServerTimeout: *timeout,
+	})
+
+	report, err := ragClient.VerifyContract(context.Background(), *userID, *mutating)
+	if err != nil {
+		log.Fatalf("Failed to verify contract: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,126 @@
+// Command replay re-runs a user's stored chat conversations through a server's
+// current prompt and model configuration via POST /api/admin/replay, and prints
+// how each reply and its evaluation score changed.
+//
+// Usage:
+//
+//	go run ./cmd/replay -target http://staging:3000 -admin-key $ADMIN_API_KEY -user user_123 -limit 20
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"llm/internal/models"
+	"llm/internal/textutil"
+)
+
+type replayEnvelope struct {
+	Success bool                  `json:"success"`
+	Data    models.ReplayResponse `json:"data"`
+	Error   *models.ErrorInfo     `json:"error"`
+}
+
+func main() {
+	target := flag.String("target", "http://localhost:3000", "base URL of the server to replay against")
+	adminKey := flag.String("admin-key", os.Getenv("ADMIN_API_KEY"), "admin API key (defaults to $ADMIN_API_KEY)")
+	userID := flag.String("user", "", "user whose conversations to replay")
+	limit := flag.Int("limit", 0, "number of most recent chat turns to replay (server default when 0)")
+	model := flag.String("model", "", "replay with this model instead of the configured one")
+	from := flag.String("from", "", "only replay turns on or after this date (YYYY-MM-DD)")
+	to := flag.String("to", "", "only replay turns before this date (YYYY-MM-DD)")
+	changedOnly := flag.Bool("changed", false, "print only turns whose reply changed")
+	timeout := flag.Duration("timeout", 10*time.Minute, "request timeout")
+	flag.Parse()
+
+	if *userID == "" {
+		log.Fatal("-user is required")
+	}
+	if *adminKey == "" {
+		log.Fatal("-admin-key or ADMIN_API_KEY is required")
+	}
+
+	req := models.ReplayRequest{UserID: *userID, Limit: *limit}
+	req.Model = *model
+	if *from != "" {
+		date, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid -from date: %v", err)
+		}
+		req.From = &date
+	}
+	if *to != "" {
+		date, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid -to date: %v", err)
+		}
+		req.To = &date
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		log.Fatalf("Failed to encode request: %v", err)
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, *target+"/api/admin/replay", bytes.NewReader(body))
+	if err != nil {
+		log.Fatalf("Failed to build request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+*adminKey)
+
+	client := &http.Client{Timeout: *timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		log.Fatalf("Replay request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope replayEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		log.Fatalf("Failed to decode response (status %d): %v", resp.StatusCode, err)
+	}
+	if !envelope.Success {
+		if envelope.Error != nil {
+			log.Fatalf("Replay failed (status %d): %s: %s", resp.StatusCode, envelope.Error.Code, envelope.Error.Message)
+		}
+		log.Fatalf("Replay failed (status %d)", resp.StatusCode)
+	}
+
+	report := envelope.Data
+	for _, result := range report.Results {
+		if *changedOnly && !result.Changed && result.Error == "" {
+			continue
+		}
+		fmt.Printf("=== %s (%s)\n", result.ConversationID, result.Timestamp.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("user:     %s\n", textutil.Truncate(result.Message, 200))
+		fmt.Printf("original: %s\n", result.OriginalResponse)
+		if result.Error != "" {
+			fmt.Printf("error:    %s\n\n", result.Error)
+			continue
+		}
+		fmt.Printf("replayed: %s\n", result.ReplayedResponse)
+		fmt.Printf("similarity %.2f, changed %t", result.Similarity, result.Changed)
+		if result.ScoreDelta != nil {
+			fmt.Printf(", score %d -> %d (%+d)", *result.OriginalScore, *result.ReplayedScore, *result.ScoreDelta)
+		} else if result.ReplayedScore != nil {
+			fmt.Printf(", score %d (not scored before)", *result.ReplayedScore)
+		}
+		fmt.Print("\n\n")
+	}
+
+	variant := report.PromptVariant
+	if variant == "" {
+		variant = "control"
+	}
+	fmt.Printf("Replayed %d turns for %s with %s, prompt %s (%d changed, %d failed)\n", report.Replayed, report.UserID, report.Model, variant, report.Changed, report.Failed)
+	fmt.Printf("Average similarity: %.2f\n", report.AverageSimilarity)
+	if report.AverageScoreDelta != nil {
+		fmt.Printf("Average score change: %+.2f (evaluation prompt %s)\n", *report.AverageScoreDelta, report.ScoreVersion)
+	}
+}
@@ -0,0 +1,81 @@
+// Command replayer re-issues recorded request/response pairs against a target
+// server (typically a staging build) and reports status mismatches and latency.
+//
+// Usage:
+//
+//	go run ./cmd/replayer -file recordings/2024-01-01.jsonl -target http://staging:3000
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"llm/internal/recording"
+)
+
+func main() {
+	file := flag.String("file", "", "recording file (JSONL) to replay")
+	target := flag.String("target", "http://localhost:3000", "base URL of the server to replay against")
+	timeout := flag.Duration("timeout", 60*time.Second, "per-request timeout")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("-file is required")
+	}
+
+	records, err := recording.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("Failed to load recordings: %v", err)
+	}
+
+	client := &http.Client{Timeout: *timeout}
+
+	var mismatches, failures int
+	var originalLatency, replayLatency int64
+
+	for _, record := range records {
+		url := *target + record.Path
+		if record.Query != "" {
+			url += "?" + record.Query
+		}
+
+		req, err := http.NewRequest(record.Method, url, bytes.NewReader(record.RequestBody))
+		if err != nil {
+			log.Printf("[%s] failed to build request: %v", record.RequestID, err)
+			failures++
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-ID", "replay-"+record.RequestID)
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		latency := time.Since(start).Milliseconds()
+		if err != nil {
+			log.Printf("[%s] %s %s failed: %v", record.RequestID, record.Method, record.Path, err)
+			failures++
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		originalLatency += record.LatencyMs
+		replayLatency += latency
+
+		if resp.StatusCode != record.Status {
+			mismatches++
+			log.Printf("[%s] %s %s status mismatch: recorded %d, replayed %d", record.RequestID, record.Method, record.Path, record.Status, resp.StatusCode)
+		}
+	}
+
+	replayed := len(records) - failures
+	fmt.Printf("Replayed %d/%d requests (%d failed, %d status mismatches)\n", replayed, len(records), failures, mismatches)
+	if replayed > 0 {
+		fmt.Printf("Average latency: recorded %dms, replayed %dms\n", originalLatency/int64(replayed), replayLatency/int64(replayed))
+	}
+}
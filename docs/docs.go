@@ -1,4 +1,5 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
+// Code generated by swaggo/swag. DO NOT EDIT.
+
 package docs
 
 import "github.com/swaggo/swag"
@@ -23,9 +24,61 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/analysis": {
+        "/api/admin/backfill/scores": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Progress of the running or most recent score backfill",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get score backfill status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ScoreBackfillJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Analyze user's conversation history and incorrect quizzes across 4 domains (family, life events, career, hobbies)",
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Re-run the response quality evaluation with the current prompt version over the users' stored chat turns and write the updated scores back to RAG. Turns already scored with the current version are skipped unless force is set. Runs in the background; poll GET /api/admin/backfill/scores.",
                 "consumes": [
                     "application/json"
                 ],
@@ -33,25 +86,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Analysis"
+                    "Admin"
                 ],
-                "summary": "Process domain analysis",
+                "summary": "Start a score backfill",
                 "parameters": [
                     {
-                        "description": "Analysis request",
+                        "description": "Users to backfill",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.AnalysisRequest"
+                            "$ref": "#/definitions/models.ScoreBackfillRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ScoreBackfillJob"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -60,8 +125,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
@@ -69,45 +146,113 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/analysis/domains": {
-            "post": {
-                "description": "Analyze user's conversation history and incorrect quizzes across 4 domains without generating a report",
-                "consumes": [
+        "/api/admin/cache/stats": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Size and TTL of each in-memory cache: generated questions, RAG personal info, RAG incorrect quiz attempts and semantic chat replies",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get cache stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CacheStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/cache/{name}/{key}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Remove the entries under a key from a cache so the next request reads fresh data. Keys are user IDs; the questions cache also takes a question ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Analysis"
+                    "Admin"
                 ],
-                "summary": "Process domain analysis only",
+                "summary": "Evict cache entries",
                 "parameters": [
                     {
-                        "description": "Analysis request (user_id required)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AnalysisRequest"
-                        }
+                        "enum": [
+                            "questions",
+                            "profiles",
+                            "incorrect_attempts",
+                            "semantic"
+                        ],
+                        "type": "string",
+                        "description": "Cache name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID, or question ID for the questions cache",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CacheEvictResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
@@ -115,55 +260,141 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/analysis/report": {
-            "post": {
-                "description": "Generate a professional markdown report based on provided domain analysis scores",
-                "consumes": [
-                    "application/json"
+        "/api/admin/calibration": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
                 ],
+                "description": "How well high/medium/low confidence labels predicted later recall of the same fact, with suggested thresholds",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Analysis"
+                    "Admin"
                 ],
-                "summary": "Generate professional report from domain scores",
-                "parameters": [
-                    {
-                        "description": "Report generation request (4 domains required)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get confidence calibration report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ReportGenerationRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ConfidenceCalibrationReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/admin/calibration/run": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
                 ],
+                "description": "Evaluate calibration now. Thresholds are adjusted only when CONFIDENCE_CALIBRATION_ENABLED is set and enough outcomes exist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Run confidence calibration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ConfidenceCalibrationReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/admin/config": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Runtime-tunable settings currently in effect",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get runtime config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RuntimeConfigResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/chat": {
-            "post": {
-                "description": "Send a message and get a response based on conversation history",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Partially update runtime-tunable settings without restarting. Omitted fields keep their current values.",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,17 +402,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Chat"
+                    "Admin"
                 ],
-                "summary": "Process chat message",
+                "summary": "Update runtime config",
                 "parameters": [
                     {
-                        "description": "Chat request",
+                        "description": "Settings to change",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ChatRequest"
+                            "$ref": "#/definitions/models.RuntimeConfigPatchRequest"
                         }
                     }
                 ],
@@ -189,7 +420,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RuntimeConfigResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -198,8 +441,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
@@ -207,35 +450,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/game/question": {
+        "/api/admin/config/reload": {
             "post": {
-                "description": "Generate an OX or multiple choice question based on user's conversation history",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
                 ],
+                "description": "Re-read the environment and CONFIG_FILE, as on SIGHUP, and apply their runtime-tunable settings. Admin overrides are replaced; safe-mode switches are kept. On a validation error the current settings stay in effect.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
-                ],
-                "summary": "Generate a game question",
-                "parameters": [
-                    {
-                        "description": "Question generation request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.GameQuestionRequest"
-                        }
-                    }
+                    "Admin"
                 ],
+                "summary": "Reload config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RuntimeConfigResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -244,14 +490,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.APIResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
-                        "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
@@ -259,45 +499,91 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/game/result": {
-            "post": {
-                "description": "Evaluate user's game result and store the evaluation",
-                "consumes": [
-                    "application/json"
+        "/api/admin/experiments/results": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
                 ],
+                "description": "Configured prompt variants with usage, conversation quality scores, and quiz accuracy joined per prompt/model cohort",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Admin"
                 ],
-                "summary": "Evaluate game result",
-                "parameters": [
-                    {
-                        "description": "Game result",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get experiment results",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GameResultRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ExperimentResultsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/admin/maintenance": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "What the last compaction of local stores (expired questions, idle experiment usage, old quiz history, old mood entries) removed, and when the nightly run is next due",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
                 ],
+                "summary": "Get maintenance report",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MaintenanceReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.APIResponse"
                         }
@@ -305,124 +591,5990 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Check if the LLM server is running and healthy",
+        "/api/admin/maintenance/run": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Compact local stores now instead of waiting for the nightly run",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Admin"
                 ],
-                "summary": "Health check",
+                "summary": "Run maintenance",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MaintenanceReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.APIResponse": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "error": {
-                    "$ref": "#/definitions/models.ErrorInfo"
-                },
-                "metadata": {
-                    "$ref": "#/definitions/models.Metadata"
-                },
-                "success": {
-                    "type": "boolean"
+        },
+        "/api/admin/questions/preview": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Generate a question for a supervised session. It stays pending, and results for it are refused, until approved through the review endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Preview a game question",
+                "parameters": [
+                    {
+                        "description": "Question generation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GameQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SupervisedQuestionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/questions/{id}/lineage": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Return everything a cached question was generated from: the full source conversations (or photo description), the generation prompt, the raw model output, and the validation results. Use it to investigate reports of made-up questions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get a question's lineage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuestionLineageResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/questions/{id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Approve a pending question, optionally overriding its difficulty and topic labels, or reject it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Review a previewed question",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review decision",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuestionReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SupervisedQuestionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/replay": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Re-run a user's stored chat turns, newest first, through the current prompt and model configuration (or the given generation overrides) and report how each reply and its evaluation score changed. Nothing is saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Replay stored conversations",
+                "parameters": [
+                    {
+                        "description": "User and turns to replay",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReplayResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/safe-mode": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Whether each subsystem with a kill switch (analysis, evaluation, suggestions) is currently enabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get safe-mode kill switches",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SafeModeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Switch subsystems off during an incident, or back on, without redeploying. Requests to a disabled subsystem fail with 503 FEATURE_DISABLED; background evaluation of chat turns and chat follow-up suggestions are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Update safe-mode kill switches",
+                "parameters": [
+                    {
+                        "description": "Features to switch on (true) or off (false)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SafeModePatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SafeModeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/users/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Erase everything stored about a user, in this service and on the RAG server, including saves still queued for delivery. Local data is removed even when the RAG erasure fails; retrying is safe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete user data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.UserDeletionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/users/{user_id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Download everything stored about a user (conversations, personal info, quiz attempts, mood entries, analysis reports) as one JSON archive. Fails rather than return a partial archive when the RAG server cannot be read.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Export user data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserDataExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Registered caregiver webhooks and the event types they can subscribe to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Register a URL to receive signed caregiver events (analysis.completed, retention.dropped, quiz.incorrect_streak, emergency.detected). Deliveries carry an X-Webhook-Signature HMAC-SHA256 of \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\" and are retried with exponential backoff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook registration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscriptionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Remove an API-registered webhook. Webhooks configured through WEBHOOKS cannot be removed here.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookDeleteResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/analysis": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Analyze user's conversation history and incorrect quizzes across 4 domains (family, life events, career, hobbies)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analysis"
+                ],
+                "summary": "Process domain analysis",
+                "parameters": [
+                    {
+                        "description": "Analysis request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AnalysisRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AnalysisResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/analysis/domains": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Analyze user's conversation history and incorrect quizzes across 4 domains without generating a report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analysis"
+                ],
+                "summary": "Process domain analysis only",
+                "parameters": [
+                    {
+                        "description": "Analysis request (user_id required)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AnalysisRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DomainAnalysisOnlyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/analysis/report": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Generate a professional markdown report based on provided domain analysis scores",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analysis"
+                ],
+                "summary": "Generate professional report from domain scores",
+                "parameters": [
+                    {
+                        "description": "Report generation request (4 domains required)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportGenerationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReportGenerationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/analysis/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Daily quiz retention and chat quality averages, completed assessment scores and analysis domain scores over the last days (default 90), oldest first, for the caregiver dashboard's trend charts. If chat history cannot be read, the timeline is returned without chat quality scores and with a warning.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analysis"
+                ],
+                "summary": "Get a cognitive score timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days to cover (1-365, default 90)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 Not Modified if unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CognitiveTimelineResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/assessment": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Start a short K-MMSE style screen (orientation to time, registration, attention, recall) for the user. The returned prompt is the first question; the user's following chat messages answer one item each, and the chat replies with the next question until the assessment is completed. An assessment with no answer for 30 minutes is abandoned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analysis"
+                ],
+                "summary": "Start a cognitive assessment",
+                "parameters": [
+                    {
+                        "description": "Assessment start request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssessmentStartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AssessmentProgress"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/assessment/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "The assessment's status, total and per-domain scores, and each answered item with its expected answer. Scores are raw K-MMSE item points for the administered items, not a full 30-point K-MMSE score.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analysis"
+                ],
+                "summary": "Get an assessment result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Assessment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 Not Modified if unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AssessmentResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/chat": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Send a message and get a response based on conversation history. With include_evaluation, the message is evaluated before replying and its quality (speech coherence) score is returned. With include_suggestions, 2-3 follow-up lines the voice frontend can say if the user goes silent are returned. Photos in image_urls (http(s) URLs or data:image/ URIs, up to 4) are discussed with the vision model and described in the saved conversation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Process chat message",
+                "parameters": [
+                    {
+                        "description": "Chat request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ChatResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/chat/mood": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Emotional state of the user's recent chat turns with daily averages and dominant emotions, for the caregiver dashboard",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Get mood timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days to include (1-90, default 14)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 Not Modified if unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.MoodTimelineResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/conversations/{id}/correct": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Mark an assistant statement in a conversation as factually wrong. The correction is stored with the user's profile and used as a guardrail in future chats.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Correct an assistant statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Correction request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ConversationCorrectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ConversationCorrectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/debug/prompt/chat": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Assemble the exact prompt and retrieved context a chat message would be answered with, without calling the LLM. Guardrails and emergency alerts are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Dry-run a chat message",
+                "parameters": [
+                    {
+                        "description": "Chat request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ChatPromptPreviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/debug/prompt/question": {
+            "post": {
+                "security": [
+                    {
+                        "AdminAPIKey": []
+                    }
+                ],
+                "description": "Assemble the exact prompt a question would be generated with, from the same conversation selection, without calling the LLM. Photo questions require image_description.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Dry-run a game question",
+                "parameters": [
+                    {
+                        "description": "Question generation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GameQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuestionPromptPreviewResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/game/orientation": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Generate multiple choice questions about today's date, weekday and season, plus a holiday within two weeks ahead or a few days behind. They are built from the calendar and need no conversation history, so they suit users with too few conversations for other questions. Answers are submitted like any other question.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Generate orientation questions",
+                "parameters": [
+                    {
+                        "description": "Orientation question request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrientationQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.OrientationQuestionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/game/question": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Generate an OX or multiple choice question based on user's conversation history. The integrative type combines 2-3 related conversations into one harder question. The photo type asks about the people and places in image_url, using image_description or a vision-model caption. Set from/to to base the question on conversations from a period, e.g. last week. With allow_profile_fallback, users with too few conversations get a question about their personal info (family names, hometown, former job) instead of a 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Generate a game question",
+                "parameters": [
+                    {
+                        "description": "Question generation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GameQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GameQuestionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/game/question-set": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Generate a cohesive set of multiple choice questions with an intro line for a theme (chuseok, birthday, family_visit), based on conversations matching the theme's keywords and date window (chuseok matches on keywords across all dates)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Generate a themed question set",
+                "parameters": [
+                    {
+                        "description": "Question set request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuestionSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuestionSetResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/game/question/{id}/answer": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Grade the user's answer (option ID or option text), record the result, and return a gentle explanation of the correct answer that refers back to the original conversation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Answer a game question",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Question ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuestionAnswerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.QuestionAnswerResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/game/recap": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Turn the user's recent conversations and missed quiz topics into short recap cards (title, 1-2 sentences, source conversation) for \"오늘의 회상\". Set from/to to recap a specific period, e.g. yesterday.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Generate recap cards",
+                "parameters": [
+                    {
+                        "description": "Recap request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecapRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.RecapResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/game/result": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Evaluate user's game result and store the evaluation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Evaluate game result",
+                "parameters": [
+                    {
+                        "description": "Game result",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GameResultRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GameResultResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reminders": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "The user's reminders, soonest first. Acknowledged reminders are included only on request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "List reminders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include acknowledged reminders",
+                        "name": "include_acknowledged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 Not Modified if unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReminderListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Store a medication or appointment reminder. Chat replies bring it up naturally from a few hours before it is due (\"오늘 3시에 병원 가시는 날이죠?\") until the user confirms it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Register a reminder",
+                "parameters": [
+                    {
+                        "description": "Reminder",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReminderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Reminder"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reminders/{id}/acknowledge": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Mark a reminder as confirmed, e.g. from the caregiver app, so chats stop bringing it up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Acknowledge a reminder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reminder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Acknowledgement",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReminderAcknowledgeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Reminder"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Register a user with their name, birth year, preferred call time and optional preferences. With USER_REGISTRY_ENFORCED set, chat, game and analysis requests for unregistered users are rejected with 404 USER_NOT_FOUND, so a mistyped user_id cannot create a phantom profile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Register a user",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "The user's registry entry.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a registered user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}/avoid-topics": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Register a topic (e.g. deceased relatives, traumatic events) that the assistant must avoid for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Register a blacklisted topic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Avoid topic request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AvoidTopicRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.AvoidTopicResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}/care-mode": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Flag a user for a specialized prompt mode (e.g. grief_sensitive) used when discussing loss and sensitive memories",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Set a user's care mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Care mode request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CareModeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CareModeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}/pins": {
+            "post": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Pin a fact or conversation excerpt (e.g. \"uses a walker\", \"sister visits on Sundays\") that is included in every chat prompt for the user. Up to 10 pins per user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Pin a fact to chat context",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pin request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.PinResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}/plan": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "The user's current weekly cognitive exercise plan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Get a user's care plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 Not Modified if unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CarePlanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Replace the user's weekly cognitive exercise plan. On scheduled days the chat steers towards the first goal whose daily target is not yet met (family_reminiscence, date_orientation, name_recall).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Set a user's care plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Care plan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CarePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CarePlanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}/plan/summary": {
+            "get": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "How many of a day's chat turns were steered towards and touched each care plan goal, for the caregiver's daily summary",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chat"
+                ],
+                "summary": "Get daily goal coverage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day (YYYY-MM-DD, default today)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 Not Modified if unchanged",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GoalSummaryResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{user_id}/preferences": {
+            "put": {
+                "security": [
+                    {
+                        "TenantAPIKey": []
+                    }
+                ],
+                "description": "Replace the user's preferences. Chat replies use the preferred language and persona and report the voice speed for the voice frontend; generated questions are never easier than the difficulty floor unless a request asks for a difficulty. Omitted fields fall back to the server defaults.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Set a user's preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the LLM server is running and healthy. The status is \"degraded\" while a configured model is deprecated or not found; calls for it use the fallback model when one is configured.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HealthResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {
+                    "$ref": "#/definitions/models.ErrorInfo"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/models.Metadata"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.AnalysisRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AnalysisResponse": {
+            "type": "object",
+            "properties": {
+                "analyzed_at": {
+                    "type": "string"
+                },
+                "consolidation": {
+                    "$ref": "#/definitions/models.ConsolidationMetric"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainScore"
+                    }
+                },
+                "report": {
+                    "description": "MD 형식 리포트 (2000자 이상)",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AssessmentDomainScore": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string",
+                    "enum": [
+                        "orientation",
+                        "registration",
+                        "attention",
+                        "recall"
+                    ]
+                },
+                "max_score": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssessmentItemResult": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string",
+                    "enum": [
+                        "orientation",
+                        "registration",
+                        "attention",
+                        "recall"
+                    ]
+                },
+                "expected": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssessmentPoint": {
+            "type": "object",
+            "properties": {
+                "assessment_id": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AssessmentDomainScore"
+                    }
+                },
+                "max_score": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssessmentProgress": {
+            "type": "object",
+            "properties": {
+                "assessment_id": {
+                    "type": "string"
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "domain": {
+                    "description": "of the item asked next",
+                    "type": "string",
+                    "enum": [
+                        "orientation",
+                        "registration",
+                        "attention",
+                        "recall"
+                    ]
+                },
+                "item": {
+                    "description": "1-based number of the item asked next",
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string",
+                    "example": "올해는 몇 년도인가요?"
+                },
+                "total_items": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssessmentResult": {
+            "type": "object",
+            "properties": {
+                "assessment_id": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AssessmentDomainScore"
+                    }
+                },
+                "items": {
+                    "description": "answered items, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AssessmentItemResult"
+                    }
+                },
+                "max_score": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "in_progress",
+                        "completed",
+                        "abandoned"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AssessmentStartRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.AvoidTopicRequest": {
+            "type": "object",
+            "required": [
+                "topic"
+            ],
+            "properties": {
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AvoidTopicResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "topic_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CacheEvictResponse": {
+            "type": "object",
+            "properties": {
+                "evicted": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CacheStats": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "profiles"
+                },
+                "ttl_seconds": {
+                    "description": "how long entries are served, 0 when disabled",
+                    "type": "integer"
+                },
+                "users": {
+                    "description": "users with at least one entry",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CacheStatsResponse": {
+            "type": "object",
+            "properties": {
+                "caches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CacheStats"
+                    }
+                }
+            }
+        },
+        "models.CareModeRequest": {
+            "type": "object",
+            "required": [
+                "mode"
+            ],
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "grief_sensitive"
+                    ]
+                }
+            }
+        },
+        "models.CareModeResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CarePlanGoal": {
+            "type": "object",
+            "required": [
+                "kind"
+            ],
+            "properties": {
+                "days": {
+                    "description": "empty schedules every day",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "mon",
+                        "wed",
+                        "fri"
+                    ]
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "family_reminiscence",
+                        "date_orientation",
+                        "name_recall"
+                    ],
+                    "example": "family_reminiscence"
+                },
+                "note": {
+                    "description": "caregiver detail woven into the guidance",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "손녀 지수가 이번 달에 대학에 입학했어요"
+                },
+                "target_turns": {
+                    "description": "engaged turns per scheduled day, default 3",
+                    "type": "integer",
+                    "maximum": 20,
+                    "minimum": 1,
+                    "example": 3
+                }
+            }
+        },
+        "models.CarePlanRequest": {
+            "type": "object",
+            "required": [
+                "goals"
+            ],
+            "properties": {
+                "goals": {
+                    "type": "array",
+                    "maxItems": 7,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.CarePlanGoal"
+                    }
+                }
+            }
+        },
+        "models.CarePlanResponse": {
+            "type": "object",
+            "properties": {
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CarePlanGoal"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChatEvaluation": {
+            "type": "object",
+            "properties": {
+                "score": {
+                    "description": "0-100",
+                    "type": "integer",
+                    "example": 78
+                },
+                "score_version": {
+                    "description": "prompt version the score was evaluated with",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChatPromptPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "context_messages": {
+                    "description": "retrieved messages that passed the score filter",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "context_used": {
+                    "$ref": "#/definitions/models.ContextUsage"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromptMessage"
+                    }
+                },
+                "model": {
+                    "type": "string"
+                },
+                "previous_summary": {
+                    "type": "string"
+                },
+                "skipped_steps": {
+                    "description": "LLM-backed steps left out of the dry run",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "small_talk": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ChatRequest": {
+            "type": "object",
+            "required": [
+                "image_urls",
+                "message",
+                "user_id"
+            ],
+            "properties": {
+                "channel": {
+                    "description": "Channel the message arrived through, saved with the conversation; api when omitted",
+                    "type": "string",
+                    "enum": [
+                        "phone",
+                        "app",
+                        "api"
+                    ]
+                },
+                "image_urls": {
+                    "description": "Photos to discuss with the user, as http(s) URLs or uploaded data:image/\nURIs. They are answered by the vision model and described in the saved\nconversation.",
+                    "type": "array",
+                    "maxItems": 4,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "include_evaluation": {
+                    "description": "Evaluate the message before replying and return its quality score, instead\nof only storing it with the saved conversation",
+                    "type": "boolean"
+                },
+                "include_suggestions": {
+                    "description": "Return 2-3 follow-up lines the voice frontend can say when the user goes\nsilent after the reply, generated by a second, cheaper model call",
+                    "type": "boolean"
+                },
+                "max_tokens": {
+                    "type": "integer",
+                    "maximum": 8192,
+                    "minimum": 1
+                },
+                "message": {
+                    "type": "string",
+                    "example": "오늘 딸이 손주들을 데리고 왔어요"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.ChatResponse": {
+            "type": "object",
+            "properties": {
+                "assessment": {
+                    "description": "set when the turn was an assessment answer",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AssessmentProgress"
+                        }
+                    ]
+                },
+                "cached": {
+                    "description": "reused the reply to a near-identical recent question",
+                    "type": "boolean"
+                },
+                "context_used": {
+                    "$ref": "#/definitions/models.ContextUsage"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "emergency": {
+                    "description": "set when the message suggested an emergency",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EmergencyInfo"
+                        }
+                    ]
+                },
+                "evaluation": {
+                    "description": "set on include_evaluation when the message was evaluated",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChatEvaluation"
+                        }
+                    ]
+                },
+                "guardrail": {
+                    "description": "set when a safety guardrail changed the reply",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GuardrailInfo"
+                        }
+                    ]
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reminders_acknowledged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reminders_mentioned": {
+                    "description": "Reminders the reply was asked to bring up, and reminders the message confirmed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "response": {
+                    "type": "string"
+                },
+                "small_talk": {
+                    "description": "answered by the small-talk fast path without retrieval",
+                    "type": "boolean"
+                },
+                "suggestions": {
+                    "description": "on include_suggestions, follow-up lines to offer if the user stays silent",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "voice_speed": {
+                    "description": "speech rate from the user's preferences, for the voice frontend",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CognitiveDailyPoint": {
+            "type": "object",
+            "properties": {
+                "chat_quality_score": {
+                    "description": "0-100, omitted without scored chats",
+                    "type": "number"
+                },
+                "chat_turns": {
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "quiz_attempts": {
+                    "type": "integer"
+                },
+                "retention_score": {
+                    "description": "0-1, omitted without quiz attempts",
+                    "type": "number"
+                }
+            }
+        },
+        "models.CognitiveTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "assessments": {
+                    "description": "completed assessments",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AssessmentPoint"
+                    }
+                },
+                "daily": {
+                    "description": "days with quiz attempts or scored chats",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CognitiveDailyPoint"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "domain_scores": {
+                    "description": "analysis reports",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainScorePoint"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ConfidenceCalibrationReport": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "thresholds were changed by this evaluation",
+                    "type": "boolean"
+                },
+                "auto_adjust": {
+                    "type": "boolean"
+                },
+                "evaluated_at": {
+                    "type": "string"
+                },
+                "high_threshold": {
+                    "type": "number"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConfidenceLabelRecall"
+                    }
+                },
+                "medium_threshold": {
+                    "type": "number"
+                },
+                "min_samples": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "suggested_high_threshold": {
+                    "type": "number"
+                },
+                "suggested_medium_threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ConfidenceLabelRecall": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "later_recall_rate": {
+                    "description": "0-1",
+                    "type": "number"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "target_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ConsolidationMetric": {
+            "type": "object",
+            "properties": {
+                "consolidated_facts": {
+                    "type": "integer"
+                },
+                "consolidated_topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "immediate_only_facts": {
+                    "type": "integer"
+                },
+                "immediate_topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "not_recalled_facts": {
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "0-100, share of re-quizzed facts recalled in 2+ sessions",
+                    "type": "integer"
+                },
+                "tracked_facts": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ContextUsage": {
+            "type": "object",
+            "properties": {
+                "incorrect_attempts_loaded": {
+                    "type": "boolean"
+                },
+                "profile_loaded": {
+                    "type": "boolean"
+                },
+                "query_strategy": {
+                    "description": "raw, llm, hybrid",
+                    "type": "string"
+                },
+                "search_query": {
+                    "description": "query actually sent to conversation search",
+                    "type": "string"
+                },
+                "top_score": {
+                    "type": "number"
+                },
+                "total_conversations": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ConversationCorrectionRequest": {
+            "type": "object",
+            "required": [
+                "correction",
+                "incorrect_statement",
+                "user_id"
+            ],
+            "properties": {
+                "correction": {
+                    "type": "string"
+                },
+                "incorrect_statement": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConversationCorrectionResponse": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "correction_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DailyMood": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "type": "number"
+                },
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "dominant_emotion": {
+                    "type": "string"
+                },
+                "turns": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainAnalysisOnlyResponse": {
+            "type": "object",
+            "properties": {
+                "analyzed_at": {
+                    "type": "string"
+                },
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainScore"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DomainScore": {
+            "type": "object",
+            "properties": {
+                "analysis": {
+                    "description": "상세 분석 텍스트",
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "\"family\", \"life_events\", \"career\", \"hobbies\"",
+                    "type": "string"
+                },
+                "insights": {
+                    "description": "인사이트 (최대 5줄)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "description": "0-100",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainScorePoint": {
+            "type": "object",
+            "properties": {
+                "analyzed_at": {
+                    "type": "string"
+                },
+                "consolidation_score": {
+                    "description": "0-100",
+                    "type": "integer"
+                },
+                "scores": {
+                    "description": "domain -\u003e 0-100",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.EmergencyInfo": {
+            "type": "object",
+            "properties": {
+                "incident_id": {
+                    "type": "string"
+                },
+                "signals": {
+                    "description": "chest_pain, fall, breathing, stroke, call_for_help",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ErrorInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExperimentCohortResult": {
+            "type": "object",
+            "properties": {
+                "avg_conversation_score": {
+                    "type": "number"
+                },
+                "avg_retention_score": {
+                    "type": "number"
+                },
+                "chat_turns": {
+                    "type": "integer"
+                },
+                "cohort": {
+                    "type": "string"
+                },
+                "evaluations": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "prompt_version": {
+                    "type": "string"
+                },
+                "query_strategy": {
+                    "type": "string"
+                },
+                "questions_generated": {
+                    "type": "integer"
+                },
+                "quiz_accuracy": {
+                    "type": "number"
+                },
+                "quiz_attempts": {
+                    "type": "integer"
+                },
+                "quiz_correct": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExperimentResultsResponse": {
+            "type": "object",
+            "properties": {
+                "cohorts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExperimentCohortResult"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "query_strategy_variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExperimentVariant"
+                    }
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExperimentVariant"
+                    }
+                }
+            }
+        },
+        "models.ExperimentVariant": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FeatureSwitch": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "feature": {
+                    "type": "string",
+                    "enum": [
+                        "analysis",
+                        "evaluation",
+                        "suggestions"
+                    ],
+                    "example": "analysis"
+                }
+            }
+        },
+        "models.GameQuestionRequest": {
+            "type": "object",
+            "required": [
+                "question_type",
+                "user_id"
+            ],
+            "properties": {
+                "allow_profile_fallback": {
+                    "description": "With too few conversations, ask about a personal info fact (family names,\nhometown, former job) instead of failing. Integrative questions fall back\nto multiple choice.",
+                    "type": "boolean"
+                },
+                "difficulty_hint": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-10-07T00:00:00+09:00"
+                },
+                "image_description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "2019년 추석, 왼쪽부터 큰딸 민지와 손자 준호, 고향집 마당"
+                },
+                "image_url": {
+                    "description": "Photo questions: the photo to ask about, and optionally the caregiver's\ndescription of who and what it shows. Without a description the photo is\ncaptioned by the vision model.",
+                    "type": "string",
+                    "example": "https://example.com/photos/family.jpg"
+                },
+                "max_tokens": {
+                    "type": "integer",
+                    "maximum": 8192,
+                    "minimum": 1
+                },
+                "model": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "type": "string",
+                    "enum": [
+                        "fill_in_blank",
+                        "multiple_choice",
+                        "integrative",
+                        "photo"
+                    ],
+                    "example": "multiple_choice"
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-10-14T00:00:00+09:00"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.GameQuestionResponse": {
+            "type": "object",
+            "properties": {
+                "based_on_conversation": {
+                    "type": "string",
+                    "example": "conv_123"
+                },
+                "based_on_conversations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "based_on_personal_info": {
+                    "description": "personal info ID of a profile fallback question",
+                    "type": "string"
+                },
+                "correct_answer": {
+                    "description": "omitted from API v2, which grades through the answer endpoint",
+                    "type": "string",
+                    "enum": [
+                        "A",
+                        "B",
+                        "C",
+                        "D"
+                    ],
+                    "example": "B"
+                },
+                "difficulty": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ],
+                    "example": "medium"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/models.QuestionMetadata"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionOption"
+                    }
+                },
+                "question": {
+                    "type": "string",
+                    "example": "지난주 딸과 함께 간 곳은 어디였나요?"
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "5f1c2b1e-8a4d-4f57-9a57-0a8c1b7e2d31"
+                },
+                "question_type": {
+                    "type": "string",
+                    "enum": [
+                        "fill_in_blank",
+                        "multiple_choice",
+                        "integrative",
+                        "photo",
+                        "orientation"
+                    ],
+                    "example": "multiple_choice"
+                }
+            }
+        },
+        "models.GameResultRequest": {
+            "type": "object",
+            "required": [
+                "question_id",
+                "user_answer",
+                "user_id"
+            ],
+            "properties": {
+                "game_session_id": {
+                    "type": "string",
+                    "example": "session_2024_10_01"
+                },
+                "is_correct": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "5f1c2b1e-8a4d-4f57-9a57-0a8c1b7e2d31"
+                },
+                "response_time_ms": {
+                    "type": "integer",
+                    "example": 4200
+                },
+                "user_answer": {
+                    "type": "string",
+                    "example": "B"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.GameResultResponse": {
+            "type": "object",
+            "properties": {
+                "memory_evaluation": {
+                    "$ref": "#/definitions/models.MemoryEvaluation"
+                },
+                "next_question_suggestion": {
+                    "$ref": "#/definitions/models.NextQuestionSuggestion"
+                },
+                "result_id": {
+                    "type": "string"
+                },
+                "stored_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GoalCoverage": {
+            "type": "object",
+            "properties": {
+                "coverage": {
+                    "description": "engaged turns over the target, at most 1",
+                    "type": "number"
+                },
+                "engaged_turns": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "met": {
+                    "type": "boolean"
+                },
+                "prompted_turns": {
+                    "type": "integer"
+                },
+                "scheduled": {
+                    "type": "boolean"
+                },
+                "target_turns": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GoalSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "coverage": {
+                    "description": "mean coverage of the day's scheduled goals, 0 when none",
+                    "type": "number"
+                },
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GoalCoverage"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GuardrailInfo": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "rewrite, block",
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stage": {
+                    "description": "input, output",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "model_warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModelWarning"
+                    }
+                },
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"ok\" or \"degraded\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncorrectQuizAttempt": {
+            "type": "object",
+            "properties": {
+                "attempt_id": {
+                    "type": "integer"
+                },
+                "attempt_order": {
+                    "type": "integer"
+                },
+                "attempt_time": {
+                    "type": "string"
+                },
+                "correct": {
+                    "type": "boolean"
+                },
+                "correct_answer": {
+                    "type": "string"
+                },
+                "is_correct": {
+                    "type": "boolean"
+                },
+                "quiz": {
+                    "$ref": "#/definitions/models.QuizInfo"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "user_answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MaintenanceReport": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "description": "nil when nightly maintenance is disabled",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MaintenanceTaskResult"
+                    }
+                },
+                "total_reclaimed_bytes": {
+                    "description": "approximate",
+                    "type": "integer"
+                },
+                "total_removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MaintenanceTaskResult": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "expired_questions"
+                },
+                "reclaimed_bytes": {
+                    "description": "approximate",
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MemoryEvaluation": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "description": "\"high\", \"medium\", \"low\"",
+                    "type": "string"
+                },
+                "recommendation": {
+                    "type": "string"
+                },
+                "retention_score": {
+                    "type": "number"
+                },
+                "score_breakdown": {
+                    "description": "zero when scored by the llm strategy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RetentionScoreBreakdown"
+                        }
+                    ]
+                },
+                "strategy": {
+                    "description": "\"formula\" or \"llm\", the strategy that produced the score",
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Metadata": {
+            "type": "object",
+            "properties": {
+                "request_id": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModelWarning": {
+            "type": "object",
+            "properties": {
+                "detected_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "description": "empty when no fallback model is configured, so calls fail",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoodEntry": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "emotion": {
+                    "type": "string"
+                },
+                "mood_score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MoodTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "average_score": {
+                    "description": "omitted when no turns were scored",
+                    "type": "number"
+                },
+                "daily": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DailyMood"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "emotions": {
+                    "description": "turn count per emotion",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MoodEntry"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NextQuestionSuggestion": {
+            "type": "object",
+            "properties": {
+                "difficulty": {
+                    "type": "string"
+                },
+                "topic_preference": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrientationQuestionRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "count": {
+                    "description": "default every available question",
+                    "type": "integer",
+                    "maximum": 4,
+                    "minimum": 1
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrientationQuestionResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-10-16"
+                },
+                "questions": {
+                    "description": "always orientation",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GameQuestionResponse"
+                    }
+                }
+            }
+        },
+        "models.PersonalInfoResponse": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "importance": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PinRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "maxLength": 300,
+                    "example": "보행 보조기를 사용하심"
+                }
+            }
+        },
+        "models.PinResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "pin_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PromptMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionAnswerRequest": {
+            "type": "object",
+            "required": [
+                "answer",
+                "user_id"
+            ],
+            "properties": {
+                "answer": {
+                    "description": "option ID (\"A\") or the option text",
+                    "type": "string"
+                },
+                "game_session_id": {
+                    "type": "string"
+                },
+                "response_time_ms": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionAnswerResponse": {
+            "type": "object",
+            "properties": {
+                "correct_answer": {
+                    "type": "string"
+                },
+                "correct_answer_text": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "type": "string"
+                },
+                "is_correct": {
+                    "type": "boolean"
+                },
+                "question_id": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/models.GameResultResponse"
+                },
+                "selected_option": {
+                    "description": "option the answer matched, empty if none",
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionGeneration": {
+            "type": "object",
+            "properties": {
+                "model": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromptMessage"
+                    }
+                },
+                "raw_output": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionLineageResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "generation": {
+                    "$ref": "#/definitions/models.QuestionGeneration"
+                },
+                "question": {
+                    "$ref": "#/definitions/models.GameQuestionResponse"
+                },
+                "question_id": {
+                    "type": "string"
+                },
+                "review_status": {
+                    "type": "string"
+                },
+                "source_content": {
+                    "description": "conversation text or photo description given to the model",
+                    "type": "string"
+                },
+                "source_conversations": {
+                    "description": "empty for photo questions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RAGConversationSearchResult"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "validation": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuestionValidationCheck"
+                    }
+                }
+            }
+        },
+        "models.QuestionMetadata": {
+            "type": "object",
+            "properties": {
+                "days_since_conversation": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "memory_score": {
+                    "type": "number",
+                    "example": 0.82
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "딸과 공원 산책"
+                }
+            }
+        },
+        "models.QuestionOption": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "enum": [
+                        "A",
+                        "B",
+                        "C",
+                        "D"
+                    ],
+                    "example": "B"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "동네 공원"
+                }
+            }
+        },
+        "models.QuestionPromptPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "based_on_conversations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromptMessage"
+                    }
+                },
+                "model": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionReviewRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "reject"
+                    ]
+                },
+                "difficulty": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.QuestionSetRequest": {
+            "type": "object",
+            "required": [
+                "theme",
+                "user_id"
+            ],
+            "properties": {
+                "count": {
+                    "description": "default 5",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                },
+                "max_tokens": {
+                    "type": "integer",
+                    "maximum": 8192,
+                    "minimum": 1
+                },
+                "model": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0
+                },
+                "theme": {
+                    "type": "string",
+                    "enum": [
+                        "chuseok",
+                        "birthday",
+                        "family_visit"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionSetResponse": {
+            "type": "object",
+            "properties": {
+                "based_on_conversations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "intro": {
+                    "description": "opening line introducing the theme",
+                    "type": "string"
+                },
+                "questions": {
+                    "description": "always multiple_choice",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GameQuestionResponse"
+                    }
+                },
+                "set_id": {
+                    "type": "string"
+                },
+                "theme": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuestionValidationCheck": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "enum": [
+                        "option_count",
+                        "correct_answer_in_options",
+                        "distinct_options",
+                        "answer_in_source"
+                    ],
+                    "example": "answer_in_source"
+                },
+                "passed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.QuizHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "string"
+                },
+                "fact_id": {
+                    "type": "string"
+                },
+                "is_correct": {
+                    "type": "boolean"
+                },
+                "retention_score": {
+                    "type": "number"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuizInfo": {
+            "type": "object",
+            "properties": {
+                "based_on_conversation": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "hint": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuizOption"
+                    }
+                },
+                "question": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "description": "\"ox\", \"multiple_choice\"",
+                    "type": "string"
+                },
+                "quiz_id": {
+                    "type": "string"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuizOption": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RAGConversationSearchResult": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RAGMessage"
+                    }
+                },
+                "metadata": {
+                    "description": "returned by RAG servers that expose stored metadata",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RAGMetadata"
+                        }
+                    ]
+                },
+                "score": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RAGMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "\"user\" or \"assistant\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RAGMetadata": {
+            "type": "object",
+            "properties": {
+                "conversation_score": {
+                    "description": "0-100: Quality score of the conversation",
+                    "type": "integer"
+                },
+                "emotion": {
+                    "type": "string"
+                },
+                "language": {
+                    "description": "response language of the chat (ko, en)",
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "mood_score": {
+                    "description": "0-100: emotional state of the user's message, 50 is neutral",
+                    "type": "integer"
+                },
+                "prompt_variant": {
+                    "description": "prompt experiment variant the user was served",
+                    "type": "string"
+                },
+                "query_strategy": {
+                    "description": "retrieval query strategy the user was served",
+                    "type": "string"
+                },
+                "question_id": {
+                    "type": "string"
+                },
+                "retention_score": {
+                    "type": "number"
+                },
+                "schema_version": {
+                    "type": "integer"
+                },
+                "score_version": {
+                    "description": "prompt version ConversationScore was evaluated with",
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "source_channel": {
+                    "description": "phone, app or api",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "description": "care facility whose API key saved the conversation",
+                    "type": "string"
+                },
+                "topics": {
+                    "description": "conversation topics, most prominent first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "description": "\"chat\", \"memory_evaluation\", etc.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecapCard": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "1-2 sentences",
+                    "type": "string"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "missed_topic": {
+                    "description": "revisits a topic of a recently missed quiz question",
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecapRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "count": {
+                    "description": "default 5",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 1
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-10-07T00:00:00+09:00"
+                },
+                "max_tokens": {
+                    "type": "integer",
+                    "maximum": 8192,
+                    "minimum": 1
+                },
+                "model": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-10-14T00:00:00+09:00"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecapResponse": {
+            "type": "object",
+            "properties": {
+                "based_on_conversations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecapCard"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "missed_topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "recap_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Reminder": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "description": "when the user confirmed it",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "mentioned_at": {
+                    "description": "last time a chat prompt included it",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReminderAcknowledgeRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.ReminderListResponse": {
+            "type": "object",
+            "properties": {
+                "reminders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reminder"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReminderRequest": {
+            "type": "object",
+            "required": [
+                "due_at",
+                "kind",
+                "title",
+                "user_id"
+            ],
+            "properties": {
+                "due_at": {
+                    "type": "string",
+                    "example": "2024-10-14T15:00:00+09:00"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "medication",
+                        "appointment"
+                    ],
+                    "example": "appointment"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "딸이 모시러 옴"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "정형외과 진료"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.ReplayRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2024-10-07T00:00:00+09:00"
+                },
+                "limit": {
+                    "description": "default 10",
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 1
+                },
+                "max_tokens": {
+                    "type": "integer",
+                    "maximum": 8192,
+                    "minimum": 1
+                },
+                "model": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-10-14T00:00:00+09:00"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.ReplayResponse": {
+            "type": "object",
+            "properties": {
+                "average_score_delta": {
+                    "description": "over turns scored both times",
+                    "type": "number"
+                },
+                "average_similarity": {
+                    "type": "number"
+                },
+                "changed": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "prompt_variant": {
+                    "description": "empty for the control prompt",
+                    "type": "string"
+                },
+                "replayed": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplayResult"
+                    }
+                },
+                "score_version": {
+                    "description": "evaluation prompt version of the replayed scores",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReplayResult": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "boolean"
+                },
+                "conversation_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "original_response": {
+                    "type": "string"
+                },
+                "original_score": {
+                    "type": "integer"
+                },
+                "original_score_version": {
+                    "type": "string"
+                },
+                "replayed_response": {
+                    "type": "string"
+                },
+                "replayed_score": {
+                    "type": "integer"
+                },
+                "score_delta": {
+                    "description": "replayed minus original score",
+                    "type": "integer"
+                },
+                "similarity": {
+                    "description": "0-1 character n-gram similarity of the two replies",
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportGenerationRequest": {
+            "type": "object",
+            "required": [
+                "domains"
+            ],
+            "properties": {
+                "domains": {
+                    "description": "4개 도메인 필수",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainScore"
+                    }
+                }
+            }
+        },
+        "models.ReportGenerationResponse": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "report": {
+                    "description": "MD 형식 리포트",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RetentionScoreBreakdown": {
+            "type": "object",
+            "properties": {
+                "correctness": {
+                    "$ref": "#/definitions/models.ScoreComponent"
+                },
+                "recency": {
+                    "$ref": "#/definitions/models.ScoreComponent"
+                },
+                "speed": {
+                    "$ref": "#/definitions/models.ScoreComponent"
+                }
+            }
+        },
+        "models.RuntimeConfigPatchRequest": {
+            "type": "object",
+            "properties": {
+                "confidence_high_threshold": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "confidence_medium_threshold": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "context_max_messages_per_conversation": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "context_min_score": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "context_query_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "raw",
+                        "llm",
+                        "hybrid"
+                    ]
+                },
+                "context_rerank_enabled": {
+                    "type": "boolean"
+                },
+                "context_summary_enabled": {
+                    "type": "boolean"
+                },
+                "log_debug_users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "log_sample_rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "openai_max_tokens": {
+                    "type": "integer",
+                    "maximum": 16384,
+                    "minimum": 1
+                },
+                "openai_model": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "openai_temperature": {
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0
+                },
+                "question_cache_ttl_seconds": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "models.RuntimeConfigResponse": {
+            "type": "object",
+            "properties": {
+                "confidence_high_threshold": {
+                    "type": "number"
+                },
+                "confidence_medium_threshold": {
+                    "type": "number"
+                },
+                "context_max_messages_per_conversation": {
+                    "type": "integer"
+                },
+                "context_min_score": {
+                    "type": "number"
+                },
+                "context_query_strategy": {
+                    "type": "string"
+                },
+                "context_rerank_enabled": {
+                    "type": "boolean"
+                },
+                "context_summary_enabled": {
+                    "type": "boolean"
+                },
+                "log_debug_users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "log_level": {
+                    "type": "string"
+                },
+                "log_sample_rate": {
+                    "type": "number"
+                },
+                "memory_evaluation_weights": {
+                    "description": "correct, speed, recency",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "openai_max_tokens": {
+                    "type": "integer"
+                },
+                "openai_model": {
+                    "type": "string"
+                },
+                "openai_temperature": {
+                    "type": "number"
+                },
+                "question_cache_ttl_seconds": {
+                    "type": "integer"
                 }
             }
         },
-        "models.AnalysisRequest": {
+        "models.SafeModePatchRequest": {
             "type": "object",
             "required": [
-                "user_id"
+                "features"
             ],
             "properties": {
-                "user_id": {
-                    "type": "string"
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
                 }
             }
         },
-        "models.ChatRequest": {
+        "models.SafeModeResponse": {
             "type": "object",
-            "required": [
-                "message",
-                "user_id"
-            ],
             "properties": {
-                "message": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeatureSwitch"
+                    }
+                }
+            }
+        },
+        "models.ScoreBackfillJob": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
                     "type": "string"
                 },
-                "user_id": {
+                "force": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "prompt_version": {
+                    "type": "string"
+                },
+                "rescored": {
+                    "type": "integer"
+                },
+                "scanned": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "not chat turns, or already at the current version",
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "completed"
+                    ]
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.DomainScore": {
+        "models.ScoreBackfillRequest": {
             "type": "object",
+            "required": [
+                "user_ids"
+            ],
             "properties": {
-                "analysis": {
-                    "description": "상세 분석 텍스트",
-                    "type": "string"
+                "force": {
+                    "description": "also re-score conversations already at the current version",
+                    "type": "boolean"
                 },
-                "domain": {
-                    "description": "\"family\", \"life_events\", \"career\", \"hobbies\"",
-                    "type": "string"
+                "limit": {
+                    "description": "conversations per user, newest first",
+                    "type": "integer",
+                    "maximum": 500,
+                    "minimum": 1
                 },
-                "insights": {
-                    "description": "인사이트 (최대 5줄)",
+                "user_ids": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
                         "type": "string"
                     }
+                }
+            }
+        },
+        "models.ScoreComponent": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "type": "number"
                 },
                 "score": {
-                    "description": "0-100",
-                    "type": "integer"
+                    "description": "0-1",
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
                 }
             }
         },
-        "models.ErrorInfo": {
+        "models.SupervisedQuestionResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "question": {
+                    "description": "omitted once rejected",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GameQuestionResponse"
+                        }
+                    ]
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "5f1c2b1e-8a4d-4f57-9a57-0a8c1b7e2d31"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ]
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "birth_year": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "details": {},
-                "message": {
+                "name": {
+                    "type": "string"
+                },
+                "preferences": {
+                    "$ref": "#/definitions/models.UserPreferences"
+                },
+                "preferred_call_time": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "last preferences change",
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.GameQuestionRequest": {
+        "models.UserCreateRequest": {
             "type": "object",
             "required": [
-                "question_type",
+                "name",
                 "user_id"
             ],
             "properties": {
-                "difficulty_hint": {
-                    "description": "easy, medium, hard",
-                    "type": "string"
+                "birth_year": {
+                    "type": "integer",
+                    "minimum": 1900,
+                    "example": 1942
                 },
-                "question_type": {
+                "name": {
                     "type": "string",
-                    "enum": [
-                        "fill_in_blank",
-                        "multiple_choice"
+                    "maxLength": 50,
+                    "example": "김영희"
+                },
+                "preferences": {
+                    "$ref": "#/definitions/models.UserPreferences"
+                },
+                "preferred_call_time": {
+                    "description": "HH:MM, local time",
+                    "type": "string",
+                    "example": "10:30"
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "user_123"
+                }
+            }
+        },
+        "models.UserDataExport": {
+            "type": "object",
+            "properties": {
+                "analysis_reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AnalysisResponse"
+                    }
+                },
+                "assessments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AssessmentResult"
+                    }
+                },
+                "care_plan": {
+                    "$ref": "#/definitions/models.CarePlanResponse"
+                },
+                "conversations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RAGConversationSearchResult"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "incorrect_quiz_attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IncorrectQuizAttempt"
+                    }
+                },
+                "mood_entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MoodEntry"
+                    }
+                },
+                "personal_info": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PersonalInfoResponse"
+                    }
+                },
+                "quiz_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuizHistoryEntry"
+                    }
+                },
+                "reminders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reminder"
+                    }
+                },
+                "user": {
+                    "description": "registry entry, if registered",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
                     ]
                 },
                 "user_id": {
@@ -430,60 +6582,153 @@ const docTemplate = `{
                 }
             }
         },
-        "models.GameResultRequest": {
+        "models.UserDeletionResponse": {
             "type": "object",
-            "required": [
-                "question_id",
-                "user_answer",
-                "user_id"
-            ],
             "properties": {
-                "game_session_id": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "is_correct": {
+                "local_removed": {
+                    "description": "items removed from each in-process store",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "rag_deleted": {
                     "type": "boolean"
                 },
-                "question_id": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.UserPreferences": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "chat reply language, CHAT_LANGUAGE when empty",
+                    "type": "string",
+                    "enum": [
+                        "ko",
+                        "en"
+                    ]
                 },
-                "response_time_ms": {
-                    "type": "integer"
+                "persona": {
+                    "description": "how the chat addresses the user",
+                    "type": "string",
+                    "enum": [
+                        "friend",
+                        "grandchild",
+                        "counselor"
+                    ]
                 },
-                "user_answer": {
-                    "type": "string"
+                "quiz_difficulty_floor": {
+                    "description": "questions are never easier than this",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
                 },
-                "user_id": {
-                    "type": "string"
+                "voice_speed": {
+                    "description": "speech rate for the voice frontend, 1 is normal",
+                    "type": "number",
+                    "maximum": 2,
+                    "minimum": 0.5,
+                    "example": 0.8
                 }
             }
         },
-        "models.Metadata": {
+        "models.WebhookDeleteResponse": {
             "type": "object",
             "properties": {
-                "request_id": {
-                    "type": "string"
+                "deleted": {
+                    "type": "boolean"
                 },
-                "timestamp": {
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "models.ReportGenerationRequest": {
+        "models.WebhookListResponse": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookSubscriptionResponse"
+                    }
+                }
+            }
+        },
+        "models.WebhookRegisterRequest": {
             "type": "object",
             "required": [
-                "domains"
+                "secret",
+                "url"
             ],
             "properties": {
-                "domains": {
-                    "description": "4개 도메인 필수",
+                "events": {
+                    "description": "omit to receive every event",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.DomainScore"
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "HMAC-SHA256 signing key",
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "config, api",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "AdminAPIKey": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "TenantAPIKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        }
     }
 }`
 
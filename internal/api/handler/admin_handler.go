@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// AdminHandler handles operator API requests
+type AdminHandler struct {
+	adminService       *service.AdminService
+	calibrationService *service.CalibrationService
+	backfillService    *service.BackfillService
+	maintenanceService *service.MaintenanceService
+	replayService      *service.ReplayService
+	cacheService       *service.CacheService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService *service.AdminService, calibrationService *service.CalibrationService, backfillService *service.BackfillService, maintenanceService *service.MaintenanceService, replayService *service.ReplayService, cacheService *service.CacheService) *AdminHandler {
+	return &AdminHandler{
+		adminService:       adminService,
+		calibrationService: calibrationService,
+		backfillService:    backfillService,
+		maintenanceService: maintenanceService,
+		replayService:      replayService,
+		cacheService:       cacheService,
+	}
+}
+
+// GetConfig handles runtime config requests
+// @Summary Get runtime config
+// @Description Runtime-tunable settings currently in effect
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.RuntimeConfigResponse}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/config [get]
+func (h *AdminHandler) GetConfig(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.adminService.GetRuntimeConfig())
+}
+
+// UpdateConfig handles runtime config updates
+// @Summary Update runtime config
+// @Description Partially update runtime-tunable settings without restarting. Omitted fields keep their current values.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.RuntimeConfigPatchRequest true "Settings to change"
+// @Success 200 {object} models.APIResponse{data=models.RuntimeConfigResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/config [patch]
+func (h *AdminHandler) UpdateConfig(c *gin.Context) {
+	var req models.RuntimeConfigPatchRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_CONFIG", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.adminService.UpdateRuntimeConfig(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_CONFIG", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// ReloadConfig handles config reload requests
+// @Summary Reload config
+// @Description Re-read the environment and CONFIG_FILE, as on SIGHUP, and apply their runtime-tunable settings. Admin overrides are replaced; safe-mode switches are kept. On a validation error the current settings stay in effect.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.RuntimeConfigResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/config/reload [post]
+func (h *AdminHandler) ReloadConfig(c *gin.Context) {
+	resp, err := h.adminService.ReloadConfig(c.Request.Context())
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_CONFIG", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetSafeMode handles safe-mode kill switch requests
+// @Summary Get safe-mode kill switches
+// @Description Whether each subsystem with a kill switch (analysis, evaluation, suggestions) is currently enabled
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.SafeModeResponse}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/safe-mode [get]
+func (h *AdminHandler) GetSafeMode(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.adminService.GetSafeMode())
+}
+
+// UpdateSafeMode handles safe-mode kill switch updates
+// @Summary Update safe-mode kill switches
+// @Description Switch subsystems off during an incident, or back on, without redeploying. Requests to a disabled subsystem fail with 503 FEATURE_DISABLED; background evaluation of chat turns and chat follow-up suggestions are skipped.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.SafeModePatchRequest true "Features to switch on (true) or off (false)"
+// @Success 200 {object} models.APIResponse{data=models.SafeModeResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/safe-mode [patch]
+func (h *AdminHandler) UpdateSafeMode(c *gin.Context) {
+	var req models.SafeModePatchRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_SAFE_MODE", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.adminService.UpdateSafeMode(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_FEATURE", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetCalibration handles confidence calibration report requests
+// @Summary Get confidence calibration report
+// @Description How well high/medium/low confidence labels predicted later recall of the same fact, with suggested thresholds
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.ConfidenceCalibrationReport}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/calibration [get]
+func (h *AdminHandler) GetCalibration(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.calibrationService.Report())
+}
+
+// RunCalibration handles on-demand confidence calibration
+// @Summary Run confidence calibration
+// @Description Evaluate calibration now. Thresholds are adjusted only when CONFIDENCE_CALIBRATION_ENABLED is set and enough outcomes exist.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.ConfidenceCalibrationReport}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/calibration/run [post]
+func (h *AdminHandler) RunCalibration(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.calibrationService.Run(c.Request.Context()))
+}
+
+// StartScoreBackfill handles score backfill requests
+// @Summary Start a score backfill
+// @Description Re-run the response quality evaluation with the current prompt version over the users' stored chat turns and write the updated scores back to RAG. Turns already scored with the current version are skipped unless force is set. Runs in the background; poll GET /api/admin/backfill/scores.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.ScoreBackfillRequest true "Users to backfill"
+// @Success 202 {object} models.APIResponse{data=models.ScoreBackfillJob}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Router /api/admin/backfill/scores [post]
+func (h *AdminHandler) StartScoreBackfill(c *gin.Context) {
+	var req models.ScoreBackfillRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_BACKFILL", "Invalid request format", err.Error())
+		return
+	}
+
+	job, err := h.backfillService.StartScoreBackfill(c.Request.Context(), &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "backfill_running:") {
+			h.respondError(c, http.StatusConflict, "BACKFILL_RUNNING", err.Error(), "")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "feature_disabled:") {
+			h.respondError(c, http.StatusServiceUnavailable, "FEATURE_DISABLED", err.Error(), "")
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusAccepted, job)
+}
+
+// Replay handles conversation replay requests
+// @Summary Replay stored conversations
+// @Description Re-run a user's stored chat turns, newest first, through the current prompt and model configuration (or the given generation overrides) and report how each reply and its evaluation score changed. Nothing is saved.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.ReplayRequest true "User and turns to replay"
+// @Success 200 {object} models.APIResponse{data=models.ReplayResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/admin/replay [post]
+func (h *AdminHandler) Replay(c *gin.Context) {
+	var req models.ReplayRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REPLAY", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.replayService.Replay(c.Request.Context(), &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid_override:") {
+			h.respondError(c, http.StatusBadRequest, "INVALID_OVERRIDE", err.Error(), "")
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetCacheStats handles cache inspection requests
+// @Summary Get cache stats
+// @Description Size and TTL of each in-memory cache: generated questions, RAG personal info, RAG incorrect quiz attempts and semantic chat replies
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.CacheStatsResponse}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/cache/stats [get]
+func (h *AdminHandler) GetCacheStats(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.cacheService.Stats())
+}
+
+// EvictCache handles cache eviction requests
+// @Summary Evict cache entries
+// @Description Remove the entries under a key from a cache so the next request reads fresh data. Keys are user IDs; the questions cache also takes a question ID.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Param name path string true "Cache name" Enums(questions, profiles, incorrect_attempts, semantic)
+// @Param key path string true "User ID, or question ID for the questions cache"
+// @Success 200 {object} models.APIResponse{data=models.CacheEvictResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/admin/cache/{name}/{key} [delete]
+func (h *AdminHandler) EvictCache(c *gin.Context) {
+	resp, err := h.cacheService.Evict(c.Param("name"), c.Param("key"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown_cache:") {
+			h.respondError(c, http.StatusNotFound, "UNKNOWN_CACHE", err.Error(), "")
+			return
+		}
+		h.respondError(c, http.StatusNotFound, "CACHE_ENTRY_NOT_FOUND", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetScoreBackfill handles score backfill status requests
+// @Summary Get score backfill status
+// @Description Progress of the running or most recent score backfill
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.ScoreBackfillJob}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/admin/backfill/scores [get]
+func (h *AdminHandler) GetScoreBackfill(c *gin.Context) {
+	job := h.backfillService.ScoreBackfillJob()
+	if job == nil {
+		h.respondError(c, http.StatusNotFound, "BACKFILL_NOT_FOUND", "No score backfill has run", "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, job)
+}
+
+// GetMaintenance handles maintenance report requests
+// @Summary Get maintenance report
+// @Description What the last compaction of local stores (expired questions, idle experiment usage, old quiz history, old mood entries) removed, and when the nightly run is next due
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.MaintenanceReport}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/admin/maintenance [get]
+func (h *AdminHandler) GetMaintenance(c *gin.Context) {
+	report := h.maintenanceService.Report()
+	if report == nil {
+		h.respondError(c, http.StatusNotFound, "MAINTENANCE_NOT_FOUND", "No maintenance has run", "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, report)
+}
+
+// RunMaintenance handles on-demand maintenance
+// @Summary Run maintenance
+// @Description Compact local stores now instead of waiting for the nightly run
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.MaintenanceReport}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/maintenance/run [post]
+func (h *AdminHandler) RunMaintenance(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.maintenanceService.Run(c.Request.Context()))
+}
+
+// Helper methods
+
+func (h *AdminHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *AdminHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
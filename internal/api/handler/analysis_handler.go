@@ -2,10 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"llm/internal/api/middleware"
 	"llm/internal/models"
 	"llm/internal/service"
 )
@@ -28,10 +30,15 @@ func NewAnalysisHandler(analysisService *service.AnalysisService) *AnalysisHandl
 // @Tags Analysis
 // @Accept json
 // @Produce json
+// @Security TenantAPIKey
 // @Param request body models.AnalysisRequest true "Analysis request"
-// @Success 200 {object} models.APIResponse
+// @Success 200 {object} models.APIResponse{data=models.AnalysisResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
 // @Router /api/analysis [post]
 func (h *AnalysisHandler) ProcessAnalysis(c *gin.Context) {
 	var req models.AnalysisRequest
@@ -42,12 +49,16 @@ func (h *AnalysisHandler) ProcessAnalysis(c *gin.Context) {
 	}
 
 	if req.UserID == "" {
-		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", nil)
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", "")
 		return
 	}
 
 	resp, err := h.analysisService.ProcessAnalysisRequest(c.Request.Context(), &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "feature_disabled:") {
+			h.respondError(c, http.StatusServiceUnavailable, "FEATURE_DISABLED", err.Error(), "")
+			return
+		}
 		h.respondError(c, http.StatusInternalServerError, "ANALYSIS_FAILED", "Failed to process analysis", err.Error())
 		return
 	}
@@ -55,16 +66,52 @@ func (h *AnalysisHandler) ProcessAnalysis(c *gin.Context) {
 	h.respondSuccess(c, http.StatusOK, resp)
 }
 
+// Timeline handles cognitive timeline requests
+// @Summary Get a cognitive score timeline
+// @Description Daily quiz retention and chat quality averages, completed assessment scores and analysis domain scores over the last days (default 90), oldest first, for the caregiver dashboard's trend charts. If chat history cannot be read, the timeline is returned without chat quality scores and with a warning.
+// @Tags Analysis
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id query string true "User ID"
+// @Param days query int false "Days to cover (1-365, default 90)"
+// @Param If-None-Match header string false "ETag of a previous response; 304 Not Modified if unchanged"
+// @Success 200 {object} models.APIResponse{data=models.CognitiveTimelineResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/analysis/timeline [get]
+func (h *AnalysisHandler) Timeline(c *gin.Context) {
+	var req models.CognitiveTimelineRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp := h.analysisService.Timeline(c.Request.Context(), &req)
+	if notModified(c, resp) {
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
 // ProcessDomainAnalysisOnly handles domain analysis only requests (without report)
 // @Summary Process domain analysis only
 // @Description Analyze user's conversation history and incorrect quizzes across 4 domains without generating a report
 // @Tags Analysis
 // @Accept json
 // @Produce json
+// @Security TenantAPIKey
 // @Param request body models.AnalysisRequest true "Analysis request (user_id required)"
-// @Success 200 {object} models.APIResponse
+// @Success 200 {object} models.APIResponse{data=models.DomainAnalysisOnlyResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
 // @Router /api/analysis/domains [post]
 func (h *AnalysisHandler) ProcessDomainAnalysisOnly(c *gin.Context) {
 	var req models.AnalysisRequest
@@ -75,12 +122,16 @@ func (h *AnalysisHandler) ProcessDomainAnalysisOnly(c *gin.Context) {
 	}
 
 	if req.UserID == "" {
-		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", nil)
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", "")
 		return
 	}
 
 	resp, err := h.analysisService.ProcessDomainAnalysisOnly(c.Request.Context(), &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "feature_disabled:") {
+			h.respondError(c, http.StatusServiceUnavailable, "FEATURE_DISABLED", err.Error(), "")
+			return
+		}
 		h.respondError(c, http.StatusInternalServerError, "DOMAIN_ANALYSIS_FAILED", "Failed to process domain analysis", err.Error())
 		return
 	}
@@ -94,10 +145,15 @@ func (h *AnalysisHandler) ProcessDomainAnalysisOnly(c *gin.Context) {
 // @Tags Analysis
 // @Accept json
 // @Produce json
+// @Security TenantAPIKey
 // @Param request body models.ReportGenerationRequest true "Report generation request (4 domains required)"
-// @Success 200 {object} models.APIResponse
+// @Success 200 {object} models.APIResponse{data=models.ReportGenerationResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
 // @Router /api/analysis/report [post]
 func (h *AnalysisHandler) ProcessReportGeneration(c *gin.Context) {
 	var req models.ReportGenerationRequest
@@ -108,12 +164,16 @@ func (h *AnalysisHandler) ProcessReportGeneration(c *gin.Context) {
 	}
 
 	if len(req.Domains) != 4 {
-		h.respondError(c, http.StatusBadRequest, "INVALID_DOMAINS", "Exactly 4 domains are required", nil)
+		h.respondError(c, http.StatusBadRequest, "INVALID_DOMAINS", "Exactly 4 domains are required", "")
 		return
 	}
 
 	report, err := h.analysisService.ProcessReportGenerationOnly(c.Request.Context(), &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "feature_disabled:") {
+			h.respondError(c, http.StatusServiceUnavailable, "FEATURE_DISABLED", err.Error(), "")
+			return
+		}
 		h.respondError(c, http.StatusInternalServerError, "REPORT_GENERATION_FAILED", "Failed to generate report", err.Error())
 		return
 	}
@@ -129,6 +189,10 @@ func (h *AnalysisHandler) ProcessReportGeneration(c *gin.Context) {
 // Helper methods
 
 func (h *AnalysisHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
 	c.JSON(statusCode, models.APIResponse{
 		Success: true,
 		Data:    data,
@@ -139,7 +203,7 @@ func (h *AnalysisHandler) respondSuccess(c *gin.Context, statusCode int, data in
 	})
 }
 
-func (h *AnalysisHandler) respondError(c *gin.Context, statusCode int, code string, message string, details interface{}) {
+func (h *AnalysisHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
 	c.JSON(statusCode, models.APIResponse{
 		Success: false,
 		Error: &models.ErrorInfo{
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// AssessmentHandler handles cognitive assessment requests
+type AssessmentHandler struct {
+	assessmentService *service.AssessmentService
+}
+
+// NewAssessmentHandler creates a new assessment handler
+func NewAssessmentHandler(assessmentService *service.AssessmentService) *AssessmentHandler {
+	return &AssessmentHandler{
+		assessmentService: assessmentService,
+	}
+}
+
+// Start handles starting an assessment
+// @Summary Start a cognitive assessment
+// @Description Start a short K-MMSE style screen (orientation to time, registration, attention, recall) for the user. The returned prompt is the first question; the user's following chat messages answer one item each, and the chat replies with the next question until the assessment is completed. An assessment with no answer for 30 minutes is abandoned.
+// @Tags Analysis
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param request body models.AssessmentStartRequest true "Assessment start request"
+// @Success 201 {object} models.APIResponse{data=models.AssessmentProgress}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/assessment [post]
+func (h *AssessmentHandler) Start(c *gin.Context) {
+	var req models.AssessmentStartRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_ASSESSMENT_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.assessmentService.Start(c.Request.Context(), req.UserID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "assessment_in_progress:") {
+			h.respondError(c, http.StatusConflict, "ASSESSMENT_IN_PROGRESS", "The user already has an assessment in progress", err.Error())
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to start assessment", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusCreated, resp)
+}
+
+// Get handles assessment result lookups
+// @Summary Get an assessment result
+// @Description The assessment's status, total and per-domain scores, and each answered item with its expected answer. Scores are raw K-MMSE item points for the administered items, not a full 30-point K-MMSE score.
+// @Tags Analysis
+// @Produce json
+// @Security TenantAPIKey
+// @Param id path string true "Assessment ID"
+// @Param If-None-Match header string false "ETag of a previous response; 304 Not Modified if unchanged"
+// @Success 200 {object} models.APIResponse{data=models.AssessmentResult}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/assessment/{id} [get]
+func (h *AssessmentHandler) Get(c *gin.Context) {
+	assessmentID := c.Param("id")
+
+	resp, err := h.assessmentService.Result(assessmentID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "assessment_not_found:") {
+			h.respondError(c, http.StatusNotFound, "ASSESSMENT_NOT_FOUND", "Assessment not found", assessmentID)
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get assessment", err.Error())
+		return
+	}
+	// Another facility's assessment is reported as missing rather than forbidden
+	if !middleware.UserInTenant(c, resp.UserID) {
+		h.respondError(c, http.StatusNotFound, "ASSESSMENT_NOT_FOUND", "Assessment not found", assessmentID)
+		return
+	}
+	if notModified(c, resp) {
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Helper methods
+
+func (h *AssessmentHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *AssessmentHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"llm/internal/api/middleware"
 	"llm/internal/models"
 	"llm/internal/service"
 )
@@ -24,14 +26,20 @@ func NewChatHandler(chatService *service.ChatService) *ChatHandler {
 
 // Handle handles chat requests
 // @Summary Process chat message
-// @Description Send a message and get a response based on conversation history
+// @Description Send a message and get a response based on conversation history. With include_evaluation, the message is evaluated before replying and its quality (speech coherence) score is returned. With include_suggestions, 2-3 follow-up lines the voice frontend can say if the user goes silent are returned. Photos in image_urls (http(s) URLs or data:image/ URIs, up to 4) are discussed with the vision model and described in the saved conversation.
 // @Tags Chat
 // @Accept json
 // @Produce json
+// @Security TenantAPIKey
 // @Param request body models.ChatRequest true "Chat request"
-// @Success 200 {object} models.APIResponse
+// @Success 200 {object} models.APIResponse{data=models.ChatResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 413 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
 // @Router /api/chat [post]
 func (h *ChatHandler) Handle(c *gin.Context) {
 	var req models.ChatRequest
@@ -42,12 +50,28 @@ func (h *ChatHandler) Handle(c *gin.Context) {
 	}
 
 	if req.Message == "" {
-		h.respondError(c, http.StatusBadRequest, "INVALID_MESSAGE", "Message cannot be empty", nil)
+		h.respondError(c, http.StatusBadRequest, "INVALID_MESSAGE", "Message cannot be empty", "")
 		return
 	}
 
 	resp, err := h.chatService.ProcessChat(c.Request.Context(), &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid_override:") {
+			h.respondError(c, http.StatusBadRequest, "INVALID_OVERRIDE", err.Error(), "")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid_image:") {
+			h.respondError(c, http.StatusBadRequest, "INVALID_IMAGE", err.Error(), "")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "context_too_large:") {
+			h.respondError(c, http.StatusRequestEntityTooLarge, "CONTEXT_TOO_LARGE", "Conversation context is too large for the model", err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "chat_in_progress:") {
+			h.respondError(c, http.StatusConflict, "CHAT_IN_PROGRESS", "Another chat request for this user is still being processed", err.Error())
+			return
+		}
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to process chat", err.Error())
 		return
 	}
@@ -55,9 +79,248 @@ func (h *ChatHandler) Handle(c *gin.Context) {
 	h.respondSuccess(c, http.StatusOK, resp)
 }
 
+// DryRun handles chat prompt dry runs
+// @Summary Dry-run a chat message
+// @Description Assemble the exact prompt and retrieved context a chat message would be answered with, without calling the LLM. Guardrails and emergency alerts are skipped.
+// @Tags Debug
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.ChatRequest true "Chat request"
+// @Success 200 {object} models.APIResponse{data=models.ChatPromptPreviewResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/debug/prompt/chat [post]
+func (h *ChatHandler) DryRun(c *gin.Context) {
+	var req models.ChatRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_MESSAGE", "Invalid request format", err.Error())
+		return
+	}
+
+	if req.Message == "" {
+		h.respondError(c, http.StatusBadRequest, "INVALID_MESSAGE", "Message cannot be empty", "")
+		return
+	}
+
+	resp, err := h.chatService.DryRunChat(c.Request.Context(), &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid_override:") {
+			h.respondError(c, http.StatusBadRequest, "INVALID_OVERRIDE", err.Error(), "")
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid_image:") {
+			h.respondError(c, http.StatusBadRequest, "INVALID_IMAGE", err.Error(), "")
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to assemble chat prompt", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Correct handles caregiver corrections of assistant statements
+// @Summary Correct an assistant statement
+// @Description Mark an assistant statement in a conversation as factually wrong. The correction is stored with the user's profile and used as a guardrail in future chats.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param id path string true "Conversation ID"
+// @Param request body models.ConversationCorrectionRequest true "Correction request"
+// @Success 200 {object} models.APIResponse{data=models.ConversationCorrectionResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/conversations/{id}/correct [post]
+func (h *ChatHandler) Correct(c *gin.Context) {
+	var req models.ConversationCorrectionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_CORRECTION", "Invalid request format", err.Error())
+		return
+	}
+
+	conversationID := c.Param("id")
+	if conversationID == "" {
+		h.respondError(c, http.StatusBadRequest, "INVALID_CONVERSATION_ID", "Conversation ID cannot be empty", "")
+		return
+	}
+
+	resp, err := h.chatService.CorrectConversation(c.Request.Context(), conversationID, &req)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to store correction", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// AddAvoidTopic handles registering a topic the assistant must avoid
+// @Summary Register a blacklisted topic
+// @Description Register a topic (e.g. deceased relatives, traumatic events) that the assistant must avoid for a user
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param request body models.AvoidTopicRequest true "Avoid topic request"
+// @Success 200 {object} models.APIResponse{data=models.AvoidTopicResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/users/{user_id}/avoid-topics [post]
+func (h *ChatHandler) AddAvoidTopic(c *gin.Context) {
+	var req models.AvoidTopicRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_TOPIC", "Invalid request format", err.Error())
+		return
+	}
+
+	userID := c.Param("user_id")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", "")
+		return
+	}
+
+	resp, err := h.chatService.AddAvoidTopic(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to store avoid topic", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// AddPin handles pinning a fact to a user's chat context
+// @Summary Pin a fact to chat context
+// @Description Pin a fact or conversation excerpt (e.g. "uses a walker", "sister visits on Sundays") that is included in every chat prompt for the user. Up to 10 pins per user.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param request body models.PinRequest true "Pin request"
+// @Success 200 {object} models.APIResponse{data=models.PinResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/users/{user_id}/pins [post]
+func (h *ChatHandler) AddPin(c *gin.Context) {
+	var req models.PinRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_PIN", "Invalid request format", err.Error())
+		return
+	}
+
+	userID := c.Param("user_id")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", "")
+		return
+	}
+
+	resp, err := h.chatService.AddPin(c.Request.Context(), userID, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "pin_limit:") {
+			h.respondError(c, http.StatusConflict, "PIN_LIMIT_REACHED", err.Error(), "")
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to store pin", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// SetCareMode handles flagging a user for a special prompt mode
+// @Summary Set a user's care mode
+// @Description Flag a user for a specialized prompt mode (e.g. grief_sensitive) used when discussing loss and sensitive memories
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param request body models.CareModeRequest true "Care mode request"
+// @Success 200 {object} models.APIResponse{data=models.CareModeResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/users/{user_id}/care-mode [post]
+func (h *ChatHandler) SetCareMode(c *gin.Context) {
+	var req models.CareModeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_CARE_MODE", "Invalid request format", err.Error())
+		return
+	}
+
+	userID := c.Param("user_id")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", "")
+		return
+	}
+
+	resp, err := h.chatService.SetCareMode(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to store care mode", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Mood handles mood timeline requests
+// @Summary Get mood timeline
+// @Description Emotional state of the user's recent chat turns with daily averages and dominant emotions, for the caregiver dashboard
+// @Tags Chat
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id query string true "User ID"
+// @Param days query int false "Days to include (1-90, default 14)"
+// @Param If-None-Match header string false "ETag of a previous response; 304 Not Modified if unchanged"
+// @Success 200 {object} models.APIResponse{data=models.MoodTimelineResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/chat/mood [get]
+func (h *ChatHandler) Mood(c *gin.Context) {
+	var req models.MoodTimelineRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_MOOD_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp := h.chatService.MoodTimeline(c.Request.Context(), &req)
+	if notModified(c, resp) {
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
 // Helper methods
 
 func (h *ChatHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
 	c.JSON(statusCode, models.APIResponse{
 		Success: true,
 		Data:    data,
@@ -68,7 +331,7 @@ func (h *ChatHandler) respondSuccess(c *gin.Context, statusCode int, data interf
 	})
 }
 
-func (h *ChatHandler) respondError(c *gin.Context, statusCode int, code string, message string, details interface{}) {
+func (h *ChatHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
 	c.JSON(statusCode, models.APIResponse{
 		Success: false,
 		Error: &models.ErrorInfo{
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+)
+
+// notModified sets a weak ETag derived from the response data and, when the
+// request's If-None-Match already names it, answers 304 Not Modified and returns
+// true. The envelope's timestamp and request ID change on every response, so
+// only the data is hashed. Clients polling GET endpoints then skip unchanged bodies.
+func notModified(c *gin.Context, data interface{}) bool {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16])
+	if c.GetBool(middleware.RawResponseKey) {
+		etag += "-raw"
+	}
+	etag += `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
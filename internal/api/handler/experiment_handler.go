@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// ExperimentHandler handles experiment results API requests
+type ExperimentHandler struct {
+	experimentService *service.ExperimentService
+}
+
+// NewExperimentHandler creates a new experiment handler
+func NewExperimentHandler(experimentService *service.ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{
+		experimentService: experimentService,
+	}
+}
+
+// Results handles experiment results requests
+// @Summary Get experiment results
+// @Description Configured prompt variants with usage, conversation quality scores, and quiz accuracy joined per prompt/model cohort
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.ExperimentResultsResponse}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/experiments/results [get]
+func (h *ExperimentHandler) Results(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.experimentService.Results())
+}
+
+// Helper methods
+
+func (h *ExperimentHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
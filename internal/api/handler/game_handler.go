@@ -2,10 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"llm/internal/api/middleware"
 	"llm/internal/models"
 	"llm/internal/service"
 )
@@ -24,15 +26,21 @@ func NewGameHandler(gameService *service.GameService) *GameHandler {
 
 // GenerateQuestion handles game question generation
 // @Summary Generate a game question
-// @Description Generate an OX or multiple choice question based on user's conversation history
+// @Description Generate an OX or multiple choice question based on user's conversation history. The integrative type combines 2-3 related conversations into one harder question. The photo type asks about the people and places in image_url, using image_description or a vision-model caption. Set from/to to base the question on conversations from a period, e.g. last week. With allow_profile_fallback, users with too few conversations get a question about their personal info (family names, hometown, former job) instead of a 422.
 // @Tags Game
 // @Accept json
 // @Produce json
+// @Security TenantAPIKey
 // @Param request body models.GameQuestionRequest true "Question generation request"
-// @Success 200 {object} models.APIResponse
+// @Success 200 {object} models.APIResponse{data=models.GameQuestionResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 413 {object} models.APIResponse
 // @Failure 422 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
 // @Router /api/game/question [post]
 func (h *GameHandler) GenerateQuestion(c *gin.Context) {
 	var req models.GameQuestionRequest
@@ -52,12 +60,177 @@ func (h *GameHandler) GenerateQuestion(c *gin.Context) {
 		if errMsg == "invalid_question_type" {
 			statusCode = http.StatusBadRequest
 			errCode = "INVALID_QUESTION_TYPE"
-		} else if len(errMsg) > 18 && errMsg[:18] == "insufficient_data:" {
+		} else if strings.HasPrefix(errMsg, "insufficient_data:") {
 			statusCode = http.StatusUnprocessableEntity
 			errCode = "INSUFFICIENT_DATA"
+		} else if strings.HasPrefix(errMsg, "invalid_override:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_OVERRIDE"
+		} else if strings.HasPrefix(errMsg, "invalid_window:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_WINDOW"
+		} else if strings.HasPrefix(errMsg, "context_too_large:") {
+			statusCode = http.StatusRequestEntityTooLarge
+			errCode = "CONTEXT_TOO_LARGE"
+		} else if strings.HasPrefix(errMsg, "question_quality:") {
+			statusCode = http.StatusBadGateway
+			errCode = "QUESTION_QUALITY_FAILED"
+		} else if strings.HasPrefix(errMsg, "invalid_photo:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_PHOTO"
+		}
+
+		h.respondError(c, statusCode, errCode, errMsg, "")
+		return
+	}
+
+	if middleware.APIVersion(c) != middleware.APIVersion1 {
+		question := *resp
+		question.CorrectAnswer = ""
+		resp = &question
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GenerateQuestionSet handles themed question set generation
+// @Summary Generate a themed question set
+// @Description Generate a cohesive set of multiple choice questions with an intro line for a theme (chuseok, birthday, family_visit), based on conversations matching the theme's keywords and date window (chuseok matches on keywords across all dates)
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param request body models.QuestionSetRequest true "Question set request"
+// @Success 200 {object} models.APIResponse{data=models.QuestionSetResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 413 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/game/question-set [post]
+func (h *GameHandler) GenerateQuestionSet(c *gin.Context) {
+	var req models.QuestionSetRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_GAME_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.gameService.GenerateQuestionSet(c.Request.Context(), &req)
+	if err != nil {
+		errMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		errCode := "INTERNAL_ERROR"
+
+		if strings.HasPrefix(errMsg, "invalid_theme:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_THEME"
+		} else if strings.HasPrefix(errMsg, "insufficient_data:") {
+			statusCode = http.StatusUnprocessableEntity
+			errCode = "INSUFFICIENT_DATA"
+		} else if strings.HasPrefix(errMsg, "invalid_override:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_OVERRIDE"
+		} else if strings.HasPrefix(errMsg, "context_too_large:") {
+			statusCode = http.StatusRequestEntityTooLarge
+			errCode = "CONTEXT_TOO_LARGE"
+		} else if strings.HasPrefix(errMsg, "question_quality:") {
+			statusCode = http.StatusBadGateway
+			errCode = "QUESTION_QUALITY_FAILED"
+		}
+
+		h.respondError(c, statusCode, errCode, errMsg, "")
+		return
+	}
+
+	if middleware.APIVersion(c) != middleware.APIVersion1 {
+		set := *resp
+		set.Questions = withoutAnswers(resp.Questions)
+		resp = &set
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GenerateOrientationQuestions handles reality-orientation question generation
+// @Summary Generate orientation questions
+// @Description Generate multiple choice questions about today's date, weekday and season, plus a holiday within two weeks ahead or a few days behind. They are built from the calendar and need no conversation history, so they suit users with too few conversations for other questions. Answers are submitted like any other question.
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param request body models.OrientationQuestionRequest true "Orientation question request"
+// @Success 200 {object} models.APIResponse{data=models.OrientationQuestionResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/game/orientation [post]
+func (h *GameHandler) GenerateOrientationQuestions(c *gin.Context) {
+	var req models.OrientationQuestionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_GAME_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp := h.gameService.GenerateOrientationQuestions(c.Request.Context(), &req)
+	if middleware.APIVersion(c) != middleware.APIVersion1 {
+		resp.Questions = withoutAnswers(resp.Questions)
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GenerateRecap handles recap card generation
+// @Summary Generate recap cards
+// @Description Turn the user's recent conversations and missed quiz topics into short recap cards (title, 1-2 sentences, source conversation) for "오늘의 회상". Set from/to to recap a specific period, e.g. yesterday.
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param request body models.RecapRequest true "Recap request"
+// @Success 200 {object} models.APIResponse{data=models.RecapResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 413 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/game/recap [post]
+func (h *GameHandler) GenerateRecap(c *gin.Context) {
+	var req models.RecapRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_GAME_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.gameService.GenerateRecap(c.Request.Context(), &req)
+	if err != nil {
+		errMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		errCode := "INTERNAL_ERROR"
+
+		if strings.HasPrefix(errMsg, "insufficient_data:") {
+			statusCode = http.StatusUnprocessableEntity
+			errCode = "INSUFFICIENT_DATA"
+		} else if strings.HasPrefix(errMsg, "invalid_override:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_OVERRIDE"
+		} else if strings.HasPrefix(errMsg, "invalid_window:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_WINDOW"
+		} else if strings.HasPrefix(errMsg, "context_too_large:") {
+			statusCode = http.StatusRequestEntityTooLarge
+			errCode = "CONTEXT_TOO_LARGE"
 		}
 
-		h.respondError(c, statusCode, errCode, errMsg, nil)
+		h.respondError(c, statusCode, errCode, errMsg, "")
 		return
 	}
 
@@ -70,10 +243,16 @@ func (h *GameHandler) GenerateQuestion(c *gin.Context) {
 // @Tags Game
 // @Accept json
 // @Produce json
+// @Security TenantAPIKey
 // @Param request body models.GameResultRequest true "Game result"
-// @Success 200 {object} models.APIResponse
+// @Success 200 {object} models.APIResponse{data=models.GameResultResponse}
 // @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
 // @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
 // @Router /api/game/result [post]
 func (h *GameHandler) EvaluateResult(c *gin.Context) {
 	var req models.GameResultRequest
@@ -85,6 +264,18 @@ func (h *GameHandler) EvaluateResult(c *gin.Context) {
 
 	resp, err := h.gameService.EvaluateGameResult(c.Request.Context(), &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "question_pending_review:") {
+			h.respondError(c, http.StatusConflict, "QUESTION_PENDING_REVIEW", "Question has not been approved yet", err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "question_rejected:") {
+			h.respondError(c, http.StatusConflict, "QUESTION_REJECTED", "Question was rejected in review", err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "feature_disabled:") {
+			h.respondError(c, http.StatusServiceUnavailable, "FEATURE_DISABLED", err.Error(), "")
+			return
+		}
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to evaluate result", err.Error())
 		return
 	}
@@ -92,9 +283,257 @@ func (h *GameHandler) EvaluateResult(c *gin.Context) {
 	h.respondSuccess(c, http.StatusOK, resp)
 }
 
+// AnswerQuestion handles answers to generated questions
+// @Summary Answer a game question
+// @Description Grade the user's answer (option ID or option text), record the result, and return a gentle explanation of the correct answer that refers back to the original conversation
+// @Tags Game
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param id path string true "Question ID"
+// @Param request body models.QuestionAnswerRequest true "Answer"
+// @Success 200 {object} models.APIResponse{data=models.QuestionAnswerResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 503 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/game/question/{id}/answer [post]
+func (h *GameHandler) AnswerQuestion(c *gin.Context) {
+	var req models.QuestionAnswerRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_ANSWER", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.gameService.AnswerQuestion(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		errMsg := err.Error()
+		if strings.HasPrefix(errMsg, "question_not_found:") {
+			h.respondError(c, http.StatusNotFound, "QUESTION_NOT_FOUND", "Question not found or expired", errMsg)
+			return
+		}
+		if strings.HasPrefix(errMsg, "question_pending_review:") {
+			h.respondError(c, http.StatusConflict, "QUESTION_PENDING_REVIEW", "Question has not been approved yet", errMsg)
+			return
+		}
+		if strings.HasPrefix(errMsg, "question_rejected:") {
+			h.respondError(c, http.StatusConflict, "QUESTION_REJECTED", "Question was rejected in review", errMsg)
+			return
+		}
+		if strings.HasPrefix(errMsg, "feature_disabled:") {
+			h.respondError(c, http.StatusServiceUnavailable, "FEATURE_DISABLED", errMsg, "")
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to grade answer", errMsg)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// PreviewQuestion handles supervised question generation
+// @Summary Preview a game question
+// @Description Generate a question for a supervised session. It stays pending, and results for it are refused, until approved through the review endpoint.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.GameQuestionRequest true "Question generation request"
+// @Success 200 {object} models.APIResponse{data=models.SupervisedQuestionResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 413 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Router /api/admin/questions/preview [post]
+func (h *GameHandler) PreviewQuestion(c *gin.Context) {
+	var req models.GameQuestionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_GAME_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.gameService.PreviewQuestion(c.Request.Context(), &req)
+	if err != nil {
+		errMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		errCode := "INTERNAL_ERROR"
+
+		if strings.HasPrefix(errMsg, "invalid_question_type:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_QUESTION_TYPE"
+		} else if strings.HasPrefix(errMsg, "insufficient_data:") {
+			statusCode = http.StatusUnprocessableEntity
+			errCode = "INSUFFICIENT_DATA"
+		} else if strings.HasPrefix(errMsg, "invalid_override:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_OVERRIDE"
+		} else if strings.HasPrefix(errMsg, "invalid_window:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_WINDOW"
+		} else if strings.HasPrefix(errMsg, "context_too_large:") {
+			statusCode = http.StatusRequestEntityTooLarge
+			errCode = "CONTEXT_TOO_LARGE"
+		} else if strings.HasPrefix(errMsg, "question_quality:") {
+			statusCode = http.StatusBadGateway
+			errCode = "QUESTION_QUALITY_FAILED"
+		} else if strings.HasPrefix(errMsg, "invalid_photo:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_PHOTO"
+		}
+
+		h.respondError(c, statusCode, errCode, errMsg, "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// DryRunQuestion handles question prompt dry runs
+// @Summary Dry-run a game question
+// @Description Assemble the exact prompt a question would be generated with, from the same conversation selection, without calling the LLM. Photo questions require image_description.
+// @Tags Debug
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.GameQuestionRequest true "Question generation request"
+// @Success 200 {object} models.APIResponse{data=models.QuestionPromptPreviewResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 422 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /api/debug/prompt/question [post]
+func (h *GameHandler) DryRunQuestion(c *gin.Context) {
+	var req models.GameQuestionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_GAME_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.gameService.DryRunQuestion(c.Request.Context(), &req)
+	if err != nil {
+		errMsg := err.Error()
+		statusCode := http.StatusInternalServerError
+		errCode := "INTERNAL_ERROR"
+
+		if strings.HasPrefix(errMsg, "invalid_question_type:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_QUESTION_TYPE"
+		} else if strings.HasPrefix(errMsg, "insufficient_data:") {
+			statusCode = http.StatusUnprocessableEntity
+			errCode = "INSUFFICIENT_DATA"
+		} else if strings.HasPrefix(errMsg, "invalid_override:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_OVERRIDE"
+		} else if strings.HasPrefix(errMsg, "invalid_window:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_WINDOW"
+		} else if strings.HasPrefix(errMsg, "invalid_photo:") {
+			statusCode = http.StatusBadRequest
+			errCode = "INVALID_PHOTO"
+		}
+
+		h.respondError(c, statusCode, errCode, errMsg, "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// ReviewQuestion handles approval or rejection of a previewed question
+// @Summary Review a previewed question
+// @Description Approve a pending question, optionally overriding its difficulty and topic labels, or reject it
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param id path string true "Question ID"
+// @Param request body models.QuestionReviewRequest true "Review decision"
+// @Success 200 {object} models.APIResponse{data=models.SupervisedQuestionResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /api/admin/questions/{id}/review [post]
+func (h *GameHandler) ReviewQuestion(c *gin.Context) {
+	var req models.QuestionReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REVIEW", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.gameService.ReviewQuestion(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		errMsg := err.Error()
+		if strings.HasPrefix(errMsg, "question_not_found:") {
+			h.respondError(c, http.StatusNotFound, "QUESTION_NOT_FOUND", "Question not found or expired", errMsg)
+			return
+		}
+		if strings.HasPrefix(errMsg, "invalid_review:") {
+			h.respondError(c, http.StatusConflict, "INVALID_REVIEW", errMsg, "")
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to review question", errMsg)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetQuestionLineage handles question lineage lookups
+// @Summary Get a question's lineage
+// @Description Return everything a cached question was generated from: the full source conversations (or photo description), the generation prompt, the raw model output, and the validation results. Use it to investigate reports of made-up questions.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Param id path string true "Question ID"
+// @Success 200 {object} models.APIResponse{data=models.QuestionLineageResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/admin/questions/{id}/lineage [get]
+func (h *GameHandler) GetQuestionLineage(c *gin.Context) {
+	resp, err := h.gameService.GetQuestionLineage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		errMsg := err.Error()
+		if strings.HasPrefix(errMsg, "question_not_found:") {
+			h.respondError(c, http.StatusNotFound, "QUESTION_NOT_FOUND", "Question not found or expired", errMsg)
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get question lineage", errMsg)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
 // Helper methods
 
+// withoutAnswers returns copies of the questions with their correct answers
+// removed, for API versions whose clients grade through the answer endpoint.
+// The game service keeps the cached originals for grading.
+func withoutAnswers(questions []models.GameQuestionResponse) []models.GameQuestionResponse {
+	hidden := make([]models.GameQuestionResponse, len(questions))
+	for i, question := range questions {
+		question.CorrectAnswer = ""
+		hidden[i] = question
+	}
+	return hidden
+}
+
 func (h *GameHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
 	c.JSON(statusCode, models.APIResponse{
 		Success: true,
 		Data:    data,
@@ -105,7 +544,7 @@ func (h *GameHandler) respondSuccess(c *gin.Context, statusCode int, data interf
 	})
 }
 
-func (h *GameHandler) respondError(c *gin.Context, statusCode int, code string, message string, details interface{}) {
+func (h *GameHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
 	c.JSON(statusCode, models.APIResponse{
 		Success: false,
 		Error: &models.ErrorInfo{
@@ -4,26 +4,39 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"llm/internal/models"
+	"llm/internal/service"
 )
 
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	openaiService service.LLMService
+}
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(openaiService service.LLMService) *HealthHandler {
+	return &HealthHandler{
+		openaiService: openaiService,
+	}
 }
 
 // Check handles health check requests
 // @Summary Health check
-// @Description Check if the LLM server is running and healthy
+// @Description Check if the LLM server is running and healthy. The status is "degraded" while a configured model is deprecated or not found; calls for it use the fallback model when one is configured.
 // @Tags Health
 // @Produce json
-// @Success 200 {object} map[string]string
+// @Success 200 {object} models.HealthResponse
 // @Router /health [get]
 func (h *HealthHandler) Check(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"service": "llm-server",
-	})
+	resp := models.HealthResponse{
+		Status:        "ok",
+		Service:       "llm-server",
+		ModelWarnings: h.openaiService.UnavailableModels(),
+	}
+	if len(resp.ModelWarnings) > 0 {
+		resp.Status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// PlanHandler handles caregiver care plan requests
+type PlanHandler struct {
+	planService *service.PlanService
+}
+
+// NewPlanHandler creates a new plan handler
+func NewPlanHandler(planService *service.PlanService) *PlanHandler {
+	return &PlanHandler{
+		planService: planService,
+	}
+}
+
+// SetPlan handles replacing a user's care plan
+// @Summary Set a user's care plan
+// @Description Replace the user's weekly cognitive exercise plan. On scheduled days the chat steers towards the first goal whose daily target is not yet met (family_reminiscence, date_orientation, name_recall).
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param request body models.CarePlanRequest true "Care plan"
+// @Success 200 {object} models.APIResponse{data=models.CarePlanResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/users/{user_id}/plan [put]
+func (h *PlanHandler) SetPlan(c *gin.Context) {
+	var req models.CarePlanRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_PLAN", "Invalid request format", err.Error())
+		return
+	}
+
+	userID := c.Param("user_id")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER_ID", "User ID cannot be empty", "")
+		return
+	}
+
+	resp, err := h.planService.SetPlan(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_PLAN", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// GetPlan handles care plan lookups
+// @Summary Get a user's care plan
+// @Description The user's current weekly cognitive exercise plan
+// @Tags Chat
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param If-None-Match header string false "ETag of a previous response; 304 Not Modified if unchanged"
+// @Success 200 {object} models.APIResponse{data=models.CarePlanResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/users/{user_id}/plan [get]
+func (h *PlanHandler) GetPlan(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	plan := h.planService.Plan(userID)
+	if plan == nil {
+		h.respondError(c, http.StatusNotFound, "PLAN_NOT_FOUND", "User has no care plan", userID)
+		return
+	}
+	if notModified(c, plan) {
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, plan)
+}
+
+// Summary handles daily goal coverage requests
+// @Summary Get daily goal coverage
+// @Description How many of a day's chat turns were steered towards and touched each care plan goal, for the caregiver's daily summary
+// @Tags Chat
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param date query string false "Day (YYYY-MM-DD, default today)"
+// @Param If-None-Match header string false "ETag of a previous response; 304 Not Modified if unchanged"
+// @Success 200 {object} models.APIResponse{data=models.GoalSummaryResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/users/{user_id}/plan/summary [get]
+func (h *PlanHandler) Summary(c *gin.Context) {
+	var req models.GoalSummaryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_SUMMARY_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	date := req.Date
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	resp, err := h.planService.DailySummary(c.Request.Context(), c.Param("user_id"), date)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "plan_not_found:") {
+			h.respondError(c, http.StatusNotFound, "PLAN_NOT_FOUND", "User has no care plan", err.Error())
+			return
+		}
+		h.respondError(c, http.StatusBadRequest, "INVALID_SUMMARY_REQUEST", err.Error(), "")
+		return
+	}
+	if notModified(c, resp) {
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Helper methods
+
+func (h *PlanHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *PlanHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// PrivacyHandler handles user data export and erasure requests
+type PrivacyHandler struct {
+	privacyService *service.PrivacyService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(privacyService *service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// Export handles user data export requests
+// @Summary Export user data
+// @Description Download everything stored about a user (conversations, personal info, quiz attempts, mood entries, analysis reports) as one JSON archive. Fails rather than return a partial archive when the RAG server cannot be read.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.UserDataExport
+// @Failure 401 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Router /api/admin/users/{user_id}/export [get]
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	export, err := h.privacyService.ExportUserData(c.Request.Context(), userID)
+	if err != nil {
+		h.respondServiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "user-"+userID+"-export.json"))
+	c.JSON(http.StatusOK, export)
+}
+
+// Delete handles right-to-erasure requests
+// @Summary Delete user data
+// @Description Erase everything stored about a user, in this service and on the RAG server, including saves still queued for delivery. Local data is removed even when the RAG erasure fails; retrying is safe.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.UserDeletionResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 502 {object} models.APIResponse
+// @Router /api/admin/users/{user_id} [delete]
+func (h *PrivacyHandler) Delete(c *gin.Context) {
+	resp, err := h.privacyService.DeleteUserData(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		h.respondServiceError(c, err)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Helper methods
+
+func (h *PrivacyHandler) respondServiceError(c *gin.Context, err error) {
+	if strings.HasPrefix(err.Error(), "rag_unavailable:") {
+		h.respondError(c, http.StatusBadGateway, "RAG_UNAVAILABLE", err.Error(), "")
+		return
+	}
+	h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error(), "")
+}
+
+func (h *PrivacyHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *PrivacyHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// ReminderHandler handles medication and appointment reminder requests
+type ReminderHandler struct {
+	reminderService *service.ReminderService
+}
+
+// NewReminderHandler creates a new reminder handler
+func NewReminderHandler(reminderService *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// Create handles reminder registration
+// @Summary Register a reminder
+// @Description Store a medication or appointment reminder. Chat replies bring it up naturally from a few hours before it is due ("오늘 3시에 병원 가시는 날이죠?") until the user confirms it.
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param request body models.ReminderRequest true "Reminder"
+// @Success 201 {object} models.APIResponse{data=models.Reminder}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/reminders [post]
+func (h *ReminderHandler) Create(c *gin.Context) {
+	var req models.ReminderRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REMINDER", "Invalid request format", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusCreated, h.reminderService.Create(c.Request.Context(), &req))
+}
+
+// List handles reminder listing
+// @Summary List reminders
+// @Description The user's reminders, soonest first. Acknowledged reminders are included only on request.
+// @Tags Chat
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id query string true "User ID"
+// @Param include_acknowledged query bool false "Include acknowledged reminders"
+// @Param If-None-Match header string false "ETag of a previous response; 304 Not Modified if unchanged"
+// @Success 200 {object} models.APIResponse{data=models.ReminderListResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/reminders [get]
+func (h *ReminderHandler) List(c *gin.Context) {
+	var req models.ReminderListRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REMINDER_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	resp := models.ReminderListResponse{
+		UserID:    req.UserID,
+		Reminders: h.reminderService.List(req.UserID, req.IncludeAcknowledged),
+	}
+	if notModified(c, resp) {
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Acknowledge handles confirming a reminder outside of chat
+// @Summary Acknowledge a reminder
+// @Description Mark a reminder as confirmed, e.g. from the caregiver app, so chats stop bringing it up
+// @Tags Chat
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param id path string true "Reminder ID"
+// @Param request body models.ReminderAcknowledgeRequest true "Acknowledgement"
+// @Success 200 {object} models.APIResponse{data=models.Reminder}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 504 {object} models.APIResponse
+// @Router /api/reminders/{id}/acknowledge [post]
+func (h *ReminderHandler) Acknowledge(c *gin.Context) {
+	var req models.ReminderAcknowledgeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REMINDER_REQUEST", "Invalid request format", err.Error())
+		return
+	}
+
+	reminderID := c.Param("id")
+
+	resp, err := h.reminderService.Acknowledge(c.Request.Context(), req.UserID, reminderID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "reminder_not_found:") {
+			h.respondError(c, http.StatusNotFound, "REMINDER_NOT_FOUND", "Reminder not found", reminderID)
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to acknowledge reminder", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, resp)
+}
+
+// Helper methods
+
+func (h *ReminderHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *ReminderHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// UserHandler handles user registry requests
+type UserHandler struct {
+	userService *service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// Create handles user registration
+// @Summary Register a user
+// @Description Register a user with their name, birth year, preferred call time and optional preferences. With USER_REGISTRY_ENFORCED set, chat, game and analysis requests for unregistered users are rejected with 404 USER_NOT_FOUND, so a mistyped user_id cannot create a phantom profile.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param request body models.UserCreateRequest true "User"
+// @Success 201 {object} models.APIResponse{data=models.User}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /api/users [post]
+func (h *UserHandler) Create(c *gin.Context) {
+	var req models.UserCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_USER", "Invalid request format", err.Error())
+		return
+	}
+
+	user, err := h.userService.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid_user:"):
+			h.respondError(c, http.StatusBadRequest, "INVALID_USER", "Invalid user", err.Error())
+		case strings.HasPrefix(err.Error(), "user_exists:"):
+			h.respondError(c, http.StatusConflict, "USER_EXISTS", "The user is already registered", err.Error())
+		default:
+			h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to register user", err.Error())
+		}
+		return
+	}
+
+	h.respondSuccess(c, http.StatusCreated, user)
+}
+
+// Get handles user lookups
+// @Summary Get a registered user
+// @Description The user's registry entry.
+// @Tags Users
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.APIResponse{data=models.User}
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/users/{user_id} [get]
+func (h *UserHandler) Get(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	user, err := h.userService.Get(userID)
+	if err != nil {
+		h.respondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", userID)
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, user)
+}
+
+// SetPreferences handles preference updates
+// @Summary Set a user's preferences
+// @Description Replace the user's preferences. Chat replies use the preferred language and persona and report the voice speed for the voice frontend; generated questions are never easier than the difficulty floor unless a request asks for a difficulty. Omitted fields fall back to the server defaults.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security TenantAPIKey
+// @Param user_id path string true "User ID"
+// @Param request body models.UserPreferences true "Preferences"
+// @Success 200 {object} models.APIResponse{data=models.User}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/users/{user_id}/preferences [put]
+func (h *UserHandler) SetPreferences(c *gin.Context) {
+	userID := c.Param("user_id")
+	var req models.UserPreferences
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_PREFERENCES", "Invalid request format", err.Error())
+		return
+	}
+
+	user, err := h.userService.SetPreferences(c.Request.Context(), userID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "user_not_found:") {
+			h.respondError(c, http.StatusNotFound, "USER_NOT_FOUND", "User not found", userID)
+			return
+		}
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to set preferences", err.Error())
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, user)
+}
+
+// Helper methods
+
+func (h *UserHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *UserHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
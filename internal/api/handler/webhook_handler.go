@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/api/middleware"
+	"llm/internal/models"
+	"llm/internal/service"
+)
+
+// WebhookHandler handles caregiver webhook subscription requests
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// List handles webhook listing requests
+// @Summary List webhooks
+// @Description Registered caregiver webhooks and the event types they can subscribe to
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Success 200 {object} models.APIResponse{data=models.WebhookListResponse}
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	h.respondSuccess(c, http.StatusOK, h.webhookService.List())
+}
+
+// Register handles webhook registration requests
+// @Summary Register a webhook
+// @Description Register a URL to receive signed caregiver events (analysis.completed, retention.dropped, quiz.incorrect_streak, emergency.detected). Deliveries carry an X-Webhook-Signature HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" and are retried with exponential backoff.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminAPIKey
+// @Param request body models.WebhookRegisterRequest true "Webhook registration"
+// @Success 201 {object} models.APIResponse{data=models.WebhookSubscriptionResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /api/admin/webhooks [post]
+func (h *WebhookHandler) Register(c *gin.Context) {
+	var req models.WebhookRegisterRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_WEBHOOK", "Invalid request format", err.Error())
+		return
+	}
+
+	resp, err := h.webhookService.Register(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_WEBHOOK", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusCreated, resp)
+}
+
+// Delete handles webhook removal requests
+// @Summary Delete a webhook
+// @Description Remove an API-registered webhook. Webhooks configured through WEBHOOKS cannot be removed here.
+// @Tags Admin
+// @Produce json
+// @Security AdminAPIKey
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} models.APIResponse{data=models.WebhookDeleteResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /api/admin/webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.webhookService.Delete(c.Request.Context(), id); err != nil {
+		if strings.HasPrefix(err.Error(), "webhook_not_found:") {
+			h.respondError(c, http.StatusNotFound, "WEBHOOK_NOT_FOUND", "Webhook not found", id)
+			return
+		}
+		h.respondError(c, http.StatusBadRequest, "INVALID_WEBHOOK", err.Error(), "")
+		return
+	}
+
+	h.respondSuccess(c, http.StatusOK, models.WebhookDeleteResponse{ID: id, Deleted: true})
+}
+
+// Helper methods
+
+func (h *WebhookHandler) respondSuccess(c *gin.Context, statusCode int, data interface{}) {
+	if c.GetBool(middleware.RawResponseKey) {
+		c.JSON(statusCode, data)
+		return
+	}
+	c.JSON(statusCode, models.APIResponse{
+		Success: true,
+		Data:    data,
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
+
+func (h *WebhookHandler) respondError(c *gin.Context, statusCode int, code string, message string, details string) {
+	c.JSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+			Details: details,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
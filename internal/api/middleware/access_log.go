@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/metrics"
+	"llm/internal/util"
+)
+
+// maxAccessLogBodyBytes is how much of the request body is kept to find its user_id
+const maxAccessLogBodyBytes = 64 << 10
+
+// accessLogEntry is one access log line
+type accessLogEntry struct {
+	Time             time.Time        `json:"time"`
+	RequestID        string           `json:"request_id"`
+	Method           string           `json:"method"`
+	Path             string           `json:"path"`
+	Route            string           `json:"route,omitempty"` // registered route pattern, e.g. /api/users/:user_id/plan
+	Status           int              `json:"status"`
+	LatencyMs        int64            `json:"latency_ms"`
+	ClientIP         string           `json:"client_ip"`
+	UserID           string           `json:"user_id,omitempty"`
+	TenantID         string           `json:"tenant_id,omitempty"`
+	RequestBytes     int64            `json:"request_bytes"`
+	ResponseBytes    int              `json:"response_bytes"`
+	UpstreamMs       map[string]int64 `json:"upstream_ms,omitempty"`    // summed latency per upstream target
+	UpstreamCalls    map[string]int   `json:"upstream_calls,omitempty"` // calls per upstream target
+	PromptTokens     int              `json:"prompt_tokens,omitempty"`
+	CompletionTokens int              `json:"completion_tokens,omitempty"`
+	Errors           string           `json:"errors,omitempty"`
+}
+
+// bodyCapture keeps the first bytes of a request body as a handler reads it
+type bodyCapture struct {
+	io.ReadCloser
+	captured *bytes.Buffer
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxAccessLogBodyBytes - b.captured.Len(); room > 0 {
+		b.captured.Write(p[:min(n, room)])
+	}
+	return n, err
+}
+
+// AccessLogMiddleware writes one JSON line per request to stdout with its status,
+// latency, user, upstream latencies and token usage, and records the latency in
+// the per-route percentiles
+func AccessLogMiddleware() gin.HandlerFunc {
+	logger := log.New(os.Stdout, "", 0)
+
+	return func(c *gin.Context) {
+		ctx, stats := util.WithRequestStats(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		captured := &bytes.Buffer{}
+		if c.Request.Body != nil {
+			c.Request.Body = &bodyCapture{ReadCloser: c.Request.Body, captured: captured}
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		route := c.FullPath()
+		if route != "" {
+			metrics.HTTPRequestLatency.Observe(c.Request.Method+" "+route, latency)
+		}
+
+		entry := accessLogEntry{
+			Time:          start.UTC(),
+			RequestID:     c.GetString("request_id"),
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.Path,
+			Route:         route,
+			Status:        c.Writer.Status(),
+			LatencyMs:     latency.Milliseconds(),
+			ClientIP:      c.ClientIP(),
+			UserID:        requestUserID(c, captured.Bytes()),
+			TenantID:      c.GetString(TenantKey),
+			RequestBytes:  max(c.Request.ContentLength, 0),
+			ResponseBytes: max(c.Writer.Size(), 0),
+			Errors:        c.Errors.ByType(gin.ErrorTypePrivate).String(),
+		}
+		entry.UpstreamMs, entry.UpstreamCalls = stats.Upstream()
+		entry.PromptTokens, entry.CompletionTokens = stats.Tokens()
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			log.Printf("WARNING: failed to encode access log entry - %v\n", err)
+			return
+		}
+		logger.Println(string(line))
+	}
+}
+
+// requestUserID returns the user a request is for, from the user_id path or
+// query parameter or the top-level user_id field of a JSON body
+func requestUserID(c *gin.Context, body []byte) string {
+	if userID := c.Param("user_id"); userID != "" {
+		return userID
+	}
+	if userID := c.Query("user_id"); userID != "" {
+		return userID
+	}
+	return bodyUserID(body)
+}
+
+// bodyUserID scans the top-level fields of a JSON object for user_id. The body
+// may be cut off, so fields are read one at a time rather than decoded whole.
+func bodyUserID(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return ""
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return ""
+		}
+		if key == "user_id" {
+			var userID string
+			json.Unmarshal(value, &userID)
+			return userID
+		}
+	}
+	return ""
+}
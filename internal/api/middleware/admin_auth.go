@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/models"
+)
+
+// AdminAuthMiddleware rejects requests that do not carry the admin API key as a bearer token.
+// A bare key without the Bearer scheme is rejected too.
+// The key is read on every request so a rotated key takes effect immediately.
+func AdminAuthMiddleware(apiKey func() string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, hasScheme := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		key := apiKey()
+		if !hasScheme || key == "" || subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.APIResponse{
+				Success: false,
+				Error: &models.ErrorInfo{
+					Code:    "UNAUTHORIZED",
+					Message: "Valid admin API key required",
+				},
+				Metadata: models.Metadata{
+					Timestamp: time.Now().UTC().Format(time.RFC3339),
+					RequestID: c.GetString("request_id"),
+				},
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		key           string
+		authorization string
+		want          int
+	}{
+		{name: "bearer key", key: "secret", authorization: "Bearer secret", want: http.StatusOK},
+		{name: "bare key without scheme", key: "secret", authorization: "secret", want: http.StatusUnauthorized},
+		{name: "wrong key", key: "secret", authorization: "Bearer other", want: http.StatusUnauthorized},
+		{name: "lowercase scheme", key: "secret", authorization: "bearer secret", want: http.StatusUnauthorized},
+		{name: "missing header", key: "secret", want: http.StatusUnauthorized},
+		{name: "no key configured", key: "", authorization: "Bearer ", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminAuthMiddleware(func() string { return tt.key }), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
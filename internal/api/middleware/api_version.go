@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions. v2 question payloads leave out correct_answer; clients grade
+// through the answer endpoint instead.
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+// API version negotiation
+const (
+	// APIVersionHeader selects the version on unversioned /api routes, and reports the version served
+	APIVersionHeader = "API-Version"
+	// APIVersionKey is the gin context key holding the version a request is served with
+	APIVersionKey = "api_version"
+)
+
+// APIVersions lists the supported versions, oldest first
+var APIVersions = []string{APIVersion1, APIVersion2}
+
+// APIVersionMiddleware serves a versioned route group (/api/v1, /api/v2) with its version
+func APIVersionMiddleware(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(APIVersionKey, version)
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// UnversionedAPIMiddleware serves the deprecated unversioned /api routes. The
+// version comes from the API-Version header ("v2" or "2"), defaulting to v1 so
+// clients built before versioning keep their response shapes. Responses carry
+// Deprecation, a Link to the versioned route and, when sunset is set, Sunset.
+func UnversionedAPIMiddleware(sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, ok := negotiateAPIVersion(c.GetHeader(APIVersionHeader))
+		if !ok {
+			abortWithError(c, http.StatusBadRequest, "UNSUPPORTED_API_VERSION", fmt.Sprintf("API version must be one of %s", strings.Join(APIVersions, ", ")))
+			return
+		}
+
+		c.Set(APIVersionKey, version)
+		c.Header(APIVersionHeader, version)
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf("</api/%s%s>; rel=\"successor-version\"", version, strings.TrimPrefix(c.Request.URL.Path, "/api")))
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Next()
+	}
+}
+
+// APIVersion returns the version the request is served with
+func APIVersion(c *gin.Context) string {
+	if version := c.GetString(APIVersionKey); version != "" {
+		return version
+	}
+	return APIVersion1
+}
+
+// negotiateAPIVersion maps a requested version to a supported one; empty means v1
+func negotiateAPIVersion(requested string) (string, bool) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested == "" {
+		return APIVersion1, true
+	}
+	if !strings.HasPrefix(requested, "v") {
+		requested = "v" + requested
+	}
+	for _, version := range APIVersions {
+		if requested == version {
+			return version, true
+		}
+	}
+	return "", false
+}
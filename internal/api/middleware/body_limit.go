@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/models"
+)
+
+// BodyLimitMiddleware rejects request bodies larger than maxBytes with 413, and
+// bodies that are not valid UTF-8 or whose top-level "message" field is longer
+// than maxMessageLength characters with 400, before they reach a handler or the
+// model. Accepted bodies are passed on unchanged.
+func BodyLimitMiddleware(maxBytes int64, maxMessageLength int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			abortWithError(c, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", fmt.Sprintf("Request body must not exceed %d bytes", maxBytes))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abortWithError(c, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE", fmt.Sprintf("Request body must not exceed %d bytes", maxBytes))
+				return
+			}
+			abortWithError(c, http.StatusBadRequest, "INVALID_BODY", "Failed to read request body")
+			return
+		}
+
+		if !utf8.Valid(body) {
+			abortWithError(c, http.StatusBadRequest, "INVALID_ENCODING", "Request body must be valid UTF-8")
+			return
+		}
+
+		// Bodies that are not a JSON object are left for the handler's binding to reject
+		var fields struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &fields) == nil && utf8.RuneCountInString(fields.Message) > maxMessageLength {
+			abortWithError(c, http.StatusBadRequest, "MESSAGE_TOO_LONG", fmt.Sprintf("Message must not exceed %d characters", maxMessageLength))
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func abortWithError(c *gin.Context, statusCode int, code string, message string) {
+	c.AbortWithStatusJSON(statusCode, models.APIResponse{
+		Success: false,
+		Error: &models.ErrorInfo{
+			Code:    code,
+			Message: message,
+		},
+		Metadata: models.Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			RequestID: c.GetString("request_id"),
+		},
+	})
+}
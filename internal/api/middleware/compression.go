@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Supported content encodings, in order of preference
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
+// CompressionMiddleware gzip- or deflate-compresses responses for clients that
+// accept it, when the body is at least minBytes long and its content type starts
+// with one of contentTypes (e.g. "application/json", "text/"). Responses are
+// buffered so their size is known before headers are sent.
+func CompressionMiddleware(minBytes int, contentTypes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: writer}
+		c.Writer = buffered
+
+		c.Next()
+
+		c.Writer = writer
+		if !buffered.Written() {
+			return
+		}
+
+		body := buffered.body.Bytes()
+		header := writer.Header()
+		if len(body) >= minBytes && header.Get("Content-Encoding") == "" &&
+			compressible(header.Get("Content-Type"), contentTypes) {
+			if compressed, err := compress(encoding, body); err == nil {
+				header.Set("Content-Encoding", encoding)
+				header.Set("Content-Length", strconv.Itoa(len(compressed)))
+				body = compressed
+			}
+		}
+
+		writer.WriteHeader(buffered.status)
+		writer.Write(body)
+	}
+}
+
+// negotiateEncoding picks the preferred encoding the Accept-Encoding header allows,
+// or "" for none. Encodings listed with q=0 are refused.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err != nil || q == 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressible reports whether a content type is on the list
+func compressible(contentType string, contentTypes []string) bool {
+	for _, prefix := range contentTypes {
+		if prefix != "" && strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// compress encodes body with the encoding. HTTP deflate is the zlib format.
+func compress(encoding string, body []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	var encoder io.WriteCloser = zlib.NewWriter(&compressed)
+	if encoding == EncodingGzip {
+		encoder = gzip.NewWriter(&compressed)
+	}
+
+	if _, err := encoder.Write(body); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
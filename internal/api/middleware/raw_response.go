@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Raw response opt-out for trusted internal services
+const (
+	// InternalAPIKeyHeader carries the internal API key that marks a caller as trusted
+	InternalAPIKeyHeader = "X-Internal-API-Key"
+	// RawResponseHeader requests the bare data payload ("true"); the "raw" query parameter works the same way
+	RawResponseHeader = "X-Raw-Response"
+	// RawResponseKey is the gin context key set when the envelope should be skipped
+	RawResponseKey = "raw_response"
+)
+
+// RawResponseMiddleware lets trusted internal callers receive successful responses
+// without the APIResponse envelope. The request must ask for it with the
+// X-Raw-Response header or raw query parameter and carry the internal API key;
+// everyone else, including all callers when internalAPIKey is empty, keeps the envelope.
+// Errors are always enveloped. The key is read on every request so rotation takes effect immediately.
+func RawResponseMiddleware(internalAPIKey func() string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := internalAPIKey()
+		requested := strings.EqualFold(c.GetHeader(RawResponseHeader), "true") || strings.EqualFold(c.Query("raw"), "true")
+		if requested && key != "" &&
+			subtle.ConstantTimeCompare([]byte(c.GetHeader(InternalAPIKeyHeader)), []byte(key)) == 1 {
+			c.Set(RawResponseKey, true)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/recording"
+)
+
+// responseRecorder captures the response body while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// RecordingMiddleware captures anonymized request/response pairs for replay in staging
+func RecordingMiddleware(recorder *recording.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody []byte
+		if c.Request.Body != nil {
+			requestBody, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(requestBody))
+		}
+
+		writer := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		start := time.Now()
+		c.Next()
+
+		record := recording.Record{
+			RequestID:    c.GetString("request_id"),
+			Method:       c.Request.Method,
+			Path:         c.Request.URL.Path,
+			Query:        c.Request.URL.RawQuery,
+			RequestBody:  requestBody,
+			Status:       c.Writer.Status(),
+			ResponseBody: writer.body.Bytes(),
+			LatencyMs:    time.Since(start).Milliseconds(),
+			RecordedAt:   start,
+		}
+
+		if err := recorder.Write(record); err != nil {
+			log.Printf("WARNING: failed to write recording - %v\n", err)
+		}
+	}
+}
@@ -3,17 +3,20 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"llm/internal/util"
 )
 
-// RequestIDMiddleware adds a request ID to each request
+// RequestIDMiddleware adds a request ID to each request and its context
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
+		requestID := c.GetHeader(util.RequestIDHeader)
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
 		c.Set("request_id", requestID)
-		c.Header("X-Request-ID", requestID)
+		c.Header(util.RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(util.WithRequestID(c.Request.Context(), requestID))
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errDuplicateField marks a JSON body that repeats a field. A check reading the
+// first value and a handler binding the last could then see different users.
+var errDuplicateField = errors.New("duplicate field")
+
+// userIDFields are the JSON fields naming users, matched case-insensitively as
+// encoding/json binds them
+var userIDFields = map[string]bool{
+	"user_id":  true,
+	"user_ids": true,
+}
+
+// requestUserIDs returns every user a request names: each user_id path and query
+// parameter and every user_id or user_ids field anywhere in a JSON body, nested
+// objects and arrays included. A body repeating a field is refused with
+// errDuplicateField. The body is read whole and put back for the handler.
+func requestUserIDs(c *gin.Context) ([]string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(c.Request.Body); err != nil {
+			return nil, err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	userIDs, err := bodyUserIDs(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range append([]string{c.Param("user_id")}, c.QueryArray("user_id")...) {
+		if userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// bodyUserIDs walks a JSON body for the users it names. A body that is not JSON
+// names no users; the handler rejects it when binding.
+func bodyUserIDs(body []byte) ([]string, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+
+	var userIDs []string
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := walkUserIDs(decoder, &userIDs); err != nil {
+		if errors.Is(err, errDuplicateField) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return userIDs, nil
+}
+
+// walkUserIDs consumes the next JSON value, collecting user IDs from its fields
+func walkUserIDs(decoder *json.Decoder, userIDs *[]string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := strings.ToLower(keyToken.(string))
+			if seen[key] {
+				return fmt.Errorf("%w %q", errDuplicateField, keyToken)
+			}
+			seen[key] = true
+
+			if !userIDFields[key] {
+				if err := walkUserIDs(decoder, userIDs); err != nil {
+					return err
+				}
+				continue
+			}
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return err
+			}
+			var userID string
+			var list []string
+			if json.Unmarshal(value, &userID) == nil {
+				list = []string{userID}
+			} else {
+				json.Unmarshal(value, &list)
+			}
+			for _, userID := range list {
+				if userID != "" {
+					*userIDs = append(*userIDs, userID)
+				}
+			}
+		}
+		_, err = decoder.Token()
+		return err
+	case json.Delim('['):
+		for decoder.More() {
+			if err := walkUserIDs(decoder, userIDs); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+		return err
+	}
+	return nil
+}
+
+// abortWithBodyError rejects a request whose body could not be checked for users
+func abortWithBodyError(c *gin.Context, err error) {
+	if errors.Is(err, errDuplicateField) {
+		abortWithError(c, http.StatusBadRequest, "INVALID_BODY", "Request body repeats a field")
+		return
+	}
+	abortWithError(c, http.StatusBadRequest, "INVALID_BODY", "Failed to read request body")
+}
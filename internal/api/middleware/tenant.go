@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/util"
+)
+
+// Tenant authentication
+const (
+	// TenantAPIKeyHeader carries a tenant's API key on public routes
+	TenantAPIKeyHeader = "X-API-Key"
+	// TenantKey is the gin context key holding the ID of the request's tenant
+	TenantKey = "tenant_id"
+
+	tenantsKey = "tenants"
+)
+
+// TenantMiddleware scopes public routes to care facilities. Each request must
+// carry a tenant's API key, and every user it names (see requestUserIDs) must
+// belong to that tenant. Requests and LLM tokens are counted per tenant. With no
+// tenants configured every request passes, as for a single facility.
+func TenantMiddleware(tenants config.Tenants) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(tenants) == 0 {
+			c.Next()
+			return
+		}
+
+		tenant, ok := tenants.ByAPIKey(c.GetHeader(TenantAPIKeyHeader))
+		if !ok {
+			abortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "Valid tenant API key required")
+			return
+		}
+
+		userIDs, err := requestUserIDs(c)
+		if err != nil {
+			abortWithBodyError(c, err)
+			return
+		}
+		for _, userID := range userIDs {
+			if owner, _ := tenants.Owner(userID); owner.ID != tenant.ID {
+				abortWithError(c, http.StatusForbidden, "FORBIDDEN", "user_id does not belong to this tenant")
+				return
+			}
+		}
+
+		c.Set(TenantKey, tenant.ID)
+		c.Set(tenantsKey, tenants)
+		c.Request = c.Request.WithContext(util.WithTenantID(c.Request.Context(), tenant.ID))
+
+		c.Next()
+
+		metrics.TenantRequests.Add(tenant.ID+"."+statusClass(c.Writer.Status()), 1)
+		if stats := util.RequestStatsFromContext(c.Request.Context()); stats != nil {
+			prompt, completion := stats.Tokens()
+			metrics.TenantTokens.Add(tenant.ID, int64(prompt+completion))
+		}
+	}
+}
+
+// UserInTenant reports whether a user belongs to the request's tenant. Handlers
+// use it for resources looked up by their own ID, whose user the middleware
+// cannot see. Without tenants every user is allowed.
+func UserInTenant(c *gin.Context, userID string) bool {
+	tenants, ok := c.Value(tenantsKey).(config.Tenants)
+	if !ok {
+		return true
+	}
+	owner, _ := tenants.Owner(userID)
+	return owner.ID == c.GetString(TenantKey)
+}
+
+// statusClass groups a status code for metric keys
+func statusClass(code int) string {
+	switch {
+	case code >= 500:
+		return "5xx"
+	case code >= 400:
+		return "4xx"
+	case code >= 300:
+		return "3xx"
+	default:
+		return "2xx"
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/config"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tenants := config.Tenants{
+		{ID: "sunrise", APIKey: "sunrise-key", UserPrefix: "sunrise-"},
+		{ID: "harbor", APIKey: "harbor-key", UserPrefix: "harbor-"},
+	}
+
+	tests := []struct {
+		name  string
+		key   string
+		query string
+		body  string
+		want  int
+	}{
+		{name: "own user", key: "sunrise-key", body: `{"user_id": "sunrise-1"}`, want: http.StatusOK},
+		{name: "missing key", body: `{"user_id": "sunrise-1"}`, want: http.StatusUnauthorized},
+		{name: "other tenant's user", key: "sunrise-key", body: `{"user_id": "harbor-1"}`, want: http.StatusForbidden},
+		{name: "duplicate user_id", key: "sunrise-key", body: `{"user_id": "sunrise-1", "user_id": "harbor-1"}`, want: http.StatusBadRequest},
+		{name: "duplicate user_id in another case", key: "sunrise-key", body: `{"user_id": "sunrise-1", "USER_ID": "harbor-1"}`, want: http.StatusBadRequest},
+		{name: "nested user_id", key: "sunrise-key", body: `{"user_id": "sunrise-1", "items": [{"user_id": "harbor-1"}]}`, want: http.StatusForbidden},
+		{name: "user_ids list", key: "sunrise-key", body: `{"user_ids": ["sunrise-1", "harbor-1"]}`, want: http.StatusForbidden},
+		{name: "repeated query parameter", key: "sunrise-key", query: "?user_id=sunrise-1&user_id=harbor-1", want: http.StatusForbidden},
+		{name: "body that is not JSON", key: "sunrise-key", body: `user_id=harbor-1`, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/chat", TenantMiddleware(tenants), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/chat"+tt.query, strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(TenantAPIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
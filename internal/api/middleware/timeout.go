@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"llm/internal/metrics"
+)
+
+// bufferedWriter holds a handler's response until the timeout middleware knows
+// whether the deadline passed. Headers go straight to the underlying writer.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0
+}
+
+// TimeoutMiddleware gives each request a deadline. The request context is
+// cancelled when it passes, so upstream calls stop, and whatever the handler
+// wrote is replaced by a 504. A timeout of 0 leaves requests unbounded.
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: writer}
+		c.Writer = buffered
+
+		c.Next()
+
+		c.Writer = writer
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			metrics.HTTPRequestTimeouts.Add(c.Request.Method+" "+c.FullPath(), 1)
+			abortWithError(c, http.StatusGatewayTimeout, "TIMEOUT", fmt.Sprintf("Request did not complete within %s", timeout))
+			return
+		}
+
+		if buffered.Written() {
+			writer.WriteHeader(buffered.status)
+			writer.Write(buffered.body.Bytes())
+		}
+	}
+}
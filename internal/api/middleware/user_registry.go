@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserRegistryMiddleware rejects requests naming a user that is not registered,
+// so a mistyped user_id fails instead of silently creating a new profile. Every
+// user the request names is checked, read the same way as by TenantMiddleware
+// (see requestUserIDs).
+// A nil registered passes every request, for deployments without the registry.
+func UserRegistryMiddleware(registered func(userID string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if registered == nil {
+			c.Next()
+			return
+		}
+
+		userIDs, err := requestUserIDs(c)
+		if err != nil {
+			abortWithBodyError(c, err)
+			return
+		}
+		for _, userID := range userIDs {
+			if !registered(userID) {
+				abortWithError(c, http.StatusNotFound, "USER_NOT_FOUND", fmt.Sprintf("User %s is not registered", userID))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
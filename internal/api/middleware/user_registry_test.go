@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUserRegistryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registered := func(userID string) bool { return userID == "user-1" || userID == "user-2" }
+
+	tests := []struct {
+		name  string
+		path  string
+		query string
+		body  string
+		want  int
+	}{
+		{name: "registered user", body: `{"user_id": "user-1"}`, want: http.StatusOK},
+		{name: "unregistered user", body: `{"user_id": "user-9"}`, want: http.StatusNotFound},
+		{name: "registered user in the path", path: "/user-2", want: http.StatusOK},
+		{name: "unregistered user in the path", path: "/user-9", want: http.StatusNotFound},
+		{name: "duplicate user_id", body: `{"user_id": "user-1", "user_id": "user-9"}`, want: http.StatusBadRequest},
+		{name: "nested unregistered user", body: `{"user_id": "user-1", "reminder": {"user_id": "user-9"}}`, want: http.StatusNotFound},
+		{name: "repeated query parameter", query: "?user_id=user-1&user_id=user-9", want: http.StatusNotFound},
+		{name: "no user", body: `{"message": "안녕"}`, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.POST("/profile", UserRegistryMiddleware(registered), handler)
+			router.POST("/profile/:user_id", UserRegistryMiddleware(registered), handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/profile"+tt.path+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -8,21 +10,49 @@ import (
 	"llm/internal/api/handler"
 	"llm/internal/api/middleware"
 	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/recording"
+	"llm/internal/secrets"
 	"llm/internal/service"
 )
 
 // Router sets up all API routes
-func Router(cfg *config.Config, chatService *service.ChatService, gameService *service.GameService, analysisService *service.AnalysisService) *gin.Engine {
-	router := gin.Default()
+func Router(cfg *config.Config, chatService *service.ChatService, gameService *service.GameService, analysisService *service.AnalysisService, experimentService *service.ExperimentService, adminService *service.AdminService, calibrationService *service.CalibrationService, webhookService *service.WebhookService, backfillService *service.BackfillService, maintenanceService *service.MaintenanceService, replayService *service.ReplayService, cacheService *service.CacheService, privacyService *service.PrivacyService, planService *service.PlanService, reminderService *service.ReminderService, assessmentService *service.AssessmentService, openaiService service.LLMService, secretManager *secrets.Manager, userService *service.UserService) *gin.Engine {
+	router := gin.New()
 
 	// Apply middlewares
+	router.Use(gin.Recovery())
 	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.AccessLogMiddleware())
+	router.Use(middleware.BodyLimitMiddleware(cfg.MaxRequestBodyBytes, cfg.MaxMessageLength))
+	router.Use(middleware.RawResponseMiddleware(secretManager.Secret(secrets.InternalAPIKey).Value))
+	if cfg.CompressionEnabled {
+		router.Use(middleware.CompressionMiddleware(cfg.CompressionMinBytes, cfg.CompressionContentTypes))
+	}
+
+	if cfg.RecordingEnabled {
+		recorder, err := recording.NewRecorder(cfg.RecordingDir)
+		if err != nil {
+			log.Printf("Warning: request recording disabled: %v", err)
+		} else {
+			router.Use(middleware.RecordingMiddleware(recorder))
+			log.Printf("Recording requests to %s", cfg.RecordingDir)
+		}
+	}
 
 	// Create handlers
 	chatHandler := handler.NewChatHandler(chatService)
 	gameHandler := handler.NewGameHandler(gameService)
 	analysisHandler := handler.NewAnalysisHandler(analysisService)
-	healthHandler := handler.NewHealthHandler()
+	healthHandler := handler.NewHealthHandler(openaiService)
+	experimentHandler := handler.NewExperimentHandler(experimentService)
+	adminHandler := handler.NewAdminHandler(adminService, calibrationService, backfillService, maintenanceService, replayService, cacheService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	planHandler := handler.NewPlanHandler(planService)
+	reminderHandler := handler.NewReminderHandler(reminderService)
+	assessmentHandler := handler.NewAssessmentHandler(assessmentService)
+	userHandler := handler.NewUserHandler(userService)
 
 	// Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -30,25 +60,102 @@ func Router(cfg *config.Config, chatService *service.ChatService, gameService *s
 	// Health check
 	router.GET("/health", healthHandler.Check)
 
-	// Chat API routes
-	chat := router.Group("/api")
-	{
-		chat.POST("/chat", chatHandler.Handle)
+	// Metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Public API routes are served under /api/v1 and /api/v2, and under the
+	// deprecated unversioned /api prefix used by clients predating versioning.
+	// With TENANTS set they require a tenant's API key, and with
+	// USER_REGISTRY_ENFORCED set chat, game and analysis only serve registered users.
+	var userRegistered func(string) bool
+	if cfg.UserRegistryEnforced {
+		userRegistered = userService.Exists
+	}
+	publicRoutes := func(api *gin.RouterGroup) {
+		api.Use(middleware.TenantMiddleware(cfg.Tenants))
+
+		// User registry routes
+		api.POST("/users", userHandler.Create)
+		api.GET("/users/:user_id", userHandler.Get)
+		api.PUT("/users/:user_id/preferences", userHandler.SetPreferences)
+
+		// Chat API routes
+		chat := api.Group("", middleware.TimeoutMiddleware(cfg.ChatRequestTimeout), middleware.UserRegistryMiddleware(userRegistered))
+		{
+			chat.POST("/chat", chatHandler.Handle)
+			chat.GET("/chat/mood", chatHandler.Mood)
+			chat.POST("/conversations/:id/correct", chatHandler.Correct)
+			chat.POST("/users/:user_id/avoid-topics", chatHandler.AddAvoidTopic)
+			chat.POST("/users/:user_id/pins", chatHandler.AddPin)
+			chat.POST("/users/:user_id/care-mode", chatHandler.SetCareMode)
+			chat.PUT("/users/:user_id/plan", planHandler.SetPlan)
+			chat.GET("/users/:user_id/plan", planHandler.GetPlan)
+			chat.GET("/users/:user_id/plan/summary", planHandler.Summary)
+			chat.POST("/reminders", reminderHandler.Create)
+			chat.GET("/reminders", reminderHandler.List)
+			chat.POST("/reminders/:id/acknowledge", reminderHandler.Acknowledge)
+		}
+
+		// Game API routes
+		game := api.Group("/game", middleware.TimeoutMiddleware(cfg.GameRequestTimeout), middleware.UserRegistryMiddleware(userRegistered))
+		{
+			game.POST("/question", gameHandler.GenerateQuestion)
+			game.POST("/question-set", gameHandler.GenerateQuestionSet)
+			game.POST("/recap", gameHandler.GenerateRecap)
+			game.POST("/orientation", gameHandler.GenerateOrientationQuestions)
+			game.POST("/result", gameHandler.EvaluateResult)
+			game.POST("/question/:id/answer", gameHandler.AnswerQuestion)
+		}
+
+		// Analysis API routes
+		analysis := api.Group("", middleware.TimeoutMiddleware(cfg.AnalysisRequestTimeout), middleware.UserRegistryMiddleware(userRegistered))
+		{
+			analysis.POST("/analysis", analysisHandler.ProcessAnalysis)                   // 통합: 도메인 분석 + 리포트
+			analysis.POST("/analysis/domains", analysisHandler.ProcessDomainAnalysisOnly) // 도메인 분석만
+			analysis.POST("/analysis/report", analysisHandler.ProcessReportGeneration)    // 리포트 생성만
+			analysis.GET("/analysis/timeline", analysisHandler.Timeline)                  // 인지 점수 추이
+			analysis.POST("/assessment", assessmentHandler.Start)
+			analysis.GET("/assessment/:id", assessmentHandler.Get)
+		}
+	}
+	for _, version := range middleware.APIVersions {
+		publicRoutes(router.Group("/api/"+version, middleware.APIVersionMiddleware(version)))
 	}
+	publicRoutes(router.Group("/api", middleware.UnversionedAPIMiddleware(cfg.UnversionedAPISunset)))
 
-	// Game API routes
-	game := router.Group("/api/game")
+	// Admin API routes (rejected unless ADMIN_API_KEY is configured)
+	admin := router.Group("/api/admin", middleware.AdminAuthMiddleware(secretManager.Secret(secrets.AdminAPIKey).Value))
 	{
-		game.POST("/question", gameHandler.GenerateQuestion)
-		game.POST("/result", gameHandler.EvaluateResult)
+		admin.GET("/config", adminHandler.GetConfig)
+		admin.PATCH("/config", adminHandler.UpdateConfig)
+		admin.POST("/config/reload", adminHandler.ReloadConfig)
+		admin.GET("/safe-mode", adminHandler.GetSafeMode)
+		admin.PATCH("/safe-mode", adminHandler.UpdateSafeMode)
+		admin.GET("/experiments/results", experimentHandler.Results)
+		admin.GET("/calibration", adminHandler.GetCalibration)
+		admin.POST("/calibration/run", adminHandler.RunCalibration)
+		admin.GET("/backfill/scores", adminHandler.GetScoreBackfill)
+		admin.POST("/backfill/scores", adminHandler.StartScoreBackfill)
+		admin.GET("/maintenance", adminHandler.GetMaintenance)
+		admin.POST("/maintenance/run", adminHandler.RunMaintenance)
+		admin.POST("/replay", adminHandler.Replay)
+		admin.GET("/cache/stats", adminHandler.GetCacheStats)
+		admin.DELETE("/cache/:name/:key", adminHandler.EvictCache)
+		admin.POST("/questions/preview", gameHandler.PreviewQuestion)
+		admin.POST("/questions/:id/review", gameHandler.ReviewQuestion)
+		admin.GET("/questions/:id/lineage", gameHandler.GetQuestionLineage)
+		admin.GET("/webhooks", webhookHandler.List)
+		admin.POST("/webhooks", webhookHandler.Register)
+		admin.DELETE("/webhooks/:id", webhookHandler.Delete)
+		admin.GET("/users/:user_id/export", privacyHandler.Export)
+		admin.DELETE("/users/:user_id", privacyHandler.Delete)
 	}
 
-	// Analysis API routes
-	analysis := router.Group("/api")
+	// Prompt debugging routes; they expose real user data, so they share the admin key
+	debug := router.Group("/api/debug", middleware.AdminAuthMiddleware(secretManager.Secret(secrets.AdminAPIKey).Value))
 	{
-		analysis.POST("/analysis", analysisHandler.ProcessAnalysis)                   // 통합: 도메인 분석 + 리포트
-		analysis.POST("/analysis/domains", analysisHandler.ProcessDomainAnalysisOnly) // 도메인 분석만
-		analysis.POST("/analysis/report", analysisHandler.ProcessReportGeneration)    // 리포트 생성만
+		debug.POST("/prompt/chat", chatHandler.DryRun)
+		debug.POST("/prompt/question", gameHandler.DryRunQuestion)
 	}
 
 	return router
@@ -0,0 +1,100 @@
+package chaos
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"llm/internal/config"
+)
+
+// Dependency names accepted in CHAOS_TARGETS
+const (
+	TargetRAG    = "rag"
+	TargetOpenAI = "openai"
+)
+
+// malformedBody replaces a successful response body when a malformed JSON fault fires
+const malformedBody = `{"success": true, "data": {`
+
+// Transport injects latency, errors, and malformed JSON into outbound calls
+type Transport struct {
+	base          http.RoundTripper
+	target        string
+	latency       time.Duration
+	errorRate     float64
+	malformedRate float64
+}
+
+// WrapTransport wraps base with fault injection when chaos is enabled for target,
+// otherwise it returns base unchanged
+func WrapTransport(cfg *config.Config, target string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !cfg.ChaosEnabled || !targets(cfg.ChaosTargets)[target] {
+		return base
+	}
+
+	log.Printf("WARNING: chaos fault injection enabled for %s (latency=%v, error_rate=%.2f, malformed_rate=%.2f)",
+		target, cfg.ChaosLatency, cfg.ChaosErrorRate, cfg.ChaosMalformedRate)
+
+	return &Transport{
+		base:          base,
+		target:        target,
+		latency:       cfg.ChaosLatency,
+		errorRate:     cfg.ChaosErrorRate,
+		malformedRate: cfg.ChaosMalformedRate,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.latency > 0 {
+		select {
+		case <-time.After(t.latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if rand.Float64() < t.errorRate {
+		log.Printf("CHAOS: injecting error for %s %s", t.target, req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Status:     "503 Service Unavailable",
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"success": false, "error": {"code": "CHAOS_INJECTED", "message": "injected fault for %s"}}`, t.target))),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if rand.Float64() < t.malformedRate {
+		log.Printf("CHAOS: injecting malformed JSON for %s %s", t.target, req.URL.Path)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader([]byte(malformedBody)))
+		resp.ContentLength = int64(len(malformedBody))
+	}
+
+	return resp, nil
+}
+
+func targets(list string) map[string]bool {
+	result := make(map[string]bool)
+	for _, target := range strings.Split(list, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			result[target] = true
+		}
+	}
+	return result
+}
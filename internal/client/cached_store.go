@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// CachedStore is a RAGStore that caches the per-user reads every chat turn
+// makes: personal info and recent incorrect quiz attempts. Writes through the
+// store invalidate the user's entries; writes made elsewhere show up once the
+// entries expire. While the RAG server fails, expired personal info is still
+// served for up to profileMaxStale past its expiry.
+type CachedStore struct {
+	RAGStore
+	profileTTL      time.Duration // 0 disables the personal info cache
+	profileMaxStale time.Duration
+	attemptsTTL     time.Duration // 0 disables the incorrect attempts cache
+	profiles        map[string]cachedProfile
+	attempts        map[string]map[int]cachedAttempts // user ID -> limit -> attempts
+	versions        map[string]uint64                 // user ID -> invalidation count, guards against in-flight fetches
+	mutex           sync.RWMutex
+	logger          *util.Logger
+}
+
+type cachedProfile struct {
+	profile   *models.PersonalInfoListResponse
+	fetchedAt time.Time
+}
+
+type cachedAttempts struct {
+	attempts  *models.IncorrectQuizAttemptsResponse
+	fetchedAt time.Time
+}
+
+var _ RAGStore = (*CachedStore)(nil)
+
+// NewCachedStore wraps store with the read caches. A zero TTL disables that cache.
+func NewCachedStore(store RAGStore, profileTTL, profileMaxStale, attemptsTTL time.Duration) *CachedStore {
+	return &CachedStore{
+		RAGStore:        store,
+		profileTTL:      profileTTL,
+		profileMaxStale: profileMaxStale,
+		attemptsTTL:     attemptsTTL,
+		profiles:        make(map[string]cachedProfile),
+		attempts:        make(map[string]map[int]cachedAttempts),
+		versions:        make(map[string]uint64),
+		logger:          util.NewLogger("CachedStore"),
+	}
+}
+
+// GetPersonalInfoByUser returns the cached personal info while it is fresh and
+// fetches it from the wrapped store otherwise. Callers must not modify the result.
+func (cs *CachedStore) GetPersonalInfoByUser(ctx context.Context, userID string) (*models.PersonalInfoListResponse, error) {
+	if cs.profileTTL == 0 {
+		return cs.RAGStore.GetPersonalInfoByUser(ctx, userID)
+	}
+	now := time.Now()
+
+	cs.mutex.RLock()
+	entry, cached := cs.profiles[userID]
+	version := cs.versions[userID]
+	cs.mutex.RUnlock()
+
+	if cached && now.Sub(entry.fetchedAt) <= cs.profileTTL {
+		metrics.RAGCache.Add(metrics.RAGCacheProfile+"."+metrics.RAGCacheHit, 1)
+		return entry.profile, nil
+	}
+	metrics.RAGCache.Add(metrics.RAGCacheProfile+"."+metrics.RAGCacheMiss, 1)
+
+	profile, err := cs.RAGStore.GetPersonalInfoByUser(ctx, userID)
+	if err != nil {
+		if cached && now.Sub(entry.fetchedAt) <= cs.profileTTL+cs.profileMaxStale {
+			cs.logger.WithContext(ctx).Warn("Failed to get personal info, serving cached profile", err)
+			metrics.RAGCache.Add(metrics.RAGCacheProfile+"."+metrics.RAGCacheStale, 1)
+			return entry.profile, nil
+		}
+		return nil, err
+	}
+
+	// A write that landed while fetching may not be reflected in the result
+	cs.mutex.Lock()
+	if profile != nil && cs.versions[userID] == version {
+		cs.profiles[userID] = cachedProfile{profile: profile, fetchedAt: now}
+	}
+	cs.mutex.Unlock()
+
+	return profile, nil
+}
+
+// GetIncorrectQuizAttempts returns the cached attempts for the user and limit
+// while they are fresh and fetches them from the wrapped store otherwise.
+// Callers must not modify the result.
+func (cs *CachedStore) GetIncorrectQuizAttempts(ctx context.Context, userID string, limit int) (*models.IncorrectQuizAttemptsResponse, error) {
+	if cs.attemptsTTL == 0 {
+		return cs.RAGStore.GetIncorrectQuizAttempts(ctx, userID, limit)
+	}
+	now := time.Now()
+
+	cs.mutex.RLock()
+	entry, cached := cs.attempts[userID][limit]
+	version := cs.versions[userID]
+	cs.mutex.RUnlock()
+
+	if cached && now.Sub(entry.fetchedAt) <= cs.attemptsTTL {
+		metrics.RAGCache.Add(metrics.RAGCacheIncorrectAttempts+"."+metrics.RAGCacheHit, 1)
+		return entry.attempts, nil
+	}
+	metrics.RAGCache.Add(metrics.RAGCacheIncorrectAttempts+"."+metrics.RAGCacheMiss, 1)
+
+	attempts, err := cs.RAGStore.GetIncorrectQuizAttempts(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	cs.mutex.Lock()
+	if attempts != nil && cs.versions[userID] == version {
+		if cs.attempts[userID] == nil {
+			cs.attempts[userID] = make(map[int]cachedAttempts)
+		}
+		cs.attempts[userID][limit] = cachedAttempts{attempts: attempts, fetchedAt: now}
+	}
+	cs.mutex.Unlock()
+
+	return attempts, nil
+}
+
+// SaveConversation saves the conversation on the wrapped store. Saving a game
+// result invalidates the user's cached incorrect attempts once the save returns,
+// so the next chat prompt sees the new mistake.
+func (cs *CachedStore) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	if req.Metadata != nil && req.Metadata.Type == util.ConversationTypeMemoryEvaluation {
+		if userID := util.UserIDFromContext(ctx); userID != "" {
+			defer cs.InvalidateIncorrectAttempts(userID)
+		}
+	}
+	return cs.RAGStore.SaveConversation(ctx, req)
+}
+
+// CreatePersonalInfo stores the entry and invalidates the user's cached personal
+// info. The entry is invalidated even when the write fails, since it may have
+// landed before the error.
+func (cs *CachedStore) CreatePersonalInfo(ctx context.Context, req *models.PersonalInfoCreateRequest) (string, error) {
+	defer cs.Invalidate(req.UserID)
+	return cs.RAGStore.CreatePersonalInfo(ctx, req)
+}
+
+// DeleteUserData drops the user's cached entries and erases the user on the wrapped store
+func (cs *CachedStore) DeleteUserData(ctx context.Context, userID string) error {
+	defer cs.Invalidate(userID)
+	defer cs.InvalidateIncorrectAttempts(userID)
+	return cs.RAGStore.DeleteUserData(ctx, userID)
+}
+
+// Invalidate drops the user's cached personal info
+func (cs *CachedStore) Invalidate(userID string) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	delete(cs.profiles, userID)
+	cs.versions[userID]++
+}
+
+// InvalidateIncorrectAttempts drops the user's cached incorrect attempts for every limit
+func (cs *CachedStore) InvalidateIncorrectAttempts(userID string) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	delete(cs.attempts, userID)
+	cs.versions[userID]++
+}
+
+// ProfileStats returns how many users have cached personal info
+func (cs *CachedStore) ProfileStats() int {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	return len(cs.profiles)
+}
+
+// IncorrectAttemptsStats returns how many incorrect attempt lists are cached,
+// one per user and limit, and for how many users
+func (cs *CachedStore) IncorrectAttemptsStats() (int, int) {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	entries := 0
+	for _, byLimit := range cs.attempts {
+		entries += len(byLimit)
+	}
+	return entries, len(cs.attempts)
+}
+
+// EvictProfile drops the user's cached personal info and returns how many entries were removed
+func (cs *CachedStore) EvictProfile(userID string) int {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	_, exists := cs.profiles[userID]
+	delete(cs.profiles, userID)
+	cs.versions[userID]++
+	if exists {
+		return 1
+	}
+	return 0
+}
+
+// EvictIncorrectAttempts drops the user's cached incorrect attempts and returns how many entries were removed
+func (cs *CachedStore) EvictIncorrectAttempts(userID string) int {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	removed := len(cs.attempts[userID])
+	delete(cs.attempts, userID)
+	cs.versions[userID]++
+	return removed
+}
+
+// Compact removes entries too old to be served even while the RAG server fails
+// and returns how many were removed and the approximate bytes their content held
+func (cs *CachedStore) Compact(now time.Time) (int, int64) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for userID, entry := range cs.profiles {
+		if now.Sub(entry.fetchedAt) <= cs.profileTTL+cs.profileMaxStale {
+			continue
+		}
+		for _, item := range entry.profile.Items {
+			reclaimed += int64(len(item.Content))
+		}
+		delete(cs.profiles, userID)
+		removed++
+	}
+
+	for userID, byLimit := range cs.attempts {
+		for limit, entry := range byLimit {
+			if now.Sub(entry.fetchedAt) <= cs.attemptsTTL {
+				continue
+			}
+			for _, attempt := range entry.attempts.Items {
+				reclaimed += int64(len(attempt.Quiz.Question) + len(attempt.UserAnswer) + len(attempt.CorrectAnswer))
+			}
+			delete(byLimit, limit)
+			removed++
+		}
+		if len(byLimit) == 0 {
+			delete(cs.attempts, userID)
+		}
+	}
+	return removed, reclaimed
+}
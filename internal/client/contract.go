@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//go:embed contracts/rag_api.json
+var ragContractSpec []byte
+
+// Contract describes the RAG API endpoints and response envelopes this service relies on
+type Contract struct {
+	Name      string             `json:"name"`
+	Version   string             `json:"version"`
+	Endpoints []ContractEndpoint `json:"endpoints"`
+}
+
+// ContractEndpoint describes a single endpoint's request and expected response envelope
+type ContractEndpoint struct {
+	Name           string            `json:"name"`
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	Query          map[string]string `json:"query,omitempty"`
+	Body           json.RawMessage   `json:"body,omitempty"`
+	Mutating       bool              `json:"mutating,omitempty"`
+	ExpectStatus   []int             `json:"expect_status"`
+	RequiredFields map[string]string `json:"required_fields,omitempty"`
+}
+
+// ContractViolation describes a mismatch between the contract and a live RAG server
+type ContractViolation struct {
+	Endpoint string `json:"endpoint"`
+	Message  string `json:"message"`
+}
+
+// ContractReport summarizes a contract verification run
+type ContractReport struct {
+	Target     string              `json:"target"`
+	Checked    []string            `json:"checked"`
+	Skipped    []string            `json:"skipped"`
+	Violations []ContractViolation `json:"violations"`
+}
+
+// OK reports whether verification found no violations
+func (r *ContractReport) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// LoadContract parses the embedded RAG API contract
+func LoadContract() (*Contract, error) {
+	var contract Contract
+	if err := json.Unmarshal(ragContractSpec, &contract); err != nil {
+		return nil, fmt.Errorf("failed to parse rag contract: %w", err)
+	}
+	return &contract, nil
+}
+
+// VerifyContract checks the RAG server against the embedded contract.
+// Mutating endpoints are only exercised when includeMutating is set; probeUserID is
+// substituted for {user_id} placeholders.
+func (rc *RAGClient) VerifyContract(ctx context.Context, probeUserID string, includeMutating bool) (*ContractReport, error) {
+	contract, err := LoadContract()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ContractReport{
+		Target:     rc.baseURL,
+		Checked:    []string{},
+		Skipped:    []string{},
+		Violations: []ContractViolation{},
+	}
+
+	for _, endpoint := range contract.Endpoints {
+		if endpoint.Mutating && !includeMutating {
+			report.Skipped = append(report.Skipped, endpoint.Name)
+			continue
+		}
+
+		report.Checked = append(report.Checked, endpoint.Name)
+		for _, message := range rc.verifyEndpoint(ctx, endpoint, probeUserID) {
+			report.Violations = append(report.Violations, ContractViolation{Endpoint: endpoint.Name, Message: message})
+		}
+	}
+
+	return report, nil
+}
+
+func (rc *RAGClient) verifyEndpoint(ctx context.Context, endpoint ContractEndpoint, probeUserID string) []string {
+	fill := func(s string) string {
+		return strings.ReplaceAll(s, "{user_id}", probeUserID)
+	}
+
+	fullURL := rc.baseURL + fill(endpoint.Path)
+	if len(endpoint.Query) > 0 {
+		params := url.Values{}
+		for key, value := range endpoint.Query {
+			params.Add(key, fill(value))
+		}
+		fullURL += "?" + params.Encode()
+	}
+
+	var body io.Reader
+	if len(endpoint.Body) > 0 {
+		body = bytes.NewReader([]byte(fill(string(endpoint.Body))))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rc.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, endpoint.Method, fullURL, body)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to create request: %v", err)}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return []string{fmt.Sprintf("request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read response: %v", err)}
+	}
+
+	violations := []string{}
+	if !containsStatus(endpoint.ExpectStatus, resp.StatusCode) {
+		violations = append(violations, fmt.Sprintf("unexpected status %d, expected one of %v", resp.StatusCode, endpoint.ExpectStatus))
+	}
+
+	if len(endpoint.RequiredFields) == 0 {
+		return violations
+	}
+
+	var envelope interface{}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return append(violations, fmt.Sprintf("response is not valid JSON: %v", err))
+	}
+
+	for path, expectedType := range endpoint.RequiredFields {
+		value, found := lookupField(envelope, path)
+		if !found {
+			violations = append(violations, fmt.Sprintf("missing field %q", path))
+			continue
+		}
+		if actual := jsonType(value); !typeAllowed(expectedType, actual) {
+			violations = append(violations, fmt.Sprintf("field %q has type %s, expected %s", path, actual, expectedType))
+		}
+	}
+
+	return violations
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupField resolves a dotted path such as "data.results" in a decoded JSON value
+func lookupField(value interface{}, path string) (interface{}, bool) {
+	current := value
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = obj[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func typeAllowed(expected string, actual string) bool {
+	for _, t := range strings.Split(expected, "|") {
+		if strings.TrimSpace(t) == actual {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"llm/internal/embedding"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// LocalIndex is a small in-process vector index of each user's most recently
+// saved conversations
+type LocalIndex struct {
+	maxPerUser int
+	entries    map[string][]indexedConversation // user ID -> entries, oldest first
+	mutex      sync.RWMutex
+}
+
+type indexedConversation struct {
+	conversation models.RAGConversationSearchResult
+	text         string
+	vector       embedding.Vector
+}
+
+// NewLocalIndex creates an index keeping up to maxPerUser conversations per user
+func NewLocalIndex(maxPerUser int) *LocalIndex {
+	return &LocalIndex{
+		maxPerUser: maxPerUser,
+		entries:    make(map[string][]indexedConversation),
+	}
+}
+
+// Add indexes a conversation for the user, dropping the oldest one beyond the limit
+func (li *LocalIndex) Add(userID string, conv models.RAGConversationSearchResult, text string, vector embedding.Vector) {
+	li.mutex.Lock()
+	defer li.mutex.Unlock()
+
+	entries := append(li.entries[userID], indexedConversation{conversation: conv, text: text, vector: vector})
+	if len(entries) > li.maxPerUser {
+		entries = entries[len(entries)-li.maxPerUser:]
+	}
+	li.entries[userID] = entries
+}
+
+// Len returns how many conversations are indexed for the user
+func (li *LocalIndex) Len(userID string) int {
+	li.mutex.RLock()
+	defer li.mutex.RUnlock()
+
+	return len(li.entries[userID])
+}
+
+// Remove drops every conversation indexed for the user
+func (li *LocalIndex) Remove(userID string) {
+	li.mutex.Lock()
+	defer li.mutex.Unlock()
+
+	delete(li.entries, userID)
+}
+
+// Search returns up to limit of the user's conversations most similar to the
+// query, best first. Conversations embedded with a different model than the
+// query are compared by their local vectors instead.
+func (li *LocalIndex) Search(userID, query string, queryVector embedding.Vector, limit int) []models.RAGConversationSearchResult {
+	li.mutex.RLock()
+	entries := li.entries[userID]
+	li.mutex.RUnlock()
+
+	var localQuery embedding.Vector
+	results := make([]models.RAGConversationSearchResult, 0, len(entries))
+	for _, entry := range entries {
+		var score float32
+		if entry.vector.Model == queryVector.Model {
+			score = embedding.Cosine(queryVector, entry.vector)
+		} else {
+			if localQuery.Model == "" {
+				localQuery = embedding.Local(query)
+			}
+			score = embedding.Cosine(localQuery, embedding.Local(entry.text))
+		}
+
+		result := entry.conversation
+		result.Score = score
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// BufferedStore is a RAGStore that also keeps recently saved conversations in a
+// LocalIndex, so conversation search keeps returning context while the RAG
+// server is unreachable. Conversations are indexed even when saving them fails.
+type BufferedStore struct {
+	RAGStore
+	index    *LocalIndex
+	embedder *embedding.Service
+	logger   *util.Logger
+}
+
+var _ RAGStore = (*BufferedStore)(nil)
+
+// NewBufferedStore wraps store with a local index of up to maxPerUser conversations per user
+func NewBufferedStore(store RAGStore, embedder *embedding.Service, maxPerUser int) *BufferedStore {
+	return &BufferedStore{
+		RAGStore: store,
+		index:    NewLocalIndex(maxPerUser),
+		embedder: embedder,
+		logger:   util.NewLogger("BufferedStore"),
+	}
+}
+
+// SaveConversation indexes the conversation locally and saves it to the RAG server
+func (bs *BufferedStore) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	userID := util.UserIDFromContext(ctx)
+	if userID == "" && req.Metadata != nil {
+		userID = req.Metadata.SessionID
+	}
+
+	if userID != "" {
+		text := conversationText(req.Messages)
+		bs.index.Add(userID, models.RAGConversationSearchResult{
+			ConversationID: req.ConversationID,
+			Timestamp:      time.Now(),
+			Messages:       req.Messages,
+			Metadata:       req.Metadata,
+		}, text, bs.embedder.Embed(ctx, text))
+	}
+
+	return bs.RAGStore.SaveConversation(ctx, req)
+}
+
+// SearchConversations searches the RAG server, falling back to the local index
+// when the search fails and the user has conversations indexed
+func (bs *BufferedStore) SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error) {
+	results, err := bs.RAGStore.SearchConversations(ctx, query, limit)
+	if err == nil {
+		return results, nil
+	}
+	return bs.searchLocal(ctx, query, limit, err)
+}
+
+// SearchConversationsPage fetches a page from the RAG server. A failed first
+// page falls back to the local index like SearchConversations; later pages do
+// not, since the index cannot continue the server's paging.
+func (bs *BufferedStore) SearchConversationsPage(ctx context.Context, req models.RAGSearchPageRequest) (*models.RAGSearchPage, error) {
+	page, err := bs.RAGStore.SearchConversationsPage(ctx, req)
+	if err == nil || req.Offset > 0 || req.Cursor != "" {
+		return page, err
+	}
+
+	results, err := bs.searchLocal(ctx, req.Query, req.Limit, err)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RAGSearchPage{Results: FilterByTime(results, req.From, req.To)}, nil
+}
+
+// searchLocal answers a failed search from the local index when the user has
+// conversations indexed, and returns searchErr otherwise
+func (bs *BufferedStore) searchLocal(ctx context.Context, query string, limit int, searchErr error) ([]models.RAGConversationSearchResult, error) {
+	userID := util.UserIDFromContext(ctx)
+	if userID == "" || bs.index.Len(userID) == 0 || errors.Is(ctx.Err(), context.Canceled) {
+		return nil, searchErr
+	}
+
+	bs.logger.WithContext(ctx).Warn("Failed to search conversations, searching local index", searchErr)
+	metrics.RAGLocalSearches.Add(1)
+
+	// The search deadline may be nearly spent; embedding the query gets its own
+	return bs.index.Search(userID, query, bs.embedder.Embed(context.WithoutCancel(ctx), query), limit), nil
+}
+
+// DeleteUserData drops the user's locally indexed conversations and erases the user on the wrapped store
+func (bs *BufferedStore) DeleteUserData(ctx context.Context, userID string) error {
+	bs.index.Remove(userID)
+	return bs.RAGStore.DeleteUserData(ctx, userID)
+}
+
+// conversationText joins the message contents for embedding
+func conversationText(messages []models.RAGMessage) string {
+	contents := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		contents = append(contents, msg.Content)
+	}
+	return strings.Join(contents, "\n")
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"fmt"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// ValidateMetadata checks conversation metadata against the current schema before
+// it is saved. Nil metadata is valid; SchemaVersion is not checked since the RAG
+// client stamps it.
+func ValidateMetadata(metadata *models.RAGMetadata) error {
+	if metadata == nil {
+		return nil
+	}
+
+	switch metadata.Type {
+	case util.ConversationTypeChat, util.ConversationTypeMemoryEvaluation:
+	default:
+		return fmt.Errorf("invalid_metadata: unknown conversation type %q", metadata.Type)
+	}
+
+	if metadata.ConversationScore < util.MinScore || metadata.ConversationScore > util.MaxScore {
+		return fmt.Errorf("invalid_metadata: conversation_score %d must be between %d and %d", metadata.ConversationScore, util.MinScore, util.MaxScore)
+	}
+	if metadata.RetentionScore < 0 || metadata.RetentionScore > 1 {
+		return fmt.Errorf("invalid_metadata: retention_score %.2f must be between 0 and 1", metadata.RetentionScore)
+	}
+	if metadata.MoodScore != nil && (*metadata.MoodScore < util.MinScore || *metadata.MoodScore > util.MaxScore) {
+		return fmt.Errorf("invalid_metadata: mood_score %d must be between %d and %d", *metadata.MoodScore, util.MinScore, util.MaxScore)
+	}
+	if metadata.Emotion != "" && !util.IsEmotion(metadata.Emotion) {
+		return fmt.Errorf("invalid_metadata: unknown emotion %q", metadata.Emotion)
+	}
+
+	if len(metadata.Topics) > util.MaxConversationTopics {
+		return fmt.Errorf("invalid_metadata: at most %d topics, got %d", util.MaxConversationTopics, len(metadata.Topics))
+	}
+	for _, topic := range metadata.Topics {
+		if !util.IsConversationTopic(topic) {
+			return fmt.Errorf("invalid_metadata: unknown topic %q", topic)
+		}
+	}
+
+	switch metadata.Language {
+	case "", util.LanguageKorean, util.LanguageEnglish:
+	default:
+		return fmt.Errorf("invalid_metadata: unknown language %q", metadata.Language)
+	}
+
+	switch metadata.SourceChannel {
+	case "", util.SourceChannelPhone, util.SourceChannelApp, util.SourceChannelAPI:
+	default:
+		return fmt.Errorf("invalid_metadata: unknown source channel %q", metadata.SourceChannel)
+	}
+	return nil
+}
+
+// stampMetadata returns a copy of the request whose metadata carries the current
+// schema version, leaving the caller's request unchanged
+func stampMetadata(req *models.RAGConversationSaveRequest) *models.RAGConversationSaveRequest {
+	if req.Metadata == nil {
+		return req
+	}
+	stamped := *req
+	metadata := *req.Metadata
+	metadata.SchemaVersion = util.RAGMetadataSchemaVersion
+	stamped.Metadata = &metadata
+	return &stamped
+}
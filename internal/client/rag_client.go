@@ -8,38 +8,141 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	"llm/internal/chaos"
 	"llm/internal/config"
+	"llm/internal/httptransport"
 	"llm/internal/models"
+	"llm/internal/textutil"
+	"llm/internal/util"
 )
 
 // RAGClient handles communication with RAG server
 type RAGClient struct {
 	baseURL    string
+	routes     map[string]config.RAGRoute
+	userRoutes []config.RAGUserRoute
 	httpClient *http.Client
-	timeout    time.Duration
+	timeout    time.Duration            // request timeout for endpoints without an override
+	timeouts   map[string]time.Duration // request timeouts by endpoint
 }
 
+// RAGNamespaceHeader selects the namespace on a shared RAG instance
+const RAGNamespaceHeader = "X-RAG-Namespace"
+
 // NewRAGClient creates a new RAG client
 func NewRAGClient(cfg *config.Config) *RAGClient {
 	return &RAGClient{
-		baseURL: cfg.RAGServerURL,
+		baseURL:    cfg.RAGServerURL,
+		routes:     cfg.RAGRoutes,
+		userRoutes: cfg.RAGUserRoutes,
+		// Timeouts are applied per request, since saves may take longer than searches
 		httpClient: &http.Client{
-			Timeout: cfg.RAGServerTimeout,
+			Transport: &requestIDTransport{base: chaos.WrapTransport(cfg, chaos.TargetRAG, httptransport.Instrument(httptransport.New(httptransport.Options{
+				MaxIdleConns:          cfg.RAGMaxIdleConnsPerHost * (len(cfg.RAGRoutes) + 1),
+				MaxIdleConnsPerHost:   cfg.RAGMaxIdleConnsPerHost,
+				IdleConnTimeout:       90 * time.Second,
+				KeepAlive:             30 * time.Second,
+				DialTimeout:           cfg.RAGConnectTimeout,
+				ResponseHeaderTimeout: cfg.RAGReadTimeout,
+			}), httptransport.TargetRAG))},
 		},
-		timeout: cfg.RAGServerTimeout,
+		timeout:  cfg.RAGServerTimeout,
+		timeouts: cfg.RAGEndpointTimeouts,
+	}
+}
+
+// withTimeout bounds a call to endpoint by its configured timeout
+func (rc *RAGClient) withTimeout(ctx context.Context, endpoint string) (context.Context, context.CancelFunc) {
+	timeout, ok := rc.timeouts[endpoint]
+	if !ok {
+		timeout = rc.timeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// requestIDTransport forwards the request ID carried by the request context to the RAG server
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if requestID := util.RequestIDFromContext(req.Context()); requestID != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(util.RequestIDHeader, requestID)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// routeFor resolves the RAG instance a user's data lives on. When userID is
+// empty the user carried by ctx is used; unrouted users go to the default server.
+// A call for no user is refused rather than sent to the default server, where it
+// would read or write outside the user's route.
+func (rc *RAGClient) routeFor(ctx context.Context, userID string) (config.RAGRoute, error) {
+	if userID == "" {
+		userID = util.UserIDFromContext(ctx)
+	}
+	if userID == "" {
+		return config.RAGRoute{}, fmt.Errorf("rag_unroutable: no user to route the request by")
+	}
+	for _, rule := range rc.userRoutes {
+		if strings.HasPrefix(userID, rule.Prefix) {
+			return rc.routes[rule.Route], nil
+		}
+	}
+	return rc.defaultRoute(), nil
+}
+
+// defaultRoute is the default server, for calls that concern no user's data
+func (rc *RAGClient) defaultRoute() config.RAGRoute {
+	return config.RAGRoute{BaseURL: rc.baseURL}
+}
+
+// applyRoute tags the request with the route's namespace, if any
+func applyRoute(req *http.Request, route config.RAGRoute) {
+	if route.Namespace != "" {
+		req.Header.Set(RAGNamespaceHeader, route.Namespace)
 	}
 }
 
 // SearchConversations searches for similar conversations in RAG server
 func (rc *RAGClient) SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error) {
-	baseURL := fmt.Sprintf("%s/api/rag/conversation/search", rc.baseURL)
+	page, err := rc.SearchConversationsPage(ctx, models.RAGSearchPageRequest{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
+
+// SearchConversationsPage fetches one page of conversation search results
+func (rc *RAGClient) SearchConversationsPage(ctx context.Context, pageReq models.RAGSearchPageRequest) (*models.RAGSearchPage, error) {
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointSearch)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	baseURL := fmt.Sprintf("%s/api/rag/conversation/search", route.BaseURL)
 
 	// Build query parameters with proper URL encoding
 	params := url.Values{}
-	params.Add("query", query)
-	params.Add("top_k", fmt.Sprintf("%d", limit))
+	params.Add("query", pageReq.Query)
+	params.Add("top_k", fmt.Sprintf("%d", pageReq.Limit))
+	if pageReq.Cursor != "" {
+		params.Add("cursor", pageReq.Cursor)
+	} else if pageReq.Offset > 0 {
+		params.Add("offset", fmt.Sprintf("%d", pageReq.Offset))
+	}
+	if !pageReq.From.IsZero() {
+		params.Add("from", pageReq.From.Format(time.RFC3339))
+	}
+	if !pageReq.To.IsZero() {
+		params.Add("to", pageReq.To.Format(time.RFC3339))
+	}
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
 	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
@@ -47,6 +150,8 @@ func (rc *RAGClient) SearchConversations(ctx context.Context, query string, limi
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	applyRoute(req, route)
+
 	resp, err := rc.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search conversations: %w", err)
@@ -59,15 +164,13 @@ func (rc *RAGClient) SearchConversations(ctx context.Context, query string, limi
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search conversations failed: status %d, body: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("search conversations failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
 	}
 
 	var apiResp struct {
-		Success bool `json:"success"`
-		Data    struct {
-			Results []models.RAGConversationSearchResult `json:"results"`
-		} `json:"data"`
-		Error *struct {
+		Success bool                 `json:"success"`
+		Data    models.RAGSearchPage `json:"data"`
+		Error   *struct {
 			Code    string `json:"code"`
 			Message string `json:"message"`
 		} `json:"error"`
@@ -84,12 +187,27 @@ func (rc *RAGClient) SearchConversations(ctx context.Context, query string, limi
 		return nil, fmt.Errorf("search failed: unknown error")
 	}
 
-	return apiResp.Data.Results, nil
+	// Servers that predate date filters ignore from/to
+	apiResp.Data.Results = FilterByTime(apiResp.Data.Results, pageReq.From, pageReq.To)
+	return &apiResp.Data, nil
 }
 
-// SaveConversation saves a conversation to RAG server
+// SaveConversation saves a conversation to RAG server. Metadata is validated
+// and stamped with the current schema version.
 func (rc *RAGClient) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
-	url := fmt.Sprintf("%s/api/rag/conversation/store", rc.baseURL)
+	if err := ValidateMetadata(req.Metadata); err != nil {
+		return "", err
+	}
+	req = stampMetadata(req)
+
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointSave)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, "")
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/api/rag/conversation/store", route.BaseURL)
 
 	data, err := json.Marshal(req)
 	if err != nil {
@@ -102,6 +220,7 @@ func (rc *RAGClient) SaveConversation(ctx context.Context, req *models.RAGConver
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	applyRoute(httpReq, route)
 
 	resp, err := rc.httpClient.Do(httpReq)
 	if err != nil {
@@ -115,7 +234,7 @@ func (rc *RAGClient) SaveConversation(ctx context.Context, req *models.RAGConver
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("save conversation failed: status %d, body: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("save conversation failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
 	}
 
 	var apiResp struct {
@@ -145,13 +264,19 @@ func (rc *RAGClient) SaveConversation(ctx context.Context, req *models.RAGConver
 
 // Health checks if RAG server is healthy
 func (rc *RAGClient) Health(ctx context.Context) (bool, error) {
-	url := fmt.Sprintf("%s/api/rag/health", rc.baseURL)
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointHealth)
+	defer cancel()
+
+	route := rc.defaultRoute()
+	url := fmt.Sprintf("%s/api/rag/health", route.BaseURL)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	applyRoute(req, route)
+
 	resp, err := rc.httpClient.Do(req)
 	if err != nil {
 		return false, fmt.Errorf("failed to check health: %w", err)
@@ -163,7 +288,14 @@ func (rc *RAGClient) Health(ctx context.Context) (bool, error) {
 
 // CreatePersonalInfo creates a new personal information entry
 func (rc *RAGClient) CreatePersonalInfo(ctx context.Context, req *models.PersonalInfoCreateRequest) (string, error) {
-	url := fmt.Sprintf("%s/api/rag/personal-info", rc.baseURL)
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointPersonalInfo)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, req.UserID)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/api/rag/personal-info", route.BaseURL)
 
 	data, err := json.Marshal(req)
 	if err != nil {
@@ -176,6 +308,7 @@ func (rc *RAGClient) CreatePersonalInfo(ctx context.Context, req *models.Persona
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	applyRoute(httpReq, route)
 
 	resp, err := rc.httpClient.Do(httpReq)
 	if err != nil {
@@ -189,7 +322,7 @@ func (rc *RAGClient) CreatePersonalInfo(ctx context.Context, req *models.Persona
 	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("create personal info failed: status %d, body: %s", resp.StatusCode, string(body))
+		return "", fmt.Errorf("create personal info failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
 	}
 
 	var apiResp struct {
@@ -221,13 +354,22 @@ func (rc *RAGClient) CreatePersonalInfo(ctx context.Context, req *models.Persona
 
 // GetPersonalInfoByUser retrieves all personal information for a user
 func (rc *RAGClient) GetPersonalInfoByUser(ctx context.Context, userID string) (*models.PersonalInfoListResponse, error) {
-	url := fmt.Sprintf("%s/api/rag/personal-info/user/%s", rc.baseURL, userID)
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointPersonalInfo)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/api/rag/personal-info/user/%s", route.BaseURL, userID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	applyRoute(req, route)
+
 	resp, err := rc.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get personal info: %w", err)
@@ -240,7 +382,7 @@ func (rc *RAGClient) GetPersonalInfoByUser(ctx context.Context, userID string) (
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get personal info failed: status %d, body: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("get personal info failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
 	}
 
 	var apiResp struct {
@@ -270,13 +412,22 @@ func (rc *RAGClient) GetPersonalInfoByUser(ctx context.Context, userID string) (
 
 // GetIncorrectQuizAttempts retrieves incorrect quiz attempts for a user
 func (rc *RAGClient) GetIncorrectQuizAttempts(ctx context.Context, userID string, limit int) (*models.IncorrectQuizAttemptsResponse, error) {
-	url := fmt.Sprintf("%s/api/rag/quiz-attempts/incorrect?user_id=%s&limit=%d", rc.baseURL, userID, limit)
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointIncorrectAttempts)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/api/rag/quiz-attempts/incorrect?user_id=%s&limit=%d", route.BaseURL, userID, limit)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	applyRoute(req, route)
+
 	resp, err := rc.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get incorrect attempts: %w", err)
@@ -289,7 +440,7 @@ func (rc *RAGClient) GetIncorrectQuizAttempts(ctx context.Context, userID string
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get incorrect attempts failed: status %d, body: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("get incorrect attempts failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
 	}
 
 	var apiResp struct {
@@ -323,3 +474,101 @@ func (rc *RAGClient) GetIncorrectQuizAttempts(ctx context.Context, userID string
 		UserID: apiResp.Data.UserID,
 	}, nil
 }
+
+// ListConversationsByUser retrieves every stored conversation of a user, newest first
+func (rc *RAGClient) ListConversationsByUser(ctx context.Context, userID string) ([]models.RAGConversationSearchResult, error) {
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointUserConversations)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/api/rag/conversation/user/%s", route.BaseURL, userID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	applyRoute(req, route)
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list conversations failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
+	}
+
+	var apiResp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Conversations []models.RAGConversationSearchResult `json:"conversations"`
+		} `json:"data"`
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if !apiResp.Success {
+		if apiResp.Error != nil {
+			return nil, fmt.Errorf("list failed: %s - %s", apiResp.Error.Code, apiResp.Error.Message)
+		}
+		return nil, fmt.Errorf("list failed: unknown error")
+	}
+
+	return apiResp.Data.Conversations, nil
+}
+
+// DeleteUserData erases everything the RAG server stores for a user:
+// conversations, personal info and quiz attempts
+func (rc *RAGClient) DeleteUserData(ctx context.Context, userID string) error {
+	ctx, cancel := rc.withTimeout(ctx, util.RAGEndpointUserDelete)
+	defer cancel()
+
+	route, err := rc.routeFor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/api/rag/user/%s", route.BaseURL, userID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	applyRoute(req, route)
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete user data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Deleting a user the RAG server has never seen is not an error
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("delete user data failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
+	}
+	return nil
+}
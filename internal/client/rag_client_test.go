@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"llm/internal/config"
+	"llm/internal/util"
+)
+
+func TestRouteFor(t *testing.T) {
+	rc := &RAGClient{
+		baseURL:    "http://rag-default",
+		routes:     map[string]config.RAGRoute{"eu": {Name: "eu", BaseURL: "http://rag-eu", Namespace: "eu"}},
+		userRoutes: []config.RAGUserRoute{{Prefix: "eu-", Route: "eu"}},
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		ctxUserID   string
+		wantBaseURL string
+		wantErr     bool
+	}{
+		{name: "routed user", userID: "eu-1", wantBaseURL: "http://rag-eu"},
+		{name: "unrouted user goes to the default server", userID: "kr-1", wantBaseURL: "http://rag-default"},
+		{name: "user from the context", ctxUserID: "eu-2", wantBaseURL: "http://rag-eu"},
+		{name: "no user is refused", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ctxUserID != "" {
+				ctx = util.WithUserID(ctx, tt.ctxUserID)
+			}
+
+			route, err := rc.routeFor(ctx, tt.userID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("routeFor() = %+v, want an error", route)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("routeFor() error = %v", err)
+			}
+			if route.BaseURL != tt.wantBaseURL {
+				t.Errorf("routeFor() base URL = %s, want %s", route.BaseURL, tt.wantBaseURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+// Package ragtest provides doubles for client.RAGStore so services can be
+// exercised without a live RAG server: Fake is an in-memory store, and
+// Recorder/Replayer capture and replay real RAG responses as fixtures.
+package ragtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/client"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// RAGStore method names, used to inject errors and count calls
+const (
+	MethodSearchConversations      = "SearchConversations"
+	MethodSearchConversationsPage  = "SearchConversationsPage"
+	MethodSaveConversation         = "SaveConversation"
+	MethodHealth                   = "Health"
+	MethodCreatePersonalInfo       = "CreatePersonalInfo"
+	MethodGetPersonalInfoByUser    = "GetPersonalInfoByUser"
+	MethodGetIncorrectQuizAttempts = "GetIncorrectQuizAttempts"
+	MethodListConversationsByUser  = "ListConversationsByUser"
+	MethodDeleteUserData           = "DeleteUserData"
+)
+
+// Fake is an in-memory RAGStore. Conversations are stored per user (the user
+// carried by ctx, like RAGClient routing) and searched by keyword overlap.
+type Fake struct {
+	conversations map[string][]models.RAGConversationSearchResult
+	personalInfo  map[string][]models.PersonalInfoResponse
+	attempts      map[string][]models.IncorrectQuizAttempt
+	errors        map[string]error
+	calls         map[string]int
+	mutex         sync.Mutex
+}
+
+var _ client.RAGStore = (*Fake)(nil)
+
+// NewFake creates an empty fake store
+func NewFake() *Fake {
+	return &Fake{
+		conversations: make(map[string][]models.RAGConversationSearchResult),
+		personalInfo:  make(map[string][]models.PersonalInfoResponse),
+		attempts:      make(map[string][]models.IncorrectQuizAttempt),
+		errors:        make(map[string]error),
+		calls:         make(map[string]int),
+	}
+}
+
+// AddConversation seeds a conversation for the user
+func (f *Fake) AddConversation(userID string, conv models.RAGConversationSearchResult) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if conv.ConversationID == "" {
+		conv.ConversationID = uuid.New().String()
+	}
+	if conv.Timestamp.IsZero() {
+		conv.Timestamp = time.Now()
+	}
+	f.conversations[userID] = append(f.conversations[userID], conv)
+}
+
+// AddIncorrectAttempt seeds an incorrect quiz attempt for the user
+func (f *Fake) AddIncorrectAttempt(userID string, attempt models.IncorrectQuizAttempt) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.attempts[userID] = append(f.attempts[userID], attempt)
+}
+
+// SetError makes every later call to method fail with err; a nil err clears it
+func (f *Fake) SetError(method string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err == nil {
+		delete(f.errors, method)
+		return
+	}
+	f.errors[method] = err
+}
+
+// Calls returns how many times method has been called
+func (f *Fake) Calls(method string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.calls[method]
+}
+
+// begin counts the call and returns the injected error, if any. The caller must hold the mutex.
+func (f *Fake) begin(method string) error {
+	f.calls[method]++
+	return f.errors[method]
+}
+
+// SearchConversations returns the user's conversations, best keyword match
+// first and newest first among equal matches
+func (f *Fake) SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodSearchConversations); err != nil {
+		return nil, err
+	}
+
+	results := f.search(ctx, query)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// SearchConversationsPage returns a page of SearchConversations results. The
+// cursor of the next page is the offset it starts at.
+func (f *Fake) SearchConversationsPage(ctx context.Context, req models.RAGSearchPageRequest) (*models.RAGSearchPage, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodSearchConversationsPage); err != nil {
+		return nil, err
+	}
+
+	results := client.FilterByTime(f.search(ctx, req.Query), req.From, req.To)
+	start := req.Offset
+	if req.Cursor != "" {
+		offset, err := strconv.Atoi(req.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %q", req.Cursor)
+		}
+		start = offset
+	}
+	start = min(start, len(results))
+	end := len(results)
+	if req.Limit > 0 {
+		end = min(start+req.Limit, len(results))
+	}
+
+	page := &models.RAGSearchPage{Results: results[start:end]}
+	if end < len(results) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+// search scores and sorts the user's conversations. The caller must hold the mutex.
+func (f *Fake) search(ctx context.Context, query string) []models.RAGConversationSearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	results := []models.RAGConversationSearchResult{}
+	for _, conv := range f.conversations[util.UserIDFromContext(ctx)] {
+		conv.Score = matchScore(conv, terms)
+		results = append(results, conv)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	return results
+}
+
+// matchScore is the fraction of query terms that appear in the conversation
+func matchScore(conv models.RAGConversationSearchResult, terms []string) float32 {
+	if len(terms) == 0 {
+		return 0
+	}
+	var text strings.Builder
+	for _, msg := range conv.Messages {
+		text.WriteString(strings.ToLower(msg.Content))
+		text.WriteString(" ")
+	}
+	matched := 0
+	for _, term := range terms {
+		if strings.Contains(text.String(), term) {
+			matched++
+		}
+	}
+	return float32(matched) / float32(len(terms))
+}
+
+// SaveConversation stores the conversation for the user carried by ctx,
+// replacing any stored conversation with the same ID
+func (f *Fake) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodSaveConversation); err != nil {
+		return "", err
+	}
+
+	id := req.ConversationID
+	if id == "" {
+		id = uuid.New().String()
+	}
+	conv := models.RAGConversationSearchResult{
+		ConversationID: id,
+		Timestamp:      time.Now(),
+		Messages:       append([]models.RAGMessage(nil), req.Messages...),
+	}
+	if req.Metadata != nil {
+		metadata := *req.Metadata
+		conv.Metadata = &metadata
+	}
+
+	userID := util.UserIDFromContext(ctx)
+	for i, existing := range f.conversations[userID] {
+		if existing.ConversationID == id {
+			conv.Timestamp = existing.Timestamp
+			f.conversations[userID][i] = conv
+			return id, nil
+		}
+	}
+	f.conversations[userID] = append(f.conversations[userID], conv)
+	return id, nil
+}
+
+// Health reports the fake as healthy unless an error is injected
+func (f *Fake) Health(ctx context.Context) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodHealth); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreatePersonalInfo stores a personal information entry
+func (f *Fake) CreatePersonalInfo(ctx context.Context, req *models.PersonalInfoCreateRequest) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodCreatePersonalInfo); err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	item := models.PersonalInfoResponse{
+		ID:         uuid.New().String(),
+		UserID:     req.UserID,
+		Content:    req.Content,
+		Category:   req.Category,
+		Importance: req.Importance,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	f.personalInfo[req.UserID] = append(f.personalInfo[req.UserID], item)
+	return item.ID, nil
+}
+
+// GetPersonalInfoByUser returns the user's personal information entries
+func (f *Fake) GetPersonalInfoByUser(ctx context.Context, userID string) (*models.PersonalInfoListResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodGetPersonalInfoByUser); err != nil {
+		return nil, err
+	}
+
+	items := append([]models.PersonalInfoResponse{}, f.personalInfo[userID]...)
+	return &models.PersonalInfoListResponse{
+		Items:  items,
+		Total:  len(items),
+		UserID: userID,
+	}, nil
+}
+
+// GetIncorrectQuizAttempts returns up to limit of the user's seeded incorrect attempts
+func (f *Fake) GetIncorrectQuizAttempts(ctx context.Context, userID string, limit int) (*models.IncorrectQuizAttemptsResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodGetIncorrectQuizAttempts); err != nil {
+		return nil, err
+	}
+
+	items := append([]models.IncorrectQuizAttempt{}, f.attempts[userID]...)
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return &models.IncorrectQuizAttemptsResponse{
+		Items:  items,
+		Total:  len(items),
+		UserID: userID,
+	}, nil
+}
+
+// ListConversationsByUser returns all of the user's conversations, newest first
+func (f *Fake) ListConversationsByUser(ctx context.Context, userID string) ([]models.RAGConversationSearchResult, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodListConversationsByUser); err != nil {
+		return nil, err
+	}
+
+	results := append([]models.RAGConversationSearchResult{}, f.conversations[userID]...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
+	return results, nil
+}
+
+// DeleteUserData removes the user's conversations, personal info and incorrect attempts
+func (f *Fake) DeleteUserData(ctx context.Context, userID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err := f.begin(MethodDeleteUserData); err != nil {
+		return err
+	}
+
+	delete(f.conversations, userID)
+	delete(f.personalInfo, userID)
+	delete(f.attempts, userID)
+	return nil
+}
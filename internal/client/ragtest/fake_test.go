@@ -0,0 +1,260 @@
+package ragtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+func conversation(id string, timestamp time.Time, contents ...string) models.RAGConversationSearchResult {
+	conv := models.RAGConversationSearchResult{ConversationID: id, Timestamp: timestamp}
+	for _, content := range contents {
+		conv.Messages = append(conv.Messages, models.RAGMessage{Role: "user", Content: content})
+	}
+	return conv
+}
+
+func conversationIDs(results []models.RAGConversationSearchResult) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.ConversationID
+	}
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFakeSearchConversations(t *testing.T) {
+	base := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake()
+	fake.AddConversation("user-1", conversation("old-songpyeon", base, "추석에 송편을 빚었어"))
+	fake.AddConversation("user-1", conversation("new-songpyeon", base.AddDate(0, 0, 1), "송편 맛있었지"))
+	fake.AddConversation("user-1", conversation("walk", base.AddDate(0, 0, 2), "아침에 산책했어"))
+	fake.AddConversation("user-2", conversation("other-user", base, "추석 송편"))
+
+	tests := []struct {
+		name   string
+		userID string
+		query  string
+		limit  int
+		want   []string
+	}{
+		{
+			name:   "best match first",
+			userID: "user-1",
+			query:  "추석 송편",
+			want:   []string{"old-songpyeon", "new-songpyeon", "walk"},
+		},
+		{
+			name:   "newest first among equal matches",
+			userID: "user-1",
+			query:  "송편",
+			want:   []string{"new-songpyeon", "old-songpyeon", "walk"},
+		},
+		{
+			name:   "limit",
+			userID: "user-1",
+			query:  "산책",
+			limit:  1,
+			want:   []string{"walk"},
+		},
+		{
+			name:   "only the user's conversations",
+			userID: "user-2",
+			query:  "송편",
+			want:   []string{"other-user"},
+		},
+		{
+			name:   "unknown user",
+			userID: "user-3",
+			query:  "송편",
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := util.WithUserID(context.Background(), tt.userID)
+			results, err := fake.SearchConversations(ctx, tt.query, tt.limit)
+			if err != nil {
+				t.Fatalf("SearchConversations() error = %v", err)
+			}
+			if got := conversationIDs(results); !equalIDs(got, tt.want) {
+				t.Errorf("SearchConversations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeSearchConversationsPage(t *testing.T) {
+	base := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake()
+	for i, id := range []string{"day-0", "day-1", "day-2", "day-3"} {
+		fake.AddConversation("user-1", conversation(id, base.AddDate(0, 0, i), "산책"))
+	}
+	ctx := util.WithUserID(context.Background(), "user-1")
+
+	tests := []struct {
+		name       string
+		req        models.RAGSearchPageRequest
+		want       []string
+		wantCursor string
+		wantErr    bool
+	}{
+		{
+			name:       "first page",
+			req:        models.RAGSearchPageRequest{Query: "산책", Limit: 2},
+			want:       []string{"day-3", "day-2"},
+			wantCursor: "2",
+		},
+		{
+			name: "last page",
+			req:  models.RAGSearchPageRequest{Query: "산책", Limit: 2, Cursor: "2"},
+			want: []string{"day-1", "day-0"},
+		},
+		{
+			name: "time window",
+			req:  models.RAGSearchPageRequest{Query: "산책", From: base.AddDate(0, 0, 1), To: base.AddDate(0, 0, 3)},
+			want: []string{"day-2", "day-1"},
+		},
+		{
+			name: "offset past the end",
+			req:  models.RAGSearchPageRequest{Query: "산책", Offset: 10},
+			want: []string{},
+		},
+		{
+			name:    "invalid cursor",
+			req:     models.RAGSearchPageRequest{Query: "산책", Cursor: "next"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := fake.SearchConversationsPage(ctx, tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("SearchConversationsPage() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SearchConversationsPage() error = %v", err)
+			}
+			if got := conversationIDs(page.Results); !equalIDs(got, tt.want) {
+				t.Errorf("SearchConversationsPage() = %v, want %v", got, tt.want)
+			}
+			if page.NextCursor != tt.wantCursor {
+				t.Errorf("NextCursor = %q, want %q", page.NextCursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestFakeSaveConversation(t *testing.T) {
+	fake := NewFake()
+	ctx := util.WithUserID(context.Background(), "user-1")
+
+	id, err := fake.SaveConversation(ctx, &models.RAGConversationSaveRequest{
+		Messages: []models.RAGMessage{{Role: "user", Content: "손주가 왔어"}},
+	})
+	if err != nil {
+		t.Fatalf("SaveConversation() error = %v", err)
+	}
+	if id == "" {
+		t.Fatal("SaveConversation() returned an empty ID")
+	}
+
+	// Saving under the same ID replaces the conversation instead of adding one
+	if _, err := fake.SaveConversation(ctx, &models.RAGConversationSaveRequest{
+		ConversationID: id,
+		Messages:       []models.RAGMessage{{Role: "user", Content: "손녀가 왔어"}},
+	}); err != nil {
+		t.Fatalf("SaveConversation() error = %v", err)
+	}
+
+	results, err := fake.ListConversationsByUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("ListConversationsByUser() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Messages[0].Content != "손녀가 왔어" {
+		t.Errorf("ListConversationsByUser() = %+v, want the replaced conversation only", results)
+	}
+}
+
+func TestFakePersonalInfoAndAttempts(t *testing.T) {
+	fake := NewFake()
+	ctx := context.Background()
+
+	if _, err := fake.CreatePersonalInfo(ctx, &models.PersonalInfoCreateRequest{UserID: "user-1", Content: "혈압약 복용", Category: "medical", Importance: "high"}); err != nil {
+		t.Fatalf("CreatePersonalInfo() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		fake.AddIncorrectAttempt("user-1", models.IncorrectQuizAttempt{})
+	}
+
+	profile, err := fake.GetPersonalInfoByUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("GetPersonalInfoByUser() error = %v", err)
+	}
+	if profile.Total != 1 || profile.Items[0].Content != "혈압약 복용" {
+		t.Errorf("GetPersonalInfoByUser() = %+v, want the created item", profile)
+	}
+
+	attempts, err := fake.GetIncorrectQuizAttempts(ctx, "user-1", 2)
+	if err != nil {
+		t.Fatalf("GetIncorrectQuizAttempts() error = %v", err)
+	}
+	if attempts.Total != 2 {
+		t.Errorf("GetIncorrectQuizAttempts() total = %d, want 2", attempts.Total)
+	}
+
+	if err := fake.DeleteUserData(ctx, "user-1"); err != nil {
+		t.Fatalf("DeleteUserData() error = %v", err)
+	}
+	profile, _ = fake.GetPersonalInfoByUser(ctx, "user-1")
+	attempts, _ = fake.GetIncorrectQuizAttempts(ctx, "user-1", 0)
+	if profile.Total != 0 || attempts.Total != 0 {
+		t.Errorf("after DeleteUserData profile total = %d, attempts total = %d, want 0", profile.Total, attempts.Total)
+	}
+}
+
+func TestFakeErrorsAndCalls(t *testing.T) {
+	fake := NewFake()
+	ctx := util.WithUserID(context.Background(), "user-1")
+	unavailable := errors.New("rag unavailable")
+
+	fake.SetError(MethodSearchConversations, unavailable)
+	if _, err := fake.SearchConversations(ctx, "송편", 5); !errors.Is(err, unavailable) {
+		t.Errorf("SearchConversations() error = %v, want %v", err, unavailable)
+	}
+	if healthy, err := fake.Health(ctx); !healthy || err != nil {
+		t.Errorf("Health() = %v, %v, want an error only on the failing method", healthy, err)
+	}
+
+	fake.SetError(MethodSearchConversations, nil)
+	if _, err := fake.SearchConversations(ctx, "송편", 5); err != nil {
+		t.Errorf("SearchConversations() error = %v after clearing it", err)
+	}
+
+	if got := fake.Calls(MethodSearchConversations); got != 2 {
+		t.Errorf("Calls(%s) = %d, want 2", MethodSearchConversations, got)
+	}
+	if got := fake.Calls(MethodSaveConversation); got != 0 {
+		t.Errorf("Calls(%s) = %d, want 0", MethodSaveConversation, got)
+	}
+}
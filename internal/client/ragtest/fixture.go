@@ -0,0 +1,284 @@
+package ragtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"llm/internal/client"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// Interaction is one recorded RAGStore call. Key identifies the call by its
+// user and arguments; Response holds the JSON-encoded result.
+type Interaction struct {
+	Method   string          `json:"method"`
+	Key      string          `json:"key"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// interactionKey identifies a call. Save requests are keyed by user only since
+// they carry generated conversation IDs.
+func interactionKey(ctx context.Context, method string, args ...interface{}) string {
+	key := fmt.Sprintf("%s user=%s", method, util.UserIDFromContext(ctx))
+	for _, arg := range args {
+		key += fmt.Sprintf(" %v", arg)
+	}
+	return key
+}
+
+// Recorder wraps a RAGStore (usually a live RAGClient) and records every call
+// so it can be saved as a fixture and replayed with Replayer
+type Recorder struct {
+	store        client.RAGStore
+	interactions []Interaction
+	mutex        sync.Mutex
+}
+
+var _ client.RAGStore = (*Recorder)(nil)
+
+// NewRecorder creates a recorder around store
+func NewRecorder(store client.RAGStore) *Recorder {
+	return &Recorder{store: store}
+}
+
+func (r *Recorder) record(method, key string, response interface{}, err error) {
+	interaction := Interaction{Method: method, Key: key}
+	if err != nil {
+		interaction.Error = err.Error()
+	} else if data, marshalErr := json.Marshal(response); marshalErr == nil {
+		interaction.Response = data
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.interactions = append(r.interactions, interaction)
+}
+
+// Interactions returns the calls recorded so far, in order
+func (r *Recorder) Interactions() []Interaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded calls to a JSON fixture file
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// SearchConversations records a search
+func (r *Recorder) SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error) {
+	results, err := r.store.SearchConversations(ctx, query, limit)
+	r.record(MethodSearchConversations, interactionKey(ctx, MethodSearchConversations, query, limit), results, err)
+	return results, err
+}
+
+// SearchConversationsPage records a page of search results
+func (r *Recorder) SearchConversationsPage(ctx context.Context, req models.RAGSearchPageRequest) (*models.RAGSearchPage, error) {
+	page, err := r.store.SearchConversationsPage(ctx, req)
+	r.record(MethodSearchConversationsPage, interactionKey(ctx, MethodSearchConversationsPage, req.Query, req.Limit, req.Offset, req.Cursor, req.From.Unix(), req.To.Unix()), page, err)
+	return page, err
+}
+
+// SaveConversation records a save
+func (r *Recorder) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	id, err := r.store.SaveConversation(ctx, req)
+	r.record(MethodSaveConversation, interactionKey(ctx, MethodSaveConversation), id, err)
+	return id, err
+}
+
+// Health records a health check
+func (r *Recorder) Health(ctx context.Context) (bool, error) {
+	healthy, err := r.store.Health(ctx)
+	r.record(MethodHealth, interactionKey(ctx, MethodHealth), healthy, err)
+	return healthy, err
+}
+
+// CreatePersonalInfo records a personal information entry creation
+func (r *Recorder) CreatePersonalInfo(ctx context.Context, req *models.PersonalInfoCreateRequest) (string, error) {
+	id, err := r.store.CreatePersonalInfo(ctx, req)
+	r.record(MethodCreatePersonalInfo, interactionKey(ctx, MethodCreatePersonalInfo, req.UserID, req.Category), id, err)
+	return id, err
+}
+
+// GetPersonalInfoByUser records a personal information lookup
+func (r *Recorder) GetPersonalInfoByUser(ctx context.Context, userID string) (*models.PersonalInfoListResponse, error) {
+	profile, err := r.store.GetPersonalInfoByUser(ctx, userID)
+	r.record(MethodGetPersonalInfoByUser, interactionKey(ctx, MethodGetPersonalInfoByUser, userID), profile, err)
+	return profile, err
+}
+
+// GetIncorrectQuizAttempts records an incorrect attempts lookup
+func (r *Recorder) GetIncorrectQuizAttempts(ctx context.Context, userID string, limit int) (*models.IncorrectQuizAttemptsResponse, error) {
+	attempts, err := r.store.GetIncorrectQuizAttempts(ctx, userID, limit)
+	r.record(MethodGetIncorrectQuizAttempts, interactionKey(ctx, MethodGetIncorrectQuizAttempts, userID, limit), attempts, err)
+	return attempts, err
+}
+
+// ListConversationsByUser records a conversation listing
+func (r *Recorder) ListConversationsByUser(ctx context.Context, userID string) ([]models.RAGConversationSearchResult, error) {
+	results, err := r.store.ListConversationsByUser(ctx, userID)
+	r.record(MethodListConversationsByUser, interactionKey(ctx, MethodListConversationsByUser, userID), results, err)
+	return results, err
+}
+
+// DeleteUserData records a user data deletion
+func (r *Recorder) DeleteUserData(ctx context.Context, userID string) error {
+	err := r.store.DeleteUserData(ctx, userID)
+	r.record(MethodDeleteUserData, interactionKey(ctx, MethodDeleteUserData, userID), err == nil, err)
+	return err
+}
+
+// Replayer serves recorded interactions. Calls are matched by key; repeated
+// calls with the same key replay their recordings in order, and the last one
+// is reused once they run out. Unrecorded calls fail.
+type Replayer struct {
+	interactions map[string][]Interaction
+	served       map[string]int
+	mutex        sync.Mutex
+}
+
+var _ client.RAGStore = (*Replayer)(nil)
+
+// NewReplayer creates a replayer from recorded interactions
+func NewReplayer(interactions []Interaction) *Replayer {
+	rp := &Replayer{
+		interactions: make(map[string][]Interaction),
+		served:       make(map[string]int),
+	}
+	for _, interaction := range interactions {
+		rp.interactions[interaction.Key] = append(rp.interactions[interaction.Key], interaction)
+	}
+	return rp
+}
+
+// LoadReplayer creates a replayer from a fixture file written by Recorder.Save
+func LoadReplayer(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return NewReplayer(interactions), nil
+}
+
+// replay decodes the next recorded response for key into out
+func (rp *Replayer) replay(key string, out interface{}) error {
+	rp.mutex.Lock()
+	recorded := rp.interactions[key]
+	if len(recorded) == 0 {
+		rp.mutex.Unlock()
+		return fmt.Errorf("ragtest: no recorded interaction for %q", key)
+	}
+	index := rp.served[key]
+	if index >= len(recorded) {
+		index = len(recorded) - 1
+	}
+	rp.served[key]++
+	interaction := recorded[index]
+	rp.mutex.Unlock()
+
+	if interaction.Error != "" {
+		return errors.New(interaction.Error)
+	}
+	if err := json.Unmarshal(interaction.Response, out); err != nil {
+		return fmt.Errorf("ragtest: failed to decode recorded response for %q: %w", key, err)
+	}
+	return nil
+}
+
+// SearchConversations replays a recorded search
+func (rp *Replayer) SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error) {
+	var results []models.RAGConversationSearchResult
+	if err := rp.replay(interactionKey(ctx, MethodSearchConversations, query, limit), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SearchConversationsPage replays a recorded page of search results
+func (rp *Replayer) SearchConversationsPage(ctx context.Context, req models.RAGSearchPageRequest) (*models.RAGSearchPage, error) {
+	var page models.RAGSearchPage
+	if err := rp.replay(interactionKey(ctx, MethodSearchConversationsPage, req.Query, req.Limit, req.Offset, req.Cursor, req.From.Unix(), req.To.Unix()), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SaveConversation replays a recorded save
+func (rp *Replayer) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	var id string
+	if err := rp.replay(interactionKey(ctx, MethodSaveConversation), &id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Health replays a recorded health check
+func (rp *Replayer) Health(ctx context.Context) (bool, error) {
+	var healthy bool
+	if err := rp.replay(interactionKey(ctx, MethodHealth), &healthy); err != nil {
+		return false, err
+	}
+	return healthy, nil
+}
+
+// CreatePersonalInfo replays a recorded personal information entry creation
+func (rp *Replayer) CreatePersonalInfo(ctx context.Context, req *models.PersonalInfoCreateRequest) (string, error) {
+	var id string
+	if err := rp.replay(interactionKey(ctx, MethodCreatePersonalInfo, req.UserID, req.Category), &id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetPersonalInfoByUser replays a recorded personal information lookup
+func (rp *Replayer) GetPersonalInfoByUser(ctx context.Context, userID string) (*models.PersonalInfoListResponse, error) {
+	var profile models.PersonalInfoListResponse
+	if err := rp.replay(interactionKey(ctx, MethodGetPersonalInfoByUser, userID), &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetIncorrectQuizAttempts replays a recorded incorrect attempts lookup
+func (rp *Replayer) GetIncorrectQuizAttempts(ctx context.Context, userID string, limit int) (*models.IncorrectQuizAttemptsResponse, error) {
+	var attempts models.IncorrectQuizAttemptsResponse
+	if err := rp.replay(interactionKey(ctx, MethodGetIncorrectQuizAttempts, userID, limit), &attempts); err != nil {
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+// ListConversationsByUser replays a recorded conversation listing
+func (rp *Replayer) ListConversationsByUser(ctx context.Context, userID string) ([]models.RAGConversationSearchResult, error) {
+	var results []models.RAGConversationSearchResult
+	if err := rp.replay(interactionKey(ctx, MethodListConversationsByUser, userID), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeleteUserData replays a recorded user data deletion
+func (rp *Replayer) DeleteUserData(ctx context.Context, userID string) error {
+	var deleted bool
+	return rp.replay(interactionKey(ctx, MethodDeleteUserData, userID), &deleted)
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"llm/internal/models"
+)
+
+// RAGStore is the RAG server API the services depend on. RAGClient implements
+// it over HTTP; package ragtest provides in-memory and recorded-fixture doubles.
+type RAGStore interface {
+	SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error)
+	SearchConversationsPage(ctx context.Context, req models.RAGSearchPageRequest) (*models.RAGSearchPage, error)
+	SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error)
+	Health(ctx context.Context) (bool, error)
+	CreatePersonalInfo(ctx context.Context, req *models.PersonalInfoCreateRequest) (string, error)
+	GetPersonalInfoByUser(ctx context.Context, userID string) (*models.PersonalInfoListResponse, error)
+	GetIncorrectQuizAttempts(ctx context.Context, userID string, limit int) (*models.IncorrectQuizAttemptsResponse, error)
+	ListConversationsByUser(ctx context.Context, userID string) ([]models.RAGConversationSearchResult, error)
+	DeleteUserData(ctx context.Context, userID string) error
+}
+
+var _ RAGStore = (*RAGClient)(nil)
+
+// SearchConversationsAll pages through the search results for query, pageSize
+// at a time, until the server runs out of results or maxResults are collected.
+// Results repeated across pages, as when history is saved while paging by
+// offset, are kept once. A server that ignores paging returns its first page
+// again, which ends the loop.
+func SearchConversationsAll(ctx context.Context, store RAGStore, query string, pageSize, maxResults int) ([]models.RAGConversationSearchResult, error) {
+	results := []models.RAGConversationSearchResult{}
+	seen := make(map[string]bool)
+	req := models.RAGSearchPageRequest{Query: query, Limit: pageSize}
+
+	for len(results) < maxResults {
+		page, err := store.SearchConversationsPage(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, result := range page.Results {
+			if seen[result.ConversationID] || len(results) >= maxResults {
+				continue
+			}
+			seen[result.ConversationID] = true
+			results = append(results, result)
+			added++
+		}
+
+		if added == 0 || len(page.Results) < pageSize {
+			break
+		}
+		if page.NextCursor != "" {
+			req.Cursor = page.NextCursor
+		} else {
+			req.Cursor = ""
+			req.Offset += len(page.Results)
+		}
+	}
+	return results, nil
+}
+
+// FilterByTime keeps the results saved at or after from and before to. A zero
+// bound is open, and results without a timestamp are kept.
+func FilterByTime(results []models.RAGConversationSearchResult, from, to time.Time) []models.RAGConversationSearchResult {
+	if from.IsZero() && to.IsZero() {
+		return results
+	}
+	kept := make([]models.RAGConversationSearchResult, 0, len(results))
+	for _, result := range results {
+		if !result.Timestamp.IsZero() && ((!from.IsZero() && result.Timestamp.Before(from)) || (!to.IsZero() && !result.Timestamp.Before(to))) {
+			continue
+		}
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// FilterByTopic keeps the results tagged with topic. Results saved before topic
+// tagging carry no topics and are kept, so callers relying on the filter still
+// see older history.
+func FilterByTopic(results []models.RAGConversationSearchResult, topic string) []models.RAGConversationSearchResult {
+	kept := make([]models.RAGConversationSearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Metadata != nil && len(result.Metadata.Topics) > 0 && !slices.Contains(result.Metadata.Topics, topic) {
+			continue
+		}
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// FilterByTenant drops the results another tenant saved. Results saved without
+// a tenant, before tagging or outside a tenant's request, are kept.
+func FilterByTenant(results []models.RAGConversationSearchResult, tenantID string) []models.RAGConversationSearchResult {
+	kept := make([]models.RAGConversationSearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Metadata != nil && result.Metadata.TenantID != "" && result.Metadata.TenantID != tenantID {
+			continue
+		}
+		kept = append(kept, result)
+	}
+	return kept
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// TenantStore is a RAGStore that tags every saved conversation with the tenant
+// carried by the request context, so each facility's data can be told apart
+// in the RAG server, and drops conversations another tenant saved from searches
+// and listings. Calls made outside a tenant's request are left as they are.
+type TenantStore struct {
+	RAGStore
+}
+
+var _ RAGStore = (*TenantStore)(nil)
+
+// NewTenantStore wraps store with tenant tagging
+func NewTenantStore(store RAGStore) *TenantStore {
+	return &TenantStore{RAGStore: store}
+}
+
+// SaveConversation saves a conversation whose metadata carries the caller's tenant
+func (s *TenantStore) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	if tenantID := util.TenantIDFromContext(ctx); tenantID != "" && req.Metadata != nil {
+		tagged := *req
+		metadata := *req.Metadata
+		metadata.TenantID = tenantID
+		tagged.Metadata = &metadata
+		req = &tagged
+	}
+	return s.RAGStore.SaveConversation(ctx, req)
+}
+
+// SearchConversations searches conversations, dropping those another tenant saved
+func (s *TenantStore) SearchConversations(ctx context.Context, query string, limit int) ([]models.RAGConversationSearchResult, error) {
+	results, err := s.RAGStore.SearchConversations(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.filter(ctx, results), nil
+}
+
+// SearchConversationsPage fetches a page of search results, dropping those another
+// tenant saved. The page may come back shorter than asked; the cursor still
+// continues after it.
+func (s *TenantStore) SearchConversationsPage(ctx context.Context, req models.RAGSearchPageRequest) (*models.RAGSearchPage, error) {
+	page, err := s.RAGStore.SearchConversationsPage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	filtered := *page
+	filtered.Results = s.filter(ctx, page.Results)
+	return &filtered, nil
+}
+
+// ListConversationsByUser lists the user's conversations, dropping those another tenant saved
+func (s *TenantStore) ListConversationsByUser(ctx context.Context, userID string) ([]models.RAGConversationSearchResult, error) {
+	results, err := s.RAGStore.ListConversationsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.filter(ctx, results), nil
+}
+
+// filter drops the results another tenant saved when ctx carries a tenant
+func (s *TenantStore) filter(ctx context.Context, results []models.RAGConversationSearchResult) []models.RAGConversationSearchResult {
+	tenantID := util.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		return results
+	}
+	return FilterByTenant(results, tenantID)
+}
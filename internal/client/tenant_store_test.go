@@ -0,0 +1,75 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"llm/internal/client"
+	"llm/internal/client/ragtest"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+func TestTenantStoreDropsOtherTenants(t *testing.T) {
+	fake := ragtest.NewFake()
+	for id, tenantID := range map[string]string{"own": "sunrise", "other": "harbor", "untagged": ""} {
+		fake.AddConversation("user-1", models.RAGConversationSearchResult{
+			ConversationID: id,
+			Messages:       []models.RAGMessage{{Role: "user", Content: "산책"}},
+			Metadata:       &models.RAGMetadata{Type: util.ConversationTypeChat, TenantID: tenantID},
+		})
+	}
+	store := client.NewTenantStore(fake)
+
+	tests := []struct {
+		name     string
+		tenantID string
+		want     map[string]bool
+	}{
+		{name: "tenant request", tenantID: "sunrise", want: map[string]bool{"own": true, "untagged": true}},
+		{name: "outside a tenant's request", want: map[string]bool{"own": true, "other": true, "untagged": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := util.WithUserID(context.Background(), "user-1")
+			if tt.tenantID != "" {
+				ctx = util.WithTenantID(ctx, tt.tenantID)
+			}
+
+			searched, err := store.SearchConversations(ctx, "산책", 10)
+			if err != nil {
+				t.Fatalf("SearchConversations() error = %v", err)
+			}
+			page, err := store.SearchConversationsPage(ctx, models.RAGSearchPageRequest{Query: "산책"})
+			if err != nil {
+				t.Fatalf("SearchConversationsPage() error = %v", err)
+			}
+			listed, err := store.ListConversationsByUser(ctx, "user-1")
+			if err != nil {
+				t.Fatalf("ListConversationsByUser() error = %v", err)
+			}
+
+			for method, results := range map[string][]models.RAGConversationSearchResult{
+				"SearchConversations":     searched,
+				"SearchConversationsPage": page.Results,
+				"ListConversationsByUser": listed,
+			} {
+				got := map[string]bool{}
+				for _, result := range results {
+					got[result.ConversationID] = true
+				}
+				if len(got) != len(tt.want) {
+					t.Errorf("%s() = %v, want %v", method, got, tt.want)
+					continue
+				}
+				for id := range tt.want {
+					if !got[id] {
+						t.Errorf("%s() = %v, want %v", method, got, tt.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
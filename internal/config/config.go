@@ -2,92 +2,994 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"math"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"llm/internal/util"
+)
+
+// LLM providers
+const (
+	LLMProviderOpenAI = "openai"
+	LLMProviderOllama = "ollama"
+
+	// DefaultOllamaBaseURL is Ollama's OpenAI-compatible endpoint on its default port
+	DefaultOllamaBaseURL = "http://localhost:11434/v1"
+
+	// EmbeddingModelLocal embeds text locally instead of calling the embeddings API
+	EmbeddingModelLocal = "local"
 )
 
 // Config holds all application configuration
 type Config struct {
 	// Server
-	Port int
-	Env  string // development, production
+	Port       int
+	Env        string // development, production
+	ConfigFile string // optional YAML or KEY=VALUE file beneath the environment, re-read on reload
+	DataDir    string // base directory of the files the server writes, unless each is set
 
 	// RAG Server
-	RAGServerURL     string
-	RAGServerTimeout time.Duration
+	RAGServerURL           string
+	RAGServerTimeout       time.Duration            // per-request timeout for endpoints without an override
+	RAGEndpointTimeouts    map[string]time.Duration // per-endpoint request timeouts, e.g. longer for saves
+	RAGConnectTimeout      time.Duration            // TCP connect timeout
+	RAGReadTimeout         time.Duration            // wait for response headers once the request is sent, 0 for none
+	RAGMaxIdleConnsPerHost int                      // idle connections kept to each RAG instance
+	RAGContractCheck       bool                     // verify the RAG API contract at startup
+	RAGRoutes              map[string]RAGRoute
+	RAGUserRoutes          []RAGUserRoute // ordered by longest prefix first
+
+	// Save queue (disk-backed write-behind queue for conversation saves)
+	SaveQueueEnabled        bool
+	SaveQueueDir            string
+	SaveQueueMaxAttempts    int
+	SaveQueueInitialBackoff time.Duration // doubled after each failed attempt
+
+	// Local conversation index (searched while the RAG server is unreachable). Opt-in, since
+	// every save then also makes an embedding call and the index is held in memory.
+	LocalIndexEnabled    bool
+	LocalIndexMaxPerUser int // recently saved conversations kept per user
+
+	// Profile cache (per-user personal info loaded on every chat turn)
+	ProfileCacheEnabled  bool
+	ProfileCacheTTL      time.Duration // how long a cached profile is served without refetching
+	ProfileCacheMaxStale time.Duration // how long past the TTL a profile is still served while the RAG server fails
+
+	// Incorrect attempts cache (recent quiz mistakes loaded on every chat turn)
+	IncorrectAttemptsCacheEnabled bool
+	IncorrectAttemptsCacheTTL     time.Duration // kept short: results submitted through other services are not seen until it expires
+
+	// LLM provider: "openai", or "ollama" for a local OpenAI-compatible server
+	LLMProvider    string
+	OpenAIBaseURL  string            // overrides the API base URL, e.g. http://localhost:11434/v1
+	OpenAIModelMap map[string]string // configured model name -> model the provider serves
 
 	// OpenAI
-	OpenAIAPIKey      string
-	OpenAIModel       string
-	OpenAITemperature float32
-	OpenAIMaxTokens   int
+	OpenAIAPIKey       string
+	OpenAIModel        string
+	OpenAITemperature  float32
+	OpenAIMaxTokens    int
+	OpenAISummaryModel string // cheaper model used for context summarization
+	// Models per task; empty uses OpenAIModel. E.g. gpt-4o for chat and analysis,
+	// gpt-4o-mini for evaluation and question generation.
+	OpenAIChatModel       string
+	OpenAIEvaluationModel string // response quality and memory evaluation
+	OpenAIQuestionModel   string // quiz questions, recaps and answer explanations
+	OpenAIAnalysisModel   string // domain analysis and reports
+	// Generation limits per LLM task (OPENAI_<TASK>_MAX_TOKENS, OPENAI_<TASK>_TEMPERATURE)
+	OpenAITaskGeneration map[string]TaskGeneration
+	OpenAIVisionModel    string   // image-capable model used to caption photos
+	OpenAIFallbackModel  string   // replaces any model the provider rejects as deprecated or not found
+	OpenAIContextWindow  int      // context window in tokens for every model; 0 uses the known model windows
+	ContextOverflowMode  string   // trim or reject prompts estimated to exceed the context window
+	OpenAIModelAllowlist []string // models requests may select via per-request overrides
+	OpenAIEmbeddingModel string   // embeddings model, or "local" for hashed n-gram vectors
+
+	// OpenAI HTTP connection pool
+	OpenAIProxyURL            string        // proxy for OpenAI calls; empty uses HTTPS_PROXY/NO_PROXY from the environment
+	OpenAIMaxIdleConns        int           // idle connections kept across hosts
+	OpenAIMaxIdleConnsPerHost int           // idle connections kept to the API host
+	OpenAIMaxConnsPerHost     int           // connections to the API host at once, 0 for no limit
+	OpenAIIdleConnTimeout     time.Duration // how long an idle connection is kept
+	OpenAIKeepAlive           time.Duration // TCP keep-alive interval
+
+	// Fake LLM (tests, CI, and local development without an API key)
+	FakeLLMEnabled   bool
+	FakeLLMResponses string // JSON file of canned responses by prompt type overriding the built-in ones
+
+	// Chat Context
+	ContextMinScore                   float32 // minimum similarity score for retrieved conversations
+	ContextMaxMessagesPerConversation int
+	ContextRerankEnabled              bool
+	ContextSummaryEnabled             bool   // opt-in: summarizes retrieved history with the summary model, one extra LLM call per turn
+	ContextQueryStrategy              string // how the retrieval query is built: raw, llm, hybrid
+
+	// Prompt Experiments
+	PromptVariants        []PromptVariant
+	QueryStrategyVariants []PromptVariant // empty serves every user ContextQueryStrategy
+	PromptExperimentSalt  string          // changing the salt reshuffles users across variants
+
+	// Shadow Mode (a candidate prompt/model answers sampled chats alongside production;
+	// only the production reply is returned)
+	ShadowSampleRate    float64 // share of chat turns (0-1) also run through the shadow variant; 0 disables
+	ShadowPromptVariant string  // candidate chat prompt variant; empty keeps the user's variant
+	ShadowModel         string  // candidate model; empty keeps the production model
+	ShadowLogDir        string  // directory for the shadow comparison log
+
+	// Chat Output
+	ChatLanguage             string // expected response language (ko, en)
+	SmallTalkFastPathEnabled bool   // answer acknowledgements and greetings without retrieval or evaluation
+
+	// Chat Tools (functions the chat model may call mid-conversation)
+	ChatToolsEnabled bool
+	WeatherAPIURL    string // Open-Meteo compatible forecast API; empty disables the weather tool
+	WeatherLatitude  float64
+	WeatherLongitude float64
+	WeatherTimezone  string // IANA time zone the daily forecast is reported in
+	WeatherTimeout   time.Duration
+
+	// Semantic Cache (reuse replies to near-identical repeated questions)
+	SemanticCacheEnabled    bool
+	SemanticCacheTTL        time.Duration // how long a cached reply stays fresh
+	SemanticCacheThreshold  float64       // minimum message similarity (0-1) for a cache hit
+	SemanticCacheMaxEntries int           // cached replies kept per user, oldest dropped first
+
+	// Domain Analysis
+	AnalysisMaxConversations int // conversations of history paged in for an analysis
+
+	// Per-user Chat Concurrency (overlapping calls from e.g. a double-tapped call button)
+	ChatUserMaxInFlight  int           // chat requests processed at once per user, 0 for no limit
+	ChatUserOverlapMode  string        // queue or reject requests beyond the limit
+	ChatUserQueueTimeout time.Duration // how long a queued request waits for its turn
+
+	// PII Redaction (mask personal identifiers before retrieved context reaches the LLM)
+	PIIRedactionEnabled bool
+	PIIRedactionKinds   []string // identifier kinds to mask (phone, rrn, address)
+
+	// Guardrails
+	GuardrailsEnabled          bool
+	GuardrailModerationEnabled bool   // opt-in: also check input and output with the OpenAI moderation API, two extra calls per turn
+	GuardrailAuditDir          string // directory for the guardrail event audit log
+
+	// Emergency Detection
+	EmergencyDetectionEnabled bool
+	EmergencyWebhookURL       string // receives the incident as JSON
+	EmergencySMSURL           string // SMS gateway hook, called once per contact with {to, message}
+	EmergencyNotifyTimeout    time.Duration
+	EmergencyIncidentDir      string // directory for the emergency incident log
+
+	// Caregiver Webhooks
+	Webhooks                  []Webhook
+	WebhookTimeout            time.Duration
+	WebhookMaxAttempts        int
+	WebhookInitialBackoff     time.Duration // doubled after each failed attempt
+	RetentionAlertThreshold   float32       // retention score below which a drop is reported
+	IncorrectStreakAlertCount int           // consecutive incorrect answers that trigger an alert
 
 	// Game Settings
-	MinConversationsForGame int
-	QuestionCacheTTL        time.Duration
-	MemoryEvaluationWeights [3]float32 // correct, speed, recency weights
+	MinConversationsForGame           int
+	QuestionCacheTTL                  time.Duration
+	MemoryEvaluationWeights           [3]float32            // correct, speed, recency weights
+	MemoryEvaluationStrategy          string                // how game results are scored: formula or llm
+	MemoryEvaluationDifficultyWeights map[string][3]float32 // per-difficulty weights overriding MemoryEvaluationWeights
+	RecencyDecayCurve                 string                // how the recency score falls with the source conversation's age
+	RecencyDecayDays                  float64               // half-life (exponential) or days to zero (linear)
+
+	// Confidence Calibration
+	ConfidenceHighThreshold         float32 // retention score at or above which confidence is "high"
+	ConfidenceMediumThreshold       float32 // retention score at or above which confidence is "medium"
+	ConfidenceCalibrationEnabled    bool    // periodically auto-adjust the thresholds from quiz outcomes
+	ConfidenceCalibrationInterval   time.Duration
+	ConfidenceCalibrationMinSamples int // outcomes required before thresholds are adjusted
+
+	// Maintenance (nightly compaction of in-memory stores)
+	MaintenanceEnabled       bool
+	MaintenanceHour          int // local hour (0-23) the nightly run starts
+	UsageRetentionDays       int // experiment usage rows idle longer than this are removed
+	QuizHistoryRetentionDays int // quiz attempts older than this are removed
 
 	// Logging
-	LogLevel string
+	LogLevel      string
+	LogSampleRate float64  // share of requests (0-1) that log full prompts and context
+	LogDebugUsers []string // users whose requests always log full prompts and context
+
+	// Request limits
+	MaxRequestBodyBytes    int64         // larger request bodies are rejected with 413
+	MaxMessageLength       int           // longest "message" field accepted, in characters
+	ChatRequestTimeout     time.Duration // chat, plan and reminder routes, 0 for none
+	AnalysisRequestTimeout time.Duration // analysis and assessment routes, 0 for none
+	GameRequestTimeout     time.Duration // game routes, 0 for none
+
+	// Response compression
+	CompressionEnabled      bool
+	CompressionMinBytes     int      // smaller responses are sent uncompressed
+	CompressionContentTypes []string // content type prefixes worth compressing
+
+	// API versioning
+	UnversionedAPISunset time.Time // announced removal date of the unversioned /api routes, zero if none
+
+	// Recording (staging replay)
+	RecordingEnabled bool
+	RecordingDir     string
+
+	// Safe mode
+	DisabledFeatures []string // subsystems switched off at startup, see SafeModeFeatures
+
+	// Admin
+	AdminAPIKey    string // enables /api/admin routes when set
+	InternalAPIKey string // lets internal callers opt out of the response envelope when set
+
+	// Tenants (care facilities). When set, public routes require a tenant's API key
+	// and only serve the users that belong to it.
+	Tenants Tenants
+
+	// User registry. When enforced, chat, game and analysis requests naming a
+	// user that was never registered through POST /api/users are rejected.
+	UserRegistryFile     string // JSON file the registry is kept in
+	UserRegistryEnforced bool
+
+	// Secrets. OPENAI_API_KEY, ADMIN_API_KEY and INTERNAL_API_KEY may hold a reference instead of
+	// the key: file:/run/secrets/openai, vault://secret/data/llm#openai_api_key or aws-sm://llm/prod#openai_api_key
+	SecretsRefreshInterval    time.Duration // how often referenced secrets are re-read to pick up rotation, 0 to read them once
+	VaultAddr                 string
+	VaultToken                string
+	VaultNamespace            string
+	AWSRegion                 string
+	AWSAccessKeyID            string
+	AWSSecretAccessKey        string
+	AWSSessionToken           string
+	AWSSecretsManagerEndpoint string // overrides https://secretsmanager.<region>.amazonaws.com
+
+	// Runtime holds the subset of settings that can be tuned without a restart
+	Runtime *Runtime
+
+	settings []Setting // every setting as Load resolved it, see EffectiveConfig
+
+	// Chaos (fault injection for staging)
+	ChaosEnabled       bool
+	ChaosTargets       string // comma-separated: rag, openai
+	ChaosLatency       time.Duration
+	ChaosErrorRate     float64
+	ChaosMalformedRate float64
+}
+
+// RAGRoute is a RAG instance dedicated to a tenant or region
+type RAGRoute struct {
+	Name      string
+	BaseURL   string
+	Namespace string // sent as X-RAG-Namespace, empty for the instance default
+}
+
+// RAGUserRoute maps users whose ID starts with Prefix to a named RAG route
+type RAGUserRoute struct {
+	Prefix string
+	Route  string
+}
+
+// Webhook is an endpoint registered through configuration
+type Webhook struct {
+	URL    string
+	Secret string   // HMAC signing key
+	Events []string // empty subscribes to every event
+}
+
+// TaskGeneration overrides the generation settings for one LLM task
+type TaskGeneration struct {
+	MaxTokens   int      // 0 uses OpenAIMaxTokens
+	Temperature *float32 // nil uses OpenAITemperature
+}
+
+// maxTaskTokens bounds the output tokens a task may be configured with
+const maxTaskTokens = 16384
+
+// PromptVariant is an experiment variant (a chat prompt or a query strategy) and its share of traffic
+type PromptVariant struct {
+	Name   string
+	Weight int
 }
 
-// Load loads configuration from environment variables
+// Load loads configuration in layers: defaults, then the YAML or KEY=VALUE file
+// named by CONFIG_FILE, then environment variables. File keys are named like
+// the environment variables they stand in for.
 func Load() (*Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	resolved = make(map[string]Setting)
+	fileEnv = nil
+	configFile := getEnv("CONFIG_FILE", "")
+	values, err := readConfigFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+	fileEnv = values
+
+	// Every file the server writes defaults to a place under DATA_DIR, so a test or a
+	// scratch instance moves them all with one variable
+	dataDir := getEnv("DATA_DIR", ".")
+
 	cfg := &Config{
-		Port:                    getEnvAsInt("PORT", 3000),
-		Env:                     getEnv("ENVIRONMENT", "development"),
-		RAGServerURL:            getEnv("RAG_SERVER_URL", "http://localhost:8080"),
-		RAGServerTimeout:        time.Duration(getEnvAsInt("RAG_SERVER_TIMEOUT", 5000)) * time.Millisecond,
-		OpenAIAPIKey:            getEnv("OPENAI_API_KEY", ""),
-		OpenAIModel:             getEnv("OPENAI_MODEL", "gpt-4"),
-		OpenAITemperature:       float32(getEnvAsFloat("OPENAI_TEMPERATURE", 0.7)),
-		OpenAIMaxTokens:         getEnvAsInt("OPENAI_MAX_TOKENS", 3000),
-		MinConversationsForGame: getEnvAsInt("MIN_CONVERSATIONS_FOR_GAME", 5),
-		QuestionCacheTTL:        time.Duration(getEnvAsInt("QUESTION_CACHE_TTL", 300)) * time.Second,
-		LogLevel:                getEnv("LOG_LEVEL", "info"),
+		Port:                              getEnvAsInt("PORT", 3000),
+		ConfigFile:                        configFile,
+		DataDir:                           dataDir,
+		Env:                               getEnv("ENVIRONMENT", "development"),
+		RAGServerURL:                      getEnv("RAG_SERVER_URL", "http://localhost:8080"),
+		RAGServerTimeout:                  time.Duration(getEnvAsInt("RAG_SERVER_TIMEOUT", 5000)) * time.Millisecond,
+		RAGConnectTimeout:                 time.Duration(getEnvAsInt("RAG_CONNECT_TIMEOUT", 2000)) * time.Millisecond,
+		RAGReadTimeout:                    time.Duration(getEnvAsInt("RAG_READ_TIMEOUT", 0)) * time.Millisecond,
+		RAGMaxIdleConnsPerHost:            getEnvAsInt("RAG_MAX_IDLE_CONNS_PER_HOST", 20),
+		RAGContractCheck:                  getEnvAsBool("RAG_CONTRACT_CHECK", false),
+		SaveQueueEnabled:                  getEnvAsBool("SAVE_QUEUE_ENABLED", true),
+		SaveQueueDir:                      getEnv("SAVE_QUEUE_DIR", filepath.Join(dataDir, "save-queue")),
+		SaveQueueMaxAttempts:              getEnvAsInt("SAVE_QUEUE_MAX_ATTEMPTS", 8),
+		SaveQueueInitialBackoff:           time.Duration(getEnvAsInt("SAVE_QUEUE_INITIAL_BACKOFF", 2000)) * time.Millisecond,
+		LocalIndexEnabled:                 getEnvAsBool("LOCAL_INDEX_ENABLED", false),
+		LocalIndexMaxPerUser:              getEnvAsInt("LOCAL_INDEX_MAX_PER_USER", 50),
+		ProfileCacheEnabled:               getEnvAsBool("PROFILE_CACHE_ENABLED", true),
+		ProfileCacheTTL:                   time.Duration(getEnvAsInt("PROFILE_CACHE_TTL", 300)) * time.Second,
+		ProfileCacheMaxStale:              time.Duration(getEnvAsInt("PROFILE_CACHE_MAX_STALE", 1800)) * time.Second,
+		IncorrectAttemptsCacheEnabled:     getEnvAsBool("INCORRECT_ATTEMPTS_CACHE_ENABLED", true),
+		IncorrectAttemptsCacheTTL:         time.Duration(getEnvAsInt("INCORRECT_ATTEMPTS_CACHE_TTL", 60)) * time.Second,
+		LLMProvider:                       getEnv("LLM_PROVIDER", LLMProviderOpenAI),
+		OpenAIBaseURL:                     getEnv("OPENAI_BASE_URL", ""),
+		OpenAIAPIKey:                      getEnv("OPENAI_API_KEY", ""),
+		OpenAIModel:                       getEnv("OPENAI_MODEL", "gpt-4"),
+		OpenAITemperature:                 float32(getEnvAsFloat("OPENAI_TEMPERATURE", 0.7)),
+		OpenAIMaxTokens:                   getEnvAsInt("OPENAI_MAX_TOKENS", 3000),
+		OpenAISummaryModel:                getEnv("OPENAI_SUMMARY_MODEL", "gpt-4o-mini"),
+		OpenAIChatModel:                   getEnv("OPENAI_CHAT_MODEL", ""),
+		OpenAIEvaluationModel:             getEnv("OPENAI_EVALUATION_MODEL", ""),
+		OpenAIQuestionModel:               getEnv("OPENAI_QUESTION_MODEL", ""),
+		OpenAIAnalysisModel:               getEnv("OPENAI_ANALYSIS_MODEL", ""),
+		OpenAIVisionModel:                 getEnv("OPENAI_VISION_MODEL", "gpt-4o"),
+		OpenAIFallbackModel:               getEnv("OPENAI_FALLBACK_MODEL", ""),
+		OpenAIContextWindow:               getEnvAsInt("OPENAI_CONTEXT_WINDOW", 0),
+		ContextOverflowMode:               getEnv("CONTEXT_OVERFLOW_MODE", util.ContextOverflowTrim),
+		OpenAIModelAllowlist:              getEnvAsSlice("OPENAI_MODEL_ALLOWLIST", nil),
+		OpenAIEmbeddingModel:              getEnv("OPENAI_EMBEDDING_MODEL", "text-embedding-3-small"),
+		OpenAIProxyURL:                    getEnv("OPENAI_PROXY_URL", ""),
+		OpenAIMaxIdleConns:                getEnvAsInt("OPENAI_MAX_IDLE_CONNS", 100),
+		OpenAIMaxIdleConnsPerHost:         getEnvAsInt("OPENAI_MAX_IDLE_CONNS_PER_HOST", 20),
+		OpenAIMaxConnsPerHost:             getEnvAsInt("OPENAI_MAX_CONNS_PER_HOST", 0),
+		OpenAIIdleConnTimeout:             time.Duration(getEnvAsInt("OPENAI_IDLE_CONN_TIMEOUT", 90)) * time.Second,
+		OpenAIKeepAlive:                   time.Duration(getEnvAsInt("OPENAI_KEEP_ALIVE", 30)) * time.Second,
+		FakeLLMEnabled:                    getEnvAsBool("FAKE_LLM_ENABLED", false),
+		FakeLLMResponses:                  getEnv("FAKE_LLM_RESPONSES", ""),
+		ContextMinScore:                   float32(getEnvAsFloat("CONTEXT_MIN_SCORE", 0.3)),
+		ContextMaxMessagesPerConversation: getEnvAsInt("CONTEXT_MAX_MESSAGES_PER_CONVERSATION", 4),
+		ContextRerankEnabled:              getEnvAsBool("CONTEXT_RERANK_ENABLED", false),
+		ContextSummaryEnabled:             getEnvAsBool("CONTEXT_SUMMARY_ENABLED", false),
+		ContextQueryStrategy:              getEnv("CONTEXT_QUERY_STRATEGY", "raw"),
+		PromptExperimentSalt:              getEnv("PROMPT_EXPERIMENT_SALT", "prompt-experiment"),
+		ShadowSampleRate:                  getEnvAsFloat("SHADOW_SAMPLE_RATE", 0),
+		ShadowPromptVariant:               getEnv("SHADOW_PROMPT_VARIANT", ""),
+		ShadowModel:                       getEnv("SHADOW_MODEL", ""),
+		ShadowLogDir:                      getEnv("SHADOW_LOG_DIR", filepath.Join(dataDir, "shadow")),
+		ChatLanguage:                      getEnv("CHAT_LANGUAGE", "ko"),
+		SmallTalkFastPathEnabled:          getEnvAsBool("SMALL_TALK_FAST_PATH_ENABLED", true),
+		ChatToolsEnabled:                  getEnvAsBool("CHAT_TOOLS_ENABLED", false),
+		WeatherAPIURL:                     getEnv("WEATHER_API_URL", "https://api.open-meteo.com/v1/forecast"),
+		WeatherLatitude:                   getEnvAsFloat("WEATHER_LATITUDE", 37.5665),
+		WeatherLongitude:                  getEnvAsFloat("WEATHER_LONGITUDE", 126.978),
+		WeatherTimezone:                   getEnv("WEATHER_TIMEZONE", "Asia/Seoul"),
+		WeatherTimeout:                    time.Duration(getEnvAsInt("WEATHER_TIMEOUT", 3000)) * time.Millisecond,
+		SemanticCacheEnabled:              getEnvAsBool("SEMANTIC_CACHE_ENABLED", false),
+		SemanticCacheTTL:                  time.Duration(getEnvAsInt("SEMANTIC_CACHE_TTL", 600)) * time.Second,
+		SemanticCacheThreshold:            getEnvAsFloat("SEMANTIC_CACHE_THRESHOLD", 0.85),
+		SemanticCacheMaxEntries:           getEnvAsInt("SEMANTIC_CACHE_MAX_ENTRIES", 20),
+		AnalysisMaxConversations:          getEnvAsInt("ANALYSIS_MAX_CONVERSATIONS", 500),
+		ChatUserMaxInFlight:               getEnvAsInt("CHAT_USER_MAX_IN_FLIGHT", 1),
+		ChatUserOverlapMode:               getEnv("CHAT_USER_OVERLAP_MODE", util.ChatOverlapQueue),
+		ChatUserQueueTimeout:              time.Duration(getEnvAsInt("CHAT_USER_QUEUE_TIMEOUT", 30000)) * time.Millisecond,
+		PIIRedactionEnabled:               getEnvAsBool("PII_REDACTION_ENABLED", true),
+		PIIRedactionKinds:                 getEnvAsSlice("PII_REDACTION_KINDS", util.PIIKinds),
+		GuardrailsEnabled:                 getEnvAsBool("GUARDRAILS_ENABLED", true),
+		GuardrailModerationEnabled:        getEnvAsBool("GUARDRAIL_MODERATION_ENABLED", false),
+		GuardrailAuditDir:                 getEnv("GUARDRAIL_AUDIT_DIR", filepath.Join(dataDir, "audit")),
+		EmergencyDetectionEnabled:         getEnvAsBool("EMERGENCY_DETECTION_ENABLED", true),
+		EmergencyWebhookURL:               getEnv("EMERGENCY_WEBHOOK_URL", ""),
+		EmergencySMSURL:                   getEnv("EMERGENCY_SMS_URL", ""),
+		EmergencyNotifyTimeout:            time.Duration(getEnvAsInt("EMERGENCY_NOTIFY_TIMEOUT", 5000)) * time.Millisecond,
+		EmergencyIncidentDir:              getEnv("EMERGENCY_INCIDENT_DIR", filepath.Join(dataDir, "incidents")),
+		WebhookTimeout:                    time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT", 5000)) * time.Millisecond,
+		WebhookMaxAttempts:                getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookInitialBackoff:             time.Duration(getEnvAsInt("WEBHOOK_INITIAL_BACKOFF", 1000)) * time.Millisecond,
+		RetentionAlertThreshold:           float32(getEnvAsFloat("RETENTION_ALERT_THRESHOLD", 0.4)),
+		IncorrectStreakAlertCount:         getEnvAsInt("INCORRECT_STREAK_ALERT_COUNT", 3),
+		MinConversationsForGame:           getEnvAsInt("MIN_CONVERSATIONS_FOR_GAME", 5),
+		QuestionCacheTTL:                  time.Duration(getEnvAsInt("QUESTION_CACHE_TTL", 86400)) * time.Second,
+		MemoryEvaluationStrategy:          getEnv("MEMORY_EVALUATION_STRATEGY", util.EvaluationStrategyFormula),
+		RecencyDecayCurve:                 getEnv("RECENCY_DECAY_CURVE", util.RecencyDecayExponential),
+		RecencyDecayDays:                  getEnvAsFloat("RECENCY_DECAY_DAYS", 30),
+		ConfidenceHighThreshold:           float32(getEnvAsFloat("CONFIDENCE_HIGH_THRESHOLD", 0.8)),
+		ConfidenceMediumThreshold:         float32(getEnvAsFloat("CONFIDENCE_MEDIUM_THRESHOLD", 0.5)),
+		ConfidenceCalibrationEnabled:      getEnvAsBool("CONFIDENCE_CALIBRATION_ENABLED", false),
+		ConfidenceCalibrationInterval:     time.Duration(getEnvAsInt("CONFIDENCE_CALIBRATION_INTERVAL", 3600)) * time.Second,
+		ConfidenceCalibrationMinSamples:   getEnvAsInt("CONFIDENCE_CALIBRATION_MIN_SAMPLES", 30),
+		MaintenanceEnabled:                getEnvAsBool("MAINTENANCE_ENABLED", true),
+		MaintenanceHour:                   getEnvAsInt("MAINTENANCE_HOUR", 3),
+		UsageRetentionDays:                getEnvAsInt("USAGE_RETENTION_DAYS", 90),
+		QuizHistoryRetentionDays:          getEnvAsInt("QUIZ_HISTORY_RETENTION_DAYS", 365),
+		LogLevel:                          getEnv("LOG_LEVEL", "info"),
+		LogSampleRate:                     getEnvAsFloat("LOG_SAMPLE_RATE", 0.01),
+		LogDebugUsers:                     getEnvAsSlice("LOG_DEBUG_USERS", nil),
+		DisabledFeatures:                  getEnvAsSlice("DISABLED_FEATURES", nil),
+		MaxRequestBodyBytes:               int64(getEnvAsInt("MAX_REQUEST_BODY_BYTES", 10<<20)),
+		MaxMessageLength:                  getEnvAsInt("MAX_MESSAGE_LENGTH", 2000),
+		ChatRequestTimeout:                time.Duration(getEnvAsInt("CHAT_REQUEST_TIMEOUT", 30000)) * time.Millisecond,
+		AnalysisRequestTimeout:            time.Duration(getEnvAsInt("ANALYSIS_REQUEST_TIMEOUT", 120000)) * time.Millisecond,
+		GameRequestTimeout:                time.Duration(getEnvAsInt("GAME_REQUEST_TIMEOUT", 20000)) * time.Millisecond,
+		CompressionEnabled:                getEnvAsBool("COMPRESSION_ENABLED", true),
+		CompressionMinBytes:               getEnvAsInt("COMPRESSION_MIN_BYTES", 1024),
+		CompressionContentTypes:           getEnvAsSlice("COMPRESSION_CONTENT_TYPES", []string{"application/json", "text/"}),
+		RecordingEnabled:                  getEnvAsBool("RECORDING_ENABLED", false),
+		RecordingDir:                      getEnv("RECORDING_DIR", filepath.Join(dataDir, "recordings")),
+		AdminAPIKey:                       getEnv("ADMIN_API_KEY", ""),
+		InternalAPIKey:                    getEnv("INTERNAL_API_KEY", ""),
+		UserRegistryFile:                  getEnv("USER_REGISTRY_FILE", filepath.Join(dataDir, "users.json")),
+		UserRegistryEnforced:              getEnvAsBool("USER_REGISTRY_ENFORCED", false),
+		SecretsRefreshInterval:            time.Duration(getEnvAsInt("SECRETS_REFRESH_INTERVAL", 300)) * time.Second,
+		VaultAddr:                         getEnv("VAULT_ADDR", ""),
+		VaultToken:                        getEnv("VAULT_TOKEN", ""),
+		VaultNamespace:                    getEnv("VAULT_NAMESPACE", ""),
+		AWSRegion:                         getEnv("AWS_REGION", ""),
+		AWSAccessKeyID:                    getEnv("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey:                getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:                   getEnv("AWS_SESSION_TOKEN", ""),
+		AWSSecretsManagerEndpoint:         getEnv("AWS_SECRETS_MANAGER_ENDPOINT", ""),
+		ChaosEnabled:                      getEnvAsBool("CHAOS_ENABLED", false),
+		ChaosTargets:                      getEnv("CHAOS_TARGETS", "rag,openai"),
+		ChaosLatency:                      time.Duration(getEnvAsInt("CHAOS_LATENCY_MS", 0)) * time.Millisecond,
+		ChaosErrorRate:                    getEnvAsFloat("CHAOS_ERROR_RATE", 0),
+		ChaosMalformedRate:                getEnvAsFloat("CHAOS_MALFORMED_RATE", 0),
 	}
 
 	// Parse memory evaluation weights
-	weights := parseWeights(getEnv("MEMORY_EVALUATION_WEIGHTS", "0.5,0.3,0.2"))
+	weights, err := parseWeights("MEMORY_EVALUATION_WEIGHTS", getEnv("MEMORY_EVALUATION_WEIGHTS", "0.5,0.3,0.2"))
+	if err != nil {
+		return nil, err
+	}
 	cfg.MemoryEvaluationWeights = weights
 
+	cfg.MemoryEvaluationDifficultyWeights = make(map[string][3]float32)
+	for _, difficulty := range []string{util.DifficultyEasy, util.DifficultyMedium, util.DifficultyHard} {
+		key := "MEMORY_EVALUATION_WEIGHTS_" + strings.ToUpper(difficulty)
+		weightStr := getEnv(key, "")
+		if weightStr == "" {
+			continue
+		}
+		difficultyWeights, err := parseWeights(key, weightStr)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MemoryEvaluationDifficultyWeights[difficulty] = difficultyWeights
+	}
+
+	// Parse prompt experiment variants
+	variants, err := parseVariants("PROMPT_VARIANTS", getEnv("PROMPT_VARIANTS", "v1:100"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.PromptVariants = variants
+
+	// Parse retrieval query strategy variants
+	queryVariants, err := parseVariants("QUERY_STRATEGY_VARIANTS", getEnv("QUERY_STRATEGY_VARIANTS", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.QueryStrategyVariants = queryVariants
+
+	// Parse caregiver webhooks
+	webhooks, err := parseWebhooks(getEnv("WEBHOOKS", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Webhooks = webhooks
+
+	tenants, err := parseTenants(getEnv("TENANTS", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Tenants = tenants
+
+	// Parse RAG routing for data residency
+	routes, err := parseRAGRoutes(getEnv("RAG_ROUTES", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.RAGRoutes = routes
+
+	userRoutes, err := parseRAGUserRoutes(getEnv("RAG_USER_ROUTES", ""), routes)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RAGUserRoutes = userRoutes
+
+	endpointTimeouts, err := parseRAGEndpointTimeouts(getEnv("RAG_ENDPOINT_TIMEOUTS", util.RAGEndpointSave+"=15000"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.RAGEndpointTimeouts = endpointTimeouts
+
+	if cfg.RAGServerTimeout <= 0 || cfg.RAGConnectTimeout <= 0 {
+		return nil, fmt.Errorf("RAG_SERVER_TIMEOUT and RAG_CONNECT_TIMEOUT must be positive numbers of milliseconds")
+	}
+	if cfg.RAGReadTimeout < 0 {
+		return nil, fmt.Errorf("RAG_READ_TIMEOUT must not be negative")
+	}
+	if cfg.RAGMaxIdleConnsPerHost < 0 {
+		return nil, fmt.Errorf("RAG_MAX_IDLE_CONNS_PER_HOST must not be negative")
+	}
+
+	// Parse model mapping for OpenAI-compatible providers
+	modelMap, err := parseModelMap(getEnv("OPENAI_MODEL_MAP", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.OpenAIModelMap = modelMap
+
+	taskGeneration, err := loadTaskGeneration()
+	if err != nil {
+		return nil, err
+	}
+	cfg.OpenAITaskGeneration = taskGeneration
+
+	// Ollama serves the OpenAI API locally and ignores the API key
+	switch cfg.LLMProvider {
+	case LLMProviderOpenAI:
+	case LLMProviderOllama:
+		if cfg.OpenAIBaseURL == "" {
+			cfg.OpenAIBaseURL = DefaultOllamaBaseURL
+		}
+		if cfg.OpenAIAPIKey == "" {
+			cfg.OpenAIAPIKey = LLMProviderOllama
+		}
+	default:
+		return nil, fmt.Errorf("LLM_PROVIDER must be one of %s, %s", LLMProviderOpenAI, LLMProviderOllama)
+	}
+
 	// Validate required fields
-	if cfg.OpenAIAPIKey == "" {
+	if cfg.OpenAIAPIKey == "" && !cfg.FakeLLMEnabled {
 		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is required")
 	}
 
+	if cfg.ConfidenceMediumThreshold > cfg.ConfidenceHighThreshold {
+		return nil, fmt.Errorf("CONFIDENCE_MEDIUM_THRESHOLD must not exceed CONFIDENCE_HIGH_THRESHOLD")
+	}
+
+	if cfg.MaintenanceHour < 0 || cfg.MaintenanceHour > 23 {
+		return nil, fmt.Errorf("MAINTENANCE_HOUR must be between 0 and 23")
+	}
+
+	if cfg.MinConversationsForGame < 1 {
+		return nil, fmt.Errorf("MIN_CONVERSATIONS_FOR_GAME must be at least 1")
+	}
+
+	if cfg.QuestionCacheTTL <= 0 {
+		return nil, fmt.Errorf("QUESTION_CACHE_TTL must be a positive number of seconds")
+	}
+
+	if cfg.MemoryEvaluationStrategy != util.EvaluationStrategyFormula && cfg.MemoryEvaluationStrategy != util.EvaluationStrategyLLM {
+		return nil, fmt.Errorf("MEMORY_EVALUATION_STRATEGY must be one of %s, %s", util.EvaluationStrategyFormula, util.EvaluationStrategyLLM)
+	}
+
+	switch cfg.RecencyDecayCurve {
+	case util.RecencyDecayExponential, util.RecencyDecayLinear, util.RecencyDecayNone:
+	default:
+		return nil, fmt.Errorf("RECENCY_DECAY_CURVE must be one of %s, %s, %s", util.RecencyDecayExponential, util.RecencyDecayLinear, util.RecencyDecayNone)
+	}
+
+	if cfg.RecencyDecayDays <= 0 {
+		return nil, fmt.Errorf("RECENCY_DECAY_DAYS must be a positive number of days")
+	}
+
+	if cfg.ContextOverflowMode != util.ContextOverflowTrim && cfg.ContextOverflowMode != util.ContextOverflowReject {
+		return nil, fmt.Errorf("CONTEXT_OVERFLOW_MODE must be one of %s, %s", util.ContextOverflowTrim, util.ContextOverflowReject)
+	}
+
+	if cfg.LocalIndexEnabled && cfg.LocalIndexMaxPerUser < 1 {
+		return nil, fmt.Errorf("LOCAL_INDEX_MAX_PER_USER must be at least 1")
+	}
+
+	if cfg.ProfileCacheEnabled {
+		if cfg.ProfileCacheTTL <= 0 {
+			return nil, fmt.Errorf("PROFILE_CACHE_TTL must be a positive number of seconds")
+		}
+		if cfg.ProfileCacheMaxStale < 0 {
+			return nil, fmt.Errorf("PROFILE_CACHE_MAX_STALE must not be negative")
+		}
+	}
+
+	if cfg.IncorrectAttemptsCacheEnabled && cfg.IncorrectAttemptsCacheTTL <= 0 {
+		return nil, fmt.Errorf("INCORRECT_ATTEMPTS_CACHE_TTL must be a positive number of seconds")
+	}
+
+	if cfg.SemanticCacheEnabled {
+		if cfg.SemanticCacheTTL <= 0 {
+			return nil, fmt.Errorf("SEMANTIC_CACHE_TTL must be a positive number of seconds")
+		}
+		if cfg.SemanticCacheThreshold <= 0 || cfg.SemanticCacheThreshold > 1 {
+			return nil, fmt.Errorf("SEMANTIC_CACHE_THRESHOLD must be greater than 0 and at most 1")
+		}
+		if cfg.SemanticCacheMaxEntries < 1 {
+			return nil, fmt.Errorf("SEMANTIC_CACHE_MAX_ENTRIES must be at least 1")
+		}
+	}
+
+	if cfg.OpenAIProxyURL != "" {
+		if u, err := url.Parse(cfg.OpenAIProxyURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("OPENAI_PROXY_URL must be an absolute URL")
+		}
+	}
+	if cfg.OpenAIMaxIdleConns < 0 || cfg.OpenAIMaxIdleConnsPerHost < 0 || cfg.OpenAIMaxConnsPerHost < 0 {
+		return nil, fmt.Errorf("OPENAI_MAX_IDLE_CONNS, OPENAI_MAX_IDLE_CONNS_PER_HOST and OPENAI_MAX_CONNS_PER_HOST must not be negative")
+	}
+
+	if cfg.AnalysisMaxConversations < 1 {
+		return nil, fmt.Errorf("ANALYSIS_MAX_CONVERSATIONS must be at least 1")
+	}
+
+	if cfg.ChatUserMaxInFlight < 0 {
+		return nil, fmt.Errorf("CHAT_USER_MAX_IN_FLIGHT must not be negative")
+	}
+	if cfg.ChatUserOverlapMode != util.ChatOverlapQueue && cfg.ChatUserOverlapMode != util.ChatOverlapReject {
+		return nil, fmt.Errorf("CHAT_USER_OVERLAP_MODE must be one of %s, %s", util.ChatOverlapQueue, util.ChatOverlapReject)
+	}
+	if cfg.ChatUserQueueTimeout <= 0 {
+		return nil, fmt.Errorf("CHAT_USER_QUEUE_TIMEOUT must be a positive number of milliseconds")
+	}
+
+	if cfg.MaxRequestBodyBytes <= 0 || cfg.MaxMessageLength <= 0 {
+		return nil, fmt.Errorf("MAX_REQUEST_BODY_BYTES and MAX_MESSAGE_LENGTH must be positive")
+	}
+	if cfg.CompressionMinBytes < 0 {
+		return nil, fmt.Errorf("COMPRESSION_MIN_BYTES must not be negative")
+	}
+
+	if sunset := getEnv("UNVERSIONED_API_SUNSET", ""); sunset != "" {
+		date, err := time.Parse("2006-01-02", sunset)
+		if err != nil {
+			return nil, fmt.Errorf("UNVERSIONED_API_SUNSET must be a date (YYYY-MM-DD)")
+		}
+		cfg.UnversionedAPISunset = date
+	}
+
+	if cfg.ChatRequestTimeout < 0 || cfg.AnalysisRequestTimeout < 0 || cfg.GameRequestTimeout < 0 {
+		return nil, fmt.Errorf("CHAT_REQUEST_TIMEOUT, ANALYSIS_REQUEST_TIMEOUT and GAME_REQUEST_TIMEOUT must be non-negative numbers of milliseconds")
+	}
+
+	// Saved conversations record the language, and their metadata is validated against the supported ones
+	if cfg.ChatLanguage != util.LanguageKorean && cfg.ChatLanguage != util.LanguageEnglish {
+		return nil, fmt.Errorf("CHAT_LANGUAGE must be one of %s, %s", util.LanguageKorean, util.LanguageEnglish)
+	}
+
+	if cfg.WeatherLatitude < -90 || cfg.WeatherLatitude > 90 || cfg.WeatherLongitude < -180 || cfg.WeatherLongitude > 180 {
+		return nil, fmt.Errorf("WEATHER_LATITUDE must be between -90 and 90 and WEATHER_LONGITUDE between -180 and 180")
+	}
+	if cfg.WeatherTimeout <= 0 {
+		return nil, fmt.Errorf("WEATHER_TIMEOUT must be a positive number of milliseconds")
+	}
+
+	for _, kind := range cfg.PIIRedactionKinds {
+		if !util.IsPIIKind(kind) {
+			return nil, fmt.Errorf("PII_REDACTION_KINDS: unknown kind %q, must be one of %s", kind, strings.Join(util.PIIKinds, ", "))
+		}
+	}
+
+	if cfg.ShadowSampleRate < 0 || cfg.ShadowSampleRate > 1 {
+		return nil, fmt.Errorf("SHADOW_SAMPLE_RATE must be between 0 and 1")
+	}
+	if cfg.ShadowSampleRate > 0 && cfg.ShadowPromptVariant == "" && cfg.ShadowModel == "" {
+		return nil, fmt.Errorf("SHADOW_PROMPT_VARIANT or SHADOW_MODEL must be set when SHADOW_SAMPLE_RATE is above 0")
+	}
+
+	if cfg.LogSampleRate < 0 || cfg.LogSampleRate > 1 {
+		return nil, fmt.Errorf("LOG_SAMPLE_RATE must be between 0 and 1")
+	}
+
+	for _, feature := range cfg.DisabledFeatures {
+		if !IsSafeModeFeature(feature) {
+			return nil, fmt.Errorf("DISABLED_FEATURES: unknown feature %q, must be one of %s", feature, strings.Join(SafeModeFeatures, ", "))
+		}
+	}
+
+	if cfg.SecretsRefreshInterval < 0 {
+		return nil, fmt.Errorf("SECRETS_REFRESH_INTERVAL must not be negative")
+	}
+
+	if cfg.ChaosEnabled && cfg.Env == "production" {
+		return nil, fmt.Errorf("CHAOS_ENABLED must not be set in production")
+	}
+
+	if err := checkFileKeys(configFile); err != nil {
+		return nil, err
+	}
+	cfg.settings = sortedSettings()
+
+	cfg.Runtime = NewRuntime(cfg)
+
 	return cfg, nil
 }
 
 func getEnv(key, defaultVal string) string {
-	if value, exists := os.LookupEnv(key); exists {
+	if value, exists := lookupEnv(key); exists {
 		return value
 	}
+	useDefault(key, defaultVal)
 	return defaultVal
 }
 
 func getEnvAsInt(key string, defaultVal int) int {
-	valStr := getEnv(key, "")
-	if val, err := strconv.Atoi(valStr); err == nil {
-		return val
+	if valStr, exists := lookupEnv(key); exists {
+		if val, err := strconv.Atoi(valStr); err == nil {
+			return val
+		}
 	}
+	useDefault(key, strconv.Itoa(defaultVal))
 	return defaultVal
 }
 
 func getEnvAsFloat(key string, defaultVal float64) float64 {
-	valStr := getEnv(key, "")
-	if val, err := strconv.ParseFloat(valStr, 64); err == nil {
-		return val
+	if valStr, exists := lookupEnv(key); exists {
+		if val, err := strconv.ParseFloat(valStr, 64); err == nil {
+			return val
+		}
 	}
+	useDefault(key, strconv.FormatFloat(defaultVal, 'g', -1, 64))
 	return defaultVal
 }
 
-func parseWeights(weightStr string) [3]float32 {
-	// Default weights
-	weights := [3]float32{0.5, 0.3, 0.2}
+func getEnvAsBool(key string, defaultVal bool) bool {
+	if valStr, exists := lookupEnv(key); exists {
+		if val, err := strconv.ParseBool(valStr); err == nil {
+			return val
+		}
+	}
+	useDefault(key, strconv.FormatBool(defaultVal))
+	return defaultVal
+}
+
+// loadTaskGeneration reads the generation settings of each LLM task, e.g.
+// OPENAI_EVALUATION_MAX_TOKENS=200 and OPENAI_QUESTION_TEMPERATURE=0.3
+func loadTaskGeneration() (map[string]TaskGeneration, error) {
+	settings := make(map[string]TaskGeneration)
+	for _, task := range []string{util.LLMTaskChat, util.LLMTaskEvaluation, util.LLMTaskQuestion, util.LLMTaskAnalysis, util.LLMTaskVision} {
+		prefix := "OPENAI_" + strings.ToUpper(task)
+
+		var generation TaskGeneration
+		generation.MaxTokens = getEnvAsInt(prefix+"_MAX_TOKENS", 0)
+		if generation.MaxTokens < 0 || generation.MaxTokens > maxTaskTokens {
+			return nil, fmt.Errorf("%s_MAX_TOKENS must be between 1 and %d", prefix, maxTaskTokens)
+		}
+		if value := getEnv(prefix+"_TEMPERATURE", ""); value != "" {
+			temperature, err := strconv.ParseFloat(value, 32)
+			if err != nil || temperature < 0 || temperature > 2 {
+				return nil, fmt.Errorf("%s_TEMPERATURE must be between 0 and 2", prefix)
+			}
+			t := float32(temperature)
+			generation.Temperature = &t
+		}
+		settings[task] = generation
+	}
+	return settings, nil
+}
+
+// parseVariants parses "name:weight" entries separated by commas; key names the
+// environment variable in errors
+func parseVariants(key, variantStr string) ([]PromptVariant, error) {
+	var variants []PromptVariant
+	for _, entry := range strings.Split(variantStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, weightStr, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		weight, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if !ok || name == "" || err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid %s entry %q: expected name:weight", key, entry)
+		}
+
+		variants = append(variants, PromptVariant{Name: name, Weight: weight})
+	}
+	return variants, nil
+}
+
+// parseModelMap parses "from=to" entries separated by commas, e.g.
+// "gpt-4=llama3,gpt-4o-mini=qwen2.5:7b"
+func parseModelMap(mapStr string) (map[string]string, error) {
+	modelMap := make(map[string]string)
+	for _, entry := range strings.Split(mapStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		from, to, ok := strings.Cut(entry, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid OPENAI_MODEL_MAP entry %q: expected from=to", entry)
+		}
+		modelMap[from] = to
+	}
+	return modelMap, nil
+}
+
+// parseRAGEndpointTimeouts parses "endpoint=milliseconds" entries separated by
+// commas, e.g. "save=15000,search=3000"
+func parseRAGEndpointTimeouts(timeoutStr string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(timeoutStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		endpoint, msStr, ok := strings.Cut(entry, "=")
+		endpoint = strings.TrimSpace(endpoint)
+		ms, err := strconv.Atoi(strings.TrimSpace(msStr))
+		if !ok || err != nil || ms <= 0 {
+			return nil, fmt.Errorf("invalid RAG_ENDPOINT_TIMEOUTS entry %q: expected endpoint=milliseconds", entry)
+		}
+		if !slices.Contains(util.RAGEndpoints, endpoint) {
+			return nil, fmt.Errorf("RAG_ENDPOINT_TIMEOUTS: unknown endpoint %q, must be one of %s", endpoint, strings.Join(util.RAGEndpoints, ", "))
+		}
+		timeouts[endpoint] = time.Duration(ms) * time.Millisecond
+	}
+	return timeouts, nil
+}
+
+func getEnvAsSlice(key string, defaultVal []string) []string {
+	valStr, _ := lookupEnv(key)
+	if valStr == "" {
+		useDefault(key, strings.Join(defaultVal, ","))
+		return defaultVal
+	}
+
+	var values []string
+	for _, part := range strings.Split(valStr, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// SafeModeFeatures are the subsystems that can be switched off while the server is running
+var SafeModeFeatures = []string{util.FeatureAnalysis, util.FeatureEvaluation, util.FeatureSuggestions}
+
+// IsSafeModeFeature reports whether feature has a kill switch
+func IsSafeModeFeature(feature string) bool {
+	for _, f := range SafeModeFeatures {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWeights parses "correct,speed,recency" weights such as "0.5,0.3,0.2".
+// Each weight must be between 0 and 1 and together they must sum to 1.
+func parseWeights(key, weightStr string) ([3]float32, error) {
+	var weights [3]float32
+
+	parts := strings.Split(weightStr, ",")
+	if len(parts) != len(weights) {
+		return weights, fmt.Errorf("invalid %s %q: expected correct,speed,recency weights", key, weightStr)
+	}
+
+	var sum float64
+	for i, part := range parts {
+		weight, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil || weight < 0 || weight > 1 {
+			return weights, fmt.Errorf("invalid %s weight %q: must be a number between 0 and 1", key, strings.TrimSpace(part))
+		}
+		weights[i] = float32(weight)
+		sum += weight
+	}
+
+	if math.Abs(sum-1) > 0.01 {
+		return weights, fmt.Errorf("invalid %s %q: weights must sum to 1.0, got %.2f", key, weightStr, sum)
+	}
+	return weights, nil
+}
+
+// parseWebhooks parses "url|secret[|event+event],..." entries
+func parseWebhooks(webhookStr string) ([]Webhook, error) {
+	var webhooks []Webhook
+	for _, entry := range strings.Split(webhookStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid WEBHOOKS entry %q: expected url|secret[|event+event]", entry)
+		}
+
+		url, secret := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if url == "" || secret == "" {
+			return nil, fmt.Errorf("invalid WEBHOOKS entry %q: url and secret are required", entry)
+		}
+
+		var events []string
+		if len(parts) == 3 {
+			for _, event := range strings.Split(parts[2], "+") {
+				if event = strings.TrimSpace(event); event != "" {
+					events = append(events, event)
+				}
+			}
+		}
+
+		webhooks = append(webhooks, Webhook{URL: url, Secret: secret, Events: events})
+	}
+	return webhooks, nil
+}
+
+// parseRAGRoutes parses "name=url|namespace" entries separated by commas.
+// The namespace part is optional.
+func parseRAGRoutes(routeStr string) (map[string]RAGRoute, error) {
+	routes := make(map[string]RAGRoute)
+	for _, entry := range strings.Split(routeStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, target, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid RAG_ROUTES entry %q: expected name=url[|namespace]", entry)
+		}
+
+		baseURL, namespace, _ := strings.Cut(target, "|")
+		baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+		if baseURL == "" {
+			return nil, fmt.Errorf("invalid RAG_ROUTES entry %q: missing url", entry)
+		}
+
+		routes[name] = RAGRoute{
+			Name:      name,
+			BaseURL:   baseURL,
+			Namespace: strings.TrimSpace(namespace),
+		}
+	}
+	return routes, nil
+}
+
+// parseRAGUserRoutes parses "prefix=route" entries separated by commas and
+// returns them ordered so that the longest matching prefix wins
+func parseRAGUserRoutes(ruleStr string, routes map[string]RAGRoute) ([]RAGUserRoute, error) {
+	var rules []RAGUserRoute
+	for _, entry := range strings.Split(ruleStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		prefix, route, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		route = strings.TrimSpace(route)
+		if !ok || prefix == "" {
+			return nil, fmt.Errorf("invalid RAG_USER_ROUTES entry %q: expected prefix=route", entry)
+		}
+		if _, exists := routes[route]; !exists {
+			return nil, fmt.Errorf("RAG_USER_ROUTES entry %q references unknown route %q", entry, route)
+		}
+
+		rules = append(rules, RAGUserRoute{Prefix: prefix, Route: route})
+	}
 
-	// Try to parse custom weights (format: "0.5,0.3,0.2")
-	// If parsing fails, use defaults
-	return weights
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].Prefix) > len(rules[j].Prefix)
+	})
+	return rules, nil
 }
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Where a setting's value came from, lowest precedence first
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// Setting is one configuration value as Load resolved it, keyed by its
+// environment variable name
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// fileEnv holds the values read from CONFIG_FILE by the Load in progress, and
+// resolved every setting that Load looked up. File values sit beneath the
+// process environment: a variable set in both keeps its environment value.
+// loadMu serializes loads, since a reload can run alongside startup code.
+var (
+	loadMu   sync.Mutex
+	fileEnv  map[string]string
+	resolved map[string]Setting
+)
+
+// readConfigFile reads CONFIG_FILE: YAML when it ends in .yaml or .yml, KEY=VALUE
+// lines otherwise. An empty path reads nothing.
+func readConfigFile(path string) (map[string]string, error) {
+	if path == "" {
+		return map[string]string{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CONFIG_FILE: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAMLConfig(path, data)
+	default:
+		return parseEnvConfig(path, string(data))
+	}
+}
+
+// parseEnvConfig parses KEY=VALUE lines. Blank lines and lines starting with #
+// are skipped, an "export " prefix is allowed and matching quotes around a
+// value are removed.
+func parseEnvConfig(path, data string) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("CONFIG_FILE %s line %d: expected KEY=VALUE", path, lineNo)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// parseYAMLConfig flattens a YAML document onto environment variable names:
+// nested keys are joined with underscores and upper-cased, so openai.model (or
+// openai_model) sets OPENAI_MODEL, and lists become comma-separated values.
+func parseYAMLConfig(path string, data []byte) (map[string]string, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenYAML("", doc, values); err != nil {
+		return nil, fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flattenYAML(prefix string, node map[string]interface{}, values map[string]string) error {
+	for name, value := range node {
+		key := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flattenYAML(key, v, values); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				if !isYAMLScalar(item) {
+					return fmt.Errorf("%s: list items must be plain values", key)
+				}
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			values[key] = ""
+		default:
+			if !isYAMLScalar(v) {
+				return fmt.Errorf("%s: unsupported value", key)
+			}
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+func isYAMLScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return true
+	}
+	return false
+}
+
+// lookupEnv returns a setting from the environment or the config file and
+// records where it came from
+func lookupEnv(key string) (string, bool) {
+	if value, exists := os.LookupEnv(key); exists {
+		resolved[key] = Setting{Key: key, Value: value, Source: SourceEnv}
+		return value, true
+	}
+	if value, exists := fileEnv[key]; exists {
+		resolved[key] = Setting{Key: key, Value: value, Source: SourceFile}
+		return value, true
+	}
+	return "", false
+}
+
+// useDefault records that a setting fell back to its default
+func useDefault(key, value string) {
+	resolved[key] = Setting{Key: key, Value: value, Source: SourceDefault}
+}
+
+// checkFileKeys rejects config file entries Load never looked up, which are
+// almost always misspelled settings
+func checkFileKeys(path string) error {
+	var unknown []string
+	for key := range fileEnv {
+		if _, ok := resolved[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("CONFIG_FILE %s: unknown settings %s", path, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// EffectiveConfig returns every setting with the value in effect and its source,
+// sorted by key. Secrets are redacted, so the result is safe to print.
+func (c *Config) EffectiveConfig() []Setting {
+	settings := make([]Setting, len(c.settings))
+	for i, setting := range c.settings {
+		setting.Value = redactSetting(setting.Key, setting.Value)
+		settings[i] = setting
+	}
+	return settings
+}
+
+// redactSetting hides API keys, webhook and tenant secrets, and passwords embedded in URLs
+func redactSetting(key, value string) string {
+	const redacted = "[redacted]"
+	if value == "" {
+		return value
+	}
+
+	switch {
+	case strings.HasSuffix(key, "_KEY") || strings.Contains(key, "SECRET") || strings.Contains(key, "TOKEN") || strings.Contains(key, "PASSWORD"):
+		return redacted
+	case key == "WEBHOOKS" || key == "TENANTS":
+		entries := strings.Split(value, ",")
+		for i, entry := range entries {
+			if parts := strings.Split(entry, "|"); len(parts) >= 2 {
+				parts[1] = redacted
+				entries[i] = strings.Join(parts, "|")
+			}
+		}
+		return strings.Join(entries, ",")
+	case strings.HasSuffix(key, "_URL"):
+		if u, err := url.Parse(value); err == nil {
+			return u.Redacted()
+		}
+	}
+	return value
+}
+
+// sortedSettings returns the settings resolved by the Load in progress
+func sortedSettings() []Setting {
+	settings := make([]Setting, 0, len(resolved))
+	for _, setting := range resolved {
+		settings = append(settings, setting)
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
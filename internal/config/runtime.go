@@ -0,0 +1,110 @@
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RuntimeSettings holds the settings operators can tune while the server is running
+type RuntimeSettings struct {
+	OpenAIModel                       string
+	OpenAITemperature                 float32
+	OpenAIMaxTokens                   int
+	ContextMinScore                   float32
+	ContextMaxMessagesPerConversation int
+	ContextRerankEnabled              bool
+	ContextSummaryEnabled             bool
+	ContextQueryStrategy              string
+	QuestionCacheTTL                  time.Duration
+	ConfidenceHighThreshold           float32
+	ConfidenceMediumThreshold         float32
+	MemoryEvaluationWeights           [3]float32
+	MemoryEvaluationDifficultyWeights map[string][3]float32
+	LogLevel                          string
+	LogSampleRate                     float64
+	LogDebugUsers                     []string
+	DisabledFeatures                  []string
+}
+
+// FeatureDisabled reports whether a safe-mode kill switch has turned feature off
+func (s RuntimeSettings) FeatureDisabled(feature string) bool {
+	for _, f := range s.DisabledFeatures {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// Runtime is an atomically swapped snapshot of RuntimeSettings.
+// Readers never block; writers are serialized so concurrent updates are not lost.
+type Runtime struct {
+	current atomic.Pointer[RuntimeSettings]
+	mu      sync.Mutex
+}
+
+// NewRuntime creates a runtime snapshot seeded from the loaded configuration
+func NewRuntime(cfg *Config) *Runtime {
+	r := &Runtime{}
+	settings := runtimeSettings(cfg)
+	r.current.Store(&settings)
+	return r
+}
+
+// runtimeSettings picks the runtime-tunable settings out of a configuration
+func runtimeSettings(cfg *Config) RuntimeSettings {
+	return RuntimeSettings{
+		OpenAIModel:                       cfg.OpenAIModel,
+		OpenAITemperature:                 cfg.OpenAITemperature,
+		OpenAIMaxTokens:                   cfg.OpenAIMaxTokens,
+		ContextMinScore:                   cfg.ContextMinScore,
+		ContextMaxMessagesPerConversation: cfg.ContextMaxMessagesPerConversation,
+		ContextRerankEnabled:              cfg.ContextRerankEnabled,
+		ContextSummaryEnabled:             cfg.ContextSummaryEnabled,
+		ContextQueryStrategy:              cfg.ContextQueryStrategy,
+		QuestionCacheTTL:                  cfg.QuestionCacheTTL,
+		ConfidenceHighThreshold:           cfg.ConfidenceHighThreshold,
+		ConfidenceMediumThreshold:         cfg.ConfidenceMediumThreshold,
+		MemoryEvaluationWeights:           cfg.MemoryEvaluationWeights,
+		MemoryEvaluationDifficultyWeights: cfg.MemoryEvaluationDifficultyWeights,
+		LogLevel:                          cfg.LogLevel,
+		LogSampleRate:                     cfg.LogSampleRate,
+		LogDebugUsers:                     cfg.LogDebugUsers,
+		DisabledFeatures:                  cfg.DisabledFeatures,
+	}
+}
+
+// Get returns a copy of the current settings
+func (r *Runtime) Get() RuntimeSettings {
+	return *r.current.Load()
+}
+
+// Update applies fn to a copy of the current settings and swaps the copy in
+func (r *Runtime) Update(fn func(*RuntimeSettings)) RuntimeSettings {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := *r.current.Load()
+	fn(&next)
+
+	r.current.Store(&next)
+	return next
+}
+
+// Reload re-reads the environment and CONFIG_FILE and swaps their runtime settings
+// in, replacing any admin overrides. Safe-mode switches keep their current state,
+// and settings outside RuntimeSettings still need a restart. When the new
+// configuration does not validate, the current settings stay in effect.
+func (r *Runtime) Reload() (RuntimeSettings, error) {
+	cfg, err := Load()
+	if err != nil {
+		return RuntimeSettings{}, err
+	}
+
+	next := runtimeSettings(cfg)
+	return r.Update(func(s *RuntimeSettings) {
+		next.DisabledFeatures = s.DisabledFeatures
+		*s = next
+	}), nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tenantIDPattern keeps tenant IDs safe to use in metric keys and log tags
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// Tenant is a care facility with its own API key. A user belongs to the tenant
+// with the longest UserPrefix their ID starts with; the tenant with an empty
+// prefix, if any, owns the users no other tenant claims.
+type Tenant struct {
+	ID         string
+	APIKey     string
+	UserPrefix string
+}
+
+// Tenants are the facilities sharing the server, empty when it serves a single one
+type Tenants []Tenant
+
+// ByAPIKey returns the tenant an API key belongs to
+func (ts Tenants) ByAPIKey(apiKey string) (Tenant, bool) {
+	var found Tenant
+	ok := false
+	for _, tenant := range ts {
+		// Every key is compared so the time taken does not reveal which one matched
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(tenant.APIKey)) == 1 {
+			found, ok = tenant, true
+		}
+	}
+	return found, ok
+}
+
+// Owner returns the tenant a user ID belongs to
+func (ts Tenants) Owner(userID string) (Tenant, bool) {
+	var owner Tenant
+	ok := false
+	for _, tenant := range ts {
+		if strings.HasPrefix(userID, tenant.UserPrefix) && (!ok || len(tenant.UserPrefix) > len(owner.UserPrefix)) {
+			owner, ok = tenant, true
+		}
+	}
+	return owner, ok
+}
+
+// parseTenants parses "id|api_key|user_prefix" entries separated by commas. The
+// prefix may be empty for at most one tenant, typically the facility whose users
+// predate multi-tenancy.
+func parseTenants(tenantStr string) (Tenants, error) {
+	var tenants Tenants
+	ids := make(map[string]bool)
+	keys := make(map[string]bool)
+	prefixes := make(map[string]string)
+
+	for _, entry := range strings.Split(tenantStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "|")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid TENANTS entry: expected id|api_key|user_prefix")
+		}
+		tenant := Tenant{
+			ID:         strings.TrimSpace(parts[0]),
+			APIKey:     strings.TrimSpace(parts[1]),
+			UserPrefix: strings.TrimSpace(parts[2]),
+		}
+
+		if !tenantIDPattern.MatchString(tenant.ID) {
+			return nil, fmt.Errorf("invalid TENANTS id %q: use lowercase letters, digits, - and _", tenant.ID)
+		}
+		if ids[tenant.ID] {
+			return nil, fmt.Errorf("invalid TENANTS: duplicate tenant %q", tenant.ID)
+		}
+		if tenant.APIKey == "" {
+			return nil, fmt.Errorf("invalid TENANTS entry for %q: api_key is required", tenant.ID)
+		}
+		if keys[tenant.APIKey] {
+			return nil, fmt.Errorf("invalid TENANTS entry for %q: api_key is shared with another tenant", tenant.ID)
+		}
+		if other, taken := prefixes[tenant.UserPrefix]; taken {
+			return nil, fmt.Errorf("invalid TENANTS entry for %q: user_prefix %q is also used by %q", tenant.ID, tenant.UserPrefix, other)
+		}
+
+		ids[tenant.ID] = true
+		keys[tenant.APIKey] = true
+		prefixes[tenant.UserPrefix] = tenant.ID
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
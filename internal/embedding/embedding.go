@@ -0,0 +1,178 @@
+// Package embedding turns text into vectors for similarity search. Service
+// calls the OpenAI embeddings API and falls back to Local, a hashed character
+// bigram vector that needs no network, when the API is unavailable.
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"llm/internal/chaos"
+	"llm/internal/config"
+	"llm/internal/httptransport"
+	"llm/internal/metrics"
+	"llm/internal/textutil"
+	"llm/internal/util"
+)
+
+// LocalModel names vectors produced by Local
+const LocalModel = "local-ngram"
+
+// LocalDimensions is the length of vectors produced by Local
+const LocalDimensions = 256
+
+// DefaultBaseURL is the OpenAI API base URL used when OPENAI_BASE_URL is not set
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// RequestTimeout bounds a single embeddings call
+const RequestTimeout = 10 * time.Second
+
+// Vector is an embedding and the model that produced it. Vectors from
+// different models are not comparable.
+type Vector struct {
+	Model  string
+	Values []float32
+}
+
+// Local embeds text without calling a model by hashing its character bigrams
+// (see textutil.NGramVector) into LocalDimensions buckets
+func Local(text string) Vector {
+	values := make([]float32, LocalDimensions)
+	for gram, count := range textutil.NGramVector(text) {
+		h := fnv.New32a()
+		h.Write([]byte(gram))
+		values[h.Sum32()%LocalDimensions] += float32(count)
+	}
+	return Vector{Model: LocalModel, Values: values}
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 when they come
+// from different models or either is empty
+func Cosine(a, b Vector) float32 {
+	if a.Model != b.Model || len(a.Values) == 0 || len(a.Values) != len(b.Values) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a.Values {
+		dot += float64(a.Values[i]) * float64(b.Values[i])
+		normA += float64(a.Values[i]) * float64(a.Values[i])
+		normB += float64(b.Values[i]) * float64(b.Values[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
+// Service embeds text with the configured embeddings model. The embeddings
+// endpoint is called directly since the go-openai client only accepts the
+// first-generation embedding models.
+type Service struct {
+	httpClient *http.Client // nil embeds every text with Local
+	baseURL    string
+	apiKey     atomic.Pointer[string]
+	model      string
+	logger     *util.Logger
+}
+
+// New creates an embedding service. With the fake LLM, or OPENAI_EMBEDDING_MODEL
+// set to "local", every text is embedded with Local.
+func New(cfg *config.Config) *Service {
+	s := &Service{
+		model:  cfg.OpenAIEmbeddingModel,
+		logger: util.NewLogger("EmbeddingService"),
+	}
+	if cfg.FakeLLMEnabled || cfg.OpenAIEmbeddingModel == config.EmbeddingModelLocal {
+		return s
+	}
+
+	if mapped, ok := cfg.OpenAIModelMap[s.model]; ok {
+		s.model = mapped
+	}
+
+	s.baseURL = DefaultBaseURL
+	if cfg.OpenAIBaseURL != "" {
+		s.baseURL = strings.TrimRight(cfg.OpenAIBaseURL, "/")
+	}
+	s.SetAPIKey(cfg.OpenAIAPIKey)
+	s.httpClient = &http.Client{
+		Timeout:   RequestTimeout,
+		Transport: chaos.WrapTransport(cfg, chaos.TargetOpenAI, httptransport.OpenAI(cfg)),
+	}
+	return s
+}
+
+// SetAPIKey switches to a rotated API key for the embeddings requests that follow
+func (s *Service) SetAPIKey(apiKey string) {
+	s.apiKey.Store(&apiKey)
+}
+
+// Embed returns the embedding of text. It never fails: when the embeddings API
+// returns an error the local vector is returned instead.
+func (s *Service) Embed(ctx context.Context, text string) Vector {
+	if s.httpClient == nil {
+		return Local(text)
+	}
+
+	vector, err := s.embedRemote(ctx, text)
+	if err != nil {
+		metrics.EmbeddingFallbacks.Add(1)
+		s.logger.WithContext(ctx).Warn("Failed to embed text, using local vector", err)
+		return Local(text)
+	}
+	return vector
+}
+
+func (s *Service) embedRemote(ctx context.Context, text string) (Vector, error) {
+	body, err := json.Marshal(map[string]any{
+		"model": s.model,
+		"input": []string{text},
+	})
+	if err != nil {
+		return Vector{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return Vector{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+*s.apiKey.Load())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return Vector{}, fmt.Errorf("failed to call embeddings API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Vector{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Vector{}, fmt.Errorf("embeddings failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(respBody), textutil.MaxErrorBodyRunes))
+	}
+
+	var apiResp struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return Vector{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if len(apiResp.Data) == 0 || len(apiResp.Data[0].Embedding) == 0 {
+		return Vector{}, fmt.Errorf("embeddings failed: no embedding returned")
+	}
+	return Vector{Model: s.model, Values: apiResp.Data[0].Embedding}, nil
+}
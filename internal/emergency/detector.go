@@ -0,0 +1,67 @@
+package emergency
+
+import (
+	"regexp"
+)
+
+// Signals an emergency phrase can indicate
+const (
+	SignalChestPain  = "chest_pain"
+	SignalFall       = "fall"
+	SignalBreathing  = "breathing"
+	SignalStroke     = "stroke"
+	SignalCallToHelp = "call_for_help"
+)
+
+// Phrase is a Korean distress pattern and the signal it indicates
+type Phrase struct {
+	Signal  string
+	Pattern *regexp.Regexp
+}
+
+// DefaultPhrases covers first-person distress an older user may mention in passing
+var DefaultPhrases = []Phrase{
+	{SignalChestPain, regexp.MustCompile(`가슴이?\s*(너무\s*)?(아파|아프|답답|조여|쥐어)`)},
+	{SignalChestPain, regexp.MustCompile(`심장이?\s*(너무\s*)?(아파|아프|이상)`)},
+	{SignalFall, regexp.MustCompile(`(넘어졌|넘어져서|쓰러졌|쓰러져서|미끄러졌)`)},
+	// Falling and not getting up only count from somewhere or with the body named, so money or
+	// grades dropping and oversleeping ("아침에 못 일어났어") are not taken for a fall
+	{SignalFall, regexp.MustCompile(`(계단|침대|의자|사다리|지붕|높은\s*(데|곳))에서\s*(굴러\s*)?떨어졌`)},
+	{SignalFall, regexp.MustCompile(`((다리|허리|무릎)(가|이|에)?\s*.{0,8}|바닥에서\s*)(못|안)\s*일어나`)},
+	{SignalBreathing, regexp.MustCompile(`숨(이|을)?\s*(잘\s*)?(안|못)\s*(쉬어|쉬겠|쉴)`)},
+	{SignalBreathing, regexp.MustCompile(`숨이\s*(차|막혀|막히)`)},
+	{SignalStroke, regexp.MustCompile(`(말이|발음이)\s*(잘\s*)?안\s*(나와|돼)`)},
+	{SignalStroke, regexp.MustCompile(`(한쪽|팔|다리|얼굴).{0,6}(마비|저려서\s*못|힘이\s*안)`)},
+	{SignalCallToHelp, regexp.MustCompile(`(살려\s*주세요|살려줘|도와\s*주세요.{0,6}(아파|다쳤))`)},
+	{SignalCallToHelp, regexp.MustCompile(`119\s*(불러|좀|에)`)},
+}
+
+// Match is a phrase that matched, with the matched text
+type Match struct {
+	Phrase Phrase
+	Text   string
+}
+
+// Detect returns the phrases that match text
+func Detect(phrases []Phrase, text string) []Match {
+	var matches []Match
+	for _, phrase := range phrases {
+		if found := phrase.Pattern.FindString(text); found != "" {
+			matches = append(matches, Match{Phrase: phrase, Text: found})
+		}
+	}
+	return matches
+}
+
+// Signals returns the distinct signals of matches in detection order
+func Signals(matches []Match) []string {
+	seen := make(map[string]bool)
+	signals := []string{}
+	for _, match := range matches {
+		if !seen[match.Phrase.Signal] {
+			seen[match.Phrase.Signal] = true
+			signals = append(signals, match.Phrase.Signal)
+		}
+	}
+	return signals
+}
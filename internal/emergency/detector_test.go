@@ -0,0 +1,43 @@
+package emergency
+
+import (
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "chest pain", text: "가슴이 너무 아파요", want: []string{SignalChestPain}},
+		{name: "fall", text: "화장실에서 넘어졌어", want: []string{SignalFall}},
+		{name: "fall from the stairs", text: "계단에서 굴러 떨어졌어요", want: []string{SignalFall}},
+		{name: "fall from the bed", text: "어젯밤에 침대에서 떨어졌어", want: []string{SignalFall}},
+		{name: "cannot get up on a bad leg", text: "다리가 아파서 못 일어나겠어", want: []string{SignalFall}},
+		{name: "cannot get up off the floor", text: "바닥에서 안 일어나져", want: []string{SignalFall}},
+		{name: "breathing", text: "숨이 잘 안 쉬어져", want: []string{SignalBreathing}},
+		{name: "call for help", text: "살려주세요", want: []string{SignalCallToHelp}},
+		{name: "several signals in order", text: "넘어졌는데 가슴이 답답해", want: []string{SignalChestPain, SignalFall}},
+		{name: "money running out", text: "생활비가 다 떨어졌어요"},
+		{name: "grades dropping", text: "손주 성적이 떨어졌어요"},
+		{name: "leaves falling", text: "낙엽이 많이 떨어졌어요"},
+		{name: "oversleeping", text: "오늘 아침에 늦잠 자서 못 일어났어"},
+		{name: "not getting up early", text: "요즘은 일찍 안 일어나요"},
+		{name: "small talk", text: "오늘 날씨가 참 좋네요"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Signals(Detect(DefaultPhrases, tt.text))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Signals() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Signals() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package emergency
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Contact is a caregiver to notify, taken from an emergency profile entry
+type Contact struct {
+	Description string `json:"description"` // the profile entry as written, e.g. "딸 김영희 010-1234-5678"
+	Phone       string `json:"phone,omitempty"`
+}
+
+// Notification is the delivery result for one channel and recipient
+type Notification struct {
+	Channel string `json:"channel"` // webhook, sms
+	To      string `json:"to,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Incident is a recorded emergency detection and the alerts sent for it
+type Incident struct {
+	ID            string         `json:"incident_id"`
+	RequestID     string         `json:"request_id,omitempty"`
+	UserID        string         `json:"user_id"`
+	Signals       []string       `json:"signals"`
+	Matches       []string       `json:"matches"`
+	Message       string         `json:"message"`
+	Contacts      []Contact      `json:"contacts"`
+	Notifications []Notification `json:"notifications"`
+	DetectedAt    time.Time      `json:"detected_at"`
+}
+
+var phonePattern = regexp.MustCompile(`0\d{1,2}[-\s.]?\d{3,4}[-\s.]?\d{4}`)
+
+// ParseContact extracts a phone number from a free-text profile entry
+func ParseContact(description string) Contact {
+	return Contact{
+		Description: description,
+		Phone:       phonePattern.FindString(description),
+	}
+}
+
+// IncidentLog appends incidents to a daily JSONL file
+type IncidentLog struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewIncidentLog creates an incident log writing into dir
+func NewIncidentLog(dir string) (*IncidentLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create incident directory: %w", err)
+	}
+	return &IncidentLog{dir: dir}, nil
+}
+
+// Write appends an incident
+func (l *IncidentLog) Write(incident Incident) error {
+	line, err := json.Marshal(incident)
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	path := filepath.Join(l.dir, "emergency-"+incident.DetectedAt.Format("2006-01-02")+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open incident file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write incident: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package emergency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"llm/internal/textutil"
+)
+
+// Notification channels
+const (
+	ChannelWebhook = "webhook"
+	ChannelSMS     = "sms"
+)
+
+// Notifier alerts caregivers through a webhook and an SMS gateway hook.
+// Either URL may be empty to disable that channel.
+type Notifier struct {
+	webhookURL string
+	smsURL     string
+	httpClient *http.Client
+}
+
+// NewNotifier creates a notifier
+func NewNotifier(webhookURL, smsURL string, timeout time.Duration) *Notifier {
+	return &Notifier{
+		webhookURL: webhookURL,
+		smsURL:     smsURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Enabled reports whether any channel is configured
+func (n *Notifier) Enabled() bool {
+	return n.webhookURL != "" || n.smsURL != ""
+}
+
+type smsPayload struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// Notify posts the incident to the webhook and texts every contact with a phone number.
+// Each delivery is attempted independently and reported in the result.
+func (n *Notifier) Notify(ctx context.Context, incident Incident, smsText string) []Notification {
+	notifications := []Notification{}
+
+	if n.webhookURL != "" {
+		notifications = append(notifications, n.deliver(ctx, ChannelWebhook, "", n.webhookURL, incident))
+	}
+
+	if n.smsURL != "" {
+		for _, contact := range incident.Contacts {
+			if contact.Phone == "" {
+				continue
+			}
+			notifications = append(notifications, n.deliver(ctx, ChannelSMS, contact.Phone, n.smsURL, smsPayload{
+				To:      contact.Phone,
+				Message: smsText,
+			}))
+		}
+	}
+
+	return notifications
+}
+
+func (n *Notifier) deliver(ctx context.Context, channel, to, url string, payload interface{}) Notification {
+	notification := Notification{Channel: channel, To: to}
+	if err := n.post(ctx, url, payload); err != nil {
+		notification.Error = err.Error()
+	} else {
+		notification.Success = true
+	}
+	return notification
+}
+
+func (n *Notifier) post(ctx context.Context, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("notification failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(body), textutil.MaxErrorBodyRunes))
+	}
+	return nil
+}
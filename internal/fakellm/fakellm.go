@@ -0,0 +1,451 @@
+// Package fakellm provides a deterministic service.LLMService for tests, CI, and
+// local development without an OpenAI API key. Every method replays a canned
+// JSON response keyed by its prompt type; responses can be scripted per call,
+// overridden from a file, or made to fail. Canned questions take their correct
+// answer from the source text, so they pass the service's grounding check.
+package fakellm
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"llm/internal/models"
+	"llm/internal/prompts"
+	"llm/internal/service"
+	"llm/internal/textutil"
+)
+
+// Prompt types, the keys of canned responses
+const (
+	PromptChat               = "chat"
+	PromptSmallTalk          = "small_talk"
+	PromptSummary            = "summary"
+	PromptSearchQuery        = "search_query"
+	PromptRerank             = "rerank"
+	PromptFollowUps          = "follow_ups"
+	PromptFillInBlank        = "fill_in_blank"
+	PromptMultipleChoice     = "multiple_choice"
+	PromptIntegrative        = "integrative"
+	PromptPhotoCaption       = "photo_caption"
+	PromptPhotoQuestion      = "photo"
+	PromptQuestionSet        = "question_set"
+	PromptRecap              = "recap"
+	PromptResponseEvaluation = "response_evaluation"
+	PromptSentiment          = "sentiment"
+	PromptTopics             = "topics"
+	PromptAnswerExplanation  = "answer_explanation"
+	PromptMemoryEvaluation   = "memory_evaluation"
+	PromptModeration         = "moderation"
+	PromptDomains            = "domains"
+	PromptReport             = "report"
+)
+
+// Model is the model name the fake reports for cohorts and metadata
+const Model = "fake"
+
+//go:embed responses.json
+var defaultResponses []byte
+
+// LLM is a scripted fake language model. Scripted responses are replayed in
+// order before falling back to the canned response for the prompt type.
+type LLM struct {
+	responses map[string]json.RawMessage
+	scripts   map[string][]json.RawMessage
+	errors    map[string]error
+	calls     map[string]int
+	mutex     sync.Mutex
+}
+
+var _ service.LLMService = (*LLM)(nil)
+
+// New creates a fake with the built-in canned responses
+func New() *LLM {
+	f := &LLM{
+		responses: make(map[string]json.RawMessage),
+		scripts:   make(map[string][]json.RawMessage),
+		errors:    make(map[string]error),
+		calls:     make(map[string]int),
+	}
+	if err := json.Unmarshal(defaultResponses, &f.responses); err != nil {
+		panic(fmt.Sprintf("fakellm: invalid built-in responses: %v", err))
+	}
+	return f
+}
+
+// Load creates a fake whose canned responses from the JSON file at path
+// (an object keyed by prompt type) override the built-in ones
+func Load(path string) (*LLM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake LLM responses: %w", err)
+	}
+
+	var overrides map[string]json.RawMessage
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse fake LLM responses %s: %w", path, err)
+	}
+
+	f := New()
+	for promptType, response := range overrides {
+		f.responses[promptType] = response
+	}
+	return f, nil
+}
+
+// Set replaces the canned response for a prompt type. response is marshaled to JSON.
+func (f *LLM) Set(promptType string, response interface{}) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("fakellm: failed to marshal %s response: %w", promptType, err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.responses[promptType] = data
+	return nil
+}
+
+// Script queues raw JSON responses for the next calls of a prompt type
+func (f *LLM) Script(promptType string, responses ...string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, response := range responses {
+		f.scripts[promptType] = append(f.scripts[promptType], json.RawMessage(response))
+	}
+}
+
+// SetError makes every later call of a prompt type fail with err; a nil err clears it
+func (f *LLM) SetError(promptType string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err == nil {
+		delete(f.errors, promptType)
+		return
+	}
+	f.errors[promptType] = err
+}
+
+// Calls returns how many times a prompt type has been requested
+func (f *LLM) Calls(promptType string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.calls[promptType]
+}
+
+// respond decodes the next response for a prompt type into out
+func (f *LLM) respond(promptType string, out interface{}) error {
+	_, err := f.respondScripted(promptType, out)
+	return err
+}
+
+// respondScripted decodes the next response for a prompt type into out and reports
+// whether it was scripted rather than canned
+func (f *LLM) respondScripted(promptType string, out interface{}) (bool, error) {
+	f.mutex.Lock()
+	f.calls[promptType]++
+	if err := f.errors[promptType]; err != nil {
+		f.mutex.Unlock()
+		return false, err
+	}
+	response, ok := f.responses[promptType]
+	scripted := false
+	if script := f.scripts[promptType]; len(script) > 0 {
+		response, ok, scripted = script[0], true, true
+		f.scripts[promptType] = script[1:]
+	}
+	f.mutex.Unlock()
+
+	if !ok {
+		return false, fmt.Errorf("fakellm: no response for prompt type %s", promptType)
+	}
+	if err := json.Unmarshal(response, out); err != nil {
+		return false, fmt.Errorf("fakellm: failed to decode %s response: %w", promptType, err)
+	}
+	return scripted, nil
+}
+
+func (f *LLM) text(promptType string) (string, error) {
+	var text string
+	if err := f.respond(promptType, &text); err != nil {
+		return "", err
+	}
+	return text, nil
+}
+
+// question replays a question about source. A canned question whose correct answer the
+// source does not mention gets a word of the source as its answer, as a model would quote
+// the conversation; scripted questions are returned as written.
+func (f *LLM) question(promptType string, source string) (*models.GameQuestionResponse, error) {
+	var question models.GameQuestionResponse
+	scripted, err := f.respondScripted(promptType, &question)
+	if err != nil {
+		return nil, err
+	}
+	if !scripted {
+		texts := make([]string, len(question.Options))
+		for i, opt := range question.Options {
+			texts[i] = opt.Text
+		}
+		for i, opt := range question.Options {
+			if strings.EqualFold(opt.ID, question.CorrectAnswer) {
+				question.Options[i].Text = groundAnswer(texts, i, source)
+			}
+		}
+	}
+	return &question, nil
+}
+
+// groundAnswer returns the answer, texts[answer], if source mentions it, and otherwise the
+// longest word of source that is not another option
+func groundAnswer(texts []string, answer int, source string) string {
+	normalized := strings.ToLower(textutil.Normalize(source))
+	if strings.Contains(normalized, strings.ToLower(textutil.Normalize(texts[answer]))) {
+		return texts[answer]
+	}
+
+	others := make(map[string]bool, len(texts))
+	for i, text := range texts {
+		if i != answer {
+			others[strings.ToLower(textutil.Normalize(text))] = true
+		}
+	}
+
+	best := ""
+	for _, field := range strings.Fields(normalized) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if utf8.RuneCountInString(word) > utf8.RuneCountInString(best) && !others[word] {
+			best = word
+		}
+	}
+	if best == "" {
+		return texts[answer]
+	}
+	return best
+}
+
+// ValidateOverrides accepts every override
+func (f *LLM) ValidateOverrides(overrides models.GenerationOverrides) error {
+	return nil
+}
+
+// EffectiveModel returns the requested model, or Model
+func (f *LLM) EffectiveModel(task string, overrides models.GenerationOverrides) string {
+	if overrides.Model != "" {
+		return overrides.Model
+	}
+	return Model
+}
+
+// GenerateChatResponse replays a chat response
+func (f *LLM) GenerateChatResponse(ctx context.Context, userMessage string, contextMessages []string) (string, error) {
+	return f.text(PromptChat)
+}
+
+// GenerateChatResponseWithProfile replays a chat response
+func (f *LLM) GenerateChatResponseWithProfile(ctx context.Context, userMessage string, data prompts.ChatPromptData) (string, error) {
+	return f.text(PromptChat)
+}
+
+// GenerateSmallTalkResponse replays a small talk response
+func (f *LLM) GenerateSmallTalkResponse(ctx context.Context, userMessage string, language string) (string, error) {
+	return f.text(PromptSmallTalk)
+}
+
+// SummarizeConversations replays a context summary
+func (f *LLM) SummarizeConversations(ctx context.Context, contextMessages []string) (string, error) {
+	return f.text(PromptSummary)
+}
+
+// GenerateSearchQuery replays a search query
+func (f *LLM) GenerateSearchQuery(ctx context.Context, userMessage string, profileInfo *models.PersonalInfoListResponse) (string, error) {
+	return f.text(PromptSearchQuery)
+}
+
+// RerankContext replays a reranked candidate list, keeping the original order when none is canned
+func (f *LLM) RerankContext(ctx context.Context, userMessage string, candidates []string) ([]string, error) {
+	f.mutex.Lock()
+	_, canned := f.responses[PromptRerank]
+	if !canned && len(f.scripts[PromptRerank]) == 0 {
+		f.calls[PromptRerank]++
+		err := f.errors[PromptRerank]
+		f.mutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		return candidates, nil
+	}
+	f.mutex.Unlock()
+
+	var reranked []string
+	if err := f.respond(PromptRerank, &reranked); err != nil {
+		return nil, err
+	}
+	return reranked, nil
+}
+
+// GenerateFillInTheBlankQuestion replays a fill-in-the-blank question about the conversation
+func (f *LLM) GenerateFillInTheBlankQuestion(ctx context.Context, conversationContent string, topic string) (*models.GameQuestionResponse, error) {
+	return f.question(PromptFillInBlank, conversationContent)
+}
+
+// GenerateMultipleChoiceQuestion replays a multiple choice question about the conversation
+func (f *LLM) GenerateMultipleChoiceQuestion(ctx context.Context, conversationContent string, topic string) (*models.GameQuestionResponse, error) {
+	return f.question(PromptMultipleChoice, conversationContent)
+}
+
+// GenerateIntegrativeQuestion replays an integrative question about the conversations
+func (f *LLM) GenerateIntegrativeQuestion(ctx context.Context, conversationContents []string, topic string) (*models.GameQuestionResponse, error) {
+	return f.question(PromptIntegrative, strings.Join(conversationContents, "\n\n"))
+}
+
+// DescribePhoto replays a photo caption
+func (f *LLM) DescribePhoto(ctx context.Context, imageURL string) (string, error) {
+	return f.text(PromptPhotoCaption)
+}
+
+// GeneratePhotoQuestion replays a photo question about the description
+func (f *LLM) GeneratePhotoQuestion(ctx context.Context, description string) (*models.GameQuestionResponse, error) {
+	return f.question(PromptPhotoQuestion, description)
+}
+
+// GenerateThemedQuestionSet replays a question set, keeping at most count questions. Canned
+// answers are grounded in the conversation each question names as its source.
+func (f *LLM) GenerateThemedQuestionSet(ctx context.Context, themeDescription string, conversationContents []string, count int) (*service.QuestionSet, error) {
+	var set service.QuestionSet
+	scripted, err := f.respondScripted(PromptQuestionSet, &set)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && len(set.Questions) > count {
+		set.Questions = set.Questions[:count]
+	}
+	if !scripted {
+		for _, item := range set.Questions {
+			if item.Source < 1 || item.Source > len(conversationContents) {
+				continue
+			}
+			texts := make([]string, len(item.Options))
+			for i, opt := range item.Options {
+				texts[i] = opt.Text
+			}
+			for i, opt := range item.Options {
+				if strings.EqualFold(opt.ID, item.CorrectAnswer) {
+					item.Options[i].Text = groundAnswer(texts, i, conversationContents[item.Source-1])
+				}
+			}
+		}
+	}
+	return &set, nil
+}
+
+// GenerateRecapCards replays recap cards, keeping at most count cards
+func (f *LLM) GenerateRecapCards(ctx context.Context, conversationContents []string, missedTopics []string, count int) (*service.RecapSet, error) {
+	var set service.RecapSet
+	if err := f.respond(PromptRecap, &set); err != nil {
+		return nil, err
+	}
+	if count > 0 && len(set.Cards) > count {
+		set.Cards = set.Cards[:count]
+	}
+	return &set, nil
+}
+
+// EvaluateUserResponseQuality replays a response quality score
+func (f *LLM) EvaluateUserResponseQuality(ctx context.Context, userMessage string, contextMessages []string, profileInfo *models.PersonalInfoListResponse) (int, error) {
+	var score int
+	if err := f.respond(PromptResponseEvaluation, &score); err != nil {
+		return 0, err
+	}
+	return score, nil
+}
+
+// AnalyzeSentiment replays a sentiment
+func (f *LLM) AnalyzeSentiment(ctx context.Context, userMessage, assistantResponse string) (*models.Sentiment, error) {
+	var sentiment models.Sentiment
+	if err := f.respond(PromptSentiment, &sentiment); err != nil {
+		return nil, err
+	}
+	return &sentiment, nil
+}
+
+// ClassifyTopics replays conversation topics
+func (f *LLM) ClassifyTopics(ctx context.Context, userMessage, assistantResponse string) ([]string, error) {
+	var result struct {
+		Topics []string `json:"topics"`
+	}
+	if err := f.respond(PromptTopics, &result); err != nil {
+		return nil, err
+	}
+	return result.Topics, nil
+}
+
+// SuggestFollowUps replays follow-up suggestions
+func (f *LLM) SuggestFollowUps(ctx context.Context, userMessage, assistantResponse string, language string) ([]string, error) {
+	var result struct {
+		Suggestions []string `json:"suggestions"`
+	}
+	if err := f.respond(PromptFollowUps, &result); err != nil {
+		return nil, err
+	}
+	return result.Suggestions, nil
+}
+
+// ExplainAnswer replays an answer explanation
+func (f *LLM) ExplainAnswer(ctx context.Context, q *models.StoredQuestion, userAnswer string, isCorrect bool) (string, error) {
+	return f.text(PromptAnswerExplanation)
+}
+
+// EvaluateMemory replays a memory evaluation for the topic
+func (f *LLM) EvaluateMemory(ctx context.Context, question string, userAnswer string, isCorrect bool, responseTimeMs int64, topic string) (*models.MemoryEvaluation, error) {
+	var evaluation models.MemoryEvaluation
+	if err := f.respond(PromptMemoryEvaluation, &evaluation); err != nil {
+		return nil, err
+	}
+	evaluation.Topic = topic
+	return &evaluation, nil
+}
+
+// Moderate replays the flagged moderation categories
+func (f *LLM) Moderate(ctx context.Context, text string) ([]string, error) {
+	var flagged []string
+	if err := f.respond(PromptModeration, &flagged); err != nil {
+		return nil, err
+	}
+	return flagged, nil
+}
+
+// AnalyzeDomains replays domain scores
+func (f *LLM) AnalyzeDomains(ctx context.Context, conversationHistory []string, incorrectQuizzes []string) ([]models.DomainScore, error) {
+	var domains []models.DomainScore
+	if err := f.respond(PromptDomains, &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// GenerateAnalysisReport replays a report
+func (f *LLM) GenerateAnalysisReport(ctx context.Context, domains []models.DomainScore, consolidation *models.ConsolidationMetric) (string, error) {
+	return f.text(PromptReport)
+}
+
+// GenerateReportFromDomainScores replays a report
+func (f *LLM) GenerateReportFromDomainScores(ctx context.Context, familyScore int, familyInsights []string, lifeEventsScore int, lifeEventsInsights []string, careerScore int, careerInsights []string, hobbiesScore int, hobbiesInsights []string) (string, error) {
+	return f.text(PromptReport)
+}
+
+// UnavailableModels reports no unavailable models; the fake serves every model
+func (f *LLM) UnavailableModels() []models.ModelWarning {
+	return nil
+}
@@ -0,0 +1,257 @@
+package fakellm_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"llm/internal/client/ragtest"
+	"llm/internal/config"
+	"llm/internal/fakellm"
+	"llm/internal/models"
+	"llm/internal/service"
+	"llm/internal/testutil"
+	"llm/internal/util"
+)
+
+func newUserService(t *testing.T, cfg *config.Config) *service.UserService {
+	t.Helper()
+
+	userService, err := service.NewUserService(cfg)
+	if err != nil {
+		t.Fatalf("NewUserService() error = %v", err)
+	}
+	return userService
+}
+
+func newGameService(t *testing.T, cfg *config.Config, store *ragtest.Fake, llm *fakellm.LLM) *service.GameService {
+	t.Helper()
+	return service.NewGameService(cfg, store, llm, service.NewExperimentService(cfg), service.NewQuizHistoryService(), service.NewWebhookService(cfg), newUserService(t, cfg))
+}
+
+func newChatService(t *testing.T, cfg *config.Config, store *ragtest.Fake, llm *fakellm.LLM) *service.ChatService {
+	t.Helper()
+
+	webhookService := service.NewWebhookService(cfg)
+	return service.NewChatService(cfg, store, llm, service.NewExperimentService(cfg), service.NewGuardrailService(cfg, llm),
+		service.NewEmergencyService(cfg, store, webhookService), service.NewMoodService(), service.NewPlanService(),
+		service.NewReminderService(), service.NewAssessmentService(), service.NewShadowService(cfg, llm), newUserService(t, cfg))
+}
+
+// seedConversations gives the user count conversations about messages, one a day up to yesterday
+func seedConversations(store *ragtest.Fake, userID string, count int, messages ...string) {
+	for i := 0; i < count; i++ {
+		conv := models.RAGConversationSearchResult{
+			ConversationID: fmt.Sprintf("conv-%d", i),
+			Timestamp:      time.Now().AddDate(0, 0, -count+i),
+		}
+		for _, message := range messages {
+			conv.Messages = append(conv.Messages, models.RAGMessage{Role: "user", Content: message})
+		}
+		store.AddConversation(userID, conv)
+	}
+}
+
+func correctOption(t *testing.T, q *models.GameQuestionResponse) string {
+	t.Helper()
+
+	for _, opt := range q.Options {
+		if opt.ID == q.CorrectAnswer {
+			return opt.Text
+		}
+	}
+	t.Fatalf("correct answer %q matches no option of %+v", q.CorrectAnswer, q.Options)
+	return ""
+}
+
+func TestGenerateQuestionWithFake(t *testing.T) {
+	tests := []struct {
+		name         string
+		questionType string
+		message      string
+		imageURL     string
+		description  string
+		wantAnswer   string
+	}{
+		{
+			name:         "fill in the blank keeps a canned answer the source mentions",
+			questionType: util.QuestionTypeFillInBlank,
+			message:      "추석에 가족들이랑 고향집에 다녀왔어",
+			wantAnswer:   "고향집",
+		},
+		{
+			name:         "multiple choice takes its answer from the source",
+			questionType: util.QuestionTypeMultipleChoice,
+			message:      "젊을 때 부산 자갈치시장에서 장사했지",
+			wantAnswer:   "자갈치시장에서",
+		},
+		{
+			name:         "integrative takes its answer from the sources",
+			questionType: util.QuestionTypeIntegrative,
+			message:      "주말마다 막내아들이랑 낚시하러 갔어",
+			wantAnswer:   "막내아들이랑",
+		},
+		{
+			name:         "photo takes its answer from the description",
+			questionType: util.QuestionTypePhoto,
+			imageURL:     "https://example.com/photos/wedding.jpg",
+			description:  "1975년 결혼식날 예식장 앞에서 찍은 사진",
+			wantAnswer:   "1975년",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.LoadConfig(t, nil)
+			store := ragtest.NewFake()
+			if tt.message != "" {
+				seedConversations(store, "user-1", cfg.MinConversationsForGame, tt.message)
+			}
+			llm := fakellm.New()
+			gs := newGameService(t, cfg, store, llm)
+
+			question, err := gs.GenerateQuestion(context.Background(), &models.GameQuestionRequest{
+				UserID:           "user-1",
+				QuestionType:     tt.questionType,
+				ImageURL:         tt.imageURL,
+				ImageDescription: tt.description,
+			})
+			if err != nil {
+				t.Fatalf("GenerateQuestion() error = %v", err)
+			}
+			if got := correctOption(t, question); got != tt.wantAnswer {
+				t.Errorf("correct answer = %q, want %q", got, tt.wantAnswer)
+			}
+		})
+	}
+}
+
+func TestGenerateQuestionRegeneratesUngroundedAnswers(t *testing.T) {
+	ungrounded := `{"question": "어디에 다녀오셨나요?", "options": [{"id": "A", "text": "바닷가"}, {"id": "B", "text": "산"}, {"id": "C", "text": "시장"}, {"id": "D", "text": "병원"}], "correct_answer": "A"}`
+
+	tests := []struct {
+		name      string
+		scripted  int
+		wantCalls int
+		wantErr   string
+	}{
+		{name: "canned answer passes at once", scripted: 0, wantCalls: 1},
+		{name: "regenerated after an ungrounded answer", scripted: 1, wantCalls: 2},
+		{name: "fails after every attempt is ungrounded", scripted: util.MaxQuestionGenerationAttempts, wantCalls: util.MaxQuestionGenerationAttempts, wantErr: "question_quality:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.LoadConfig(t, nil)
+			store := ragtest.NewFake()
+			seedConversations(store, "user-1", cfg.MinConversationsForGame, "추석에 고향집에 다녀왔어")
+			llm := fakellm.New()
+			for i := 0; i < tt.scripted; i++ {
+				llm.Script(fakellm.PromptMultipleChoice, ungrounded)
+			}
+			gs := newGameService(t, cfg, store, llm)
+
+			_, err := gs.GenerateQuestion(context.Background(), &models.GameQuestionRequest{UserID: "user-1", QuestionType: util.QuestionTypeMultipleChoice})
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("GenerateQuestion() error = %v, want prefix %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("GenerateQuestion() error = %v", err)
+			}
+			if got := llm.Calls(fakellm.PromptMultipleChoice); got != tt.wantCalls {
+				t.Errorf("model calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGenerateQuestionSetWithFake(t *testing.T) {
+	cfg := testutil.LoadConfig(t, nil)
+	store := ragtest.NewFake()
+	seedConversations(store, "user-1", 3, "한가위에 며느리가 전을 부쳤어")
+	gs := newGameService(t, cfg, store, fakellm.New())
+
+	set, err := gs.GenerateQuestionSet(context.Background(), &models.QuestionSetRequest{UserID: "user-1", Theme: util.ThemeChuseok})
+	if err != nil {
+		t.Fatalf("GenerateQuestionSet() error = %v", err)
+	}
+	if len(set.Questions) == 0 {
+		t.Fatal("GenerateQuestionSet() returned no questions")
+	}
+	for i := range set.Questions {
+		if got := correctOption(t, &set.Questions[i]); got != "한가위에" {
+			t.Errorf("question %d correct answer = %q, want 한가위에", i, got)
+		}
+	}
+}
+
+func TestProcessChatWithFake(t *testing.T) {
+	tests := []struct {
+		name            string
+		script          string
+		failLLM         bool
+		wantResponse    string
+		wantErr         bool
+		wantSuggestions int
+	}{
+		{
+			name:            "canned reply",
+			wantResponse:    "그러셨군요. 그때 이야기를 조금 더 들려주시겠어요?",
+			wantSuggestions: 2,
+		},
+		{
+			name:            "scripted reply",
+			script:          `"고향집 이야기를 더 들려주세요."`,
+			wantResponse:    "고향집 이야기를 더 들려주세요.",
+			wantSuggestions: 2,
+		},
+		{
+			name:    "model failure",
+			failLLM: true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.LoadConfig(t, nil)
+			store := ragtest.NewFake()
+			seedConversations(store, "user-1", 2, "추석에 고향집에 다녀왔어")
+			llm := fakellm.New()
+			if tt.script != "" {
+				llm.Script(fakellm.PromptChat, tt.script)
+			}
+			if tt.failLLM {
+				llm.SetError(fakellm.PromptChat, fmt.Errorf("model unavailable"))
+			}
+			cs := newChatService(t, cfg, store, llm)
+
+			resp, err := cs.ProcessChat(context.Background(), &models.ChatRequest{
+				UserID:             "user-1",
+				Message:            "요즘 고향집 생각이 많이 나네",
+				IncludeSuggestions: true,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ProcessChat() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessChat() error = %v", err)
+			}
+			if resp.Response != tt.wantResponse {
+				t.Errorf("response = %q, want %q", resp.Response, tt.wantResponse)
+			}
+			if len(resp.Suggestions) != tt.wantSuggestions {
+				t.Errorf("suggestions = %v, want %d", resp.Suggestions, tt.wantSuggestions)
+			}
+			if got := llm.Calls(fakellm.PromptChat); got != 1 {
+				t.Errorf("chat calls = %d, want 1", got)
+			}
+		})
+	}
+}
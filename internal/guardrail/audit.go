@@ -0,0 +1,86 @@
+package guardrail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"llm/internal/pii"
+	"llm/internal/textutil"
+	"llm/internal/util"
+)
+
+// AuditExcerptRunes is the length of the checked text kept in an audit event
+const AuditExcerptRunes = 80
+
+// Event is an auditable record of a guardrail intervention
+type Event struct {
+	RequestID  string    `json:"request_id,omitempty"`
+	UserID     string    `json:"user_id"`
+	Stage      string    `json:"stage"`
+	Action     string    `json:"action"`
+	Categories []string  `json:"categories"`
+	Matches    []string  `json:"matches,omitempty"` // text matched by custom rules, PII masked
+	Moderated  bool      `json:"moderated"`         // flagged by the moderation API
+	Flagged    []string  `json:"flagged,omitempty"` // moderation API categories
+	Hash       string    `json:"content_hash"`      // SHA-256 of the checked text
+	Excerpt    string    `json:"excerpt"`           // start of the checked text, PII masked
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// auditScrubber masks every PII kind in audited text, whatever the server's redaction settings
+var auditScrubber = pii.NewScrubber(util.PIIKinds)
+
+// RedactForAudit returns the SHA-256 of text, to match an event to the stored
+// conversation, and a short excerpt of it with PII masked
+func RedactForAudit(text string) (string, string) {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:]), textutil.Truncate(auditScrubber.Redact(pii.NewMask(), text), AuditExcerptRunes)
+}
+
+// RedactMatches masks PII in the text custom rules matched
+func RedactMatches(matches []string) []string {
+	return auditScrubber.RedactAll(pii.NewMask(), matches)
+}
+
+// AuditLog appends guardrail events to a daily JSONL file
+type AuditLog struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewAuditLog creates an audit log writing into dir
+func NewAuditLog(dir string) (*AuditLog, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	return &AuditLog{dir: dir}, nil
+}
+
+// Write appends an event
+func (a *AuditLog) Write(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	path := filepath.Join(a.dir, "guardrail-"+event.CreatedAt.Format("2006-01-02")+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
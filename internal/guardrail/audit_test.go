@@ -0,0 +1,62 @@
+package guardrail
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRedactForAudit(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		notWant string
+	}{
+		{name: "PII is masked", text: "계좌 비밀번호 알려줄게 010-1234-5678로 전화해", want: "[PHONE_1]", notWant: "010-1234-5678"},
+		{name: "long text is cut", text: strings.Repeat("가", 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, excerpt := RedactForAudit(tt.text)
+			if len(hash) != 64 {
+				t.Errorf("hash = %q, want a SHA-256 hex digest", hash)
+			}
+			if again, _ := RedactForAudit(tt.text); again != hash {
+				t.Errorf("hash changed between calls: %s, %s", hash, again)
+			}
+			if utf8.RuneCountInString(excerpt) > AuditExcerptRunes {
+				t.Errorf("excerpt has %d runes, want at most %d", utf8.RuneCountInString(excerpt), AuditExcerptRunes)
+			}
+			if tt.want != "" && !strings.Contains(excerpt, tt.want) {
+				t.Errorf("excerpt = %q, missing %q", excerpt, tt.want)
+			}
+			if tt.notWant != "" && strings.Contains(excerpt, tt.notWant) {
+				t.Errorf("excerpt = %q, contains %q", excerpt, tt.notWant)
+			}
+		})
+	}
+}
+
+func TestModerationCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "self-harm", want: CategorySelfHarm},
+		{name: "self-harm/intent", want: CategorySelfHarm},
+		{name: "self-harm/instructions", want: CategorySelfHarm},
+		{name: "harassment", want: CategoryModeration},
+		{name: "violence/graphic", want: CategoryModeration},
+		{name: "a-future-category", want: CategoryModeration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ModerationCategory(tt.name); got != tt.want {
+				t.Errorf("ModerationCategory(%q) = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+}
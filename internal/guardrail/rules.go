@@ -0,0 +1,122 @@
+package guardrail
+
+import (
+	"regexp"
+)
+
+// Stages a check runs at
+const (
+	StageInput  = "input"
+	StageOutput = "output"
+)
+
+// Actions, ordered from least to most severe
+const (
+	ActionAllow   = "allow"
+	ActionRewrite = "rewrite"
+	ActionBlock   = "block"
+)
+
+// Safety categories
+const (
+	CategoryMedicalAdvice = "medical_advice"
+	CategoryFinancialScam = "financial_scam"
+	CategorySelfHarm      = "self_harm"
+	CategoryModeration    = "moderation" // flagged by the moderation API in another category
+)
+
+// Rule is a custom Korean pattern for content the moderation API does not cover
+type Rule struct {
+	Category string
+	Stage    string
+	Action   string
+	Pattern  *regexp.Regexp
+}
+
+// DefaultRules covers medical advice from the assistant and financial scams in either direction
+var DefaultRules = []Rule{
+	// Scam scripts users relay from callers: account details, verification codes, impersonated authorities
+	{CategoryFinancialScam, StageInput, ActionBlock, regexp.MustCompile(`(계좌|통장|카드)\s*(번호|비밀번호|비번)`)},
+	{CategoryFinancialScam, StageInput, ActionBlock, regexp.MustCompile(`(인증|보안|OTP|otp)\s*(번호|코드|카드)`)},
+	{CategoryFinancialScam, StageInput, ActionBlock, regexp.MustCompile(`(검찰|경찰|금감원|금융감독원|수사관|은행).{0,20}(전화|연락|문자).{0,20}(돈|송금|이체|계좌)`)},
+	{CategoryFinancialScam, StageInput, ActionBlock, regexp.MustCompile(`(저금리|대환)\s*대출`)},
+	{CategoryFinancialScam, StageInput, ActionBlock, regexp.MustCompile(`상품권.{0,10}(사|구매|보내|번호)`)},
+
+	// The assistant must never ask for or encourage sharing financial details
+	{CategoryFinancialScam, StageOutput, ActionRewrite, regexp.MustCompile(`(계좌|비밀번호|인증번호|카드번호).{0,10}(알려|입력|보내|불러)`)},
+
+	// The assistant must not change medication or give diagnoses
+	{CategoryMedicalAdvice, StageOutput, ActionRewrite, regexp.MustCompile(`(약|복용).{0,10}(끊|중단|줄이|줄여|늘리|늘려|그만)`)},
+	{CategoryMedicalAdvice, StageOutput, ActionRewrite, regexp.MustCompile(`\d+\s*(mg|밀리그램|알|정)\s*(씩|을|를)?\s*(드세요|드시|복용)`)},
+	{CategoryMedicalAdvice, StageOutput, ActionRewrite, regexp.MustCompile(`(진단|처방)(해|드리|을 내리)`)},
+}
+
+// Match is a rule that matched, with the matched text
+type Match struct {
+	Rule Rule
+	Text string
+}
+
+// Evaluate returns the rules for stage that match text
+func Evaluate(rules []Rule, stage string, text string) []Match {
+	var matches []Match
+	for _, rule := range rules {
+		if rule.Stage != stage {
+			continue
+		}
+		if found := rule.Pattern.FindString(text); found != "" {
+			matches = append(matches, Match{Rule: rule, Text: found})
+		}
+	}
+	return matches
+}
+
+// Severity ranks an action so the most severe one wins
+func Severity(action string) int {
+	switch action {
+	case ActionBlock:
+		return 2
+	case ActionRewrite:
+		return 1
+	}
+	return 0
+}
+
+// moderationCategories maps every moderation API category to a guardrail category
+var moderationCategories = map[string]string{
+	"harassment":             CategoryModeration,
+	"harassment/threatening": CategoryModeration,
+	"hate":                   CategoryModeration,
+	"hate/threatening":       CategoryModeration,
+	"illicit":                CategoryModeration,
+	"illicit/violent":        CategoryModeration,
+	"self-harm":              CategorySelfHarm,
+	"self-harm/intent":       CategorySelfHarm,
+	"self-harm/instructions": CategorySelfHarm,
+	"sexual":                 CategoryModeration,
+	"sexual/minors":          CategoryModeration,
+	"violence":               CategoryModeration,
+	"violence/graphic":       CategoryModeration,
+}
+
+// ModerationCategory returns the guardrail category of a moderation API category.
+// Categories the API adds later are treated as generic moderation flags.
+func ModerationCategory(name string) string {
+	if category, ok := moderationCategories[name]; ok {
+		return category
+	}
+	return CategoryModeration
+}
+
+// SafeResponse returns the reply sent in place of unsafe content for a category
+func SafeResponse(category string) string {
+	switch category {
+	case CategoryFinancialScam:
+		return "혹시 누군가 돈이나 계좌번호, 인증번호를 물어봤다면 절대 알려주지 마세요. 먼저 가족분께 전화해서 확인해 보시고, 이상하다 싶으면 112에 신고하시면 돼요."
+	case CategoryMedicalAdvice:
+		return "건강이나 약에 관한 건 담당 의사 선생님이나 약사님께 여쭤보시는 게 가장 안전해요. 다음 진료 때 꼭 물어보세요."
+	case CategorySelfHarm:
+		return "많이 힘드셨군요. 혼자 견디지 않으셔도 돼요. 지금 가족분께 연락해 보시거나, 자살예방상담전화 109에 전화하시면 언제든 이야기를 들어줄 거예요."
+	}
+	return "그 이야기는 제가 도와드리기 어려워요. 우리 다른 이야기 해볼까요?"
+}
@@ -0,0 +1,151 @@
+// Package httptransport builds the pooled HTTP transports used for outbound
+// API calls and records connection-level metrics for them: whether requests
+// reuse a kept-alive connection or pay for a new one and its TLS handshake.
+// Request latencies are also added to the calling API request's stats.
+package httptransport
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/util"
+)
+
+// Metric targets
+const (
+	TargetOpenAI = "openai"
+	TargetRAG    = "rag"
+)
+
+// DefaultDialTimeout bounds connecting when Options.DialTimeout is not set
+const DefaultDialTimeout = 30 * time.Second
+
+// Options configures a pooled transport
+type Options struct {
+	ProxyURL              string // empty uses the proxy from the environment
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	KeepAlive             time.Duration
+	DialTimeout           time.Duration // 0 uses DefaultDialTimeout
+	ResponseHeaderTimeout time.Duration // wait for response headers once the request is sent, 0 for none
+}
+
+// New creates a pooled transport. A proxy URL that does not parse is ignored
+// in favour of the environment; config validates it at startup.
+func New(opts Options) *http.Transport {
+	proxy := http.ProxyFromEnvironment
+	if opts.ProxyURL != "" {
+		if u, err := url.Parse(opts.ProxyURL); err == nil {
+			proxy = http.ProxyURL(u)
+		}
+	}
+
+	dialTimeout := opts.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: opts.KeepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       opts.MaxConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+var (
+	openAIOnce      sync.Once
+	openAITransport http.RoundTripper
+)
+
+// OpenAI returns the instrumented transport shared by every OpenAI API client,
+// so chat, moderation and embedding calls draw on one connection pool
+func OpenAI(cfg *config.Config) http.RoundTripper {
+	openAIOnce.Do(func() {
+		openAITransport = Instrument(New(Options{
+			ProxyURL:            cfg.OpenAIProxyURL,
+			MaxIdleConns:        cfg.OpenAIMaxIdleConns,
+			MaxIdleConnsPerHost: cfg.OpenAIMaxIdleConnsPerHost,
+			MaxConnsPerHost:     cfg.OpenAIMaxConnsPerHost,
+			IdleConnTimeout:     cfg.OpenAIIdleConnTimeout,
+			KeepAlive:           cfg.OpenAIKeepAlive,
+		}), TargetOpenAI)
+	})
+	return openAITransport
+}
+
+// instrumentedTransport records request outcomes and connection reuse for a target
+type instrumentedTransport struct {
+	base   http.RoundTripper
+	target string
+}
+
+// Instrument wraps base so its requests are counted under target in the
+// http_client_* metrics
+func Instrument(base http.RoundTripper, target string) http.RoundTripper {
+	return &instrumentedTransport{base: base, target: target}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var handshakeStart time.Time
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				metrics.HTTPClientConnections.Add(t.target+".reused", 1)
+			} else {
+				metrics.HTTPClientConnections.Add(t.target+".new", 1)
+			}
+		},
+		TLSHandshakeStart: func() {
+			handshakeStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			metrics.HTTPClientTLSHandshakes.Add(t.target, 1)
+			metrics.HTTPClientTLSHandshakeMillis.Add(t.target, time.Since(handshakeStart).Milliseconds())
+		},
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	latency := time.Since(start)
+	metrics.HTTPClientLatencyMillis.Add(t.target, latency.Milliseconds())
+	util.RequestStatsFromContext(req.Context()).AddUpstream(t.target, latency)
+	if err != nil {
+		metrics.HTTPClientRequests.Add(t.target+".error", 1)
+		return nil, err
+	}
+	metrics.HTTPClientRequests.Add(t.target+"."+statusClass(resp.StatusCode), 1)
+	return resp, nil
+}
+
+func statusClass(code int) string {
+	switch {
+	case code >= 500:
+		return "5xx"
+	case code >= 400:
+		return "4xx"
+	case code >= 300:
+		return "3xx"
+	default:
+		return "2xx"
+	}
+}
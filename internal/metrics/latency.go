@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"expvar"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyPercentiles summarizes the latencies recently observed for one key
+type LatencyPercentiles struct {
+	Count int64 `json:"count"` // all observations, not just the window
+	P50   int64 `json:"p50_ms"`
+	P90   int64 `json:"p90_ms"`
+	P99   int64 `json:"p99_ms"`
+	Max   int64 `json:"max_ms"`
+}
+
+// LatencyWindows keeps the most recent latencies per key, e.g. per route, and
+// publishes their percentiles
+type LatencyWindows struct {
+	size    int
+	windows map[string]*latencyWindow
+	mutex   sync.Mutex
+}
+
+type latencyWindow struct {
+	millis []int64 // ring buffer of the last size observations
+	next   int
+	count  int64
+}
+
+// NewLatencyWindows creates latency windows keeping the last size observations
+// per key, published under name
+func NewLatencyWindows(name string, size int) *LatencyWindows {
+	lw := &LatencyWindows{size: size, windows: map[string]*latencyWindow{}}
+	expvar.Publish(name, expvar.Func(func() any { return lw.Percentiles() }))
+	return lw
+}
+
+// Observe records a latency for key
+func (lw *LatencyWindows) Observe(key string, latency time.Duration) {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+
+	window, ok := lw.windows[key]
+	if !ok {
+		window = &latencyWindow{millis: make([]int64, 0, lw.size)}
+		lw.windows[key] = window
+	}
+	if len(window.millis) < lw.size {
+		window.millis = append(window.millis, latency.Milliseconds())
+	} else {
+		window.millis[window.next] = latency.Milliseconds()
+	}
+	window.next = (window.next + 1) % lw.size
+	window.count++
+}
+
+// Percentiles returns the percentiles of each key's window
+func (lw *LatencyWindows) Percentiles() map[string]LatencyPercentiles {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+
+	result := make(map[string]LatencyPercentiles, len(lw.windows))
+	for key, window := range lw.windows {
+		sorted := append([]int64(nil), window.millis...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		result[key] = LatencyPercentiles{
+			Count: window.count,
+			P50:   percentile(sorted, 0.50),
+			P90:   percentile(sorted, 0.90),
+			P99:   percentile(sorted, 0.99),
+			Max:   sorted[len(sorted)-1],
+		}
+	}
+	return result
+}
+
+// percentile returns the nearest-rank percentile p (0-1) of sorted
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"expvar"
+	"net/http"
+)
+
+// Source keys for ChatContextFetchFailures
+const (
+	SourceConversations     = "conversations"
+	SourceProfile           = "profile"
+	SourceIncorrectAttempts = "incorrect_attempts"
+)
+
+// ChatContextFetchFailures counts failed RAG fetches while building chat context, keyed by source
+var ChatContextFetchFailures = expvar.NewMap("chat_context_fetch_failures")
+
+// ChatDegradedResponses counts chat replies generated without the full personalization context
+var ChatDegradedResponses = expvar.NewInt("chat_degraded_responses")
+
+// ChatSmallTalkResponses counts chat replies answered by the small-talk fast path
+var ChatSmallTalkResponses = expvar.NewInt("chat_small_talk_responses")
+
+// Outcome keys for ChatSemanticCache
+const (
+	SemanticCacheHit  = "hit"
+	SemanticCacheMiss = "miss"
+)
+
+// ChatSemanticCache counts semantic cache lookups for chat replies, keyed by outcome
+var ChatSemanticCache = expvar.NewMap("chat_semantic_cache")
+
+// PIIRedactions counts personal identifiers masked before prompts, keyed by PII kind
+var PIIRedactions = expvar.NewMap("pii_redactions")
+
+// Outcome keys for ChatUserLimited
+const (
+	ChatUserQueued   = "queued"
+	ChatUserRejected = "rejected"
+)
+
+// ChatUserLimited counts chat requests that arrived while the same user had the
+// maximum in flight, keyed by outcome
+var ChatUserLimited = expvar.NewMap("chat_user_limited")
+
+// EmbeddingFallbacks counts texts embedded locally because the embeddings API failed
+var EmbeddingFallbacks = expvar.NewInt("embedding_fallbacks")
+
+// RAGLocalSearches counts conversation searches answered from the local index while the RAG server was unreachable
+var RAGLocalSearches = expvar.NewInt("rag_local_searches")
+
+// Kind and outcome keys for RAGCache
+const (
+	RAGCacheProfile           = "profile"
+	RAGCacheIncorrectAttempts = "incorrect_attempts"
+	RAGCacheHit               = "hit"
+	RAGCacheMiss              = "miss"
+	RAGCacheStale             = "stale" // expired entry served because the RAG server failed
+)
+
+// RAGCache counts lookups in the RAG read cache, keyed by "<kind>.<outcome>"
+var RAGCache = expvar.NewMap("rag_cache")
+
+// SaveQueueDepth is the number of conversation saves waiting in the write-behind queue
+var SaveQueueDepth = expvar.NewInt("save_queue_depth")
+
+// SaveQueueDelivered counts queued conversation saves delivered to the RAG server
+var SaveQueueDelivered = expvar.NewInt("save_queue_delivered")
+
+// SaveQueueRetries counts failed delivery attempts of queued conversation saves
+var SaveQueueRetries = expvar.NewInt("save_queue_retries")
+
+// SaveQueueDeadLetters counts queued conversation saves given up on after the last attempt
+var SaveQueueDeadLetters = expvar.NewInt("save_queue_dead_letters")
+
+// GuardrailEvents counts guardrail interventions, keyed by stage.category.action
+var GuardrailEvents = expvar.NewMap("guardrail_events")
+
+// EmergencyIncidents counts detected emergencies, keyed by signal
+var EmergencyIncidents = expvar.NewMap("emergency_incidents")
+
+// EmergencyNotifyFailures counts failed caregiver alerts, keyed by channel
+var EmergencyNotifyFailures = expvar.NewMap("emergency_notify_failures")
+
+// WebhookDeliveries counts webhook deliveries, keyed by event.outcome (delivered, failed)
+var WebhookDeliveries = expvar.NewMap("webhook_deliveries")
+
+// MaintenanceRemoved counts items removed by maintenance compaction, keyed by task
+var MaintenanceRemoved = expvar.NewMap("maintenance_removed")
+
+// MaintenanceReclaimedBytes counts the approximate bytes freed by maintenance compaction, keyed by task
+var MaintenanceReclaimedBytes = expvar.NewMap("maintenance_reclaimed_bytes")
+
+// MaintenanceRuns counts completed maintenance runs
+var MaintenanceRuns = expvar.NewInt("maintenance_runs")
+
+// ModelUnavailableErrors counts calls the provider rejected because the model is deprecated or not found, keyed by model
+var ModelUnavailableErrors = expvar.NewMap("model_unavailable_errors")
+
+// ModelFallbacks counts calls retried with the fallback model, keyed by the rejected model
+var ModelFallbacks = expvar.NewMap("model_fallbacks")
+
+// PromptPreflight counts prompts that exceeded the estimated context window, keyed by outcome (trim, reject)
+var PromptPreflight = expvar.NewMap("prompt_preflight")
+
+// Outcomes of chat tool calls
+const (
+	ChatToolOK    = "ok"
+	ChatToolError = "error"
+)
+
+// ChatToolCalls counts tool calls the chat model made, keyed by "<tool>.<outcome>"
+var ChatToolCalls = expvar.NewMap("chat_tool_calls")
+
+// Outcomes of shadow runs
+const (
+	ShadowOK      = "ok"
+	ShadowError   = "error"
+	ShadowTimeout = "timeout" // the production reply never arrived, nothing was logged
+)
+
+// ShadowRuns counts chat turns also answered by the shadow variant, keyed by outcome
+var ShadowRuns = expvar.NewMap("shadow_runs")
+
+// QuestionQualityFailures counts generated questions that failed a quality check, keyed by check
+var QuestionQualityFailures = expvar.NewMap("question_quality_failures")
+
+// HTTPClientRequests counts outbound HTTP requests, keyed by "<target>.<status class>" or "<target>.error"
+var HTTPClientRequests = expvar.NewMap("http_client_requests")
+
+// HTTPClientLatencyMillis sums outbound HTTP request latency in milliseconds, keyed by target
+var HTTPClientLatencyMillis = expvar.NewMap("http_client_latency_ms")
+
+// HTTPClientConnections counts connections outbound requests were sent on, keyed by "<target>.new" or "<target>.reused"
+var HTTPClientConnections = expvar.NewMap("http_client_connections")
+
+// HTTPClientTLSHandshakes counts TLS handshakes for new outbound connections, keyed by target
+var HTTPClientTLSHandshakes = expvar.NewMap("http_client_tls_handshakes")
+
+// HTTPClientTLSHandshakeMillis sums TLS handshake time in milliseconds, keyed by target
+var HTTPClientTLSHandshakeMillis = expvar.NewMap("http_client_tls_handshake_ms")
+
+// HTTPRequestLatency keeps the latency percentiles of the last 1000 API requests
+// per route, keyed by "<method> <route>"
+var HTTPRequestLatency = NewLatencyWindows("http_request_latency_ms", 1000)
+
+// HTTPRequestTimeouts counts API requests answered with 504 after their route's deadline, keyed by "<method> <route>"
+var HTTPRequestTimeouts = expvar.NewMap("http_request_timeouts")
+
+// TenantRequests counts API requests made with a tenant's API key, keyed by "<tenant>.<status class>"
+var TenantRequests = expvar.NewMap("tenant_requests")
+
+// TenantTokens sums the LLM tokens used by each tenant's requests, keyed by tenant
+var TenantTokens = expvar.NewMap("tenant_tokens")
+
+// SecretRotations counts referenced secrets that changed when re-read, keyed by secret name
+var SecretRotations = expvar.NewMap("secret_rotations")
+
+// SecretRefreshFailures counts failed re-reads of referenced secrets, keyed by secret name
+var SecretRefreshFailures = expvar.NewMap("secret_refresh_failures")
+
+// Handler serves all published metrics as JSON
+func Handler() http.Handler {
+	return expvar.Handler()
+}
@@ -6,94 +6,298 @@ import "time"
 
 // ChatRequest represents a chat message request
 type ChatRequest struct {
-	Message string `json:"message" binding:"required"`
-	UserID  string `json:"user_id" binding:"required"`
+	Message string `json:"message" binding:"required" example:"오늘 딸이 손주들을 데리고 왔어요"`
+	UserID  string `json:"user_id" binding:"required" example:"user_123"`
+	// Evaluate the message before replying and return its quality score, instead
+	// of only storing it with the saved conversation
+	IncludeEvaluation bool `json:"include_evaluation,omitempty"`
+	// Return 2-3 follow-up lines the voice frontend can say when the user goes
+	// silent after the reply, generated by a second, cheaper model call
+	IncludeSuggestions bool `json:"include_suggestions,omitempty"`
+	// Channel the message arrived through, saved with the conversation; api when omitted
+	Channel string `json:"channel,omitempty" binding:"omitempty,oneof=phone app api" enums:"phone,app,api"`
+	// Photos to discuss with the user, as http(s) URLs or uploaded data:image/
+	// URIs. They are answered by the vision model and described in the saved
+	// conversation.
+	ImageURLs []string `json:"image_urls,omitempty" binding:"omitempty,max=4,dive,required"`
+	GenerationOverrides
+}
+
+// GenerationOverrides carries optional per-request model settings for QA comparisons.
+// The model must be on the server's allowlist.
+type GenerationOverrides struct {
+	Model       string   `json:"model,omitempty"`
+	Temperature *float32 `json:"temperature,omitempty" binding:"omitempty,gte=0,lte=2"`
+	MaxTokens   *int     `json:"max_tokens,omitempty" binding:"omitempty,gte=1,lte=8192"`
+}
+
+// TimeWindow limits retrieval to conversations from a period, e.g. last week.
+// Either bound may be omitted.
+type TimeWindow struct {
+	From *time.Time `json:"from,omitempty" example:"2024-10-07T00:00:00+09:00"`
+	To   *time.Time `json:"to,omitempty" example:"2024-10-14T00:00:00+09:00"`
 }
 
 // ChatResponse represents a chat response
 type ChatResponse struct {
-	ConversationID string       `json:"conversation_id"`
-	Message        string       `json:"message"`
-	Response       string       `json:"response"`
-	ContextUsed    ContextUsage `json:"context_used"`
-	CreatedAt      time.Time    `json:"created_at"`
+	ConversationID string         `json:"conversation_id"`
+	Message        string         `json:"message"`
+	Response       string         `json:"response"`
+	ContextUsed    ContextUsage   `json:"context_used"`
+	Guardrail      *GuardrailInfo `json:"guardrail,omitempty"`   // set when a safety guardrail changed the reply
+	Emergency      *EmergencyInfo `json:"emergency,omitempty"`   // set when the message suggested an emergency
+	SmallTalk      bool           `json:"small_talk,omitempty"`  // answered by the small-talk fast path without retrieval
+	Cached         bool           `json:"cached,omitempty"`      // reused the reply to a near-identical recent question
+	VoiceSpeed     float32        `json:"voice_speed,omitempty"` // speech rate from the user's preferences, for the voice frontend
+	// Reminders the reply was asked to bring up, and reminders the message confirmed
+	RemindersMentioned    []string            `json:"reminders_mentioned,omitempty"`
+	RemindersAcknowledged []string            `json:"reminders_acknowledged,omitempty"`
+	Assessment            *AssessmentProgress `json:"assessment,omitempty"`  // set when the turn was an assessment answer
+	Evaluation            *ChatEvaluation     `json:"evaluation,omitempty"`  // set on include_evaluation when the message was evaluated
+	Suggestions           []string            `json:"suggestions,omitempty"` // on include_suggestions, follow-up lines to offer if the user stays silent
+	CreatedAt             time.Time           `json:"created_at"`
+}
+
+// ChatEvaluation is the quality (speech coherence) score of the user's message
+type ChatEvaluation struct {
+	Score        int    `json:"score" example:"78"` // 0-100
+	ScoreVersion string `json:"score_version"`      // prompt version the score was evaluated with
+}
+
+// EmergencyInfo identifies the incident raised for a distress message.
+// Caregiver alerts are sent in the background.
+type EmergencyInfo struct {
+	IncidentID string   `json:"incident_id"`
+	Signals    []string `json:"signals"` // chest_pain, fall, breathing, stroke, call_for_help
+}
+
+// GuardrailInfo describes a guardrail intervention on a chat turn
+type GuardrailInfo struct {
+	Stage      string   `json:"stage"`  // input, output
+	Action     string   `json:"action"` // rewrite, block
+	Categories []string `json:"categories"`
 }
 
 // ContextUsage represents context information used in response
 type ContextUsage struct {
-	TotalConversations int     `json:"total_conversations"`
-	TopScore           float32 `json:"top_score"`
+	TotalConversations      int     `json:"total_conversations"`
+	TopScore                float32 `json:"top_score"`
+	ProfileLoaded           bool    `json:"profile_loaded"`
+	IncorrectAttemptsLoaded bool    `json:"incorrect_attempts_loaded"`
+	QueryStrategy           string  `json:"query_strategy"` // raw, llm, hybrid
+	SearchQuery             string  `json:"search_query"`   // query actually sent to conversation search
+}
+
+// ConversationCorrectionRequest represents a caregiver's correction of an assistant statement
+type ConversationCorrectionRequest struct {
+	UserID             string `json:"user_id" binding:"required"`
+	IncorrectStatement string `json:"incorrect_statement" binding:"required"`
+	Correction         string `json:"correction" binding:"required"`
+}
+
+// ConversationCorrectionResponse represents a stored correction
+type ConversationCorrectionResponse struct {
+	CorrectionID   string    `json:"correction_id"`
+	ConversationID string    `json:"conversation_id"`
+	UserID         string    `json:"user_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AvoidTopicRequest represents a caregiver request to blacklist a topic for a user
+type AvoidTopicRequest struct {
+	Topic string `json:"topic" binding:"required"`
+}
+
+// AvoidTopicResponse represents a stored blacklisted topic
+type AvoidTopicResponse struct {
+	TopicID   string    `json:"topic_id"`
+	UserID    string    `json:"user_id"`
+	Topic     string    `json:"topic"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PinRequest represents a caregiver request to pin a fact or conversation excerpt to a user's chat context
+type PinRequest struct {
+	Content string `json:"content" binding:"required,max=300" example:"보행 보조기를 사용하심"`
+}
+
+// PinResponse represents a stored pinned fact
+type PinResponse struct {
+	PinID     string    `json:"pin_id"`
+	UserID    string    `json:"user_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CareModeRequest represents a caregiver request to enable a special prompt mode for a user
+type CareModeRequest struct {
+	Mode string `json:"mode" binding:"required,oneof=grief_sensitive"`
+}
+
+// CareModeResponse represents a stored care mode flag
+type CareModeResponse struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Mode      string    `json:"mode"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // ===== Game Question Models =====
 
 // GameQuestionRequest represents a request to generate a game question
 type GameQuestionRequest struct {
-	UserID         string `json:"user_id" binding:"required"`
-	QuestionType   string `json:"question_type" binding:"required,oneof=fill_in_blank multiple_choice"`
-	DifficultyHint string `json:"difficulty_hint,omitempty"` // easy, medium, hard
+	UserID         string `json:"user_id" binding:"required" example:"user_123"`
+	QuestionType   string `json:"question_type" binding:"required,oneof=fill_in_blank multiple_choice integrative photo" enums:"fill_in_blank,multiple_choice,integrative,photo" example:"multiple_choice"`
+	DifficultyHint string `json:"difficulty_hint,omitempty" enums:"easy,medium,hard"`
+	// Photo questions: the photo to ask about, and optionally the caregiver's
+	// description of who and what it shows. Without a description the photo is
+	// captioned by the vision model.
+	ImageURL         string `json:"image_url,omitempty" binding:"omitempty,url" example:"https://example.com/photos/family.jpg"`
+	ImageDescription string `json:"image_description,omitempty" binding:"omitempty,max=1000" example:"2019년 추석, 왼쪽부터 큰딸 민지와 손자 준호, 고향집 마당"`
+	// With too few conversations, ask about a personal info fact (family names,
+	// hometown, former job) instead of failing. Integrative questions fall back
+	// to multiple choice.
+	AllowProfileFallback bool `json:"allow_profile_fallback,omitempty"`
+	TimeWindow
+	GenerationOverrides
 }
 
-// GameQuestionResponse represents a game question response (base)
+// QuestionReviewRequest approves or rejects a previewed question. Difficulty and
+// topic relabel an approved question; reason is recorded for rejections.
+type QuestionReviewRequest struct {
+	Action     string `json:"action" binding:"required,oneof=approve reject"`
+	Difficulty string `json:"difficulty,omitempty" binding:"omitempty,oneof=easy medium hard"`
+	Topic      string `json:"topic,omitempty" binding:"omitempty,max=50"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SupervisedQuestionResponse represents a question in supervised review
+type SupervisedQuestionResponse struct {
+	QuestionID string                `json:"question_id" example:"5f1c2b1e-8a4d-4f57-9a57-0a8c1b7e2d31"`
+	Status     string                `json:"status" enums:"pending,approved,rejected"`
+	Question   *GameQuestionResponse `json:"question,omitempty"` // omitted once rejected
+}
+
+// GameQuestionResponse represents a generated game question. QuestionType is the
+// discriminator: fill_in_blank and multiple_choice questions are based on one
+// conversation (BasedOnConversation), integrative questions on several
+// (BasedOnConversations), and photo questions on a photo (ImageURL).
 type GameQuestionResponse struct {
-	QuestionID          string           `json:"question_id"`
-	QuestionType        string           `json:"question_type"`
-	Question            string           `json:"question"`
-	CorrectAnswer       string           `json:"correct_answer"`
-	BasedOnConversation string           `json:"based_on_conversation"`
-	Difficulty          string           `json:"difficulty"`
-	Metadata            QuestionMetadata `json:"metadata"`
-}
-
-// FillInTheBlankQuestionResponse represents a fill-in-the-blank question with multiple choice options
-type FillInTheBlankQuestionResponse struct {
-	QuestionID          string           `json:"question_id"`
-	QuestionType        string           `json:"question_type"` // "fill_in_blank"
-	Question            string           `json:"question"`
-	Options             []QuestionOption `json:"options"`        // 4 choices to fill in the blank
-	CorrectAnswer       string           `json:"correct_answer"` // "A", "B", "C", "D"
-	BasedOnConversation string           `json:"based_on_conversation"`
-	Difficulty          string           `json:"difficulty"`
-	Metadata            QuestionMetadata `json:"metadata"`
-}
-
-// MultipleChoiceQuestionResponse represents a multiple choice question
-type MultipleChoiceQuestionResponse struct {
-	QuestionID          string           `json:"question_id"`
-	QuestionType        string           `json:"question_type"` // "multiple_choice"
-	Question            string           `json:"question"`
-	Options             []QuestionOption `json:"options"`
-	CorrectAnswer       string           `json:"correct_answer"` // "A", "B", "C", "D"
-	BasedOnConversation string           `json:"based_on_conversation"`
-	Difficulty          string           `json:"difficulty"`
-	Metadata            QuestionMetadata `json:"metadata"`
+	QuestionID           string           `json:"question_id" example:"5f1c2b1e-8a4d-4f57-9a57-0a8c1b7e2d31"`
+	QuestionType         string           `json:"question_type" enums:"fill_in_blank,multiple_choice,integrative,photo,orientation" example:"multiple_choice"`
+	Question             string           `json:"question" example:"지난주 딸과 함께 간 곳은 어디였나요?"`
+	Options              []QuestionOption `json:"options"`
+	CorrectAnswer        string           `json:"correct_answer,omitempty" enums:"A,B,C,D" example:"B"` // omitted from API v2, which grades through the answer endpoint
+	BasedOnConversation  string           `json:"based_on_conversation,omitempty" example:"conv_123"`
+	BasedOnConversations []string         `json:"based_on_conversations,omitempty"`
+	BasedOnPersonalInfo  string           `json:"based_on_personal_info,omitempty"` // personal info ID of a profile fallback question
+	ImageURL             string           `json:"image_url,omitempty"`
+	Difficulty           string           `json:"difficulty" enums:"easy,medium,hard" example:"medium"`
+	Metadata             QuestionMetadata `json:"metadata"`
+
+	Generation *QuestionGeneration `json:"-"` // recorded for lineage lookups, never sent to clients
+}
+
+// QuestionSetRequest represents a request to generate a themed set of questions
+type QuestionSetRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Theme  string `json:"theme" binding:"required,oneof=chuseok birthday family_visit"`
+	Count  int    `json:"count,omitempty" binding:"omitempty,min=1,max=10"` // default 5
+	GenerationOverrides
+}
+
+// QuestionSetResponse represents a cohesive themed set of multiple choice questions
+type QuestionSetResponse struct {
+	SetID                string                 `json:"set_id"`
+	Theme                string                 `json:"theme"`
+	Intro                string                 `json:"intro"`     // opening line introducing the theme
+	Questions            []GameQuestionResponse `json:"questions"` // always multiple_choice
+	BasedOnConversations []string               `json:"based_on_conversations"`
+	CreatedAt            time.Time              `json:"created_at"`
+}
+
+// OrientationQuestionRequest represents a request for reality-orientation questions
+type OrientationQuestionRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Count  int    `json:"count,omitempty" binding:"omitempty,min=1,max=4"` // default every available question
+}
+
+// OrientationQuestionResponse represents reality-orientation questions about the
+// current date, weekday, season and nearby holiday
+type OrientationQuestionResponse struct {
+	Date      string                 `json:"date" example:"2026-10-16"`
+	Questions []GameQuestionResponse `json:"questions"` // always orientation
+}
+
+// RecapRequest represents a request for "오늘의 회상" recap cards
+type RecapRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Count  int    `json:"count,omitempty" binding:"omitempty,min=1,max=10"` // default 5
+	TimeWindow
+	GenerationOverrides
+}
+
+// RecapCard is a short reminder of one past conversation
+type RecapCard struct {
+	Title          string `json:"title"`
+	Body           string `json:"body"` // 1-2 sentences
+	ConversationID string `json:"conversation_id"`
+	MissedTopic    bool   `json:"missed_topic"` // revisits a topic of a recently missed quiz question
+}
+
+// RecapResponse represents a set of recap cards built from recent conversations and missed quiz topics
+type RecapResponse struct {
+	RecapID              string      `json:"recap_id"`
+	Cards                []RecapCard `json:"cards"`
+	BasedOnConversations []string    `json:"based_on_conversations"`
+	MissedTopics         []string    `json:"missed_topics"`
+	CreatedAt            time.Time   `json:"created_at"`
 }
 
 // QuestionOption represents a single option in multiple choice
 type QuestionOption struct {
-	ID   string `json:"id"` // "A", "B", "C", "D"
-	Text string `json:"text"`
+	ID   string `json:"id" enums:"A,B,C,D" example:"B"`
+	Text string `json:"text" example:"동네 공원"`
 }
 
 // QuestionMetadata represents metadata about the generated question
 type QuestionMetadata struct {
-	Topic                 string  `json:"topic"`
-	MemoryScore           float32 `json:"memory_score"`
-	DaysSinceConversation int     `json:"days_since_conversation"`
+	Topic                 string  `json:"topic" example:"딸과 공원 산책"`
+	MemoryScore           float32 `json:"memory_score" example:"0.82"`
+	DaysSinceConversation int     `json:"days_since_conversation" example:"7"`
 }
 
 // ===== Game Result Models =====
 
 // GameResultRequest represents game result data
 type GameResultRequest struct {
+	UserID         string `json:"user_id" binding:"required" example:"user_123"`
+	QuestionID     string `json:"question_id" binding:"required" example:"5f1c2b1e-8a4d-4f57-9a57-0a8c1b7e2d31"`
+	UserAnswer     string `json:"user_answer" binding:"required" example:"B"`
+	IsCorrect      bool   `json:"is_correct" example:"true"`
+	ResponseTimeMs int64  `json:"response_time_ms" example:"4200"`
+	GameSessionID  string `json:"game_session_id" example:"session_2024_10_01"`
+}
+
+// QuestionAnswerRequest represents a user's answer to a generated question
+type QuestionAnswerRequest struct {
 	UserID         string `json:"user_id" binding:"required"`
-	QuestionID     string `json:"question_id" binding:"required"`
-	UserAnswer     string `json:"user_answer" binding:"required"`
-	IsCorrect      bool   `json:"is_correct"`
+	Answer         string `json:"answer" binding:"required"` // option ID ("A") or the option text
 	ResponseTimeMs int64  `json:"response_time_ms"`
 	GameSessionID  string `json:"game_session_id"`
 }
 
+// QuestionAnswerResponse represents a graded answer with an explanation of the correct answer
+type QuestionAnswerResponse struct {
+	QuestionID        string             `json:"question_id"`
+	IsCorrect         bool               `json:"is_correct"`
+	SelectedOption    string             `json:"selected_option"` // option the answer matched, empty if none
+	CorrectAnswer     string             `json:"correct_answer"`
+	CorrectAnswerText string             `json:"correct_answer_text"`
+	Explanation       string             `json:"explanation"`
+	Result            GameResultResponse `json:"result"`
+}
+
 // GameResultResponse represents the response after processing game result
 type GameResultResponse struct {
 	ResultID               string                 `json:"result_id"`
@@ -104,10 +308,27 @@ type GameResultResponse struct {
 
 // MemoryEvaluation represents user's memory evaluation
 type MemoryEvaluation struct {
-	Topic          string  `json:"topic"`
-	RetentionScore float32 `json:"retention_score"`
-	Confidence     string  `json:"confidence"` // "high", "medium", "low"
-	Recommendation string  `json:"recommendation"`
+	Topic          string                  `json:"topic"`
+	RetentionScore float32                 `json:"retention_score"`
+	ScoreBreakdown RetentionScoreBreakdown `json:"score_breakdown"` // zero when scored by the llm strategy
+	Confidence     string                  `json:"confidence"`      // "high", "medium", "low"
+	Recommendation string                  `json:"recommendation"`
+	Strategy       string                  `json:"strategy"` // "formula" or "llm", the strategy that produced the score
+}
+
+// RetentionScoreBreakdown explains how retention_score was composed
+type RetentionScoreBreakdown struct {
+	Correctness ScoreComponent `json:"correctness"`
+	Speed       ScoreComponent `json:"speed"`
+	Recency     ScoreComponent `json:"recency"`
+}
+
+// ScoreComponent is one weighted input of the retention score.
+// Contribution equals Score * Weight; contributions sum to retention_score.
+type ScoreComponent struct {
+	Score        float32 `json:"score"` // 0-1
+	Weight       float32 `json:"weight"`
+	Contribution float32 `json:"contribution"`
 }
 
 // NextQuestionSuggestion represents suggestions for the next question
@@ -124,12 +345,30 @@ type RAGConversationSearchRequest struct {
 	Limit int    `json:"limit"`
 }
 
+// RAGSearchPageRequest selects one page of conversation search results. Servers
+// that page by cursor return the cursor for the next page; others take an offset.
+type RAGSearchPageRequest struct {
+	Query  string    `json:"query"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset,omitempty"`
+	Cursor string    `json:"cursor,omitempty"` // next_cursor of the previous page, takes precedence over Offset
+	From   time.Time `json:"from,omitzero"`    // only conversations at or after From; zero for no lower bound
+	To     time.Time `json:"to,omitzero"`      // only conversations before To; zero for no upper bound
+}
+
+// RAGSearchPage is one page of conversation search results
+type RAGSearchPage struct {
+	Results    []RAGConversationSearchResult `json:"results"`
+	NextCursor string                        `json:"next_cursor,omitempty"` // empty on the last page or when the server pages by offset
+}
+
 // RAGConversationSearchResult represents a search result from RAG
 type RAGConversationSearchResult struct {
 	ConversationID string       `json:"conversation_id"`
 	Score          float32      `json:"score"`
 	Timestamp      time.Time    `json:"timestamp"`
 	Messages       []RAGMessage `json:"messages"`
+	Metadata       *RAGMetadata `json:"metadata,omitempty"` // returned by RAG servers that expose stored metadata
 }
 
 // RAGMessage represents a message in RAG conversation
@@ -145,14 +384,32 @@ type RAGConversationSaveRequest struct {
 	Metadata       *RAGMetadata `json:"metadata,omitempty"`
 }
 
-// RAGMetadata represents metadata for RAG storage
+// RAGMetadata represents metadata for RAG storage. The RAG client stamps
+// SchemaVersion and validates the metadata before every save.
 type RAGMetadata struct {
-	Source            string  `json:"source,omitempty"`
-	SessionID         string  `json:"session_id,omitempty"`
-	Type              string  `json:"type,omitempty"` // "chat", "memory_evaluation", etc.
-	RetentionScore    float32 `json:"retention_score,omitempty"`
-	QuestionID        string  `json:"question_id,omitempty"`
-	ConversationScore int     `json:"conversation_score,omitempty"` // 0-100: Quality score of the conversation
+	SchemaVersion     int      `json:"schema_version,omitempty"`
+	Source            string   `json:"source,omitempty"`
+	SessionID         string   `json:"session_id,omitempty"`
+	Type              string   `json:"type,omitempty"` // "chat", "memory_evaluation", etc.
+	RetentionScore    float32  `json:"retention_score,omitempty"`
+	QuestionID        string   `json:"question_id,omitempty"`
+	ConversationScore int      `json:"conversation_score,omitempty"` // 0-100: Quality score of the conversation
+	ScoreVersion      string   `json:"score_version,omitempty"`      // prompt version ConversationScore was evaluated with
+	PromptVariant     string   `json:"prompt_variant,omitempty"`     // prompt experiment variant the user was served
+	Model             string   `json:"model,omitempty"`
+	QueryStrategy     string   `json:"query_strategy,omitempty"` // retrieval query strategy the user was served
+	MoodScore         *int     `json:"mood_score,omitempty"`     // 0-100: emotional state of the user's message, 50 is neutral
+	Emotion           string   `json:"emotion,omitempty"`
+	Topics            []string `json:"topics,omitempty"`         // conversation topics, most prominent first
+	Language          string   `json:"language,omitempty"`       // response language of the chat (ko, en)
+	SourceChannel     string   `json:"source_channel,omitempty"` // phone, app or api
+	TenantID          string   `json:"tenant_id,omitempty"`      // care facility whose API key saved the conversation
+}
+
+// Sentiment is the emotional state scored for one chat turn
+type Sentiment struct {
+	MoodScore int    `json:"mood_score"` // 0-100, 50 is neutral
+	Emotion   string `json:"emotion"`    // joy, calm, neutral, sadness, loneliness, anxiety, anger
 }
 
 // ===== API Response Wrappers =====
@@ -167,9 +424,9 @@ type APIResponse struct {
 
 // ErrorInfo represents error details in API response
 type ErrorInfo struct {
-	Code    string      `json:"code"`
-	Message string      `json:"message"`
-	Details interface{} `json:"details,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
 }
 
 // Metadata represents response metadata
@@ -182,16 +439,26 @@ type Metadata struct {
 
 // StoredQuestion represents a question stored in memory for retrieval
 type StoredQuestion struct {
-	QuestionID          string
-	UserID              string
-	QuestionType        string
-	Question            string
-	CorrectAnswer       string
-	BasedOnConversation string
-	Difficulty          string
-	Topic               string
-	GeneratedAt         time.Time
-	ExpiresAt           time.Time
+	QuestionID           string
+	UserID               string
+	QuestionType         string
+	Question             string
+	Options              []QuestionOption
+	CorrectAnswer        string
+	BasedOnConversation  string
+	BasedOnConversations []string // all source conversations for integrative questions
+	ImageURL             string   // source photo for photo questions
+	Difficulty           string
+	Topic                string
+	ReviewStatus         string                // empty unless generated for supervised review
+	Payload              *GameQuestionResponse // the question as returned to clients
+	SourceContent        string                // text of the source conversations, used to explain the answer
+	SourceConversations  []RAGConversationSearchResult
+	SourceTimestamp      time.Time           // oldest source conversation; zero for photo questions
+	Generation           *QuestionGeneration // nil for questions generated without a recorded prompt
+	Validation           []QuestionValidationCheck
+	GeneratedAt          time.Time
+	ExpiresAt            time.Time
 }
 
 // RAGConversationInfo represents conversation info from RAG
@@ -304,10 +571,23 @@ type AnalysisRequest struct {
 
 // AnalysisResponse represents the API response for analysis (통합: 도메인 + 리포트)
 type AnalysisResponse struct {
-	UserID     string        `json:"user_id"`
-	Domains    []DomainScore `json:"domains"`
-	Report     string        `json:"report"` // MD 형식 리포트 (2000자 이상)
-	AnalyzedAt time.Time     `json:"analyzed_at"`
+	UserID        string               `json:"user_id"`
+	Domains       []DomainScore        `json:"domains"`
+	Consolidation *ConsolidationMetric `json:"consolidation,omitempty"`
+	Report        string               `json:"report"` // MD 형식 리포트 (2000자 이상)
+	AnalyzedAt    time.Time            `json:"analyzed_at"`
+}
+
+// ConsolidationMetric separates facts recalled across several game sessions
+// from facts recalled only in the session they were first quizzed
+type ConsolidationMetric struct {
+	Score              int      `json:"score"` // 0-100, share of re-quizzed facts recalled in 2+ sessions
+	TrackedFacts       int      `json:"tracked_facts"`
+	ConsolidatedFacts  int      `json:"consolidated_facts"`
+	ImmediateOnlyFacts int      `json:"immediate_only_facts"`
+	NotRecalledFacts   int      `json:"not_recalled_facts"`
+	ConsolidatedTopics []string `json:"consolidated_topics"`
+	ImmediateTopics    []string `json:"immediate_topics"`
 }
 
 // DomainAnalysisOnlyResponse represents the API response for domain analysis only
@@ -327,3 +607,629 @@ type ReportGenerationResponse struct {
 	Report      string    `json:"report"` // MD 형식 리포트
 	GeneratedAt time.Time `json:"generated_at"`
 }
+
+// ===== Experiment Models =====
+
+// ExperimentCohortResult represents joined usage and outcome metrics for one prompt/model cohort
+type ExperimentCohortResult struct {
+	Cohort               string  `json:"cohort"`
+	PromptVersion        string  `json:"prompt_version"`
+	Model                string  `json:"model"`
+	QueryStrategy        string  `json:"query_strategy"`
+	Users                int     `json:"users"`
+	ChatTurns            int     `json:"chat_turns"`
+	QuestionsGenerated   int     `json:"questions_generated"`
+	Evaluations          int     `json:"evaluations"`
+	AvgConversationScore float32 `json:"avg_conversation_score"`
+	QuizAttempts         int     `json:"quiz_attempts"`
+	QuizCorrect          int     `json:"quiz_correct"`
+	QuizAccuracy         float32 `json:"quiz_accuracy"`
+	AvgRetentionScore    float32 `json:"avg_retention_score"`
+}
+
+// ExperimentVariant represents a configured prompt or query strategy variant and its traffic weight
+type ExperimentVariant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+// ExperimentResultsResponse represents experiment results across cohorts
+type ExperimentResultsResponse struct {
+	Variants              []ExperimentVariant      `json:"variants"`
+	QueryStrategyVariants []ExperimentVariant      `json:"query_strategy_variants"`
+	Cohorts               []ExperimentCohortResult `json:"cohorts"`
+	GeneratedAt           time.Time                `json:"generated_at"`
+}
+
+// ===== Admin Models =====
+
+// RuntimeConfigResponse represents the runtime-tunable settings currently in effect
+type RuntimeConfigResponse struct {
+	OpenAIModel                       string    `json:"openai_model"`
+	OpenAITemperature                 float32   `json:"openai_temperature"`
+	OpenAIMaxTokens                   int       `json:"openai_max_tokens"`
+	ContextMinScore                   float32   `json:"context_min_score"`
+	ContextMaxMessagesPerConversation int       `json:"context_max_messages_per_conversation"`
+	ContextRerankEnabled              bool      `json:"context_rerank_enabled"`
+	ContextSummaryEnabled             bool      `json:"context_summary_enabled"`
+	ContextQueryStrategy              string    `json:"context_query_strategy"`
+	QuestionCacheTTLSeconds           int       `json:"question_cache_ttl_seconds"`
+	ConfidenceHighThreshold           float32   `json:"confidence_high_threshold"`
+	ConfidenceMediumThreshold         float32   `json:"confidence_medium_threshold"`
+	MemoryEvaluationWeights           []float32 `json:"memory_evaluation_weights"` // correct, speed, recency
+	LogLevel                          string    `json:"log_level"`
+	LogSampleRate                     float64   `json:"log_sample_rate"`
+	LogDebugUsers                     []string  `json:"log_debug_users"`
+}
+
+// RuntimeConfigPatchRequest represents a partial update of runtime settings (omitted fields are unchanged)
+type RuntimeConfigPatchRequest struct {
+	OpenAIModel                       *string   `json:"openai_model" binding:"omitempty,min=1"`
+	OpenAITemperature                 *float32  `json:"openai_temperature" binding:"omitempty,gte=0,lte=2"`
+	OpenAIMaxTokens                   *int      `json:"openai_max_tokens" binding:"omitempty,gte=1,lte=16384"`
+	ContextMinScore                   *float32  `json:"context_min_score" binding:"omitempty,gte=0,lte=1"`
+	ContextMaxMessagesPerConversation *int      `json:"context_max_messages_per_conversation" binding:"omitempty,gte=0"`
+	ContextRerankEnabled              *bool     `json:"context_rerank_enabled"`
+	ContextSummaryEnabled             *bool     `json:"context_summary_enabled"`
+	ContextQueryStrategy              *string   `json:"context_query_strategy" binding:"omitempty,oneof=raw llm hybrid"`
+	QuestionCacheTTLSeconds           *int      `json:"question_cache_ttl_seconds" binding:"omitempty,gte=1"`
+	ConfidenceHighThreshold           *float32  `json:"confidence_high_threshold" binding:"omitempty,gte=0,lte=1"`
+	ConfidenceMediumThreshold         *float32  `json:"confidence_medium_threshold" binding:"omitempty,gte=0,lte=1"`
+	LogSampleRate                     *float64  `json:"log_sample_rate" binding:"omitempty,gte=0,lte=1"`
+	LogDebugUsers                     *[]string `json:"log_debug_users"`
+}
+
+// FeatureSwitch is the state of one safe-mode kill switch
+type FeatureSwitch struct {
+	Feature string `json:"feature" enums:"analysis,evaluation,suggestions" example:"analysis"`
+	Enabled bool   `json:"enabled"`
+}
+
+// SafeModeResponse represents the safe-mode kill switches currently in effect
+type SafeModeResponse struct {
+	Features []FeatureSwitch `json:"features"`
+}
+
+// SafeModePatchRequest turns features on (true) or off (false) by name; omitted features are unchanged
+type SafeModePatchRequest struct {
+	Features map[string]bool `json:"features" binding:"required,min=1"`
+}
+
+// ConfidenceCalibrationReport compares confidence labels with later recall of the same fact
+type ConfidenceCalibrationReport struct {
+	HighThreshold            float32                 `json:"high_threshold"`
+	MediumThreshold          float32                 `json:"medium_threshold"`
+	SuggestedHighThreshold   float32                 `json:"suggested_high_threshold"`
+	SuggestedMediumThreshold float32                 `json:"suggested_medium_threshold"`
+	Samples                  int                     `json:"samples"`
+	MinSamples               int                     `json:"min_samples"`
+	Labels                   []ConfidenceLabelRecall `json:"labels"`
+	AutoAdjust               bool                    `json:"auto_adjust"`
+	Applied                  bool                    `json:"applied"` // thresholds were changed by this evaluation
+	EvaluatedAt              time.Time               `json:"evaluated_at"`
+}
+
+// ScoreBackfillRequest starts a job that re-scores users' stored conversations
+// with the current evaluation prompt
+type ScoreBackfillRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1,dive,required"`
+	Limit   int      `json:"limit,omitempty" binding:"omitempty,min=1,max=500"` // conversations per user, newest first
+	Force   bool     `json:"force,omitempty"`                                   // also re-score conversations already at the current version
+}
+
+// ReplayRequest re-runs a user's stored chat turns, newest first, through the
+// current prompt and model configuration. Generation overrides replay with a
+// candidate model or temperature instead.
+type ReplayRequest struct {
+	UserID string `json:"user_id" binding:"required" example:"user_123"`
+	Limit  int    `json:"limit,omitempty" binding:"omitempty,min=1,max=50"` // default 10
+	TimeWindow
+	GenerationOverrides
+}
+
+// ReplayResult compares one stored chat turn with its replay
+type ReplayResult struct {
+	ConversationID       string    `json:"conversation_id"`
+	Timestamp            time.Time `json:"timestamp"`
+	Message              string    `json:"message"`
+	OriginalResponse     string    `json:"original_response"`
+	ReplayedResponse     string    `json:"replayed_response,omitempty"`
+	Similarity           float64   `json:"similarity"` // 0-1 character n-gram similarity of the two replies
+	Changed              bool      `json:"changed"`
+	OriginalScore        *int      `json:"original_score,omitempty"`
+	OriginalScoreVersion string    `json:"original_score_version,omitempty"`
+	ReplayedScore        *int      `json:"replayed_score,omitempty"`
+	ScoreDelta           *int      `json:"score_delta,omitempty"` // replayed minus original score
+	Error                string    `json:"error,omitempty"`
+}
+
+// ReplayResponse reports a replay run
+type ReplayResponse struct {
+	UserID            string         `json:"user_id"`
+	PromptVariant     string         `json:"prompt_variant"` // empty for the control prompt
+	Model             string         `json:"model"`
+	ScoreVersion      string         `json:"score_version"` // evaluation prompt version of the replayed scores
+	Replayed          int            `json:"replayed"`
+	Changed           int            `json:"changed"`
+	Failed            int            `json:"failed"`
+	AverageSimilarity float64        `json:"average_similarity"`
+	AverageScoreDelta *float64       `json:"average_score_delta,omitempty"` // over turns scored both times
+	Results           []ReplayResult `json:"results"`
+	StartedAt         time.Time      `json:"started_at"`
+	FinishedAt        time.Time      `json:"finished_at"`
+}
+
+// ScoreBackfillJob reports the progress of a score backfill
+type ScoreBackfillJob struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status" enums:"running,completed"`
+	PromptVersion string     `json:"prompt_version"`
+	UserIDs       []string   `json:"user_ids"`
+	Force         bool       `json:"force"`
+	Scanned       int        `json:"scanned"`
+	Rescored      int        `json:"rescored"`
+	Skipped       int        `json:"skipped"` // not chat turns, or already at the current version
+	Failed        int        `json:"failed"`
+	Errors        []string   `json:"errors,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// CacheStats describes one in-memory cache
+type CacheStats struct {
+	Name       string `json:"name" example:"profiles"`
+	Enabled    bool   `json:"enabled"`
+	Entries    int    `json:"entries"`
+	Users      int    `json:"users"`       // users with at least one entry
+	TTLSeconds int64  `json:"ttl_seconds"` // how long entries are served, 0 when disabled
+}
+
+// CacheStatsResponse lists the in-memory caches
+type CacheStatsResponse struct {
+	Caches []CacheStats `json:"caches"`
+}
+
+// CacheEvictResponse reports entries removed from a cache
+type CacheEvictResponse struct {
+	Name    string `json:"name"`
+	Key     string `json:"key"`
+	Evicted int    `json:"evicted"`
+}
+
+// MaintenanceReport summarizes a maintenance run
+type MaintenanceReport struct {
+	Tasks               []MaintenanceTaskResult `json:"tasks"`
+	TotalRemoved        int                     `json:"total_removed"`
+	TotalReclaimedBytes int64                   `json:"total_reclaimed_bytes"` // approximate
+	StartedAt           time.Time               `json:"started_at"`
+	FinishedAt          time.Time               `json:"finished_at"`
+	NextRunAt           *time.Time              `json:"next_run_at,omitempty"` // nil when nightly maintenance is disabled
+}
+
+// MaintenanceTaskResult is what one compaction task removed
+type MaintenanceTaskResult struct {
+	Name           string `json:"name" example:"expired_questions"`
+	Removed        int    `json:"removed"`
+	ReclaimedBytes int64  `json:"reclaimed_bytes"` // approximate
+	DurationMs     int64  `json:"duration_ms"`
+}
+
+// ConfidenceLabelRecall is the observed later-recall rate for one confidence label
+type ConfidenceLabelRecall struct {
+	Label           string  `json:"label"`
+	Samples         int     `json:"samples"`
+	LaterRecallRate float32 `json:"later_recall_rate"` // 0-1
+	TargetRate      float32 `json:"target_rate"`
+}
+
+// AnalysisCompletedEvent is the webhook payload sent when a domain analysis finishes
+type AnalysisCompletedEvent struct {
+	Domains            []DomainScore `json:"domains"`
+	ConsolidationScore *int          `json:"consolidation_score,omitempty"`
+	AnalyzedAt         time.Time     `json:"analyzed_at"`
+}
+
+// RetentionDroppedEvent is the webhook payload sent when a retention score falls below the alert threshold
+type RetentionDroppedEvent struct {
+	QuestionID     string  `json:"question_id"`
+	Topic          string  `json:"topic"`
+	RetentionScore float32 `json:"retention_score"`
+	PreviousScore  float32 `json:"previous_score"`
+	Threshold      float32 `json:"threshold"`
+}
+
+// IncorrectStreakEvent is the webhook payload sent after consecutive incorrect answers
+type IncorrectStreakEvent struct {
+	QuestionID string `json:"question_id"`
+	Topic      string `json:"topic"`
+	Streak     int    `json:"streak"`
+}
+
+// WebhookRegisterRequest registers an endpoint for caregiver notifications
+type WebhookRegisterRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Secret string   `json:"secret" binding:"required,min=16"` // HMAC-SHA256 signing key
+	Events []string `json:"events"`                           // omit to receive every event
+}
+
+// WebhookSubscriptionResponse represents a registered webhook (the secret is never returned)
+type WebhookSubscriptionResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Source    string    `json:"source"` // config, api
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookListResponse lists webhook subscriptions and the events they can select
+type WebhookListResponse struct {
+	Subscriptions []WebhookSubscriptionResponse `json:"subscriptions"`
+	EventTypes    []string                      `json:"event_types"`
+}
+
+// WebhookDeleteResponse confirms a removed webhook subscription
+type WebhookDeleteResponse struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// MoodTimelineRequest represents a mood timeline query
+type MoodTimelineRequest struct {
+	UserID string `form:"user_id" binding:"required"`
+	Days   int    `form:"days" binding:"omitempty,min=1,max=90"`
+}
+
+// MoodTimelineResponse represents a user's mood over time for the caregiver dashboard
+type MoodTimelineResponse struct {
+	UserID       string         `json:"user_id"`
+	Days         int            `json:"days"`
+	AverageScore *float32       `json:"average_score,omitempty"` // omitted when no turns were scored
+	Daily        []DailyMood    `json:"daily"`
+	Entries      []MoodEntry    `json:"entries"`
+	Emotions     map[string]int `json:"emotions"` // turn count per emotion
+}
+
+// DailyMood summarizes one day's scored chat turns
+type DailyMood struct {
+	Date            string  `json:"date"` // YYYY-MM-DD
+	AverageScore    float32 `json:"average_score"`
+	DominantEmotion string  `json:"dominant_emotion"`
+	Turns           int     `json:"turns"`
+}
+
+// MoodEntry is the sentiment of a single chat turn
+type MoodEntry struct {
+	ConversationID string    `json:"conversation_id"`
+	MoodScore      int       `json:"mood_score"`
+	Emotion        string    `json:"emotion"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ===== Care Plan Models =====
+
+// CarePlanRequest replaces a user's weekly cognitive exercise plan
+type CarePlanRequest struct {
+	Goals []CarePlanGoal `json:"goals" binding:"required,min=1,max=7,dive"`
+}
+
+// CarePlanGoal is a cognitive exercise the assistant steers chats towards on the scheduled days
+type CarePlanGoal struct {
+	Kind        string   `json:"kind" binding:"required,oneof=family_reminiscence date_orientation name_recall" enums:"family_reminiscence,date_orientation,name_recall" example:"family_reminiscence"`
+	Note        string   `json:"note,omitempty" binding:"max=200" example:"손녀 지수가 이번 달에 대학에 입학했어요"`                               // caregiver detail woven into the guidance
+	Days        []string `json:"days,omitempty" binding:"omitempty,dive,oneof=mon tue wed thu fri sat sun" example:"mon,wed,fri"` // empty schedules every day
+	TargetTurns int      `json:"target_turns,omitempty" binding:"omitempty,min=1,max=20" example:"3"`                             // engaged turns per scheduled day, default 3
+}
+
+// CarePlanResponse is a user's stored weekly plan
+type CarePlanResponse struct {
+	UserID    string         `json:"user_id"`
+	Goals     []CarePlanGoal `json:"goals"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// GoalSummaryRequest represents a daily goal coverage query
+type GoalSummaryRequest struct {
+	Date string `form:"date" binding:"omitempty,datetime=2006-01-02"` // YYYY-MM-DD, default today
+}
+
+// GoalSummaryResponse reports how well a day's chats covered the plan's goals
+type GoalSummaryResponse struct {
+	UserID   string         `json:"user_id"`
+	Date     string         `json:"date"` // YYYY-MM-DD
+	Goals    []GoalCoverage `json:"goals"`
+	Coverage float32        `json:"coverage"` // mean coverage of the day's scheduled goals, 0 when none
+}
+
+// GoalCoverage is one goal's progress on a day. A turn is prompted when the goal
+// was injected into the chat prompt, and engaged when the user's message touched it.
+type GoalCoverage struct {
+	Kind          string  `json:"kind"`
+	Scheduled     bool    `json:"scheduled"`
+	TargetTurns   int     `json:"target_turns"`
+	PromptedTurns int     `json:"prompted_turns"`
+	EngagedTurns  int     `json:"engaged_turns"`
+	Coverage      float32 `json:"coverage"` // engaged turns over the target, at most 1
+	Met           bool    `json:"met"`
+}
+
+// ===== User Registry Models =====
+
+// UserCreateRequest registers a user. Only registered users can be served
+// when the registry is enforced.
+type UserCreateRequest struct {
+	UserID            string          `json:"user_id" binding:"required,max=64" example:"user_123"`
+	Name              string          `json:"name" binding:"required,max=50" example:"김영희"`
+	BirthYear         int             `json:"birth_year,omitempty" binding:"omitempty,min=1900" example:"1942"`
+	PreferredCallTime string          `json:"preferred_call_time,omitempty" example:"10:30"` // HH:MM, local time
+	Preferences       UserPreferences `json:"preferences,omitempty"`
+}
+
+// UserPreferences are settings chat and games apply to every request for the
+// user, so clients need not resend them. Empty fields use the server defaults.
+type UserPreferences struct {
+	Language            string  `json:"language,omitempty" binding:"omitempty,oneof=ko en" enums:"ko,en"`                                            // chat reply language, CHAT_LANGUAGE when empty
+	Persona             string  `json:"persona,omitempty" binding:"omitempty,oneof=friend grandchild counselor" enums:"friend,grandchild,counselor"` // how the chat addresses the user
+	VoiceSpeed          float32 `json:"voice_speed,omitempty" binding:"omitempty,gte=0.5,lte=2" example:"0.8"`                                       // speech rate for the voice frontend, 1 is normal
+	QuizDifficultyFloor string  `json:"quiz_difficulty_floor,omitempty" binding:"omitempty,oneof=easy medium hard" enums:"easy,medium,hard"`         // questions are never easier than this
+}
+
+// User is a registered user
+type User struct {
+	UserID            string          `json:"user_id"`
+	Name              string          `json:"name"`
+	BirthYear         int             `json:"birth_year,omitempty"`
+	PreferredCallTime string          `json:"preferred_call_time,omitempty"`
+	Preferences       UserPreferences `json:"preferences"`
+	TenantID          string          `json:"tenant_id,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         *time.Time      `json:"updated_at,omitempty"` // last preferences change
+}
+
+// ===== Reminder Models =====
+
+// ReminderRequest represents a caregiver request to remind a user of a medication or appointment
+type ReminderRequest struct {
+	UserID string    `json:"user_id" binding:"required" example:"user_123"`
+	Kind   string    `json:"kind" binding:"required,oneof=medication appointment" enums:"medication,appointment" example:"appointment"`
+	Title  string    `json:"title" binding:"required,max=100" example:"정형외과 진료"`
+	Note   string    `json:"note,omitempty" binding:"max=200" example:"딸이 모시러 옴"`
+	DueAt  time.Time `json:"due_at" binding:"required" example:"2024-10-14T15:00:00+09:00"`
+}
+
+// Reminder is a stored reminder. It is brought up in chats around its due time
+// until the user confirms it.
+type Reminder struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	Kind           string     `json:"kind"`
+	Title          string     `json:"title"`
+	Note           string     `json:"note,omitempty"`
+	DueAt          time.Time  `json:"due_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	MentionedAt    *time.Time `json:"mentioned_at,omitempty"`    // last time a chat prompt included it
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"` // when the user confirmed it
+}
+
+// ReminderAcknowledgeRequest represents a confirmation of a reminder outside of chat
+type ReminderAcknowledgeRequest struct {
+	UserID string `json:"user_id" binding:"required" example:"user_123"`
+}
+
+// ReminderListRequest represents a reminder listing query
+type ReminderListRequest struct {
+	UserID              string `form:"user_id" binding:"required"`
+	IncludeAcknowledged bool   `form:"include_acknowledged"`
+}
+
+// ReminderListResponse lists a user's reminders, soonest first
+type ReminderListResponse struct {
+	UserID    string     `json:"user_id"`
+	Reminders []Reminder `json:"reminders"`
+}
+
+// ===== Assessment Models =====
+
+// AssessmentStartRequest represents a request to start a cognitive screen for a user
+type AssessmentStartRequest struct {
+	UserID string `json:"user_id" binding:"required" example:"user_123"`
+}
+
+// AssessmentProgress is the state of an assessment after a turn. Prompt is
+// what the chat asks next, or the closing line once the assessment is completed.
+type AssessmentProgress struct {
+	AssessmentID string `json:"assessment_id"`
+	Domain       string `json:"domain,omitempty" enums:"orientation,registration,attention,recall"` // of the item asked next
+	Item         int    `json:"item"`                                                               // 1-based number of the item asked next
+	TotalItems   int    `json:"total_items"`
+	Prompt       string `json:"prompt" example:"올해는 몇 년도인가요?"`
+	Completed    bool   `json:"completed"`
+}
+
+// AssessmentItemResult is the scored answer to one assessment item
+type AssessmentItemResult struct {
+	Domain   string `json:"domain" enums:"orientation,registration,attention,recall"`
+	Prompt   string `json:"prompt"`
+	Expected string `json:"expected"`
+	Answer   string `json:"answer"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+}
+
+// AssessmentDomainScore totals the items of one domain
+type AssessmentDomainScore struct {
+	Domain   string `json:"domain" enums:"orientation,registration,attention,recall"`
+	Score    int    `json:"score"`
+	MaxScore int    `json:"max_score"`
+}
+
+// AssessmentResult is a cognitive screen and its scores so far. Scores are raw
+// K-MMSE item points for the administered items only (orientation to time,
+// registration, attention, recall), not a full 30-point K-MMSE score.
+type AssessmentResult struct {
+	AssessmentID string                  `json:"assessment_id"`
+	UserID       string                  `json:"user_id"`
+	Status       string                  `json:"status" enums:"in_progress,completed,abandoned"`
+	Score        int                     `json:"score"`
+	MaxScore     int                     `json:"max_score"`
+	Domains      []AssessmentDomainScore `json:"domains"`
+	Items        []AssessmentItemResult  `json:"items"` // answered items, in order
+	StartedAt    time.Time               `json:"started_at"`
+	CompletedAt  *time.Time              `json:"completed_at,omitempty"`
+}
+
+// ===== Cognitive Timeline Models =====
+
+// CognitiveTimelineRequest represents a cognitive trend query for the caregiver dashboard
+type CognitiveTimelineRequest struct {
+	UserID string `form:"user_id" binding:"required"`
+	Days   int    `form:"days" binding:"omitempty,min=1,max=365"` // default 90
+}
+
+// CognitiveTimelineResponse gathers a user's cognitive scores over time into
+// series for the caregiver dashboard's trend charts. Every series is oldest first.
+type CognitiveTimelineResponse struct {
+	UserID       string                `json:"user_id"`
+	Days         int                   `json:"days"`
+	Daily        []CognitiveDailyPoint `json:"daily"`         // days with quiz attempts or scored chats
+	Assessments  []AssessmentPoint     `json:"assessments"`   // completed assessments
+	DomainScores []DomainScorePoint    `json:"domain_scores"` // analysis reports
+	Warnings     []string              `json:"warnings,omitempty"`
+}
+
+// CognitiveDailyPoint averages one day's quiz retention scores and chat quality scores
+type CognitiveDailyPoint struct {
+	Date             string   `json:"date"`                      // YYYY-MM-DD
+	RetentionScore   *float32 `json:"retention_score,omitempty"` // 0-1, omitted without quiz attempts
+	QuizAttempts     int      `json:"quiz_attempts"`
+	ChatQualityScore *float32 `json:"chat_quality_score,omitempty"` // 0-100, omitted without scored chats
+	ChatTurns        int      `json:"chat_turns"`
+}
+
+// AssessmentPoint is the score of one completed assessment
+type AssessmentPoint struct {
+	AssessmentID string                  `json:"assessment_id"`
+	CompletedAt  time.Time               `json:"completed_at"`
+	Score        int                     `json:"score"`
+	MaxScore     int                     `json:"max_score"`
+	Domains      []AssessmentDomainScore `json:"domains"`
+}
+
+// DomainScorePoint is the domain scores of one analysis report
+type DomainScorePoint struct {
+	AnalyzedAt    time.Time      `json:"analyzed_at"`
+	Scores        map[string]int `json:"scores"`                        // domain -> 0-100
+	Consolidation *int           `json:"consolidation_score,omitempty"` // 0-100
+}
+
+// ===== User Data Models =====
+
+// QuizHistoryEntry is a single quiz attempt kept in the local quiz history
+type QuizHistoryEntry struct {
+	FactID         string    `json:"fact_id"`
+	Topic          string    `json:"topic"`
+	SessionID      string    `json:"session_id"`
+	IsCorrect      bool      `json:"is_correct"`
+	RetentionScore float32   `json:"retention_score"`
+	Confidence     string    `json:"confidence,omitempty"`
+	AttemptedAt    time.Time `json:"attempted_at"`
+}
+
+// UserDataExport is everything stored about a user, assembled into one archive
+type UserDataExport struct {
+	UserID                string                        `json:"user_id"`
+	ExportedAt            time.Time                     `json:"exported_at"`
+	User                  *User                         `json:"user,omitempty"` // registry entry, if registered
+	Conversations         []RAGConversationSearchResult `json:"conversations"`
+	PersonalInfo          []PersonalInfoResponse        `json:"personal_info"`
+	IncorrectQuizAttempts []IncorrectQuizAttempt        `json:"incorrect_quiz_attempts"`
+	QuizHistory           []QuizHistoryEntry            `json:"quiz_history"`
+	MoodEntries           []MoodEntry                   `json:"mood_entries"`
+	CarePlan              *CarePlanResponse             `json:"care_plan,omitempty"`
+	Reminders             []Reminder                    `json:"reminders"`
+	Assessments           []AssessmentResult            `json:"assessments"`
+	AnalysisReports       []AnalysisResponse            `json:"analysis_reports"`
+}
+
+// UserDeletionResponse reports what was erased for a user
+type UserDeletionResponse struct {
+	UserID       string         `json:"user_id"`
+	LocalRemoved map[string]int `json:"local_removed"` // items removed from each in-process store
+	RAGDeleted   bool           `json:"rag_deleted"`
+	DeletedAt    time.Time      `json:"deleted_at"`
+}
+
+// ===== Health Models =====
+
+// HealthResponse represents the service health. Status is "degraded" while there are warnings.
+type HealthResponse struct {
+	Status        string         `json:"status"` // "ok" or "degraded"
+	Service       string         `json:"service"`
+	ModelWarnings []ModelWarning `json:"model_warnings,omitempty"`
+}
+
+// ModelWarning reports a model the provider rejected as deprecated or not found
+type ModelWarning struct {
+	Model       string    `json:"model"`
+	Replacement string    `json:"replacement,omitempty"` // empty when no fallback model is configured, so calls fail
+	Error       string    `json:"error"`
+	DetectedAt  time.Time `json:"detected_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// ===== Debug Models =====
+
+// PromptMessage is one message of a prompt as it would be sent to the model
+type PromptMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatPromptPreviewResponse represents the prompt a chat request would be answered with
+type ChatPromptPreviewResponse struct {
+	Model           string          `json:"model"`
+	Messages        []PromptMessage `json:"messages"`
+	ContextUsed     ContextUsage    `json:"context_used"`
+	ContextMessages []string        `json:"context_messages"` // retrieved messages that passed the score filter
+	PreviousSummary string          `json:"previous_summary,omitempty"`
+	SmallTalk       bool            `json:"small_talk"`
+	SkippedSteps    []string        `json:"skipped_steps"` // LLM-backed steps left out of the dry run
+}
+
+// QuestionPromptPreviewResponse represents the prompt a question request would be generated with
+type QuestionPromptPreviewResponse struct {
+	Model                string          `json:"model"`
+	Messages             []PromptMessage `json:"messages"`
+	QuestionType         string          `json:"question_type"`
+	Difficulty           string          `json:"difficulty"`
+	Topic                string          `json:"topic"`
+	BasedOnConversations []string        `json:"based_on_conversations,omitempty"`
+}
+
+// QuestionGeneration records the prompt a question was generated with and the model's raw output
+type QuestionGeneration struct {
+	Model     string          `json:"model"`
+	Prompt    []PromptMessage `json:"prompt"`
+	RawOutput string          `json:"raw_output"`
+}
+
+// QuestionValidationCheck is the result of one check run on a generated question
+type QuestionValidationCheck struct {
+	Name   string `json:"name" enums:"option_count,correct_answer_in_options,distinct_options,answer_in_source" example:"answer_in_source"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// QuestionLineageResponse represents everything a cached question was generated from, for
+// investigating reports of questions about things that never came up in conversation
+type QuestionLineageResponse struct {
+	QuestionID          string                        `json:"question_id"`
+	UserID              string                        `json:"user_id"`
+	ReviewStatus        string                        `json:"review_status,omitempty"`
+	Question            *GameQuestionResponse         `json:"question"`
+	SourceConversations []RAGConversationSearchResult `json:"source_conversations"` // empty for photo questions
+	SourceContent       string                        `json:"source_content"`       // conversation text or photo description given to the model
+	Generation          *QuestionGeneration           `json:"generation,omitempty"`
+	Validation          []QuestionValidationCheck     `json:"validation"`
+	GeneratedAt         time.Time                     `json:"generated_at"`
+	ExpiresAt           time.Time                     `json:"expires_at"`
+}
@@ -0,0 +1,114 @@
+// Package pii masks personal identifiers (phone numbers, resident registration
+// numbers, addresses) in text sent to the LLM. Each identifier is replaced with
+// a placeholder such as [PHONE_1], recorded in a Mask so that a reply quoting
+// the placeholder can be restored locally.
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"llm/internal/metrics"
+	"llm/internal/util"
+)
+
+// patterns matches each PII kind. Resident registration numbers are masked
+// before phone numbers, whose digits they could otherwise partly match.
+var patterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{util.PIIKindRRN, regexp.MustCompile(`\b\d{6}\s?-\s?[1-8]\d{6}\b`)},
+	{util.PIIKindPhone, regexp.MustCompile(`(?:\+82[-\s]?|\b0)\d{1,2}[-.\s]?\d{3,4}[-.\s]?\d{4}\b`)},
+	// Road-name addresses ("서울시 종로구 세종대로 175") and lot-number addresses ("역삼동 123-4번지"),
+	// with an optional apartment building and unit
+	{util.PIIKindAddress, regexp.MustCompile(`(?:[가-힣]+(?:특별시|광역시|시|도)\s+)?[가-힣]+(?:시|군|구)\s+[가-힣0-9]+(?:로|길)\s*\d+(?:-\d+)?(?:\s*,?\s*\d+동\s*\d+호)?`)},
+	{util.PIIKindAddress, regexp.MustCompile(`[가-힣]+(?:동|읍|면|리)\s+\d+(?:-\d+)?\s*번지(?:\s*,?\s*\d+동\s*\d+호)?`)},
+}
+
+// Scrubber masks the configured PII kinds
+type Scrubber struct {
+	kinds map[string]bool
+}
+
+// NewScrubber creates a scrubber masking the given kinds; unknown kinds are ignored
+func NewScrubber(kinds []string) *Scrubber {
+	s := &Scrubber{kinds: make(map[string]bool)}
+	for _, kind := range kinds {
+		s.kinds[kind] = true
+	}
+	return s
+}
+
+// Redact replaces the identifiers in text with placeholders recorded in mask.
+// The same identifier gets the same placeholder everywhere it appears.
+func (s *Scrubber) Redact(mask *Mask, text string) string {
+	for _, p := range patterns {
+		if !s.kinds[p.kind] {
+			continue
+		}
+		text = p.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return mask.placeholder(p.kind, match)
+		})
+	}
+	return text
+}
+
+// RedactAll redacts each text into a new slice
+func (s *Scrubber) RedactAll(mask *Mask, texts []string) []string {
+	redacted := make([]string, len(texts))
+	for i, text := range texts {
+		redacted[i] = s.Redact(mask, text)
+	}
+	return redacted
+}
+
+// Mask maps the placeholders used for one prompt back to the identifiers they
+// replaced. It is not safe for concurrent use.
+type Mask struct {
+	originals    map[string]string // placeholder -> identifier
+	placeholders map[string]string // identifier -> placeholder
+	counts       map[string]int    // kind -> placeholders issued
+}
+
+// NewMask creates an empty mask
+func NewMask() *Mask {
+	return &Mask{
+		originals:    make(map[string]string),
+		placeholders: make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// Len returns how many distinct identifiers were masked
+func (m *Mask) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.originals)
+}
+
+// Restore replaces the placeholders in text with the identifiers they masked
+func (m *Mask) Restore(text string) string {
+	if m.Len() == 0 {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(m.originals))
+	for placeholder, original := range m.originals {
+		pairs = append(pairs, placeholder, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func (m *Mask) placeholder(kind, identifier string) string {
+	if placeholder, ok := m.placeholders[identifier]; ok {
+		return placeholder
+	}
+	m.counts[kind]++
+	placeholder := fmt.Sprintf("[%s_%d]", strings.ToUpper(kind), m.counts[kind])
+	m.placeholders[identifier] = placeholder
+	m.originals[placeholder] = identifier
+	metrics.PIIRedactions.Add(kind, 1)
+	return placeholder
+}
@@ -0,0 +1,147 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+func profileItem(category, importance, content string) models.PersonalInfoResponse {
+	return models.PersonalInfoResponse{Category: category, Importance: importance, Content: content}
+}
+
+func TestProfileInfoSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []models.PersonalInfoResponse
+		want    []string // substrings expected in this order
+		notWant []string
+	}{
+		{
+			name: "all items of a category are included",
+			items: []models.PersonalInfoResponse{
+				profileItem("medical", "high", "혈압약 복용"),
+				profileItem("medical", "high", "당뇨약 복용"),
+				profileItem("medical", "medium", "관절약 복용"),
+			},
+			want:    []string{"의료 정보:", "- 혈압약 복용", "- 당뇨약 복용", "- 관절약 복용"},
+			notWant: []string{"생략"},
+		},
+		{
+			name: "items and categories are ordered by importance",
+			items: []models.PersonalInfoResponse{
+				profileItem("habit", "low", "아침 산책"),
+				profileItem("preference", "medium", "트로트 좋아함"),
+				profileItem("habit", "high", "매일 일기 쓰기"),
+			},
+			want: []string{"습관:", "- 매일 일기 쓰기", "- 아침 산책", "선호도:", "- 트로트 좋아함"},
+		},
+		{
+			name: "unknown importance sorts after low",
+			items: []models.PersonalInfoResponse{
+				profileItem("habit", "", "낮잠"),
+				profileItem("habit", "low", "아침 산책"),
+			},
+			want: []string{"- 아침 산책", "- 낮잠"},
+		},
+		{
+			name: "unknown category is shown as is",
+			items: []models.PersonalInfoResponse{
+				profileItem("hobby", "medium", "화초 가꾸기"),
+			},
+			want: []string{"hobby:", "- 화초 가꾸기"},
+		},
+		{
+			name: "guardrail categories are left to their own sections",
+			items: []models.PersonalInfoResponse{
+				profileItem(util.CategoryCorrection, "high", "정정된 사실"),
+				profileItem(util.CategoryAvoidTopic, "high", "피할 주제"),
+				profileItem(util.CategoryCareMode, "high", "돌봄 모드"),
+				profileItem(util.CategoryPinned, "high", "고정된 사실"),
+				profileItem("preference", "medium", "트로트 좋아함"),
+			},
+			want:    []string{"- 트로트 좋아함"},
+			notWant: []string{"정정된 사실", "피할 주제", "돌봄 모드", "고정된 사실"},
+		},
+		{
+			name: "low importance items are cut past the budget",
+			items: []models.PersonalInfoResponse{
+				profileItem("habit", "low", strings.Repeat("가", 300)),
+				profileItem("medical", "high", strings.Repeat("나", 1000)),
+				profileItem("preference", "low", strings.Repeat("다", 250)),
+			},
+			want:    []string{"의료 정보:", strings.Repeat("나", 1000), "(중요도가 낮은 정보 2개 생략)"},
+			notWant: []string{"습관:", "선호도:"},
+		},
+		{
+			name: "an item over the budget does not crowd out smaller ones",
+			items: []models.PersonalInfoResponse{
+				profileItem("habit", "low", strings.Repeat("가", 300)),
+				profileItem("medical", "high", strings.Repeat("나", 1000)),
+				profileItem("preference", "low", "트로트 좋아함"),
+			},
+			want:    []string{"의료 정보:", "선호도:", "- 트로트 좋아함", "(중요도가 낮은 정보 1개 생략)"},
+			notWant: []string{"습관:"},
+		},
+		{
+			name: "pinned facts count against the budget",
+			items: []models.PersonalInfoResponse{
+				profileItem(util.CategoryPinned, "high", strings.Repeat("가", 1100)),
+				profileItem("medical", "high", "혈압약 복용"),
+				profileItem("habit", "low", strings.Repeat("나", 200)),
+			},
+			want:    []string{"- 혈압약 복용", "(중요도가 낮은 정보 1개 생략)"},
+			notWant: []string{"습관:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			section := ProfileInfoSection(&models.PersonalInfoListResponse{Items: tt.items})
+
+			rest := section
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("section missing %q in order:\n%s", want, section)
+				}
+				rest = rest[i+len(want):]
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(section, notWant) {
+					t.Errorf("section contains %q:\n%s", notWant, section)
+				}
+			}
+		})
+	}
+}
+
+func TestChatSystemPromptViolatedTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		topics  []string
+		want    []string
+		notWant []string
+	}{
+		{name: "first attempt", notWant: []string{"이전 답변이"}},
+		{name: "retry names the violated topics", topics: []string{"남편", "교통사고"}, want: []string{"이전 답변이", "남편, 교통사고"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := ChatSystemPrompt(ChatPromptData{ViolatedTopics: tt.topics})
+			for _, want := range tt.want {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt missing %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(prompt, notWant) {
+					t.Errorf("prompt contains %q", notWant)
+				}
+			}
+		})
+	}
+}
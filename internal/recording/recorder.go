@@ -0,0 +1,177 @@
+package recording
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"llm/internal/pii"
+	"llm/internal/util"
+)
+
+// Record represents one captured request/response pair
+type Record struct {
+	RequestID    string          `json:"request_id"`
+	Method       string          `json:"method"`
+	Path         string          `json:"path"`
+	Query        string          `json:"query,omitempty"`
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	Status       int             `json:"status"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+	LatencyMs    int64           `json:"latency_ms"`
+	RecordedAt   time.Time       `json:"recorded_at"`
+}
+
+// identifierKeys are JSON fields replaced by stable pseudonyms before writing to disk
+var identifierKeys = map[string]bool{
+	"user_id":         true,
+	"session_id":      true,
+	"game_session_id": true,
+}
+
+// scrubber masks every PII kind in recorded string values, whatever the server's redaction settings
+var scrubber = pii.NewScrubber(util.PIIKinds)
+
+// Recorder appends anonymized records to a daily JSONL file
+type Recorder struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewRecorder creates a recorder writing into dir, readable by the server's user only
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	return &Recorder{dir: dir}, nil
+}
+
+// Write anonymizes and appends a record
+func (r *Recorder) Write(record Record) error {
+	record.Query = AnonymizeQuery(record.Query)
+	record.RequestBody = Anonymize(record.RequestBody)
+	record.ResponseBody = Anonymize(record.ResponseBody)
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	path := filepath.Join(r.dir, record.RecordedAt.Format("2006-01-02")+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
+
+// ReadFile loads all records from a JSONL recording file
+func ReadFile(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer file.Close()
+
+	records := []Record{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording file: %w", err)
+	}
+	return records, nil
+}
+
+// Anonymize replaces identifier fields in a JSON body with stable pseudonyms and masks
+// phone numbers, resident registration numbers and addresses in every other string value.
+// Bodies that are not valid JSON are dropped rather than written verbatim.
+func Anonymize(body json.RawMessage) json.RawMessage {
+	if len(body) == 0 {
+		return body
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil
+	}
+
+	anonymized, err := json.Marshal(anonymizeValue(pii.NewMask(), data))
+	if err != nil {
+		return nil
+	}
+	return anonymized
+}
+
+func anonymizeValue(mask *pii.Mask, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if s, ok := field.(string); ok && identifierKeys[key] && s != "" {
+				v[key] = Pseudonym(s)
+				continue
+			}
+			v[key] = anonymizeValue(mask, field)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = anonymizeValue(mask, item)
+		}
+		return v
+	case string:
+		return scrubber.Redact(mask, v)
+	}
+	return value
+}
+
+// AnonymizeQuery replaces identifier parameters in a raw query string with stable pseudonyms
+// and masks PII in the other parameters
+func AnonymizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ""
+	}
+	mask := pii.NewMask()
+	for key, vals := range values {
+		for i, val := range vals {
+			if identifierKeys[key] {
+				vals[i] = Pseudonym(val)
+			} else {
+				vals[i] = scrubber.Redact(mask, val)
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// Pseudonym returns a stable, non-reversible replacement for an identifier
+func Pseudonym(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return "anon_" + hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,87 @@
+package recording
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymize(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "identifiers become pseudonyms",
+			body:    `{"user_id": "user-1", "session_id": "session-1"}`,
+			want:    []string{Pseudonym("user-1"), Pseudonym("session-1")},
+			notWant: []string{"user-1", "session-1"},
+		},
+		{
+			name:    "PII in free text is masked",
+			body:    `{"message": "우리 딸 번호는 010-1234-5678이야", "history": [{"content": "주민번호 800101-1234567"}]}`,
+			want:    []string{"우리 딸 번호는 [PHONE_1]", "주민번호 [RRN_1]"},
+			notWant: []string{"010-1234-5678", "800101-1234567"},
+		},
+		{
+			name: "invalid JSON is dropped",
+			body: `{"message": "010-1234-5678"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(Anonymize(json.RawMessage(tt.body)))
+			if len(tt.want) == 0 && len(tt.notWant) == 0 && got != "" {
+				t.Errorf("Anonymize() = %s, want it dropped", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Anonymize() = %s, missing %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Anonymize() = %s, contains %q", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestAnonymizeQuery(t *testing.T) {
+	got := AnonymizeQuery("user_id=user-1&phone=010-1234-5678")
+	if !strings.Contains(got, Pseudonym("user-1")) || strings.Contains(got, "user-1") || strings.Contains(got, "5678") {
+		t.Errorf("AnonymizeQuery() = %s, want the user ID pseudonymized and the phone number masked", got)
+	}
+}
+
+func TestRecorderWritesPrivateFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recordings")
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	recordedAt := time.Date(2025, time.October, 1, 9, 0, 0, 0, time.UTC)
+	if err := recorder.Write(Record{RequestID: "req-1", RecordedAt: recordedAt}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	for path, want := range map[string]os.FileMode{
+		dir:                                    0o700,
+		filepath.Join(dir, "2025-10-01.jsonl"): 0o600,
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat(%s) error = %v", path, err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %v, want %v", path, got, want)
+		}
+	}
+}
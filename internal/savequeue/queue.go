@@ -0,0 +1,337 @@
+// Package savequeue delivers RAG conversation saves in the background without
+// losing them when the RAG server is down. Every save is written to disk
+// before it is attempted, retried with exponential backoff, and moved to a
+// dead-letter directory once it runs out of attempts. Pending saves left on
+// disk by a restart are delivered when the queue starts; dead letters are kept
+// for inspection and are requeued by moving their files back to pending/.
+//
+// Queue wraps a client.RAGStore, so services keep calling SaveConversation and
+// only the save itself becomes asynchronous.
+package savequeue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/client"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// Job is a queued conversation save. The user and request IDs are kept so the
+// save is routed and traced like the request that produced it.
+type Job struct {
+	ID         string                             `json:"id"`
+	UserID     string                             `json:"user_id,omitempty"`
+	RequestID  string                             `json:"request_id,omitempty"`
+	Request    *models.RAGConversationSaveRequest `json:"request"`
+	Attempts   int                                `json:"attempts"`
+	LastError  string                             `json:"last_error,omitempty"`
+	EnqueuedAt time.Time                          `json:"enqueued_at"`
+
+	cancelled bool               // the user's data was erased while the save was queued
+	cancel    context.CancelFunc // aborts the delivery, set once the worker takes the job
+	done      chan struct{}      // closed when the worker is finished with the job
+}
+
+// Queue is a RAGStore whose conversation saves go through a disk-backed
+// write-behind queue, delivered in order by a single worker. Other calls pass
+// straight through to the wrapped store.
+type Queue struct {
+	client.RAGStore
+	pendingDir     string
+	deadDir        string
+	maxAttempts    int
+	initialBackoff time.Duration
+	jobs           []*Job
+	wake           chan struct{}
+	mutex          sync.Mutex
+	logger         *util.Logger
+}
+
+var _ client.RAGStore = (*Queue)(nil)
+
+// New creates a queue storing its files under dir, loads the saves a previous
+// run left pending, and starts the worker
+func New(store client.RAGStore, dir string, maxAttempts int, initialBackoff time.Duration) (*Queue, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	q := &Queue{
+		RAGStore:       store,
+		pendingDir:     filepath.Join(dir, "pending"),
+		deadDir:        filepath.Join(dir, "dead"),
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		wake:           make(chan struct{}, 1),
+		logger:         util.NewLogger("SaveQueue"),
+	}
+
+	for _, d := range []string{q.pendingDir, q.deadDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create save queue directory: %w", err)
+		}
+	}
+
+	jobs, err := q.loadPending()
+	if err != nil {
+		return nil, err
+	}
+	q.jobs = jobs
+	metrics.SaveQueueDepth.Set(int64(len(jobs)))
+	if len(jobs) > 0 {
+		q.logger.Info("Recovered %d pending saves", len(jobs))
+	}
+
+	go q.worker()
+	return q, nil
+}
+
+// SaveConversation queues the save and returns the conversation ID once it is
+// on disk. It fails only when the metadata is invalid or the save cannot be written.
+func (q *Queue) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	// Invalid metadata would fail every delivery attempt
+	if err := client.ValidateMetadata(req.Metadata); err != nil {
+		return "", err
+	}
+
+	// A save without a user could neither be routed nor erased with the user's data
+	userID := util.UserIDFromContext(ctx)
+	if userID == "" {
+		return "", fmt.Errorf("failed to queue save: no user for conversation %s", req.ConversationID)
+	}
+
+	job := &Job{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		RequestID:  util.RequestIDFromContext(ctx),
+		Request:    req,
+		EnqueuedAt: time.Now(),
+	}
+
+	if err := writeJob(q.pendingDir, job); err != nil {
+		return "", err
+	}
+
+	q.mutex.Lock()
+	q.jobs = append(q.jobs, job)
+	metrics.SaveQueueDepth.Set(int64(len(q.jobs)))
+	q.mutex.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return req.ConversationID, nil
+}
+
+// DeleteUserData drops the user's queued saves and dead letters, so nothing is
+// written back after the erasure, then erases the user on the wrapped store.
+// A save of the user's already being delivered is aborted and waited for first.
+func (q *Queue) DeleteUserData(ctx context.Context, userID string) error {
+	logger := q.logger.WithContext(ctx)
+
+	q.mutex.Lock()
+	var inFlight *Job
+	kept := make([]*Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		if job.UserID != userID {
+			kept = append(kept, job)
+			continue
+		}
+		job.cancelled = true
+		// The worker owns the job it has taken and removes it itself
+		if job.done != nil {
+			inFlight = job
+			kept = append(kept, job)
+			continue
+		}
+		removeJob(logger, q.pendingDir, job.ID)
+	}
+	q.jobs = kept
+	metrics.SaveQueueDepth.Set(int64(len(q.jobs)))
+	q.mutex.Unlock()
+
+	if inFlight != nil {
+		inFlight.cancel()
+		select {
+		case <-inFlight.done:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for in-flight save: %w", ctx.Err())
+		}
+	}
+
+	entries, err := os.ReadDir(q.deadDir)
+	if err != nil {
+		return fmt.Errorf("failed to read dead letters: %w", err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(q.deadDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var job Job
+		if json.Unmarshal(data, &job) == nil && job.UserID == userID {
+			removeJob(logger, q.deadDir, job.ID)
+		}
+	}
+
+	return q.RAGStore.DeleteUserData(ctx, userID)
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (q *Queue) worker() {
+	for {
+		q.mutex.Lock()
+		var job *Job
+		var ctx context.Context
+		if len(q.jobs) > 0 {
+			job = q.jobs[0]
+			ctx, job.cancel = context.WithCancel(context.Background())
+			job.done = make(chan struct{})
+		}
+		q.mutex.Unlock()
+
+		if job == nil {
+			<-q.wake
+			continue
+		}
+
+		q.deliver(ctx, job)
+		job.cancel()
+
+		q.mutex.Lock()
+		q.jobs = q.jobs[1:]
+		metrics.SaveQueueDepth.Set(int64(len(q.jobs)))
+		close(job.done)
+		q.mutex.Unlock()
+	}
+}
+
+// deliver saves the job until it succeeds or runs out of attempts, then removes
+// it from pending, moving it to the dead letters on failure. Cancelling ctx
+// aborts the delivery.
+func (q *Queue) deliver(ctx context.Context, job *Job) {
+	ctx = util.WithRequestID(util.WithUserID(ctx, job.UserID), job.RequestID)
+	logger := q.logger.WithContext(ctx)
+
+	backoff := q.initialBackoff
+	for job.Attempts < q.maxAttempts {
+		q.mutex.Lock()
+		cancelled := job.cancelled
+		q.mutex.Unlock()
+		if cancelled {
+			removeJob(logger, q.pendingDir, job.ID)
+			return
+		}
+
+		job.Attempts++
+		_, err := q.RAGStore.SaveConversation(ctx, job.Request)
+		if err == nil {
+			metrics.SaveQueueDelivered.Add(1)
+			removeJob(logger, q.pendingDir, job.ID)
+			return
+		}
+		// Only erasing the user's data aborts a delivery
+		if ctx.Err() != nil {
+			removeJob(logger, q.pendingDir, job.ID)
+			return
+		}
+
+		job.LastError = err.Error()
+		metrics.SaveQueueRetries.Add(1)
+		logger.Warn(fmt.Sprintf("Failed to save conversation %s (attempt %d/%d)", job.Request.ConversationID, job.Attempts, q.maxAttempts), err)
+
+		// The attempt count survives a restart
+		if err := writeJob(q.pendingDir, job); err != nil {
+			logger.Warn("Failed to update pending save", err)
+		}
+		if job.Attempts < q.maxAttempts {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+			}
+			backoff *= 2
+		}
+	}
+
+	metrics.SaveQueueDeadLetters.Add(1)
+	logger.Error("Moving save to dead letters", fmt.Errorf("conversation %s: %s", job.Request.ConversationID, job.LastError))
+	if err := os.Rename(jobPath(q.pendingDir, job.ID), jobPath(q.deadDir, job.ID)); err != nil {
+		logger.Warn("Failed to move save to dead letters", err)
+	}
+}
+
+// loadPending reads the pending saves on disk, oldest first
+func (q *Queue) loadPending() ([]*Job, error) {
+	entries, err := os.ReadDir(q.pendingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save queue directory: %w", err)
+	}
+
+	jobs := []*Job{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(q.pendingDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pending save: %w", err)
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil || job.Request == nil {
+			q.logger.Warn("Skipping unreadable pending save "+entry.Name(), err)
+			continue
+		}
+		// Requeued dead letters start over
+		if job.Attempts >= q.maxAttempts {
+			job.Attempts = 0
+		}
+		jobs = append(jobs, &job)
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].EnqueuedAt.Before(jobs[j].EnqueuedAt)
+	})
+	return jobs, nil
+}
+
+// writeJob writes the job atomically, so a crash never leaves a partial file
+func writeJob(dir string, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal save: %w", err)
+	}
+
+	path := jobPath(dir, job.ID)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write save: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write save: %w", err)
+	}
+	return nil
+}
+
+func removeJob(logger *util.Logger, dir, id string) {
+	if err := os.Remove(jobPath(dir, id)); err != nil && !os.IsNotExist(err) {
+		logger.Warn("Failed to remove queued save", err)
+	}
+}
+
+func jobPath(dir, id string) string {
+	return filepath.Join(dir, id+".json")
+}
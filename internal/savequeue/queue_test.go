@@ -0,0 +1,76 @@
+package savequeue
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"llm/internal/client/ragtest"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// blockingStore holds every save until its context is cancelled, then saves it anyway,
+// like a RAG server that received the request before the client gave up
+type blockingStore struct {
+	*ragtest.Fake
+	started chan struct{}
+}
+
+func (s *blockingStore) SaveConversation(ctx context.Context, req *models.RAGConversationSaveRequest) (string, error) {
+	s.started <- struct{}{}
+	<-ctx.Done()
+	s.Fake.SaveConversation(context.WithoutCancel(ctx), req)
+	return "", ctx.Err()
+}
+
+func TestDeleteUserDataWaitsForInFlightSave(t *testing.T) {
+	store := &blockingStore{Fake: ragtest.NewFake(), started: make(chan struct{}, 1)}
+	dir := t.TempDir()
+	q, err := New(store, dir, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := util.WithUserID(context.Background(), "user-1")
+
+	if _, err := q.SaveConversation(ctx, &models.RAGConversationSaveRequest{
+		ConversationID: "conv-1",
+		Messages:       []models.RAGMessage{{Role: "user", Content: "손주가 왔어"}},
+	}); err != nil {
+		t.Fatalf("SaveConversation() error = %v", err)
+	}
+	<-store.started
+
+	if err := q.DeleteUserData(ctx, "user-1"); err != nil {
+		t.Fatalf("DeleteUserData() error = %v", err)
+	}
+
+	results, _ := store.ListConversationsByUser(ctx, "user-1")
+	if len(results) != 0 {
+		t.Errorf("store kept %d conversations after the erasure, want 0", len(results))
+	}
+	for _, sub := range []string{"pending", "dead"} {
+		entries, _ := os.ReadDir(filepath.Join(dir, sub))
+		if len(entries) != 0 {
+			t.Errorf("%s holds %d saves after the erasure, want 0", sub, len(entries))
+		}
+	}
+}
+
+func TestSaveConversationRequiresUser(t *testing.T) {
+	q, err := New(ragtest.NewFake(), t.TempDir(), 1, time.Millisecond)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// The session ID is no stand-in for the user the save belongs to
+	if _, err := q.SaveConversation(context.Background(), &models.RAGConversationSaveRequest{
+		ConversationID: "conv-1",
+		Metadata:       &models.RAGMetadata{SessionID: "user-1", Type: util.ConversationTypeChat},
+	}); err == nil || !strings.Contains(err.Error(), "no user") {
+		t.Errorf("SaveConversation() error = %v without a user, want one naming the missing user", err)
+	}
+}
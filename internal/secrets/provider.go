@@ -0,0 +1,260 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"llm/internal/config"
+)
+
+// requestTimeout bounds a single call to Vault or AWS Secrets Manager
+const requestTimeout = 10 * time.Second
+
+// provider reads secrets from the backends a reference can name
+type provider struct {
+	httpClient *http.Client
+
+	vaultAddr      string
+	vaultToken     string
+	vaultNamespace string
+
+	awsRegion          string
+	awsAccessKeyID     string
+	awsSecretAccessKey string
+	awsSessionToken    string
+	awsEndpoint        string
+}
+
+func newProvider(cfg *config.Config) *provider {
+	endpoint := cfg.AWSSecretsManagerEndpoint
+	if endpoint == "" && cfg.AWSRegion != "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.AWSRegion)
+	}
+
+	return &provider{
+		httpClient:         &http.Client{Timeout: requestTimeout},
+		vaultAddr:          strings.TrimRight(cfg.VaultAddr, "/"),
+		vaultToken:         cfg.VaultToken,
+		vaultNamespace:     cfg.VaultNamespace,
+		awsRegion:          cfg.AWSRegion,
+		awsAccessKeyID:     cfg.AWSAccessKeyID,
+		awsSecretAccessKey: cfg.AWSSecretAccessKey,
+		awsSessionToken:    cfg.AWSSessionToken,
+		awsEndpoint:        strings.TrimRight(endpoint, "/"),
+	}
+}
+
+// resolve reads the secret a reference names. Surrounding whitespace, such as
+// the trailing newline of a mounted file, is removed; an empty secret is an error.
+func (p *provider) resolve(ctx context.Context, ref string) (string, error) {
+	var value string
+	var err error
+	switch {
+	case strings.HasPrefix(ref, SchemeFile):
+		value, err = p.readFile(strings.TrimPrefix(ref, SchemeFile))
+	case strings.HasPrefix(ref, SchemeVault):
+		path, field := splitField(strings.TrimPrefix(ref, SchemeVault))
+		value, err = p.readVault(ctx, path, field)
+	case strings.HasPrefix(ref, SchemeAWSSM):
+		secretID, field := splitField(strings.TrimPrefix(ref, SchemeAWSSM))
+		value, err = p.readAWS(ctx, secretID, field)
+	default:
+		return "", fmt.Errorf("unsupported secret reference")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("secret from %s is empty", scheme(ref))
+	}
+	return value, nil
+}
+
+// splitField splits "location#field" into its parts
+func splitField(ref string) (string, string) {
+	location, field, _ := strings.Cut(ref, "#")
+	return location, field
+}
+
+func (p *provider) readFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return string(data), nil
+}
+
+// readVault reads a field of a Vault KV secret, e.g. vault://secret/data/llm#openai_api_key.
+// Both KV versions are understood; the field defaults to "value".
+func (p *provider) readVault(ctx context.Context, path, field string) (string, error) {
+	if p.vaultAddr == "" || p.vaultToken == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set to read secrets from Vault")
+	}
+	if field == "" {
+		field = "value"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.vaultAddr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.vaultToken)
+	if p.vaultNamespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.vaultNamespace)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := p.do(req, "Vault", &body); err != nil {
+		return "", err
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nested
+		}
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault secret has no string field %q", field)
+	}
+	return value, nil
+}
+
+// readAWS reads a secret from AWS Secrets Manager, e.g. aws-sm://llm/prod#openai_api_key.
+// With a field the secret string is a JSON object holding the key under it.
+func (p *provider) readAWS(ctx context.Context, secretID, field string) (string, error) {
+	if p.awsEndpoint == "" || p.awsAccessKeyID == "" || p.awsSecretAccessKey == "" {
+		return "", fmt.Errorf("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to read secrets from AWS Secrets Manager")
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal AWS request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.awsEndpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	p.signAWS(req, payload, time.Now().UTC())
+
+	var body struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := p.do(req, "AWS Secrets Manager", &body); err != nil {
+		return "", err
+	}
+	if field == "" {
+		return body.SecretString, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(body.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("AWS secret is not a JSON object, cannot read field %q", field)
+	}
+	value, ok := fields[field].(string)
+	if !ok {
+		return "", fmt.Errorf("AWS secret has no string field %q", field)
+	}
+	return value, nil
+}
+
+// signAWS adds a Signature Version 4 Authorization header for Secrets Manager
+func (p *provider) signAWS(req *http.Request, payload []byte, now time.Time) {
+	const service = "secretsmanager"
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.awsSessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.awsSessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, p.awsRegion, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.awsSecretAccessKey), date)
+	key = hmacSHA256(key, p.awsRegion)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.awsAccessKeyID, scope, signedHeaders, signature))
+}
+
+// do sends a request to a secret backend and decodes its JSON response
+func (p *provider) do(req *http.Request, backend string, out interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", backend, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Error bodies name the secret and the reason but never its value
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned status %d: %s", backend, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", backend, err)
+	}
+	return nil
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,179 @@
+// Package secrets resolves the API keys the server needs. A key setting holds
+// either the key itself or a reference to where it is kept: a mounted file,
+// a Vault KV secret or an AWS Secrets Manager secret. Referenced keys are
+// re-read periodically so a rotated key is picked up without a restart.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/util"
+)
+
+// Names of the secrets the server reads, the same as their settings
+const (
+	OpenAIAPIKey   = "OPENAI_API_KEY"
+	AdminAPIKey    = "ADMIN_API_KEY"
+	InternalAPIKey = "INTERNAL_API_KEY"
+)
+
+// Reference schemes
+const (
+	SchemeFile  = "file:"
+	SchemeVault = "vault://"
+	SchemeAWSSM = "aws-sm://"
+)
+
+// refreshTimeout bounds one background refresh of every referenced secret
+const refreshTimeout = 30 * time.Second
+
+// Secret is a key that may change while the server runs
+type Secret struct {
+	ref      string // empty when the setting held the key itself
+	value    atomic.Pointer[string]
+	mu       sync.Mutex
+	onChange []func(string)
+}
+
+// Value returns the current key
+func (s *Secret) Value() string {
+	return *s.value.Load()
+}
+
+// OnChange registers fn to be called with the new key whenever it rotates
+func (s *Secret) OnChange(fn func(string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// set stores value and reports whether it differs from the previous one
+func (s *Secret) set(value string) bool {
+	if previous := s.value.Load(); previous != nil && *previous == value {
+		return false
+	}
+	s.value.Store(&value)
+	return true
+}
+
+// Manager holds the server's secrets and keeps referenced ones up to date
+type Manager struct {
+	secrets  map[string]*Secret
+	provider *provider
+	logger   *util.Logger
+}
+
+// NewManager resolves every secret setting and fails when a reference cannot be
+// read, so the server never starts with a missing key. The resolved keys replace
+// the references in cfg, so services built afterwards start with them. When
+// SECRETS_REFRESH_INTERVAL is set, referenced secrets are re-read in the
+// background and their OnChange callbacks run when one rotates.
+func NewManager(ctx context.Context, cfg *config.Config) (*Manager, error) {
+	m := &Manager{
+		secrets:  make(map[string]*Secret),
+		provider: newProvider(cfg),
+		logger:   util.NewLogger("SecretManager"),
+	}
+
+	settings := map[string]*string{
+		OpenAIAPIKey:   &cfg.OpenAIAPIKey,
+		AdminAPIKey:    &cfg.AdminAPIKey,
+		InternalAPIKey: &cfg.InternalAPIKey,
+	}
+	referenced := false
+	for name, setting := range settings {
+		secret := &Secret{}
+		if isReference(*setting) {
+			secret.ref = *setting
+			referenced = true
+		}
+
+		value := *setting
+		if secret.ref != "" {
+			resolved, err := m.provider.resolve(ctx, secret.ref)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			value = resolved
+			m.logger.Info("Loaded %s from %s", name, scheme(secret.ref))
+		}
+
+		secret.set(value)
+		*setting = value
+		m.secrets[name] = secret
+	}
+
+	if referenced && cfg.SecretsRefreshInterval > 0 {
+		go m.refreshRoutine(cfg.SecretsRefreshInterval)
+	}
+	return m, nil
+}
+
+// Secret returns the named secret, see the name constants
+func (m *Manager) Secret(name string) *Secret {
+	return m.secrets[name]
+}
+
+// Refresh re-reads every referenced secret and runs the OnChange callbacks of
+// those that rotated. A secret that cannot be read keeps its current value.
+func (m *Manager) Refresh(ctx context.Context) {
+	for name, secret := range m.secrets {
+		if secret.ref == "" {
+			continue
+		}
+
+		value, err := m.provider.resolve(ctx, secret.ref)
+		if err != nil {
+			metrics.SecretRefreshFailures.Add(name, 1)
+			m.logger.Warn(fmt.Sprintf("Failed to refresh %s, keeping the current value", name), err)
+			continue
+		}
+		if !secret.set(value) {
+			continue
+		}
+
+		metrics.SecretRotations.Add(name, 1)
+		m.logger.Info("%s rotated", name)
+		secret.mu.Lock()
+		callbacks := append([]func(string){}, secret.onChange...)
+		secret.mu.Unlock()
+		for _, fn := range callbacks {
+			fn(value)
+		}
+	}
+}
+
+func (m *Manager) refreshRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		m.Refresh(ctx)
+		cancel()
+	}
+}
+
+// isReference reports whether a setting names where a secret is kept rather than holding it
+func isReference(value string) bool {
+	return strings.HasPrefix(value, SchemeFile) || strings.HasPrefix(value, SchemeVault) || strings.HasPrefix(value, SchemeAWSSM)
+}
+
+// scheme names the backend of a reference for logging, without the secret's location
+func scheme(ref string) string {
+	switch {
+	case strings.HasPrefix(ref, SchemeVault):
+		return "vault"
+	case strings.HasPrefix(ref, SchemeAWSSM):
+		return "aws secrets manager"
+	default:
+		return "file"
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// AdminService exposes and updates runtime-tunable settings
+type AdminService struct {
+	runtime *config.Runtime
+	logger  *util.Logger
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(cfg *config.Config) *AdminService {
+	return &AdminService{
+		runtime: cfg.Runtime,
+		logger:  util.NewLogger("AdminService"),
+	}
+}
+
+// GetRuntimeConfig returns the runtime settings currently in effect
+func (ad *AdminService) GetRuntimeConfig() *models.RuntimeConfigResponse {
+	return toRuntimeConfigResponse(ad.runtime.Get())
+}
+
+// UpdateRuntimeConfig applies a partial update to the runtime settings.
+// The new snapshot is swapped in atomically, so in-flight requests keep the values they started with.
+func (ad *AdminService) UpdateRuntimeConfig(ctx context.Context, req *models.RuntimeConfigPatchRequest) (*models.RuntimeConfigResponse, error) {
+	logger := ad.logger.WithContext(ctx)
+
+	logger.Start("Update Runtime Config")
+
+	current := ad.runtime.Get()
+	high, medium := current.ConfidenceHighThreshold, current.ConfidenceMediumThreshold
+	if req.ConfidenceHighThreshold != nil {
+		high = *req.ConfidenceHighThreshold
+	}
+	if req.ConfidenceMediumThreshold != nil {
+		medium = *req.ConfidenceMediumThreshold
+	}
+	if medium > high {
+		err := fmt.Errorf("invalid_config: confidence_medium_threshold %.2f exceeds confidence_high_threshold %.2f", medium, high)
+		logger.Error("Rejected runtime config", err)
+		logger.End("Update Runtime Config")
+		return nil, err
+	}
+
+	updated := ad.runtime.Update(func(s *config.RuntimeSettings) {
+		if req.OpenAIModel != nil {
+			s.OpenAIModel = *req.OpenAIModel
+		}
+		if req.OpenAITemperature != nil {
+			s.OpenAITemperature = *req.OpenAITemperature
+		}
+		if req.OpenAIMaxTokens != nil {
+			s.OpenAIMaxTokens = *req.OpenAIMaxTokens
+		}
+		if req.ContextMinScore != nil {
+			s.ContextMinScore = *req.ContextMinScore
+		}
+		if req.ContextMaxMessagesPerConversation != nil {
+			s.ContextMaxMessagesPerConversation = *req.ContextMaxMessagesPerConversation
+		}
+		if req.ContextRerankEnabled != nil {
+			s.ContextRerankEnabled = *req.ContextRerankEnabled
+		}
+		if req.ContextSummaryEnabled != nil {
+			s.ContextSummaryEnabled = *req.ContextSummaryEnabled
+		}
+		if req.ContextQueryStrategy != nil {
+			s.ContextQueryStrategy = *req.ContextQueryStrategy
+		}
+		if req.QuestionCacheTTLSeconds != nil {
+			s.QuestionCacheTTL = time.Duration(*req.QuestionCacheTTLSeconds) * time.Second
+		}
+		if req.ConfidenceHighThreshold != nil {
+			s.ConfidenceHighThreshold = *req.ConfidenceHighThreshold
+		}
+		if req.ConfidenceMediumThreshold != nil {
+			s.ConfidenceMediumThreshold = *req.ConfidenceMediumThreshold
+		}
+		if req.LogSampleRate != nil {
+			s.LogSampleRate = *req.LogSampleRate
+		}
+		if req.LogDebugUsers != nil {
+			s.LogDebugUsers = *req.LogDebugUsers
+		}
+	})
+	util.ConfigureLogSampling(updated.LogSampleRate, updated.LogDebugUsers, updated.LogLevel == "debug")
+
+	logRuntimeSettings(logger, updated)
+
+	logger.Success("Runtime config updated")
+	logger.End("Update Runtime Config")
+
+	return toRuntimeConfigResponse(updated), nil
+}
+
+// ReloadConfig re-reads the environment and config file, as on SIGHUP, and swaps the
+// resulting runtime settings in. In-flight requests keep the snapshot they started with.
+func (ad *AdminService) ReloadConfig(ctx context.Context) (*models.RuntimeConfigResponse, error) {
+	logger := ad.logger.WithContext(ctx)
+
+	logger.Start("Reload Config")
+
+	updated, err := ad.runtime.Reload()
+	if err != nil {
+		err = fmt.Errorf("invalid_config: %v", err)
+		logger.Error("Rejected config reload, keeping current settings", err)
+		logger.End("Reload Config")
+		return nil, err
+	}
+	util.ConfigureLogSampling(updated.LogSampleRate, updated.LogDebugUsers, updated.LogLevel == "debug")
+
+	logRuntimeSettings(logger, updated)
+
+	logger.Success("Config reloaded")
+	logger.End("Reload Config")
+
+	return toRuntimeConfigResponse(updated), nil
+}
+
+// logRuntimeSettings logs the settings now in effect
+func logRuntimeSettings(logger *util.Logger, updated config.RuntimeSettings) {
+	logger.Section("Runtime Settings")
+	logger.KeyValue(
+		"Model", updated.OpenAIModel,
+		"Temperature", updated.OpenAITemperature,
+		"Max Tokens", updated.OpenAIMaxTokens,
+		"Context Min Score", updated.ContextMinScore,
+		"Context Max Messages", updated.ContextMaxMessagesPerConversation,
+		"Context Query Strategy", updated.ContextQueryStrategy,
+		"Question Cache TTL", updated.QuestionCacheTTL,
+		"Confidence Thresholds", fmt.Sprintf("%.2f/%.2f", updated.ConfidenceHighThreshold, updated.ConfidenceMediumThreshold),
+		"Evaluation Weights", updated.MemoryEvaluationWeights,
+		"Log Level", updated.LogLevel,
+		"Log Sample Rate", updated.LogSampleRate,
+		"Log Debug Users", len(updated.LogDebugUsers),
+	)
+}
+
+// GetSafeMode returns the state of every safe-mode kill switch
+func (ad *AdminService) GetSafeMode() *models.SafeModeResponse {
+	return toSafeModeResponse(ad.runtime.Get())
+}
+
+// UpdateSafeMode turns subsystems off or back on. Requests already past the feature check
+// finish normally; later ones fail with a feature_disabled error.
+func (ad *AdminService) UpdateSafeMode(ctx context.Context, req *models.SafeModePatchRequest) (*models.SafeModeResponse, error) {
+	logger := ad.logger.WithContext(ctx)
+
+	logger.Start("Update Safe Mode")
+
+	for feature := range req.Features {
+		if !config.IsSafeModeFeature(feature) {
+			err := fmt.Errorf("invalid_feature: %q, must be one of %s", feature, strings.Join(config.SafeModeFeatures, ", "))
+			logger.Error("Rejected safe mode update", err)
+			logger.End("Update Safe Mode")
+			return nil, err
+		}
+	}
+
+	updated := ad.runtime.Update(func(s *config.RuntimeSettings) {
+		disabled := []string{}
+		for _, feature := range config.SafeModeFeatures {
+			enabled, changed := req.Features[feature]
+			if (changed && !enabled) || (!changed && s.FeatureDisabled(feature)) {
+				disabled = append(disabled, feature)
+			}
+		}
+		s.DisabledFeatures = disabled
+	})
+
+	logger.Section("Safe Mode")
+	for feature, enabled := range req.Features {
+		if enabled {
+			logger.Info("Feature %s enabled", feature)
+		} else {
+			logger.Info("Feature %s disabled", feature)
+		}
+	}
+
+	logger.Success("Safe mode updated")
+	logger.End("Update Safe Mode")
+
+	return toSafeModeResponse(updated), nil
+}
+
+func toSafeModeResponse(s config.RuntimeSettings) *models.SafeModeResponse {
+	features := make([]models.FeatureSwitch, len(config.SafeModeFeatures))
+	for i, feature := range config.SafeModeFeatures {
+		features[i] = models.FeatureSwitch{Feature: feature, Enabled: !s.FeatureDisabled(feature)}
+	}
+	return &models.SafeModeResponse{Features: features}
+}
+
+// checkFeature returns a feature_disabled error when a safe-mode kill switch has turned feature off
+func checkFeature(cfg *config.Config, feature string) error {
+	if cfg.Runtime.Get().FeatureDisabled(feature) {
+		return fmt.Errorf("feature_disabled: %s is switched off in safe mode", feature)
+	}
+	return nil
+}
+
+func toRuntimeConfigResponse(s config.RuntimeSettings) *models.RuntimeConfigResponse {
+	return &models.RuntimeConfigResponse{
+		OpenAIModel:                       s.OpenAIModel,
+		OpenAITemperature:                 s.OpenAITemperature,
+		OpenAIMaxTokens:                   s.OpenAIMaxTokens,
+		ContextMinScore:                   s.ContextMinScore,
+		ContextMaxMessagesPerConversation: s.ContextMaxMessagesPerConversation,
+		ContextRerankEnabled:              s.ContextRerankEnabled,
+		ContextSummaryEnabled:             s.ContextSummaryEnabled,
+		ContextQueryStrategy:              s.ContextQueryStrategy,
+		QuestionCacheTTLSeconds:           int(s.QuestionCacheTTL / time.Second),
+		ConfidenceHighThreshold:           s.ConfidenceHighThreshold,
+		ConfidenceMediumThreshold:         s.ConfidenceMediumThreshold,
+		MemoryEvaluationWeights:           s.MemoryEvaluationWeights[:],
+		LogLevel:                          s.LogLevel,
+		LogSampleRate:                     s.LogSampleRate,
+		LogDebugUsers:                     s.LogDebugUsers,
+	}
+}
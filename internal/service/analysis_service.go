@@ -3,32 +3,57 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"llm/internal/client"
+	"llm/internal/config"
 	"llm/internal/models"
+	"llm/internal/prompts"
 	"llm/internal/util"
+	"llm/internal/webhook"
 )
 
 // AnalysisService handles domain analysis and report generation
 type AnalysisService struct {
-	ragClient     *client.RAGClient
-	openaiService *OpenAIService
-	logger        *util.Logger
+	cfg                *config.Config
+	ragClient          client.RAGStore
+	openaiService      LLMService
+	quizHistoryService *QuizHistoryService
+	assessmentService  *AssessmentService
+	webhookService     *WebhookService
+	reports            map[string][]models.AnalysisResponse // user ID -> recent reports, oldest first
+	reportsMutex       sync.RWMutex
+	logger             *util.Logger
 }
 
 // NewAnalysisService creates a new analysis service
-func NewAnalysisService(ragClient *client.RAGClient, openaiService *OpenAIService) *AnalysisService {
+func NewAnalysisService(cfg *config.Config, ragClient client.RAGStore, openaiService LLMService, quizHistoryService *QuizHistoryService, assessmentService *AssessmentService, webhookService *WebhookService) *AnalysisService {
 	return &AnalysisService{
-		ragClient:     ragClient,
-		openaiService: openaiService,
-		logger:        util.NewLogger("AnalysisService"),
+		cfg:                cfg,
+		ragClient:          ragClient,
+		openaiService:      openaiService,
+		quizHistoryService: quizHistoryService,
+		assessmentService:  assessmentService,
+		webhookService:     webhookService,
+		reports:            make(map[string][]models.AnalysisResponse),
+		logger:             util.NewLogger("AnalysisService"),
 	}
 }
 
 // ProcessAnalysisRequest processes a domain analysis request
 func (as *AnalysisService) ProcessAnalysisRequest(ctx context.Context, req *models.AnalysisRequest) (*models.AnalysisResponse, error) {
-	as.logger.Start("Process Analysis Request")
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := as.logger.WithContext(ctx)
+
+	logger.Start("Process Analysis Request")
+
+	if err := checkFeature(as.cfg, util.FeatureAnalysis); err != nil {
+		logger.Error("Feature disabled", err)
+		logger.End("Process Analysis Request")
+		return nil, err
+	}
 
 	// Fetch user's conversation history and incorrect quiz attempts in parallel
 	conversationChan := make(chan []string, 1)
@@ -38,7 +63,7 @@ func (as *AnalysisService) ProcessAnalysisRequest(ctx context.Context, req *mode
 	go func() {
 		conversations, err := as.fetchConversationHistory(ctx, req.UserID)
 		if err != nil {
-			as.logger.Warn("Failed to fetch conversations", err)
+			logger.Warn("Failed to fetch conversations", err)
 			conversationChan <- []string{}
 		} else {
 			conversationChan <- conversations
@@ -49,57 +74,216 @@ func (as *AnalysisService) ProcessAnalysisRequest(ctx context.Context, req *mode
 	go func() {
 		quizzes, err := as.fetchIncorrectQuizzes(ctx, req.UserID)
 		if err != nil {
-			as.logger.Warn("Failed to fetch incorrect quizzes", err)
+			logger.Warn("Failed to fetch incorrect quizzes", err)
 			incorrectQuizzesChan <- []string{}
 		} else {
 			incorrectQuizzesChan <- quizzes
 		}
 	}()
 
-	conversationHistory := <-conversationChan
+	conversationHistory := as.condenseConversations(ctx, <-conversationChan)
 	incorrectQuizzes := <-incorrectQuizzesChan
 
-	as.logger.Section("Fetched Data")
-	as.logger.KeyValue("Conversations", len(conversationHistory), "Incorrect Quizzes", len(incorrectQuizzes))
+	logger.Section("Fetched Data")
+	logger.KeyValue("Conversations", len(conversationHistory), "Incorrect Quizzes", len(incorrectQuizzes))
 
 	// Step 1: Analyze domains
-	as.logger.Section("Step 1: Analyzing Domains")
+	logger.Section("Step 1: Analyzing Domains")
 	domains, err := as.openaiService.AnalyzeDomains(ctx, conversationHistory, incorrectQuizzes)
 	if err != nil {
-		as.logger.Error("Failed to analyze domains", err)
-		as.logger.End("Process Analysis Request")
+		logger.Error("Failed to analyze domains", err)
+		logger.End("Process Analysis Request")
 		return nil, fmt.Errorf("failed to analyze domains: %w", err)
 	}
 
+	// Long-term memory consolidation from repeated quiz history
+	consolidation := as.quizHistoryService.Consolidation(req.UserID)
+	if consolidation != nil {
+		logger.KeyValue("Consolidation Score", consolidation.Score, "Tracked Facts", consolidation.TrackedFacts)
+	}
+
 	// Step 2: Generate professional report
-	as.logger.Section("Step 2: Generating Professional Report")
-	report, err := as.openaiService.GenerateAnalysisReport(ctx, domains)
+	logger.Section("Step 2: Generating Professional Report")
+	report, err := as.openaiService.GenerateAnalysisReport(ctx, domains, consolidation)
 	if err != nil {
-		as.logger.Error("Failed to generate report", err)
-		as.logger.End("Process Analysis Request")
+		logger.Error("Failed to generate report", err)
+		logger.End("Process Analysis Request")
 		return nil, fmt.Errorf("failed to generate report: %w", err)
 	}
 
-	as.logger.Success("Analysis completed successfully")
-	as.logger.End("Process Analysis Request")
+	analyzedAt := time.Now()
+	event := models.AnalysisCompletedEvent{Domains: domains, AnalyzedAt: analyzedAt}
+	if consolidation != nil {
+		event.ConsolidationScore = &consolidation.Score
+	}
+	as.webhookService.Publish(ctx, webhook.EventAnalysisCompleted, req.UserID, event)
+
+	response := &models.AnalysisResponse{
+		UserID:        req.UserID,
+		Domains:       domains,
+		Consolidation: consolidation,
+		Report:        report,
+		AnalyzedAt:    analyzedAt,
+	}
+	as.keepReport(response)
 
-	return &models.AnalysisResponse{
-		UserID:     req.UserID,
-		Domains:    domains,
-		Report:     report,
-		AnalyzedAt: time.Now(),
-	}, nil
+	logger.Success("Analysis completed successfully")
+	logger.End("Process Analysis Request")
+
+	return response, nil
+}
+
+// Reports returns the user's most recent analysis reports, oldest first
+func (as *AnalysisService) Reports(userID string) []models.AnalysisResponse {
+	as.reportsMutex.RLock()
+	defer as.reportsMutex.RUnlock()
+
+	return append([]models.AnalysisResponse{}, as.reports[userID]...)
+}
+
+// Timeline gathers the user's quiz retention scores, chat quality scores,
+// completed assessments and analysis domain scores over the last days. Chat
+// quality scores come from the RAG server; when it fails the timeline is
+// returned without them and with a warning.
+func (as *AnalysisService) Timeline(ctx context.Context, req *models.CognitiveTimelineRequest) *models.CognitiveTimelineResponse {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := as.logger.WithContext(ctx)
+
+	days := req.Days
+	if days == 0 {
+		days = util.DefaultCognitiveTimelineDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	resp := &models.CognitiveTimelineResponse{
+		UserID:       req.UserID,
+		Days:         days,
+		Daily:        []models.CognitiveDailyPoint{},
+		Assessments:  []models.AssessmentPoint{},
+		DomainScores: []models.DomainScorePoint{},
+	}
+
+	type dayStats struct {
+		retentionSum float32
+		attempts     int
+		qualitySum   int
+		turns        int
+	}
+	byDay := make(map[string]*dayStats)
+	statsFor := func(t time.Time) *dayStats {
+		date := t.Format("2006-01-02")
+		stats, ok := byDay[date]
+		if !ok {
+			stats = &dayStats{}
+			byDay[date] = stats
+		}
+		return stats
+	}
+
+	for _, attempt := range as.quizHistoryService.Attempts(req.UserID) {
+		if attempt.AttemptedAt.Before(since) {
+			continue
+		}
+		stats := statsFor(attempt.AttemptedAt)
+		stats.retentionSum += attempt.RetentionScore
+		stats.attempts++
+	}
+
+	conversations, err := as.ragClient.ListConversationsByUser(ctx, req.UserID)
+	if err != nil {
+		logger.Warn("Failed to list conversations, timeline has no chat quality scores", err)
+		resp.Warnings = append(resp.Warnings, "chat quality scores unavailable: "+err.Error())
+	}
+	for _, conv := range conversations {
+		// Turns saved with the default score were never evaluated
+		if conv.Timestamp.Before(since) || conv.Metadata == nil || conv.Metadata.Type != util.ConversationTypeChat || conv.Metadata.ScoreVersion == "" {
+			continue
+		}
+		stats := statsFor(conv.Timestamp)
+		stats.qualitySum += conv.Metadata.ConversationScore
+		stats.turns++
+	}
+
+	for date, stats := range byDay {
+		point := models.CognitiveDailyPoint{Date: date, QuizAttempts: stats.attempts, ChatTurns: stats.turns}
+		if stats.attempts > 0 {
+			retention := stats.retentionSum / float32(stats.attempts)
+			point.RetentionScore = &retention
+		}
+		if stats.turns > 0 {
+			quality := float32(stats.qualitySum) / float32(stats.turns)
+			point.ChatQualityScore = &quality
+		}
+		resp.Daily = append(resp.Daily, point)
+	}
+	sort.Slice(resp.Daily, func(i, j int) bool {
+		return resp.Daily[i].Date < resp.Daily[j].Date
+	})
+
+	for _, assessment := range as.assessmentService.List(req.UserID) {
+		if assessment.Status != util.AssessmentCompleted || assessment.CompletedAt.Before(since) {
+			continue
+		}
+		resp.Assessments = append(resp.Assessments, models.AssessmentPoint{
+			AssessmentID: assessment.AssessmentID,
+			CompletedAt:  *assessment.CompletedAt,
+			Score:        assessment.Score,
+			MaxScore:     assessment.MaxScore,
+			Domains:      assessment.Domains,
+		})
+	}
+
+	for _, report := range as.Reports(req.UserID) {
+		if report.AnalyzedAt.Before(since) {
+			continue
+		}
+		point := models.DomainScorePoint{AnalyzedAt: report.AnalyzedAt, Scores: make(map[string]int, len(report.Domains))}
+		for _, domain := range report.Domains {
+			point.Scores[domain.Domain] = domain.Score
+		}
+		if report.Consolidation != nil {
+			point.Consolidation = &report.Consolidation.Score
+		}
+		resp.DomainScores = append(resp.DomainScores, point)
+	}
+
+	logger.KeyValue("Days", days, "Daily Points", len(resp.Daily), "Assessments", len(resp.Assessments), "Reports", len(resp.DomainScores))
+	return resp
+}
+
+// DeleteUser removes the user's kept analysis reports and returns how many there were
+func (as *AnalysisService) DeleteUser(userID string) int {
+	as.reportsMutex.Lock()
+	defer as.reportsMutex.Unlock()
+
+	removed := len(as.reports[userID])
+	delete(as.reports, userID)
+	return removed
 }
 
 // ============================================================================
 // Helper Methods
 // ============================================================================
 
+// keepReport stores a report for export, dropping the oldest beyond MaxAnalysisReportsPerUser
+func (as *AnalysisService) keepReport(report *models.AnalysisResponse) {
+	as.reportsMutex.Lock()
+	defer as.reportsMutex.Unlock()
+
+	reports := append(as.reports[report.UserID], *report)
+	if len(reports) > util.MaxAnalysisReportsPerUser {
+		reports = reports[len(reports)-util.MaxAnalysisReportsPerUser:]
+	}
+	as.reports[report.UserID] = reports
+}
+
 func (as *AnalysisService) fetchConversationHistory(ctx context.Context, userID string) ([]string, error) {
-	as.logger.Section("Fetching Conversation History")
+	logger := as.logger.WithContext(ctx)
+
+	logger.Section("Fetching Conversation History")
 
-	// Fetch all conversations for this user using a broad search query
-	results, err := as.ragClient.SearchConversations(ctx, userID, 50)
+	// Page through the user's history using a broad search query
+	results, err := client.SearchConversationsAll(ctx, as.ragClient, userID, util.AnalysisSearchPageSize, as.cfg.AnalysisMaxConversations)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search conversations: %w", err)
 	}
@@ -111,12 +295,66 @@ func (as *AnalysisService) fetchConversationHistory(ctx context.Context, userID
 		}
 	}
 
-	as.logger.Info("Retrieved %d conversation messages", len(conversations))
+	logger.Info("Retrieved %d conversation messages from %d conversations", len(conversations), len(results))
 	return conversations, nil
 }
 
+// condenseConversations keeps histories that fit the domain analysis prompt as they are.
+// Longer ones are split into batches that are summarized concurrently, so the analysis
+// covers the whole history instead of its first messages. Batches whose summary fails
+// are represented by their first lines.
+func (as *AnalysisService) condenseConversations(ctx context.Context, conversations []string) []string {
+	if len(conversations) <= util.MaxAnalysisPromptMessages {
+		return conversations
+	}
+
+	logger := as.logger.WithContext(ctx)
+
+	var batches [][]string
+	for start := 0; start < len(conversations); start += util.AnalysisSummaryBatchSize {
+		batches = append(batches, conversations[start:min(start+util.AnalysisSummaryBatchSize, len(conversations))])
+	}
+
+	summaries := make([]string, len(batches))
+	failures := make([]error, len(batches))
+	slots := make(chan struct{}, util.AnalysisSummaryConcurrency)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			summaries[i], failures[i] = as.openaiService.SummarizeConversations(ctx, batch)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	failed := 0
+	var lastErr error
+	for i, err := range failures {
+		if err == nil && summaries[i] == "" {
+			err = fmt.Errorf("empty summary")
+		}
+		if err != nil {
+			failed++
+			lastErr = err
+			summaries[i] = prompts.ExtractiveSummary(batches[i], 3)
+		}
+	}
+	if failed > 0 {
+		logger.Warn(fmt.Sprintf("Failed to summarize %d of %d conversation batches, using their first lines", failed, len(batches)), lastErr)
+	}
+
+	logger.Info("Condensed %d conversation messages into %d batch summaries", len(conversations), len(summaries))
+	return summaries
+}
+
 func (as *AnalysisService) fetchIncorrectQuizzes(ctx context.Context, userID string) ([]string, error) {
-	as.logger.Section("Fetching Incorrect Quizzes")
+	logger := as.logger.WithContext(ctx)
+
+	logger.Section("Fetching Incorrect Quizzes")
 
 	// Fetch incorrect quiz attempts
 	attempts, err := as.ragClient.GetIncorrectQuizAttempts(ctx, userID, 20)
@@ -137,13 +375,22 @@ func (as *AnalysisService) fetchIncorrectQuizzes(ctx context.Context, userID str
 		quizzes = append(quizzes, quizStr)
 	}
 
-	as.logger.Info("Retrieved %d incorrect quiz attempts", len(quizzes))
+	logger.Info("Retrieved %d incorrect quiz attempts", len(quizzes))
 	return quizzes, nil
 }
 
 // ProcessDomainAnalysisOnly performs domain analysis only (without report generation)
 func (as *AnalysisService) ProcessDomainAnalysisOnly(ctx context.Context, req *models.AnalysisRequest) (*models.DomainAnalysisOnlyResponse, error) {
-	as.logger.Start("Process Domain Analysis Only")
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := as.logger.WithContext(ctx)
+
+	logger.Start("Process Domain Analysis Only")
+
+	if err := checkFeature(as.cfg, util.FeatureAnalysis); err != nil {
+		logger.Error("Feature disabled", err)
+		logger.End("Process Domain Analysis Only")
+		return nil, err
+	}
 
 	// Fetch user's conversation history and incorrect quiz attempts in parallel
 	conversationChan := make(chan []string, 1)
@@ -152,7 +399,7 @@ func (as *AnalysisService) ProcessDomainAnalysisOnly(ctx context.Context, req *m
 	go func() {
 		conversations, err := as.fetchConversationHistory(ctx, req.UserID)
 		if err != nil {
-			as.logger.Warn("Failed to fetch conversations", err)
+			logger.Warn("Failed to fetch conversations", err)
 			conversationChan <- []string{}
 		} else {
 			conversationChan <- conversations
@@ -162,26 +409,26 @@ func (as *AnalysisService) ProcessDomainAnalysisOnly(ctx context.Context, req *m
 	go func() {
 		quizzes, err := as.fetchIncorrectQuizzes(ctx, req.UserID)
 		if err != nil {
-			as.logger.Warn("Failed to fetch incorrect quizzes", err)
+			logger.Warn("Failed to fetch incorrect quizzes", err)
 			incorrectQuizzesChan <- []string{}
 		} else {
 			incorrectQuizzesChan <- quizzes
 		}
 	}()
 
-	conversationHistory := <-conversationChan
+	conversationHistory := as.condenseConversations(ctx, <-conversationChan)
 	incorrectQuizzes := <-incorrectQuizzesChan
 
-	as.logger.Section("Analyzing Domains")
+	logger.Section("Analyzing Domains")
 	domains, err := as.openaiService.AnalyzeDomains(ctx, conversationHistory, incorrectQuizzes)
 	if err != nil {
-		as.logger.Error("Failed to analyze domains", err)
-		as.logger.End("Process Domain Analysis Only")
+		logger.Error("Failed to analyze domains", err)
+		logger.End("Process Domain Analysis Only")
 		return nil, fmt.Errorf("failed to analyze domains: %w", err)
 	}
 
-	as.logger.Success("Domain analysis completed")
-	as.logger.End("Process Domain Analysis Only")
+	logger.Success("Domain analysis completed")
+	logger.End("Process Domain Analysis Only")
 
 	return &models.DomainAnalysisOnlyResponse{
 		UserID:     req.UserID,
@@ -192,12 +439,20 @@ func (as *AnalysisService) ProcessDomainAnalysisOnly(ctx context.Context, req *m
 
 // ProcessReportGenerationOnly generates a report from provided domain scores
 func (as *AnalysisService) ProcessReportGenerationOnly(ctx context.Context, req *models.ReportGenerationRequest) (string, error) {
-	as.logger.Start("Process Report Generation Only")
+	logger := as.logger.WithContext(ctx)
+
+	logger.Start("Process Report Generation Only")
+
+	if err := checkFeature(as.cfg, util.FeatureAnalysis); err != nil {
+		logger.Error("Feature disabled", err)
+		logger.End("Process Report Generation Only")
+		return "", err
+	}
 
 	// Validate that we have all required domains
 	if len(req.Domains) != 4 {
-		as.logger.Error("Invalid domain count", fmt.Errorf("expected 4 domains, got %d", len(req.Domains)))
-		as.logger.End("Process Report Generation Only")
+		logger.Error("Invalid domain count", fmt.Errorf("expected 4 domains, got %d", len(req.Domains)))
+		logger.End("Process Report Generation Only")
 		return "", fmt.Errorf("invalid_request: expected 4 domains, got %d", len(req.Domains))
 	}
 
@@ -224,7 +479,7 @@ func (as *AnalysisService) ProcessReportGenerationOnly(ctx context.Context, req
 		}
 	}
 
-	as.logger.Section("Generating Report")
+	logger.Section("Generating Report")
 	report, err := as.openaiService.GenerateReportFromDomainScores(
 		ctx,
 		familyScore, familyInsights,
@@ -233,13 +488,13 @@ func (as *AnalysisService) ProcessReportGenerationOnly(ctx context.Context, req
 		hobbiesScore, hobbiesInsights,
 	)
 	if err != nil {
-		as.logger.Error("Failed to generate report", err)
-		as.logger.End("Process Report Generation Only")
+		logger.Error("Failed to generate report", err)
+		logger.End("Process Report Generation Only")
 		return "", fmt.Errorf("failed to generate report: %w", err)
 	}
 
-	as.logger.Success("Report generation completed")
-	as.logger.End("Process Report Generation Only")
+	logger.Success("Report generation completed")
+	logger.End("Process Report Generation Only")
 
 	return report, nil
 }
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/models"
+	"llm/internal/textutil"
+	"llm/internal/util"
+)
+
+// assessmentWordSets are the word triples used for registration and recall
+var assessmentWordSets = [][]string{
+	{"나무", "자동차", "모자"},
+	{"비행기", "연필", "소나무"},
+	{"기차", "사과", "우산"},
+}
+
+const (
+	assessmentIntro   = "지금부터 간단한 기억력 확인을 해볼게요. 천천히 편하게 답해 주세요. "
+	assessmentClosing = "모두 답해 주셔서 고맙습니다. 오늘 정말 수고 많으셨어요!"
+)
+
+// Assessment item kinds
+const (
+	itemYear         = "year"
+	itemSeason       = "season"
+	itemMonth        = "month"
+	itemDate         = "date"
+	itemWeekday      = "weekday"
+	itemRegistration = "registration"
+	itemSerial       = "serial"
+	itemRecall       = "recall"
+)
+
+// AssessmentService administers a short K-MMSE style cognitive screen
+// (orientation to time, registration, attention, recall) over chat turns. While
+// a user's assessment is in progress, each chat message answers its next item.
+type AssessmentService struct {
+	sessions map[string]*assessmentSession // assessment ID -> session
+	active   map[string]string             // user ID -> assessment ID in progress
+	mutex    sync.RWMutex
+	logger   *util.Logger
+}
+
+type assessmentSession struct {
+	result       models.AssessmentResult
+	items        []assessmentItem
+	next         int // index of the item asked next
+	serial       int // the value the next subtraction is scored against
+	lastActivity time.Time
+}
+
+type assessmentItem struct {
+	kind     string
+	domain   string
+	prompt   string
+	value    int      // expected number of orientation items
+	words    []string // expected words of registration and recall
+	maxScore int
+}
+
+// NewAssessmentService creates a new assessment service
+func NewAssessmentService() *AssessmentService {
+	return &AssessmentService{
+		sessions: make(map[string]*assessmentSession),
+		active:   make(map[string]string),
+		logger:   util.NewLogger("AssessmentService"),
+	}
+}
+
+// Start begins an assessment for the user and returns its first prompt. A user
+// has at most one assessment in progress.
+func (as *AssessmentService) Start(ctx context.Context, userID string) (*models.AssessmentProgress, error) {
+	logger := as.logger.WithContext(ctx)
+	now := time.Now()
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	if session := as.activeSession(userID, now); session != nil {
+		return nil, fmt.Errorf("assessment_in_progress: %s", session.result.AssessmentID)
+	}
+
+	items := assessmentItems(now, assessmentWordSets[rand.Intn(len(assessmentWordSets))])
+	maxScore := 0
+	for _, item := range items {
+		maxScore += item.maxScore
+	}
+
+	session := &assessmentSession{
+		result: models.AssessmentResult{
+			AssessmentID: uuid.New().String(),
+			UserID:       userID,
+			Status:       util.AssessmentInProgress,
+			MaxScore:     maxScore,
+			Items:        []models.AssessmentItemResult{},
+			StartedAt:    now,
+		},
+		items:        items,
+		serial:       util.SerialSubtractionStart,
+		lastActivity: now,
+	}
+	as.sessions[session.result.AssessmentID] = session
+	as.active[userID] = session.result.AssessmentID
+
+	logger.KeyValue("Assessment", session.result.AssessmentID, "Items", len(items))
+	progress := session.progress()
+	progress.Prompt = assessmentIntro + progress.Prompt
+	return progress, nil
+}
+
+// AnswerTurn scores message as the answer to the user's assessment item and
+// returns the prompt for the next item, or the closing line after the last.
+// It reports false when the user has no assessment in progress.
+func (as *AssessmentService) AnswerTurn(ctx context.Context, userID, message string, now time.Time) (*models.AssessmentProgress, bool) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	session := as.activeSession(userID, now)
+	if session == nil {
+		return nil, false
+	}
+
+	item := session.items[session.next]
+	score, expected := session.score(item, message)
+	session.result.Items = append(session.result.Items, models.AssessmentItemResult{
+		Domain:   item.domain,
+		Prompt:   item.prompt,
+		Expected: expected,
+		Answer:   message,
+		Score:    score,
+		MaxScore: item.maxScore,
+	})
+	session.result.Score += score
+	session.next++
+	session.lastActivity = now
+
+	if session.next == len(session.items) {
+		completedAt := now
+		session.result.Status = util.AssessmentCompleted
+		session.result.CompletedAt = &completedAt
+		delete(as.active, userID)
+		as.logger.WithContext(ctx).KeyValue("Assessment", session.result.AssessmentID, "Score", session.result.Score, "Max", session.result.MaxScore)
+	}
+	return session.progress(), true
+}
+
+// Result returns the assessment with its scores so far
+func (as *AssessmentService) Result(assessmentID string) (*models.AssessmentResult, error) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	session, ok := as.sessions[assessmentID]
+	if !ok {
+		return nil, fmt.Errorf("assessment_not_found: %s", assessmentID)
+	}
+	as.expireIdle(session, time.Now())
+	return session.snapshot(), nil
+}
+
+// List returns the user's assessments, oldest first
+func (as *AssessmentService) List(userID string) []models.AssessmentResult {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	now := time.Now()
+	results := []models.AssessmentResult{}
+	for _, session := range as.sessions {
+		if session.result.UserID != userID {
+			continue
+		}
+		as.expireIdle(session, now)
+		results = append(results, *session.snapshot())
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartedAt.Before(results[j].StartedAt)
+	})
+	return results
+}
+
+// CompactAssessments removes assessments started before the cutoff and returns
+// how many were removed and the approximate bytes their answers held
+func (as *AssessmentService) CompactAssessments(before time.Time) (int, int64) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for id, session := range as.sessions {
+		if !session.result.StartedAt.Before(before) {
+			continue
+		}
+		for _, item := range session.result.Items {
+			reclaimed += int64(len(item.Prompt) + len(item.Expected) + len(item.Answer))
+		}
+		if as.active[session.result.UserID] == id {
+			delete(as.active, session.result.UserID)
+		}
+		delete(as.sessions, id)
+		removed++
+	}
+	return removed, reclaimed
+}
+
+// DeleteUser removes all of the user's assessments and returns how many there were
+func (as *AssessmentService) DeleteUser(userID string) int {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	removed := 0
+	for id, session := range as.sessions {
+		if session.result.UserID == userID {
+			delete(as.sessions, id)
+			removed++
+		}
+	}
+	delete(as.active, userID)
+	return removed
+}
+
+// activeSession returns the user's assessment in progress, abandoning it when it
+// has been idle too long. The caller must hold the write lock.
+func (as *AssessmentService) activeSession(userID string, now time.Time) *assessmentSession {
+	session := as.sessions[as.active[userID]]
+	if session == nil {
+		return nil
+	}
+	if as.expireIdle(session, now) {
+		return nil
+	}
+	return session
+}
+
+// expireIdle abandons an assessment in progress with no answer within the idle
+// timeout and reports whether it did. The caller must hold the write lock.
+func (as *AssessmentService) expireIdle(session *assessmentSession, now time.Time) bool {
+	if session.result.Status != util.AssessmentInProgress || now.Sub(session.lastActivity) <= util.AssessmentIdleTimeout {
+		return false
+	}
+	session.result.Status = util.AssessmentAbandoned
+	if as.active[session.result.UserID] == session.result.AssessmentID {
+		delete(as.active, session.result.UserID)
+	}
+	return true
+}
+
+// progress returns the prompt for the item asked next
+func (s *assessmentSession) progress() *models.AssessmentProgress {
+	progress := &models.AssessmentProgress{
+		AssessmentID: s.result.AssessmentID,
+		Item:         s.next + 1,
+		TotalItems:   len(s.items),
+		Completed:    s.next == len(s.items),
+	}
+	if progress.Completed {
+		progress.Item = len(s.items)
+		progress.Prompt = assessmentClosing
+		return progress
+	}
+
+	item := s.items[s.next]
+	progress.Domain = item.domain
+	progress.Prompt = item.prompt
+	return progress
+}
+
+// snapshot copies the result and totals its domains. Domain maximums cover
+// every item, answered or not.
+func (s *assessmentSession) snapshot() *models.AssessmentResult {
+	result := s.result
+	result.Items = append([]models.AssessmentItemResult{}, s.result.Items...)
+
+	domains := []models.AssessmentDomainScore{}
+	index := make(map[string]int)
+	for _, item := range s.items {
+		i, ok := index[item.domain]
+		if !ok {
+			i = len(domains)
+			index[item.domain] = i
+			domains = append(domains, models.AssessmentDomainScore{Domain: item.domain})
+		}
+		domains[i].MaxScore += item.maxScore
+	}
+	for _, item := range result.Items {
+		domains[index[item.Domain]].Score += item.Score
+	}
+	result.Domains = domains
+	return &result
+}
+
+// score scores the answer to the item and returns the score and the expected answer
+func (s *assessmentSession) score(item assessmentItem, answer string) (int, string) {
+	switch item.kind {
+	case itemYear:
+		// Two-digit years ("26년") are accepted
+		return boolScore(containsNumber(answer, item.value) || containsNumber(answer, item.value%100)), fmt.Sprintf("%d년", item.value)
+	case itemSeason:
+		return boolScore(strings.Contains(answer, koreanSeasons[item.value])), koreanSeasons[item.value]
+	case itemMonth:
+		return boolScore(containsNumber(answer, item.value)), fmt.Sprintf("%d월", item.value)
+	case itemDate:
+		return boolScore(containsNumber(answer, item.value)), fmt.Sprintf("%d일", item.value)
+	case itemWeekday:
+		weekday := koreanWeekdays[item.value]
+		short := string([]rune(weekday)[:1]) + "요"
+		return boolScore(strings.Contains(answer, weekday) || strings.Contains(answer, short)), weekday
+	case itemSerial:
+		// Each subtraction is scored against the previous answer, so one slip costs one point
+		expected := s.serial - util.SerialSubtractionStep
+		correct := containsNumber(answer, expected)
+		s.serial = expected
+		if numbers := textutil.Numbers(answer); !correct && len(numbers) > 0 {
+			s.serial = numbers[0]
+		}
+		return boolScore(correct), strconv.Itoa(expected)
+	default: // registration and recall
+		compact := strings.ReplaceAll(answer, " ", "")
+		score := 0
+		for _, word := range item.words {
+			if strings.Contains(compact, word) {
+				score++
+			}
+		}
+		return score, strings.Join(item.words, ", ")
+	}
+}
+
+// assessmentItems lists the items of an assessment administered at now
+func assessmentItems(now time.Time, words []string) []assessmentItem {
+	items := []assessmentItem{
+		{kind: itemYear, domain: util.AssessmentOrientation, prompt: "올해는 몇 년도인가요?", value: now.Year(), maxScore: 1},
+		{kind: itemSeason, domain: util.AssessmentOrientation, prompt: "지금은 어느 계절인가요?", value: seasonOf(now.Month()), maxScore: 1},
+		{kind: itemMonth, domain: util.AssessmentOrientation, prompt: "이번 달은 몇 월인가요?", value: int(now.Month()), maxScore: 1},
+		{kind: itemDate, domain: util.AssessmentOrientation, prompt: "오늘은 며칠인가요?", value: now.Day(), maxScore: 1},
+		{kind: itemWeekday, domain: util.AssessmentOrientation, prompt: "오늘은 무슨 요일인가요?", value: int(now.Weekday()), maxScore: 1},
+		{
+			kind:     itemRegistration,
+			domain:   util.AssessmentRegistration,
+			prompt:   fmt.Sprintf("이제 세 가지 단어를 말씀드릴게요. 잘 들으시고 따라 말씀해 주세요. 나중에 다시 여쭤볼 테니 꼭 기억해 두세요. %s.", strings.Join(words, ", ")),
+			words:    words,
+			maxScore: len(words),
+		},
+	}
+
+	for i := 0; i < util.SerialSubtractionCount; i++ {
+		prompt := "거기서 또 7을 빼면 얼마일까요?"
+		if i == 0 {
+			prompt = fmt.Sprintf("%d에서 %d을 빼면 얼마일까요?", util.SerialSubtractionStart, util.SerialSubtractionStep)
+		}
+		items = append(items, assessmentItem{kind: itemSerial, domain: util.AssessmentAttention, prompt: prompt, maxScore: 1})
+	}
+
+	return append(items, assessmentItem{
+		kind:     itemRecall,
+		domain:   util.AssessmentRecall,
+		prompt:   "아까 기억해 달라고 말씀드린 세 가지 단어가 무엇이었는지 말씀해 주시겠어요?",
+		words:    words,
+		maxScore: len(words),
+	})
+}
+
+// containsNumber reports whether the answer mentions the number, with the
+// month names 시월 and 유월 read as 10월 and 6월
+func containsNumber(answer string, number int) bool {
+	answer = strings.NewReplacer("시월", "10월", "유월", "6월").Replace(answer)
+	for _, n := range textutil.Numbers(answer) {
+		if n == number {
+			return true
+		}
+	}
+	return false
+}
+
+func boolScore(correct bool) int {
+	if correct {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/client"
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/prompts"
+	"llm/internal/util"
+)
+
+// BackfillService re-scores stored chat conversations with the current
+// evaluation prompt, so trend analyses compare scores of one prompt version.
+// One job runs at a time; updated scores are written back by re-storing each
+// conversation under its existing ID.
+type BackfillService struct {
+	cfg           *config.Config
+	ragClient     client.RAGStore
+	openaiService LLMService
+	job           *models.ScoreBackfillJob
+	mutex         sync.RWMutex
+	logger        *util.Logger
+}
+
+// NewBackfillService creates a new backfill service
+func NewBackfillService(cfg *config.Config, ragClient client.RAGStore, openaiService LLMService) *BackfillService {
+	return &BackfillService{
+		cfg:           cfg,
+		ragClient:     ragClient,
+		openaiService: openaiService,
+		logger:        util.NewLogger("BackfillService"),
+	}
+}
+
+// StartScoreBackfill starts a score backfill in the background and returns its
+// initial state. It fails while another backfill is running.
+func (bs *BackfillService) StartScoreBackfill(ctx context.Context, req *models.ScoreBackfillRequest) (*models.ScoreBackfillJob, error) {
+	logger := bs.logger.WithContext(ctx)
+
+	if err := checkFeature(bs.cfg, util.FeatureEvaluation); err != nil {
+		logger.Error("Feature disabled", err)
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = util.DefaultBackfillConversationLimit
+	}
+	if limit > util.MaxBackfillConversationLimit {
+		limit = util.MaxBackfillConversationLimit
+	}
+
+	bs.mutex.Lock()
+	if bs.job != nil && bs.job.Status == util.BackfillStatusRunning {
+		running := bs.job.ID
+		bs.mutex.Unlock()
+		return nil, fmt.Errorf("backfill_running: job %s has not finished", running)
+	}
+	job := &models.ScoreBackfillJob{
+		ID:            uuid.New().String(),
+		Status:        util.BackfillStatusRunning,
+		PromptVersion: prompts.PromptVersion,
+		UserIDs:       append([]string(nil), req.UserIDs...),
+		Force:         req.Force,
+		StartedAt:     time.Now(),
+	}
+	bs.job = job
+	snapshot := bs.snapshotLocked()
+	bs.mutex.Unlock()
+
+	logger.KeyValue("Job", job.ID, "Users", len(job.UserIDs), "Limit", limit, "Force", job.Force)
+	go bs.runScoreBackfill(context.WithoutCancel(ctx), limit)
+
+	return snapshot, nil
+}
+
+// ScoreBackfillJob returns the current or most recent backfill, or nil when none has run
+func (bs *BackfillService) ScoreBackfillJob() *models.ScoreBackfillJob {
+	bs.mutex.RLock()
+	defer bs.mutex.RUnlock()
+
+	if bs.job == nil {
+		return nil
+	}
+	return bs.snapshotLocked()
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// snapshotLocked copies the job. The caller must hold the mutex.
+func (bs *BackfillService) snapshotLocked() *models.ScoreBackfillJob {
+	copied := *bs.job
+	copied.UserIDs = append([]string(nil), bs.job.UserIDs...)
+	copied.Errors = append([]string(nil), bs.job.Errors...)
+	return &copied
+}
+
+// update applies fn to the running job under the lock
+func (bs *BackfillService) update(fn func(job *models.ScoreBackfillJob)) {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+	fn(bs.job)
+}
+
+// recordError counts a failure and keeps its message for the report
+func (bs *BackfillService) recordError(err error) {
+	bs.update(func(job *models.ScoreBackfillJob) {
+		job.Failed++
+		if len(job.Errors) < util.MaxBackfillErrors {
+			job.Errors = append(job.Errors, err.Error())
+		}
+	})
+}
+
+func (bs *BackfillService) runScoreBackfill(ctx context.Context, limit int) {
+	logger := bs.logger.WithContext(ctx)
+
+	logger.Start("Async: Score Backfill")
+
+	bs.mutex.RLock()
+	userIDs, force := bs.job.UserIDs, bs.job.Force
+	bs.mutex.RUnlock()
+
+	for _, userID := range userIDs {
+		if err := bs.backfillUser(util.WithUserID(ctx, userID), userID, limit, force); err != nil {
+			bs.recordError(err)
+			logger.Warn("Score backfill stopped", err)
+			break
+		}
+	}
+
+	var finished *models.ScoreBackfillJob
+	bs.update(func(job *models.ScoreBackfillJob) {
+		now := time.Now()
+		job.Status = util.BackfillStatusCompleted
+		job.FinishedAt = &now
+		finished = job
+	})
+
+	logger.KeyValue("Scanned", finished.Scanned, "Rescored", finished.Rescored, "Skipped", finished.Skipped, "Failed", finished.Failed)
+	logger.Success("Score backfill completed")
+	logger.End("Async: Score Backfill")
+}
+
+// backfillUser re-scores the user's stored chat turns, newest first. Each turn is
+// evaluated against the user's profile and the user messages of the three turns before it.
+// It returns an error only when evaluation is switched off, which stops the whole job.
+func (bs *BackfillService) backfillUser(ctx context.Context, userID string, limit int, force bool) error {
+	logger := bs.logger.WithContext(ctx)
+
+	conversations, err := bs.ragClient.SearchConversations(ctx, "conversation", limit)
+	if err != nil {
+		bs.recordError(fmt.Errorf("user %s: failed to search conversations: %w", userID, err))
+		return nil
+	}
+
+	profile, err := bs.ragClient.GetPersonalInfoByUser(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to fetch profile, evaluating without it", err)
+		profile = nil
+	}
+
+	sort.SliceStable(conversations, func(i, j int) bool {
+		return conversations[i].Timestamp.After(conversations[j].Timestamp)
+	})
+
+	for i, conv := range conversations {
+		bs.update(func(job *models.ScoreBackfillJob) { job.Scanned++ })
+
+		userMessage := firstUserMessage(conv)
+		if userMessage == "" || !isChatTurn(conv.Metadata) || (!force && conv.Metadata != nil && conv.Metadata.ScoreVersion == prompts.PromptVersion) {
+			bs.update(func(job *models.ScoreBackfillJob) { job.Skipped++ })
+			continue
+		}
+
+		contextMessages := []string{}
+		for _, older := range conversations[i+1:] {
+			if len(contextMessages) >= 3 {
+				break
+			}
+			if msg := firstUserMessage(older); msg != "" {
+				contextMessages = append(contextMessages, msg)
+			}
+		}
+
+		if err := checkFeature(bs.cfg, util.FeatureEvaluation); err != nil {
+			return err
+		}
+
+		score, err := bs.openaiService.EvaluateUserResponseQuality(ctx, userMessage, contextMessages, profile)
+		if err != nil {
+			bs.recordError(fmt.Errorf("conversation %s: failed to evaluate: %w", conv.ConversationID, err))
+			continue
+		}
+
+		metadata := &models.RAGMetadata{Source: "llm_chat", SessionID: userID, Type: util.ConversationTypeChat}
+		if conv.Metadata != nil {
+			copied := *conv.Metadata
+			metadata = &copied
+		}
+		// Conversations saved before the type was recorded are chat turns
+		if metadata.Type == "" {
+			metadata.Type = util.ConversationTypeChat
+		}
+		metadata.ConversationScore = score
+		metadata.ScoreVersion = prompts.PromptVersion
+
+		if _, err := bs.ragClient.SaveConversation(ctx, &models.RAGConversationSaveRequest{
+			ConversationID: conv.ConversationID,
+			Messages:       conv.Messages,
+			Metadata:       metadata,
+		}); err != nil {
+			bs.recordError(fmt.Errorf("conversation %s: failed to save: %w", conv.ConversationID, err))
+			continue
+		}
+
+		bs.update(func(job *models.ScoreBackfillJob) { job.Rescored++ })
+	}
+	return nil
+}
+
+// isChatTurn reports whether stored metadata belongs to a scored chat turn.
+// Conversations without metadata are assumed to be chat turns.
+func isChatTurn(metadata *models.RAGMetadata) bool {
+	return metadata == nil || metadata.Type == "" || metadata.Type == util.ConversationTypeChat
+}
+
+// firstUserMessage returns the user's message in a stored conversation
+func firstUserMessage(conv models.RAGConversationSearchResult) string {
+	for _, msg := range conv.Messages {
+		if msg.Role == "user" {
+			return msg.Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"llm/internal/client"
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// AdminCache is one in-memory cache operators can inspect and evict from. Stats
+// returns the cached entries and the users they belong to; Evict removes the
+// entries under a key and returns how many there were.
+type AdminCache struct {
+	Name    string
+	Enabled bool
+	TTL     time.Duration
+	Stats   func() (int, int)
+	Evict   func(key string) int
+}
+
+// CacheService lets support inspect the in-memory caches and evict stale
+// entries without restarting the server
+type CacheService struct {
+	caches []AdminCache
+	logger *util.Logger
+}
+
+// NewCacheService creates a cache service over the standard caches. ragCache is
+// nil when the RAG read cache is disabled.
+func NewCacheService(cfg *config.Config, chatService *ChatService, gameService *GameService, ragCache *client.CachedStore) *CacheService {
+	noEntries := func() (int, int) { return 0, 0 }
+	noEvict := func(string) int { return 0 }
+
+	profiles := AdminCache{Name: util.CacheProfiles, Stats: noEntries, Evict: noEvict}
+	attempts := AdminCache{Name: util.CacheIncorrectAttempts, Stats: noEntries, Evict: noEvict}
+	if ragCache != nil {
+		if cfg.ProfileCacheEnabled {
+			profiles.Enabled = true
+			profiles.TTL = cfg.ProfileCacheTTL
+			profiles.Stats = func() (int, int) {
+				users := ragCache.ProfileStats()
+				return users, users
+			}
+			profiles.Evict = ragCache.EvictProfile
+		}
+		if cfg.IncorrectAttemptsCacheEnabled {
+			attempts.Enabled = true
+			attempts.TTL = cfg.IncorrectAttemptsCacheTTL
+			attempts.Stats = ragCache.IncorrectAttemptsStats
+			attempts.Evict = ragCache.EvictIncorrectAttempts
+		}
+	}
+
+	semantic := AdminCache{Name: util.CacheSemantic, Stats: chatService.SemanticCacheStats, Evict: chatService.DeleteUserCache}
+	if cfg.SemanticCacheEnabled {
+		semantic.Enabled = true
+		semantic.TTL = cfg.SemanticCacheTTL
+	}
+
+	return &CacheService{
+		caches: []AdminCache{
+			{Name: util.CacheQuestions, Enabled: true, TTL: cfg.QuestionCacheTTL, Stats: gameService.QuestionCacheStats, Evict: gameService.EvictQuestions},
+			profiles,
+			attempts,
+			semantic,
+		},
+		logger: util.NewLogger("CacheService"),
+	}
+}
+
+// Stats returns the size of every cache
+func (cs *CacheService) Stats() *models.CacheStatsResponse {
+	resp := &models.CacheStatsResponse{Caches: make([]models.CacheStats, 0, len(cs.caches))}
+	for _, cache := range cs.caches {
+		entries, users := cache.Stats()
+		resp.Caches = append(resp.Caches, models.CacheStats{
+			Name:       cache.Name,
+			Enabled:    cache.Enabled,
+			Entries:    entries,
+			Users:      users,
+			TTLSeconds: int64(cache.TTL.Seconds()),
+		})
+	}
+	return resp
+}
+
+// Evict removes the entries under key from the named cache
+func (cs *CacheService) Evict(name, key string) (*models.CacheEvictResponse, error) {
+	for _, cache := range cs.caches {
+		if cache.Name != name {
+			continue
+		}
+
+		evicted := cache.Evict(key)
+		cs.logger.KeyValue("Cache", name, "Key", key, "Evicted", evicted)
+		if evicted == 0 {
+			return nil, fmt.Errorf("cache_entry_not_found: nothing cached in %s under %s", name, key)
+		}
+		return &models.CacheEvictResponse{Name: name, Key: key, Evicted: evicted}, nil
+	}
+	return nil, fmt.Errorf("unknown_cache: %s", name)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// CalibrationService checks whether confidence labels predict later recall
+// and, when enabled, moves the label thresholds to match observed outcomes
+type CalibrationService struct {
+	cfg                *config.Config
+	quizHistoryService *QuizHistoryService
+	lastReport         *models.ConfidenceCalibrationReport
+	mutex              sync.RWMutex
+	logger             *util.Logger
+}
+
+// NewCalibrationService creates a new calibration service.
+// Periodic calibration starts only when CONFIDENCE_CALIBRATION_ENABLED is set.
+func NewCalibrationService(cfg *config.Config, quizHistoryService *QuizHistoryService) *CalibrationService {
+	cs := &CalibrationService{
+		cfg:                cfg,
+		quizHistoryService: quizHistoryService,
+		logger:             util.NewLogger("CalibrationService"),
+	}
+
+	if cfg.ConfidenceCalibrationEnabled && cfg.ConfidenceCalibrationInterval > 0 {
+		go cs.calibrationRoutine()
+	}
+	return cs
+}
+
+// Report returns the most recent calibration run, or a fresh evaluation that
+// does not change thresholds when none has run yet
+func (cs *CalibrationService) Report() *models.ConfidenceCalibrationReport {
+	cs.mutex.RLock()
+	last := cs.lastReport
+	cs.mutex.RUnlock()
+
+	if last != nil {
+		return last
+	}
+	return cs.evaluate()
+}
+
+// Run evaluates calibration now and applies the suggested thresholds when
+// auto-adjust is enabled and enough outcomes have been observed
+func (cs *CalibrationService) Run(ctx context.Context) *models.ConfidenceCalibrationReport {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Calibrate Confidence")
+
+	report := cs.evaluate()
+
+	logger.KeyValue("Samples", report.Samples, "Min Samples", report.MinSamples)
+
+	if report.AutoAdjust && report.Samples >= report.MinSamples &&
+		(report.SuggestedHighThreshold != report.HighThreshold || report.SuggestedMediumThreshold != report.MediumThreshold) {
+		cs.cfg.Runtime.Update(func(s *config.RuntimeSettings) {
+			s.ConfidenceHighThreshold = report.SuggestedHighThreshold
+			s.ConfidenceMediumThreshold = report.SuggestedMediumThreshold
+		})
+		report.Applied = true
+		logger.Info("High threshold %.2f -> %.2f, medium threshold %.2f -> %.2f",
+			report.HighThreshold, report.SuggestedHighThreshold,
+			report.MediumThreshold, report.SuggestedMediumThreshold)
+		logger.Success("Confidence thresholds adjusted")
+	} else {
+		logger.Info("Thresholds unchanged")
+	}
+
+	cs.mutex.Lock()
+	cs.lastReport = report
+	cs.mutex.Unlock()
+
+	logger.End("Calibrate Confidence")
+	return report
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (cs *CalibrationService) evaluate() *models.ConfidenceCalibrationReport {
+	settings := cs.cfg.Runtime.Get()
+	outcomes := cs.quizHistoryService.RecallOutcomes()
+
+	report := &models.ConfidenceCalibrationReport{
+		HighThreshold:            settings.ConfidenceHighThreshold,
+		MediumThreshold:          settings.ConfidenceMediumThreshold,
+		SuggestedHighThreshold:   settings.ConfidenceHighThreshold,
+		SuggestedMediumThreshold: settings.ConfidenceMediumThreshold,
+		Samples:                  len(outcomes),
+		MinSamples:               cs.cfg.ConfidenceCalibrationMinSamples,
+		AutoAdjust:               cs.cfg.ConfidenceCalibrationEnabled,
+		EvaluatedAt:              time.Now(),
+	}
+
+	targets := map[string]float32{
+		util.ConfidenceHigh:   util.CalibrationTargetHighRecall,
+		util.ConfidenceMedium: util.CalibrationTargetMediumRecall,
+		util.ConfidenceLow:    0,
+	}
+	for _, label := range []string{util.ConfidenceHigh, util.ConfidenceMedium, util.ConfidenceLow} {
+		samples, recalled := 0, 0
+		for _, outcome := range outcomes {
+			if outcome.Confidence != label {
+				continue
+			}
+			samples++
+			if outcome.LaterCorrect {
+				recalled++
+			}
+		}
+		stats := models.ConfidenceLabelRecall{Label: label, Samples: samples, TargetRate: targets[label]}
+		if samples > 0 {
+			stats.LaterRecallRate = float32(recalled) / float32(samples)
+		}
+		report.Labels = append(report.Labels, stats)
+	}
+
+	if high, ok := lowestThreshold(outcomes, util.CalibrationTargetHighRecall, 1); ok {
+		report.SuggestedHighThreshold = high
+	}
+	if medium, ok := lowestThreshold(outcomes, util.CalibrationTargetMediumRecall, report.SuggestedHighThreshold); ok {
+		report.SuggestedMediumThreshold = medium
+	}
+
+	return report
+}
+
+// lowestThreshold finds the lowest score threshold, no higher than ceiling,
+// at which attempts scoring at or above it were later recalled at least at
+// the target rate. Thresholds with too few outcomes are not considered.
+func lowestThreshold(outcomes []RecallOutcome, target, ceiling float32) (float32, bool) {
+	best, found := float32(0), false
+	for step := int(ceiling/util.CalibrationThresholdStep + 0.5); step >= 0; step-- {
+		t := float32(step) * util.CalibrationThresholdStep
+		samples, recalled := 0, 0
+		for _, outcome := range outcomes {
+			if outcome.RetentionScore >= t {
+				samples++
+				if outcome.LaterCorrect {
+					recalled++
+				}
+			}
+		}
+		if samples < util.CalibrationMinBucketSamples {
+			continue
+		}
+		if float32(recalled)/float32(samples) < target {
+			break
+		}
+		best, found = t, true
+	}
+	return best, found
+}
+
+func (cs *CalibrationService) calibrationRoutine() {
+	ticker := time.NewTicker(cs.cfg.ConfidenceCalibrationInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cs.Run(context.Background())
+	}
+}
@@ -3,58 +3,531 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 
 	"llm/internal/client"
 	"llm/internal/config"
+	"llm/internal/guardrail"
+	"llm/internal/metrics"
 	"llm/internal/models"
+	"llm/internal/pii"
+	"llm/internal/prompts"
+	"llm/internal/textutil"
 	"llm/internal/util"
 )
 
 // ChatService handles chat functionality
 type ChatService struct {
-	ragClient     *client.RAGClient
-	openaiService *OpenAIService
-	cfg           *config.Config
-	logger        *util.Logger
+	ragClient         client.RAGStore
+	openaiService     LLMService
+	experimentService *ExperimentService
+	guardrailService  *GuardrailService
+	emergencyService  *EmergencyService
+	moodService       *MoodService
+	planService       *PlanService
+	reminderService   *ReminderService
+	assessmentService *AssessmentService
+	shadowService     *ShadowService
+	userService       *UserService
+	semanticCache     *SemanticCache // nil unless SEMANTIC_CACHE_ENABLED
+	piiScrubber       *pii.Scrubber  // nil unless PII_REDACTION_ENABLED
+	userLimiter       *userLimiter   // nil when CHAT_USER_MAX_IN_FLIGHT is 0
+	cfg               *config.Config
+	logger            *util.Logger
 }
 
 // NewChatService creates a new chat service
-func NewChatService(cfg *config.Config, ragClient *client.RAGClient, openaiService *OpenAIService) *ChatService {
-	return &ChatService{
-		ragClient:     ragClient,
-		openaiService: openaiService,
-		cfg:           cfg,
-		logger:        util.NewLogger("ChatService"),
+func NewChatService(cfg *config.Config, ragClient client.RAGStore, openaiService LLMService, experimentService *ExperimentService, guardrailService *GuardrailService, emergencyService *EmergencyService, moodService *MoodService, planService *PlanService, reminderService *ReminderService, assessmentService *AssessmentService, shadowService *ShadowService, userService *UserService) *ChatService {
+	cs := &ChatService{
+		ragClient:         ragClient,
+		openaiService:     openaiService,
+		experimentService: experimentService,
+		guardrailService:  guardrailService,
+		emergencyService:  emergencyService,
+		moodService:       moodService,
+		planService:       planService,
+		reminderService:   reminderService,
+		assessmentService: assessmentService,
+		shadowService:     shadowService,
+		userService:       userService,
+		cfg:               cfg,
+		logger:            util.NewLogger("ChatService"),
 	}
+	if cfg.SemanticCacheEnabled {
+		cs.semanticCache = NewSemanticCache(cfg)
+	}
+	if cfg.ChatUserMaxInFlight > 0 {
+		cs.userLimiter = newUserLimiter(cfg.ChatUserMaxInFlight)
+	}
+	if cfg.PIIRedactionEnabled {
+		cs.piiScrubber = pii.NewScrubber(cfg.PIIRedactionKinds)
+	}
+	return cs
 }
 
 // ProcessChat processes a user chat message and returns a response
 func (cs *ChatService) ProcessChat(ctx context.Context, req *models.ChatRequest) (*models.ChatResponse, error) {
-	cs.logger.Start("Process Chat")
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Process Chat")
+
+	// Overlapping requests from one user would interleave their retrieval and saves
+	release, err := cs.acquireChatSlot(ctx, req.UserID)
+	if err != nil {
+		logger.Warn("Chat already in progress", err)
+		logger.End("Process Chat")
+		return nil, err
+	}
+	defer release()
+
+	// Composed Hangul and collapsed whitespace keep keyword rules and retrieval consistent
+	req.Message = textutil.Normalize(req.Message)
+	prefs := cs.preferences(req.UserID)
+
+	if err := cs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Process Chat")
+		return nil, err
+	}
+
+	if err := validateImageURLs(req.ImageURLs); err != nil {
+		logger.Error("Rejected photo attachments", err)
+		logger.End("Process Chat")
+		return nil, err
+	}
+
+	// Distress phrases alert caregivers even when the input is blocked below
+	emergencyInfo := cs.emergencyService.Detect(ctx, req.UserID, req.Message)
+
+	// Unsafe input (e.g. a relayed scam call) gets a safety reply without reaching the model
+	if verdict := cs.guardrailService.CheckInput(ctx, req.UserID, req.Message); verdict.Action == guardrail.ActionBlock {
+		logger.Success("Input blocked by guardrail")
+		logger.End("Process Chat")
+		return &models.ChatResponse{
+			ConversationID: uuid.New().String(),
+			Message:        req.Message,
+			Response:       verdict.Replacement,
+			Guardrail:      verdict.info(),
+			Emergency:      emergencyInfo,
+			VoiceSpeed:     prefs.VoiceSpeed,
+			CreatedAt:      time.Now(),
+		}, nil
+	}
+
+	// An assessment in progress takes the turn as the answer to its next item.
+	// Emergencies get a regular reply and leave the item pending.
+	if emergencyInfo == nil {
+		if progress, ok := cs.assessmentService.AnswerTurn(ctx, req.UserID, req.Message, time.Now()); ok {
+			logger.Success("Assessment answer recorded")
+			logger.End("Process Chat")
+			return &models.ChatResponse{
+				ConversationID: uuid.New().String(),
+				Message:        req.Message,
+				Response:       progress.Prompt,
+				VoiceSpeed:     prefs.VoiceSpeed,
+				Assessment:     progress,
+				CreatedAt:      time.Now(),
+			}, nil
+		}
+	}
+
+	// A confirmation ("네, 먹었어요") acknowledges the reminders the previous reply brought up
+	acknowledged := cs.reminderService.AcknowledgeConfirmed(ctx, req.UserID, req.Message, time.Now())
+
+	// Acknowledgements and greetings ("네", "고마워요") need no memories, unless they come with photos
+	if cs.cfg.SmallTalkFastPathEnabled && len(req.ImageURLs) == 0 && util.IsSmallTalk(req.Message) {
+		resp, err := cs.respondSmallTalk(ctx, req, emergencyInfo, prefs.Language)
+		if resp != nil {
+			resp.RemindersAcknowledged = acknowledged
+			resp.VoiceSpeed = prefs.VoiceSpeed
+			if resp.Guardrail == nil && resp.Emergency == nil {
+				resp.Suggestions = cs.suggestFollowUps(ctx, req, resp.Response, prefs.Language)
+			}
+		}
+		logger.End("Process Chat")
+		return resp, err
+	}
+
+	// Assign the user's experiment cohort before retrieval, which depends on its query strategy
+	cohort := cs.experimentService.CohortFor(req.UserID)
+	cohort.Model = cs.openaiService.EffectiveModel(chatTask(req), req.GenerationOverrides)
 
 	// Parallel fetch: conversations, profile, and incorrect attempts
-	searchRes := cs.fetchConversations(ctx, req)
-	profileRes := cs.fetchUserProfile(ctx, req)
-	incorrectAttemptsRes := cs.fetchIncorrectAttempts(ctx, req)
+	searchRes, profileRes, incorrectAttemptsRes := cs.fetchChatContext(ctx, req, cohort.QueryStrategy)
 
 	// Validate search results
 	if searchRes.err != nil {
-		cs.logger.Error("Failed to search conversations", searchRes.err)
-		cs.logger.End("Process Chat")
+		metrics.ChatContextFetchFailures.Add(metrics.SourceConversations, 1)
+		logger.Error("Failed to search conversations", searchRes.err)
+		logger.End("Process Chat")
 		return nil, fmt.Errorf("failed to search conversations: %w", searchRes.err)
 	}
 
 	// Log fetched data
-	cs.logFetchedData(searchRes, profileRes, incorrectAttemptsRes)
+	cs.logFetchedData(logger, searchRes, profileRes, incorrectAttemptsRes)
+	cs.recordFetchFailures(logger, profileRes, incorrectAttemptsRes)
+
+	// Build context from relevant search results
+	settings := cs.cfg.Runtime.Get()
+	relevantResults := cs.filterRelevantResults(searchRes.results)
+	contextMessages := cs.extractContextMessages(relevantResults)
+	maxScore := cs.extractMaxScore(relevantResults)
+
+	contextUsed := models.ContextUsage{
+		TotalConversations:      len(relevantResults),
+		TopScore:                maxScore,
+		ProfileLoaded:           profileRes.err == nil && profileRes.profile != nil,
+		IncorrectAttemptsLoaded: incorrectAttemptsRes.err == nil && incorrectAttemptsRes.attempts != nil,
+		QueryStrategy:           cohort.QueryStrategy,
+		SearchQuery:             searchRes.query,
+	}
+
+	reminders := cs.reminderService.Due(req.UserID, time.Now())
+
+	// A repeated question over unchanged context reuses the recent reply. Emergencies,
+	// due reminders, photos and requests with generation overrides always get a fresh reply.
+	fingerprint := ""
+	useCache := cs.semanticCache != nil && emergencyInfo == nil && len(reminders) == 0 && len(req.ImageURLs) == 0 && req.GenerationOverrides == (models.GenerationOverrides{})
+	if useCache {
+		fingerprint = contextFingerprint(cohort, prefs, contextMessages, profileRes, incorrectAttemptsRes)
+	}
+
+	// Everything from here on may reach the LLM, including the evaluation of a cached reply
+	contextMessages, profileRes, mask := cs.redactContext(contextMessages, profileRes)
+
+	if useCache {
+		if response, ok := cs.semanticCache.Lookup(req.UserID, req.Message, fingerprint, time.Now()); ok {
+			metrics.ChatSemanticCache.Add(metrics.SemanticCacheHit, 1)
+			resp := cs.respondCached(ctx, req, response, contextMessages, profileRes, cohort, contextUsed)
+			resp.RemindersAcknowledged = acknowledged
+			resp.VoiceSpeed = prefs.VoiceSpeed
+			resp.Suggestions = cs.suggestFollowUps(ctx, req, response, prefs.Language)
+			logger.Success("Chat answered from semantic cache")
+			logger.End("Process Chat")
+			return resp, nil
+		}
+		metrics.ChatSemanticCache.Add(metrics.SemanticCacheMiss, 1)
+	}
+
+	if settings.ContextRerankEnabled && len(contextMessages) > 0 {
+		reranked, err := cs.openaiService.RerankContext(ctx, req.Message, contextMessages)
+		if err != nil {
+			logger.Warn("Failed to rerank context, using score-filtered context", err)
+		} else {
+			contextMessages = reranked
+		}
+	}
+
+	// Summarize retrieved history for the prompt
+	previousSummary := ""
+	if settings.ContextSummaryEnabled && len(contextMessages) > 0 {
+		summary, err := cs.openaiService.SummarizeConversations(ctx, contextMessages)
+		if err != nil {
+			logger.Warn("Failed to summarize context, using extractive summary", err)
+		} else {
+			previousSummary = summary
+		}
+	}
+	if previousSummary == "" && len(contextMessages) > 0 {
+		previousSummary = prompts.ExtractiveSummary(contextMessages, 3)
+	}
+
+	promptData := cs.chatPromptData(req, cohort, contextMessages, previousSummary, profileRes, incorrectAttemptsRes, reminders)
+	profileInfo := promptData.ProfileInfo
+
+	// Generate response
+	logger.Section("Generating Response")
+	genCtx := WithGenerationOverrides(ctx, req.GenerationOverrides)
+	if mask != nil {
+		genCtx = WithToolRedaction(genCtx, cs.piiScrubber, mask)
+	}
+	finishShadow := cs.shadowService.Start(ctx, req, promptData, cohort.Model)
+	response, err := cs.openaiService.GenerateChatResponseWithProfile(genCtx, req.Message, promptData)
+	if finishShadow != nil {
+		// The shadow reply is compared with the reply as generated, before enforcement and guardrails
+		finishShadow(response, err)
+	}
+	if err != nil {
+		logger.Error("Failed to generate response", err)
+		logger.End("Process Chat")
+		if strings.HasPrefix(err.Error(), "context_too_large:") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to generate response: %w", err)
+	}
+
+	// Regenerate responses in the wrong language or touching blacklisted topics
+	response = cs.enforceLanguage(genCtx, req, response, promptData)
+	response = cs.enforceTopicBlacklist(genCtx, req, response, promptData)
+
+	// Identifiers the reply quotes by placeholder are restored locally
+	response = mask.Restore(response)
+
+	// Replace unsafe output (e.g. medication changes) with a safe reply
+	var guardrailInfo *models.GuardrailInfo
+	if verdict := cs.guardrailService.CheckOutput(ctx, req.UserID, response); verdict.Action != guardrail.ActionAllow {
+		response = verdict.Replacement
+		guardrailInfo = verdict.info()
+	}
 
-	// Build context from search results
-	contextMessages := cs.extractContextMessages(searchRes.results)
-	maxScore := cs.extractMaxScore(searchRes.results)
+	// Replies a guardrail changed are not reused
+	if useCache && guardrailInfo == nil {
+		cs.semanticCache.Store(req.UserID, req.Message, fingerprint, response, time.Now())
+	}
+
+	cs.planService.RecordTurn(req.UserID, promptData.Goal, req.Message, time.Now())
+
+	// Reminders count as brought up only when the reply reached the user as generated
+	var remindersMentioned []string
+	if guardrailInfo == nil && len(reminders) > 0 {
+		cs.reminderService.MarkMentioned(req.UserID, reminders, time.Now())
+		for _, reminder := range reminders {
+			remindersMentioned = append(remindersMentioned, reminder.ID)
+		}
+	}
+
+	// Create conversation ID
+	conversationID := uuid.New().String()
+
+	cs.experimentService.RecordChat(cohort, req.UserID)
+	evaluation := cs.evaluateInline(ctx, req, contextMessages, profileInfo, cohort)
+
+	// Evaluate user response and save asynchronously
+	go cs.evaluateAndSave(context.WithoutCancel(ctx), req, response, conversationID, contextMessages, profileInfo, cohort, evaluation)
 
-	// Extract profile and incorrect attempts
+	// Follow-ups would steer away from a safety reply or an emergency
+	var suggestions []string
+	if guardrailInfo == nil && emergencyInfo == nil {
+		suggestions = cs.suggestFollowUps(ctx, req, response, prefs.Language)
+	}
+
+	logger.Success("Chat processed successfully")
+	logger.End("Process Chat")
+
+	return &models.ChatResponse{
+		ConversationID:        conversationID,
+		Message:               req.Message,
+		Response:              response,
+		ContextUsed:           contextUsed,
+		Guardrail:             guardrailInfo,
+		Emergency:             emergencyInfo,
+		RemindersMentioned:    remindersMentioned,
+		RemindersAcknowledged: acknowledged,
+		VoiceSpeed:            prefs.VoiceSpeed,
+		Evaluation:            evaluation,
+		Suggestions:           suggestions,
+		CreatedAt:             time.Now(),
+	}, nil
+}
+
+// respondCached answers with a reply reused from the semantic cache. The turn is
+// still evaluated and saved: a question repeated within minutes is itself a
+// signal worth keeping in the user's history.
+func (cs *ChatService) respondCached(ctx context.Context, req *models.ChatRequest, response string, contextMessages []string, profileRes profileResult, cohort Cohort, contextUsed models.ContextUsage) *models.ChatResponse {
+	var profileInfo *models.PersonalInfoListResponse
+	if profileRes.err == nil {
+		profileInfo = profileRes.profile
+	}
+
+	conversationID := uuid.New().String()
+
+	cs.experimentService.RecordChat(cohort, req.UserID)
+
+	// The cached reply was not steered towards a goal, but the message may still touch one
+	cs.planService.RecordTurn(req.UserID, nil, req.Message, time.Now())
+	evaluation := cs.evaluateInline(ctx, req, contextMessages, profileInfo, cohort)
+
+	go cs.evaluateAndSave(context.WithoutCancel(ctx), req, response, conversationID, contextMessages, profileInfo, cohort, evaluation)
+
+	return &models.ChatResponse{
+		ConversationID: conversationID,
+		Message:        req.Message,
+		Response:       response,
+		ContextUsed:    contextUsed,
+		Cached:         true,
+		Evaluation:     evaluation,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// CompactSemanticCache removes expired cached replies and returns how many were
+// removed and the approximate bytes they held
+func (cs *ChatService) CompactSemanticCache(now time.Time) (int, int64) {
+	if cs.semanticCache == nil {
+		return 0, 0
+	}
+	return cs.semanticCache.Compact(now)
+}
+
+// SemanticCacheStats returns how many replies are cached and for how many users
+func (cs *ChatService) SemanticCacheStats() (int, int) {
+	if cs.semanticCache == nil {
+		return 0, 0
+	}
+	return cs.semanticCache.Stats()
+}
+
+// DeleteUserCache removes the user's cached replies and returns how many there were
+func (cs *ChatService) DeleteUserCache(userID string) int {
+	if cs.semanticCache == nil {
+		return 0
+	}
+	return cs.semanticCache.DeleteUser(userID)
+}
+
+// DryRunChat assembles the prompt and retrieved context a chat request would be answered
+// with, without calling the LLM. Retrieval runs against real user data; steps that need the
+// LLM or act on the request (guardrails, emergency alerts) are skipped and listed in the response.
+func (cs *ChatService) DryRunChat(ctx context.Context, req *models.ChatRequest) (*models.ChatPromptPreviewResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Dry Run Chat")
+
+	req.Message = textutil.Normalize(req.Message)
+
+	if err := cs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Dry Run Chat")
+		return nil, err
+	}
+
+	if err := validateImageURLs(req.ImageURLs); err != nil {
+		logger.Error("Rejected photo attachments", err)
+		logger.End("Dry Run Chat")
+		return nil, err
+	}
+
+	resp := &models.ChatPromptPreviewResponse{
+		Model:        cs.openaiService.EffectiveModel(chatTask(req), req.GenerationOverrides),
+		SkippedSteps: []string{util.DryRunSkippedGuardrail, util.DryRunSkippedEmergency},
+	}
+
+	if cs.cfg.SmallTalkFastPathEnabled && len(req.ImageURLs) == 0 && util.IsSmallTalk(req.Message) {
+		resp.Messages = toPromptMessages(smallTalkMessages(req.Message, cs.preferences(req.UserID).Language))
+		resp.SmallTalk = true
+		logger.Success("Small talk prompt assembled")
+		logger.End("Dry Run Chat")
+		return resp, nil
+	}
+
+	cohort := cs.experimentService.CohortFor(req.UserID)
+	queryStrategy := cohort.QueryStrategy
+	if queryStrategy == util.QueryStrategyLLM {
+		queryStrategy = util.QueryStrategyHybrid
+		resp.SkippedSteps = append(resp.SkippedSteps, util.DryRunSkippedQueryGeneration)
+	}
+
+	searchRes, profileRes, incorrectAttemptsRes := cs.fetchChatContext(ctx, req, queryStrategy)
+	if searchRes.err != nil {
+		logger.Error("Failed to search conversations", searchRes.err)
+		logger.End("Dry Run Chat")
+		return nil, fmt.Errorf("failed to search conversations: %w", searchRes.err)
+	}
+	cs.logFetchedData(logger, searchRes, profileRes, incorrectAttemptsRes)
+
+	settings := cs.cfg.Runtime.Get()
+	relevantResults := cs.filterRelevantResults(searchRes.results)
+	contextMessages := cs.extractContextMessages(relevantResults)
+	contextMessages, profileRes, _ = cs.redactContext(contextMessages, profileRes)
+
+	previousSummary := ""
+	if len(contextMessages) > 0 {
+		if settings.ContextRerankEnabled {
+			resp.SkippedSteps = append(resp.SkippedSteps, util.DryRunSkippedRerank)
+		}
+		if settings.ContextSummaryEnabled {
+			resp.SkippedSteps = append(resp.SkippedSteps, util.DryRunSkippedSummary)
+		}
+		previousSummary = prompts.ExtractiveSummary(contextMessages, 3)
+	}
+
+	promptData := cs.chatPromptData(req, cohort, contextMessages, previousSummary, profileRes, incorrectAttemptsRes, cs.reminderService.Due(req.UserID, time.Now()))
+
+	resp.Messages = toPromptMessages(chatMessages(req.Message, promptData))
+	resp.ContextMessages = contextMessages
+	resp.PreviousSummary = previousSummary
+	resp.ContextUsed = models.ContextUsage{
+		TotalConversations:      len(relevantResults),
+		TopScore:                cs.extractMaxScore(relevantResults),
+		ProfileLoaded:           promptData.ProfileInfo != nil,
+		IncorrectAttemptsLoaded: promptData.IncorrectAttempts != nil,
+		QueryStrategy:           queryStrategy,
+		SearchQuery:             searchRes.query,
+	}
+
+	logger.Success("Chat prompt assembled")
+	logger.End("Dry Run Chat")
+	return resp, nil
+}
+
+// acquireChatSlot takes one of the user's chat slots. Beyond the limit the
+// request waits for an earlier one to finish, or fails at once in reject mode,
+// with chat_in_progress. The returned release must be called when done.
+func (cs *ChatService) acquireChatSlot(ctx context.Context, userID string) (func(), error) {
+	if cs.userLimiter == nil {
+		return func() {}, nil
+	}
+	if release, ok := cs.userLimiter.tryAcquire(userID); ok {
+		return release, nil
+	}
+
+	if cs.cfg.ChatUserOverlapMode == util.ChatOverlapReject {
+		metrics.ChatUserLimited.Add(metrics.ChatUserRejected, 1)
+		return nil, fmt.Errorf("chat_in_progress: user %s already has a chat in progress", userID)
+	}
+
+	metrics.ChatUserLimited.Add(metrics.ChatUserQueued, 1)
+	waitCtx, cancel := context.WithTimeout(ctx, cs.cfg.ChatUserQueueTimeout)
+	defer cancel()
+
+	release, err := cs.userLimiter.acquire(waitCtx, userID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("chat_in_progress: user %s still had a chat in progress after %s", userID, cs.cfg.ChatUserQueueTimeout)
+	}
+	return release, nil
+}
+
+// redactContext masks personal identifiers in the retrieved context and the
+// profile, returning the mask to restore them in the reply. Without a scrubber
+// everything is returned unchanged with a nil mask.
+func (cs *ChatService) redactContext(contextMessages []string, profileRes profileResult) ([]string, profileResult, *pii.Mask) {
+	if cs.piiScrubber == nil {
+		return contextMessages, profileRes, nil
+	}
+
+	mask := pii.NewMask()
+	contextMessages = cs.piiScrubber.RedactAll(mask, contextMessages)
+
+	// The profile is copied: the fetched one may be shared with other requests
+	if profileRes.err == nil && profileRes.profile != nil {
+		profile := *profileRes.profile
+		profile.Items = make([]models.PersonalInfoResponse, len(profileRes.profile.Items))
+		for i, item := range profileRes.profile.Items {
+			item.Content = cs.piiScrubber.Redact(mask, item.Content)
+			profile.Items[i] = item
+		}
+		profileRes.profile = &profile
+	}
+	return contextMessages, profileRes, mask
+}
+
+// chatPromptData collects the prompt inputs for a turn. Profile and incorrect attempts
+// that failed to load are left out rather than failing the turn.
+func (cs *ChatService) chatPromptData(req *models.ChatRequest, cohort Cohort, contextMessages []string, previousSummary string, profileRes profileResult, incorrectAttemptsRes incorrectAttemptsResult, reminders []models.Reminder) prompts.ChatPromptData {
 	var profileInfo *models.PersonalInfoListResponse
 	if profileRes.err == nil && profileRes.profile != nil {
 		profileInfo = profileRes.profile
@@ -65,32 +538,216 @@ func (cs *ChatService) ProcessChat(ctx context.Context, req *models.ChatRequest)
 		incorrectAttempts = incorrectAttemptsRes.attempts
 	}
 
-	// Generate response
-	cs.logger.Section("Generating Response")
-	response, err := cs.openaiService.GenerateChatResponseWithProfile(ctx, req.Message, contextMessages, profileInfo, incorrectAttempts)
+	prefs := cs.preferences(req.UserID)
+	return prompts.ChatPromptData{
+		ContextMessages:   contextMessages,
+		PreviousSummary:   previousSummary,
+		ProfileInfo:       profileInfo,
+		IncorrectAttempts: incorrectAttempts,
+		GriefSensitive:    cs.isGriefSensitive(req, profileInfo),
+		Goal:              cs.planService.ActiveGoal(req.UserID, time.Now()),
+		Reminders:         reminders,
+		ImageURLs:         req.ImageURLs,
+		Now:               time.Now(),
+		Language:          prefs.Language,
+		Persona:           prefs.Persona,
+		Variant:           cohort.PromptVersion,
+	}
+}
+
+// respondSmallTalk answers small talk with a minimal prompt. Retrieval, evaluation
+// and saving are skipped: the turn carries no memories and would only add noise to
+// later conversation searches.
+func (cs *ChatService) respondSmallTalk(ctx context.Context, req *models.ChatRequest, emergencyInfo *models.EmergencyInfo, language string) (*models.ChatResponse, error) {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Section("Small Talk Fast Path")
+
+	cohort := cs.experimentService.CohortFor(req.UserID)
+	cohort.Model = cs.openaiService.EffectiveModel(util.LLMTaskChat, req.GenerationOverrides)
+
+	genCtx := WithGenerationOverrides(ctx, req.GenerationOverrides)
+	response, err := cs.openaiService.GenerateSmallTalkResponse(genCtx, req.Message, language)
 	if err != nil {
-		cs.logger.Error("Failed to generate response", err)
-		cs.logger.End("Process Chat")
+		logger.Error("Failed to generate small talk response", err)
+		if strings.HasPrefix(err.Error(), "context_too_large:") {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to generate response: %w", err)
 	}
 
-	// Create conversation ID
-	conversationID := uuid.New().String()
+	var guardrailInfo *models.GuardrailInfo
+	if verdict := cs.guardrailService.CheckOutput(ctx, req.UserID, response); verdict.Action != guardrail.ActionAllow {
+		response = verdict.Replacement
+		guardrailInfo = verdict.info()
+	}
 
-	// Evaluate user response and save asynchronously
-	go cs.evaluateAndSave(context.Background(), req, response, conversationID, contextMessages, profileInfo)
+	cs.experimentService.RecordChat(cohort, req.UserID)
+	metrics.ChatSmallTalkResponses.Add(1)
 
-	cs.logger.Success("Chat processed successfully")
-	cs.logger.End("Process Chat")
+	logger.Success("Small talk answered")
 
 	return &models.ChatResponse{
-		ConversationID: conversationID,
+		ConversationID: uuid.New().String(),
 		Message:        req.Message,
 		Response:       response,
-		ContextUsed: models.ContextUsage{
-			TotalConversations: len(searchRes.results),
-			TopScore:           maxScore,
-		},
+		Guardrail:      guardrailInfo,
+		Emergency:      emergencyInfo,
+		SmallTalk:      true,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// MoodTimeline returns the user's mood over recent chat turns
+func (cs *ChatService) MoodTimeline(ctx context.Context, req *models.MoodTimelineRequest) *models.MoodTimelineResponse {
+	ctx = util.WithUserID(ctx, req.UserID)
+	return cs.moodService.Timeline(ctx, req)
+}
+
+// CorrectConversation stores a caregiver correction for an assistant statement.
+// Corrections are kept with the user's personal info so they are loaded with the
+// profile and injected as guardrails into future chat prompts.
+func (cs *ChatService) CorrectConversation(ctx context.Context, conversationID string, req *models.ConversationCorrectionRequest) (*models.ConversationCorrectionResponse, error) {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Correct Conversation")
+
+	createReq := &models.PersonalInfoCreateRequest{
+		UserID:     req.UserID,
+		Content:    fmt.Sprintf("[%s] 잘못된 내용: %s | 바른 내용: %s", conversationID, req.IncorrectStatement, req.Correction),
+		Category:   util.CategoryCorrection,
+		Importance: "high",
+	}
+
+	correctionID, err := cs.ragClient.CreatePersonalInfo(ctx, createReq)
+	if err != nil {
+		logger.Error("Failed to store correction", err)
+		logger.End("Correct Conversation")
+		return nil, fmt.Errorf("failed to store correction: %w", err)
+	}
+
+	logger.KeyValue("Conversation ID", conversationID, "Correction ID", correctionID)
+	logger.Success("Correction stored")
+	logger.End("Correct Conversation")
+
+	return &models.ConversationCorrectionResponse{
+		CorrectionID:   correctionID,
+		ConversationID: conversationID,
+		UserID:         req.UserID,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// AddAvoidTopic registers a topic the assistant must avoid for a user
+func (cs *ChatService) AddAvoidTopic(ctx context.Context, userID string, req *models.AvoidTopicRequest) (*models.AvoidTopicResponse, error) {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Add Avoid Topic")
+
+	createReq := &models.PersonalInfoCreateRequest{
+		UserID:     userID,
+		Content:    textutil.Normalize(req.Topic),
+		Category:   util.CategoryAvoidTopic,
+		Importance: "high",
+	}
+
+	topicID, err := cs.ragClient.CreatePersonalInfo(ctx, createReq)
+	if err != nil {
+		logger.Error("Failed to store avoid topic", err)
+		logger.End("Add Avoid Topic")
+		return nil, fmt.Errorf("failed to store avoid topic: %w", err)
+	}
+
+	logger.Success("Avoid topic stored")
+	logger.End("Add Avoid Topic")
+
+	return &models.AvoidTopicResponse{
+		TopicID:   topicID,
+		UserID:    userID,
+		Topic:     createReq.Content,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// AddPin stores a caregiver-pinned fact that is included in every chat prompt for the
+// user. Pins are capped at MaxPinnedItems since they are never cut from the prompt.
+func (cs *ChatService) AddPin(ctx context.Context, userID string, req *models.PinRequest) (*models.PinResponse, error) {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Add Pin")
+
+	profile, err := cs.ragClient.GetPersonalInfoByUser(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to fetch profile", err)
+		logger.End("Add Pin")
+		return nil, fmt.Errorf("failed to fetch profile: %w", err)
+	}
+
+	pinned := 0
+	for _, item := range profile.Items {
+		if item.Category == util.CategoryPinned {
+			pinned++
+		}
+	}
+	if pinned >= util.MaxPinnedItems {
+		err := fmt.Errorf("pin_limit: user %s already has %d pinned items", userID, pinned)
+		logger.Error("Pin limit reached", err)
+		logger.End("Add Pin")
+		return nil, err
+	}
+
+	createReq := &models.PersonalInfoCreateRequest{
+		UserID:     userID,
+		Content:    textutil.Normalize(req.Content),
+		Category:   util.CategoryPinned,
+		Importance: "high",
+	}
+
+	pinID, err := cs.ragClient.CreatePersonalInfo(ctx, createReq)
+	if err != nil {
+		logger.Error("Failed to store pin", err)
+		logger.End("Add Pin")
+		return nil, fmt.Errorf("failed to store pin: %w", err)
+	}
+
+	logger.Success("Pin stored")
+	logger.End("Add Pin")
+
+	return &models.PinResponse{
+		PinID:     pinID,
+		UserID:    userID,
+		Content:   createReq.Content,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// SetCareMode flags a user for a special prompt mode such as grief-sensitive guidance
+func (cs *ChatService) SetCareMode(ctx context.Context, userID string, req *models.CareModeRequest) (*models.CareModeResponse, error) {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Set Care Mode")
+
+	createReq := &models.PersonalInfoCreateRequest{
+		UserID:     userID,
+		Content:    req.Mode,
+		Category:   util.CategoryCareMode,
+		Importance: "high",
+	}
+
+	id, err := cs.ragClient.CreatePersonalInfo(ctx, createReq)
+	if err != nil {
+		logger.Error("Failed to store care mode", err)
+		logger.End("Set Care Mode")
+		return nil, fmt.Errorf("failed to store care mode: %w", err)
+	}
+
+	logger.Success("Care mode stored")
+	logger.End("Set Care Mode")
+
+	return &models.CareModeResponse{
+		ID:        id,
+		UserID:    userID,
+		Mode:      req.Mode,
 		CreatedAt: time.Now(),
 	}, nil
 }
@@ -100,6 +757,7 @@ func (cs *ChatService) ProcessChat(ctx context.Context, req *models.ChatRequest)
 // ============================================================================
 
 type searchResult struct {
+	query   string
 	results []*models.RAGConversationSearchResult
 	err     error
 }
@@ -114,61 +772,194 @@ type incorrectAttemptsResult struct {
 	err      error
 }
 
-func (cs *ChatService) fetchConversations(ctx context.Context, req *models.ChatRequest) searchResult {
-	results := make(chan searchResult, 1)
-	go func() {
-		rag, err := cs.ragClient.SearchConversations(ctx, req.Message, 5)
-		results <- searchResult{results: cs.convertToPointers(rag), err: err}
-	}()
-	return <-results
+// fetchChatContext runs the three RAG fetches concurrently under a single
+// combined timeout. Only a failed conversation search cancels the others,
+// since profile and incorrect attempts are optional for the prompt.
+func (cs *ChatService) fetchChatContext(ctx context.Context, req *models.ChatRequest, queryStrategy string) (searchResult, profileResult, incorrectAttemptsResult) {
+	fetchCtx, cancel := context.WithTimeout(ctx, cs.cfg.RAGServerTimeout)
+	defer cancel()
+
+	var (
+		searchRes            searchResult
+		profileRes           profileResult
+		incorrectAttemptsRes incorrectAttemptsResult
+	)
+
+	profileDone := make(chan struct{})
+
+	g, gctx := errgroup.WithContext(fetchCtx)
+	g.Go(func() error {
+		// The llm and hybrid strategies build the query from the profile, so they wait for it
+		query := req.Message
+		if queryStrategy != util.QueryStrategyRaw {
+			<-profileDone
+			query = cs.buildSearchQuery(gctx, queryStrategy, req.Message, profileRes)
+		}
+
+		searchRes = cs.fetchConversations(gctx, query)
+		return searchRes.err
+	})
+	g.Go(func() error {
+		defer close(profileDone)
+		profileRes = cs.fetchUserProfile(gctx, req)
+		return nil
+	})
+	g.Go(func() error {
+		incorrectAttemptsRes = cs.fetchIncorrectAttempts(gctx, req)
+		return nil
+	})
+	_ = g.Wait()
+
+	return searchRes, profileRes, incorrectAttemptsRes
+}
+
+func (cs *ChatService) fetchConversations(ctx context.Context, query string) searchResult {
+	rag, err := cs.ragClient.SearchConversations(ctx, query, 5)
+	return searchResult{query: query, results: cs.convertToPointers(rag), err: err}
+}
+
+// buildSearchQuery builds the conversation search query for the strategy. Short replies
+// like "응" retrieve unrelated history when searched as typed, so the llm and hybrid
+// strategies add context from the profile. Any failure falls back to the raw message.
+func (cs *ChatService) buildSearchQuery(ctx context.Context, strategy, message string, profileRes profileResult) string {
+	var profile *models.PersonalInfoListResponse
+	if profileRes.err == nil {
+		profile = profileRes.profile
+	}
+
+	switch strategy {
+	case util.QueryStrategyLLM:
+		query, err := cs.openaiService.GenerateSearchQuery(ctx, message, profile)
+		if err != nil {
+			cs.logger.WithContext(ctx).Warn("Failed to generate search query, using raw message", err)
+			return message
+		}
+		return query
+	case util.QueryStrategyHybrid:
+		if keywords := profileKeywords(profile, message); len(keywords) > 0 {
+			return message + " " + strings.Join(keywords, " ")
+		}
+	}
+	return message
+}
+
+// profileKeywords returns up to MaxQueryProfileKeywords distinct words from the user's
+// profile facts, high-importance items first, skipping words already in the message.
+// Service records (corrections, avoid topics, care mode, contacts) are not searched for.
+func profileKeywords(profile *models.PersonalInfoListResponse, message string) []string {
+	if profile == nil {
+		return nil
+	}
+
+	items := make([]models.PersonalInfoResponse, 0, len(profile.Items))
+	for _, item := range profile.Items {
+		switch item.Category {
+		case util.CategoryCorrection, util.CategoryAvoidTopic, util.CategoryCareMode, util.CategoryEmergency:
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Importance == "high" && items[j].Importance != "high"
+	})
+
+	seen := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(message)) {
+		seen[word] = true
+	}
+
+	keywords := []string{}
+	for _, item := range items {
+		for _, word := range strings.FieldsFunc(strings.ToLower(item.Content), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			if utf8.RuneCountInString(word) < 2 || seen[word] {
+				continue
+			}
+			seen[word] = true
+			keywords = append(keywords, word)
+			if len(keywords) >= util.MaxQueryProfileKeywords {
+				return keywords
+			}
+		}
+	}
+	return keywords
 }
 
 func (cs *ChatService) fetchUserProfile(ctx context.Context, req *models.ChatRequest) profileResult {
-	results := make(chan profileResult, 1)
-	go func() {
-		profile, err := cs.ragClient.GetPersonalInfoByUser(ctx, req.UserID)
-		results <- profileResult{profile: profile, err: err}
-	}()
-	return <-results
+	profile, err := cs.ragClient.GetPersonalInfoByUser(ctx, req.UserID)
+	return profileResult{profile: profile, err: err}
 }
 
 func (cs *ChatService) fetchIncorrectAttempts(ctx context.Context, req *models.ChatRequest) incorrectAttemptsResult {
-	results := make(chan incorrectAttemptsResult, 1)
-	go func() {
-		attempts, err := cs.ragClient.GetIncorrectQuizAttempts(ctx, req.UserID, 5)
-		results <- incorrectAttemptsResult{attempts: attempts, err: err}
-	}()
-	return <-results
+	attempts, err := cs.ragClient.GetIncorrectQuizAttempts(ctx, req.UserID, 5)
+	return incorrectAttemptsResult{attempts: attempts, err: err}
 }
 
 // ============================================================================
 // Helper Methods - Data Processing
 // ============================================================================
 
-func (cs *ChatService) logFetchedData(searchRes searchResult, profileRes profileResult, incorrectAttemptsRes incorrectAttemptsResult) {
-	cs.logger.Section("RAG Conversation Search Results")
-	cs.logger.KeyValue("Total conversations", len(searchRes.results))
+func (cs *ChatService) logFetchedData(logger *util.Logger, searchRes searchResult, profileRes profileResult, incorrectAttemptsRes incorrectAttemptsResult) {
+	logger.Section("RAG Conversation Search Results")
+	logger.KeyValue("Total conversations", len(searchRes.results))
 
 	if profileRes.err == nil && profileRes.profile != nil {
-		cs.logger.Section("Personal Info")
-		cs.logger.Info("Profile info found: %d items", len(profileRes.profile.Items))
+		logger.Section("Personal Info")
+		logger.Info("Profile info found: %d items", len(profileRes.profile.Items))
 	}
 
 	if incorrectAttemptsRes.err == nil && incorrectAttemptsRes.attempts != nil {
-		cs.logger.Section("Incorrect Quiz Attempts")
-		cs.logger.Info("Found %d incorrect attempts", len(incorrectAttemptsRes.attempts.Items))
+		logger.Section("Incorrect Quiz Attempts")
+		logger.Info("Found %d incorrect attempts", len(incorrectAttemptsRes.attempts.Items))
 	}
 }
 
+// recordFetchFailures reports optional context that failed to load. The reply is still
+// generated, but it is less personalized, so the failure is counted rather than only logged.
+func (cs *ChatService) recordFetchFailures(logger *util.Logger, profileRes profileResult, incorrectAttemptsRes incorrectAttemptsResult) {
+	if profileRes.err != nil {
+		logger.Warn("Failed to load user profile, responding without it", profileRes.err)
+		metrics.ChatContextFetchFailures.Add(metrics.SourceProfile, 1)
+	}
+
+	if incorrectAttemptsRes.err != nil {
+		logger.Warn("Failed to load incorrect attempts, responding without them", incorrectAttemptsRes.err)
+		metrics.ChatContextFetchFailures.Add(metrics.SourceIncorrectAttempts, 1)
+	}
+
+	if profileRes.err != nil || incorrectAttemptsRes.err != nil {
+		metrics.ChatDegradedResponses.Add(1)
+	}
+}
+
+// filterRelevantResults drops search results scoring below the configured similarity threshold
+func (cs *ChatService) filterRelevantResults(results []*models.RAGConversationSearchResult) []*models.RAGConversationSearchResult {
+	minScore := cs.cfg.Runtime.Get().ContextMinScore
+	relevant := []*models.RAGConversationSearchResult{}
+	for _, result := range results {
+		if result != nil && result.Score >= minScore {
+			relevant = append(relevant, result)
+		}
+	}
+	return relevant
+}
+
 func (cs *ChatService) extractContextMessages(results []*models.RAGConversationSearchResult) []string {
+	maxMessages := cs.cfg.Runtime.Get().ContextMaxMessagesPerConversation
 	contextMessages := []string{}
 	for _, result := range results {
 		if result == nil {
 			continue
 		}
+		count := 0
 		for _, msg := range result.Messages {
+			if maxMessages > 0 && count >= maxMessages {
+				break
+			}
 			if msg.Role == "user" || msg.Role == "assistant" {
 				contextMessages = append(contextMessages, msg.Content)
+				count++
 			}
 		}
 	}
@@ -185,51 +976,302 @@ func (cs *ChatService) extractMaxScore(results []*models.RAGConversationSearchRe
 	return maxScore
 }
 
+// ============================================================================
+// Helper Methods - Output Language
+// ============================================================================
+
+// preferences returns the user's registry preferences, with the server's
+// language when the user has none
+func (cs *ChatService) preferences(userID string) models.UserPreferences {
+	prefs := cs.userService.Preferences(userID)
+	if prefs.Language == "" {
+		prefs.Language = cs.cfg.ChatLanguage
+	}
+	return prefs
+}
+
+// maxLanguageRegenerations bounds how many times a response is regenerated for using the wrong language
+const maxLanguageRegenerations = 2
+
+func (cs *ChatService) enforceLanguage(ctx context.Context, req *models.ChatRequest, response string, promptData prompts.ChatPromptData) string {
+	logger := cs.logger.WithContext(ctx)
+
+	if promptData.Language == "" {
+		return response
+	}
+
+	for attempt := 0; attempt < maxLanguageRegenerations; attempt++ {
+		if util.IsExpectedLanguage(response, promptData.Language) {
+			return response
+		}
+
+		logger.Warn("Response in unexpected language, regenerating", fmt.Errorf("expected %s, detected %s", promptData.Language, util.DetectLanguage(response)))
+		regenerated, err := cs.openaiService.GenerateChatResponseWithProfile(ctx, req.Message, promptData)
+		if err != nil {
+			logger.Warn("Failed to regenerate response", err)
+			break
+		}
+		response = regenerated
+	}
+
+	return response
+}
+
+// ============================================================================
+// Helper Methods - Topic Blacklist
+// ============================================================================
+
+// maxTopicRegenerations bounds how many times a response is regenerated for touching a blacklisted topic
+const maxTopicRegenerations = 2
+
+// topicBlacklistFallbackResponse is returned when regeneration keeps touching blacklisted topics
+const topicBlacklistFallbackResponse = "그렇군요. 오늘은 어떻게 지내셨어요?"
+
+// enforceTopicBlacklist regenerates a response that touches blacklisted topics, naming the
+// violated topics in the prompt so the retry avoids them, and falls back to a canned reply
+// when every retry still touches one
+func (cs *ChatService) enforceTopicBlacklist(ctx context.Context, req *models.ChatRequest, response string, promptData prompts.ChatPromptData) string {
+	logger := cs.logger.WithContext(ctx)
+
+	avoidTopics := cs.extractAvoidTopics(promptData.ProfileInfo)
+	if len(avoidTopics) == 0 {
+		return response
+	}
+
+	for attempt := 0; attempt < maxTopicRegenerations; attempt++ {
+		violated := findTopics(response, avoidTopics)
+		if len(violated) == 0 {
+			return response
+		}
+
+		logger.Warn("Response touched blacklisted topics, regenerating", fmt.Errorf("%v", violated))
+		for _, topic := range violated {
+			if !slices.Contains(promptData.ViolatedTopics, topic) {
+				promptData.ViolatedTopics = append(promptData.ViolatedTopics, topic)
+			}
+		}
+		regenerated, err := cs.openaiService.GenerateChatResponseWithProfile(ctx, req.Message, promptData)
+		if err != nil {
+			logger.Warn("Failed to regenerate response", err)
+			break
+		}
+		response = regenerated
+	}
+
+	if violated := findTopics(response, avoidTopics); len(violated) > 0 {
+		logger.Warn("Response still touches blacklisted topics, using fallback", fmt.Errorf("%v", violated))
+		return topicBlacklistFallbackResponse
+	}
+	return response
+}
+
+// isGriefSensitive selects the grief-sensitive prompt when the user is flagged for it
+// or when the user gently brings up a blacklisted topic themselves
+func (cs *ChatService) isGriefSensitive(req *models.ChatRequest, profileInfo *models.PersonalInfoListResponse) bool {
+	if profileInfo == nil {
+		return false
+	}
+	for _, item := range profileInfo.Items {
+		if item.Category == util.CategoryCareMode && item.Content == util.CareModeGriefSensitive {
+			return true
+		}
+	}
+	return len(findTopics(req.Message, cs.extractAvoidTopics(profileInfo))) > 0
+}
+
+func (cs *ChatService) extractAvoidTopics(profileInfo *models.PersonalInfoListResponse) []string {
+	topics := []string{}
+	if profileInfo == nil {
+		return topics
+	}
+	for _, item := range profileInfo.Items {
+		if item.Category == util.CategoryAvoidTopic && item.Content != "" {
+			topics = append(topics, item.Content)
+		}
+	}
+	return topics
+}
+
+// findTopics returns the topics mentioned in text (case-insensitive)
+func findTopics(text string, topics []string) []string {
+	lowered := strings.ToLower(text)
+	found := []string{}
+	for _, topic := range topics {
+		if strings.Contains(lowered, strings.ToLower(topic)) {
+			found = append(found, topic)
+		}
+	}
+	return found
+}
+
 // ============================================================================
 // Helper Methods - Async Processing
 // ============================================================================
 
-func (cs *ChatService) evaluateAndSave(ctx context.Context, req *models.ChatRequest, response, conversationID string, contextMessages []string, profileInfo *models.PersonalInfoListResponse) {
-	cs.logger.Start("Async: Evaluate and Save")
+// evaluateInline evaluates the message before the reply is returned when the request
+// asks for its evaluation. It returns nil when not asked, when evaluation is switched
+// off in safe mode, or when it fails, in which case the save evaluates it again.
+func (cs *ChatService) evaluateInline(ctx context.Context, req *models.ChatRequest, contextMessages []string, profileInfo *models.PersonalInfoListResponse, cohort Cohort) *models.ChatEvaluation {
+	if !req.IncludeEvaluation || cs.cfg.Runtime.Get().FeatureDisabled(util.FeatureEvaluation) {
+		return nil
+	}
+	return cs.evaluateQuality(ctx, req, contextMessages, profileInfo, cohort)
+}
 
-	// Evaluate user response quality
-	responseScore := util.DefaultResponseScore
+// suggestFollowUps returns lines to offer if the user goes silent after the reply,
+// when the request asks for them. It returns nil when not asked, when suggestions
+// are switched off in safe mode, or when they fail: the reply is sent regardless.
+func (cs *ChatService) suggestFollowUps(ctx context.Context, req *models.ChatRequest, response, language string) []string {
+	if !req.IncludeSuggestions || cs.cfg.Runtime.Get().FeatureDisabled(util.FeatureSuggestions) {
+		return nil
+	}
+
+	suggestions, err := cs.openaiService.SuggestFollowUps(ctx, req.Message, response, language)
+	if err != nil {
+		cs.logger.WithContext(ctx).Warn("Failed to suggest follow-ups", err)
+		return nil
+	}
+	return suggestions
+}
+
+// evaluateQuality scores the quality of the user's message, or returns nil when the evaluation fails
+func (cs *ChatService) evaluateQuality(ctx context.Context, req *models.ChatRequest, contextMessages []string, profileInfo *models.PersonalInfoListResponse, cohort Cohort) *models.ChatEvaluation {
 	score, err := cs.openaiService.EvaluateUserResponseQuality(ctx, req.Message, contextMessages, profileInfo)
 	if err != nil {
-		cs.logger.Warn("Failed to evaluate response quality, using default", err)
+		cs.logger.WithContext(ctx).Warn("Failed to evaluate response quality", err)
+		return nil
+	}
+	cs.experimentService.RecordEvaluation(cohort, req.UserID, score)
+	return &models.ChatEvaluation{Score: score, ScoreVersion: prompts.PromptVersion}
+}
+
+// evaluateAndSave scores the turn and saves it to RAG. A message already
+// evaluated inline is saved with that evaluation instead of a new one.
+func (cs *ChatService) evaluateAndSave(ctx context.Context, req *models.ChatRequest, response, conversationID string, contextMessages []string, profileInfo *models.PersonalInfoListResponse, cohort Cohort, evaluation *models.ChatEvaluation) {
+	logger := cs.logger.WithContext(ctx)
+
+	logger.Start("Async: Evaluate and Save")
+
+	// Evaluate user response quality and score the emotional state of the turn for the
+	// caregiver mood timeline, unless evaluation is switched off in safe mode
+	responseScore, scoreVersion := util.DefaultResponseScore, ""
+	var moodScore *int
+	var emotion string
+	var topics []string
+	if cs.cfg.Runtime.Get().FeatureDisabled(util.FeatureEvaluation) {
+		logger.Info("Evaluation disabled, saving with default score, without mood and topics")
 	} else {
-		responseScore = score
+		if evaluation == nil {
+			evaluation = cs.evaluateQuality(ctx, req, contextMessages, profileInfo, cohort)
+		}
+		if evaluation != nil {
+			responseScore, scoreVersion = evaluation.Score, evaluation.ScoreVersion
+		} else {
+			logger.Info("Saving with default score")
+		}
+
+		sentiment, err := cs.openaiService.AnalyzeSentiment(ctx, req.Message, response)
+		if err != nil {
+			logger.Warn("Failed to analyze sentiment, saving without mood", err)
+		} else {
+			moodScore, emotion = &sentiment.MoodScore, sentiment.Emotion
+			cs.moodService.Record(req.UserID, conversationID, sentiment)
+		}
+
+		// Tagged once here so retrieval and domain analysis can filter by topic
+		if topics, err = cs.openaiService.ClassifyTopics(ctx, req.Message, response); err != nil {
+			logger.Warn("Failed to classify topics, saving without topics", err)
+		}
 	}
 
-	// Save conversation to RAG
+	// Save conversation to RAG, with the attached photos described so later
+	// retrieval and questions can draw on what they showed
 	saveReq := &models.RAGConversationSaveRequest{
 		ConversationID: conversationID,
 		Messages: []models.RAGMessage{
-			{Role: "user", Content: req.Message},
+			{Role: "user", Content: cs.describeAttachedPhotos(ctx, req)},
 			{Role: "assistant", Content: response},
 		},
 		Metadata: &models.RAGMetadata{
 			Source:            "llm_chat",
 			SessionID:         req.UserID,
-			Type:              "chat",
+			Type:              util.ConversationTypeChat,
 			ConversationScore: responseScore,
+			ScoreVersion:      scoreVersion,
+			PromptVariant:     cohort.PromptVersion,
+			Model:             cohort.Model,
+			QueryStrategy:     cohort.QueryStrategy,
+			MoodScore:         moodScore,
+			Emotion:           emotion,
+			Topics:            topics,
+			Language:          cs.preferences(req.UserID).Language,
+			SourceChannel:     sourceChannel(req),
 		},
 	}
 
-	_, err = cs.ragClient.SaveConversation(ctx, saveReq)
-	if err != nil {
-		cs.logger.Warn("Failed to save conversation", err)
+	if _, err := cs.ragClient.SaveConversation(ctx, saveReq); err != nil {
+		logger.Warn("Failed to save conversation", err)
 	} else {
-		cs.logger.Success(fmt.Sprintf("Conversation saved with quality score: %d/100", responseScore))
+		logger.Success(fmt.Sprintf("Conversation saved with quality score: %d/100", responseScore))
 	}
 
-	cs.logger.End("Async: Evaluate and Save")
+	logger.End("Async: Evaluate and Save")
+}
+
+// describeAttachedPhotos returns the user's message followed by a description of each
+// attached photo. Photos the vision model fails to describe are noted without one.
+func (cs *ChatService) describeAttachedPhotos(ctx context.Context, req *models.ChatRequest) string {
+	content := req.Message
+	for _, imageURL := range req.ImageURLs {
+		description, err := cs.openaiService.DescribePhoto(ctx, imageURL)
+		if err != nil {
+			cs.logger.WithContext(ctx).Warn("Failed to describe attached photo", err)
+			content += "\n[사진]"
+			continue
+		}
+		content += fmt.Sprintf("\n[사진: %s]", description)
+	}
+	return content
 }
 
 // ============================================================================
 // Utility Methods
 // ============================================================================
 
+// sourceChannel returns the channel the chat request arrived through
+func sourceChannel(req *models.ChatRequest) string {
+	if req.Channel == "" {
+		return util.SourceChannelAPI
+	}
+	return req.Channel
+}
+
+// chatTask returns the LLM task a chat request is answered with: turns with
+// photos need the vision model
+func chatTask(req *models.ChatRequest) string {
+	if len(req.ImageURLs) > 0 {
+		return util.LLMTaskVision
+	}
+	return util.LLMTaskChat
+}
+
+// validateImageURLs checks that every attached photo is an http(s) URL or a data:image/ URI
+func validateImageURLs(imageURLs []string) error {
+	for i, imageURL := range imageURLs {
+		if strings.HasPrefix(imageURL, "data:image/") {
+			if !strings.Contains(imageURL, ";base64,") {
+				return fmt.Errorf("invalid_image: image %d must be a base64 data URI", i+1)
+			}
+			continue
+		}
+		parsed, err := url.Parse(imageURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid_image: image %d must be an http(s) URL or a data:image/ URI", i+1)
+		}
+	}
+	return nil
+}
+
 func (cs *ChatService) convertToPointers(results []models.RAGConversationSearchResult) []*models.RAGConversationSearchResult {
 	pointers := make([]*models.RAGConversationSearchResult, len(results))
 	for i := range results {
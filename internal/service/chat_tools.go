@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+
+	"llm/internal/client"
+	"llm/internal/metrics"
+	"llm/internal/pii"
+	"llm/internal/textutil"
+	"llm/internal/util"
+	"llm/internal/weather"
+)
+
+// ChatTool is a function the chat model may call mid-conversation. Execute
+// receives the user the chat is for and the model's JSON arguments, and
+// returns the result sent back to the model.
+type ChatTool struct {
+	Name        string
+	Description string
+	Parameters  jsonschema.Definition
+	Execute     func(ctx context.Context, userID, arguments string) (string, error)
+}
+
+// ToolRegistry holds the tools offered to the chat model. Tools may be
+// registered after the registry is handed to OpenAIService.
+type ToolRegistry struct {
+	tools []ChatTool
+	mutex sync.RWMutex
+}
+
+// NewToolRegistry creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{}
+}
+
+// Register adds the tool, replacing a registered tool of the same name
+func (tr *ToolRegistry) Register(tool ChatTool) {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	for i, registered := range tr.tools {
+		if registered.Name == tool.Name {
+			tr.tools[i] = tool
+			return
+		}
+	}
+	tr.tools = append(tr.tools, tool)
+}
+
+// Len returns how many tools are registered
+func (tr *ToolRegistry) Len() int {
+	if tr == nil {
+		return 0
+	}
+
+	tr.mutex.RLock()
+	defer tr.mutex.RUnlock()
+
+	return len(tr.tools)
+}
+
+// definitions returns the registered tools in the form the chat completion API takes
+func (tr *ToolRegistry) definitions() []openai.Tool {
+	tr.mutex.RLock()
+	defer tr.mutex.RUnlock()
+
+	definitions := make([]openai.Tool, 0, len(tr.tools))
+	for _, tool := range tr.tools {
+		definitions = append(definitions, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	return definitions
+}
+
+// execute runs the tool a call names and returns its result. Failures are
+// returned as an error message for the model, which can then answer without it.
+func (tr *ToolRegistry) execute(ctx context.Context, call openai.ToolCall) (string, error) {
+	tr.mutex.RLock()
+	var tool *ChatTool
+	for i := range tr.tools {
+		if tr.tools[i].Name == call.Function.Name {
+			tool = &tr.tools[i]
+			break
+		}
+	}
+	tr.mutex.RUnlock()
+
+	if tool == nil {
+		return toolError(fmt.Errorf("unknown tool %q", call.Function.Name))
+	}
+
+	userID := util.UserIDFromContext(ctx)
+	if userID == "" {
+		return toolError(fmt.Errorf("no user for tool %s", tool.Name))
+	}
+
+	result, err := tool.Execute(ctx, userID, call.Function.Arguments)
+	if err != nil {
+		metrics.ChatToolCalls.Add(tool.Name+"."+metrics.ChatToolError, 1)
+		return toolError(err)
+	}
+	metrics.ChatToolCalls.Add(tool.Name+"."+metrics.ChatToolOK, 1)
+	if redaction, ok := ctx.Value(toolRedactionKey{}).(toolRedaction); ok {
+		result = redaction.scrubber.Redact(redaction.mask, result)
+	}
+	return result, nil
+}
+
+type toolRedactionKey struct{}
+
+// toolRedaction masks tool results with the turn's scrubber and mask
+type toolRedaction struct {
+	scrubber *pii.Scrubber
+	mask     *pii.Mask
+}
+
+// WithToolRedaction returns a copy of ctx whose tool results are masked into the turn's mask
+// before they reach the model, so identifiers a reply quotes from them are restored like those
+// from the retrieved context
+func WithToolRedaction(ctx context.Context, scrubber *pii.Scrubber, mask *pii.Mask) context.Context {
+	return context.WithValue(ctx, toolRedactionKey{}, toolRedaction{scrubber: scrubber, mask: mask})
+}
+
+// toolError formats a failed tool call as the result sent back to the model
+func toolError(err error) (string, error) {
+	content, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(content), err
+}
+
+// WeatherTool looks up today's weather for the user's area
+func WeatherTool(forecasts *weather.Client) ChatTool {
+	return ChatTool{
+		Name:        util.ChatToolWeather,
+		Description: "오늘의 날씨(날씨 상태, 최고/최저 기온, 강수 확률)를 조회합니다. 사용자가 날씨를 묻거나 외출, 빨래, 옷차림 이야기를 할 때 사용하세요.",
+		Parameters:  jsonschema.Definition{Type: jsonschema.Object, Properties: map[string]jsonschema.Definition{}},
+		Execute: func(ctx context.Context, userID, arguments string) (string, error) {
+			forecast, err := forecasts.Today(ctx)
+			if err != nil {
+				return "", err
+			}
+			return marshalToolResult(forecast)
+		},
+	}
+}
+
+type nextReminderResult struct {
+	Found bool   `json:"found"`
+	Kind  string `json:"kind,omitempty"`
+	Title string `json:"title,omitempty"`
+	Note  string `json:"note,omitempty"`
+	DueAt string `json:"due_at,omitempty"`
+}
+
+// NextReminderTool fetches the user's next unacknowledged medication or appointment reminder
+func NextReminderTool(reminders *ReminderService) ChatTool {
+	return ChatTool{
+		Name:        util.ChatToolNextReminder,
+		Description: "보호자가 등록한 다음 복약 또는 병원 일정 알림을 조회합니다. 사용자가 약이나 병원, 다음 일정을 물을 때 사용하세요.",
+		Parameters:  jsonschema.Definition{Type: jsonschema.Object, Properties: map[string]jsonschema.Definition{}},
+		Execute: func(ctx context.Context, userID, arguments string) (string, error) {
+			now := time.Now()
+			for _, reminder := range reminders.List(userID, false) {
+				if reminder.DueAt.Before(now.Add(-util.ReminderLookback)) {
+					continue
+				}
+				return marshalToolResult(nextReminderResult{
+					Found: true,
+					Kind:  reminder.Kind,
+					Title: reminder.Title,
+					Note:  reminder.Note,
+					DueAt: reminder.DueAt.Local().Format(time.RFC3339),
+				})
+			}
+			return marshalToolResult(nextReminderResult{})
+		},
+	}
+}
+
+type conversationsByDateArgs struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Topic string `json:"topic"`
+}
+
+type datedConversation struct {
+	Date     string   `json:"date"`
+	Messages []string `json:"messages"`
+}
+
+// ConversationsByDateTool searches the user's saved chat conversations from a date range,
+// optionally on one topic
+func ConversationsByDateTool(store client.RAGStore) ChatTool {
+	return ChatTool{
+		Name:        util.ChatToolConversationsByDate,
+		Description: "지정한 날짜 범위에 사용자와 나눈 지난 대화를 조회합니다. 사용자가 \"지난주 월요일에 무슨 이야기 했지?\"처럼 특정 날짜의 대화를 물을 때 사용하세요.",
+		Parameters: jsonschema.Definition{
+			Type: jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{
+				"from":  {Type: jsonschema.String, Description: "조회 시작 날짜 (YYYY-MM-DD)"},
+				"to":    {Type: jsonschema.String, Description: "조회 끝 날짜 (YYYY-MM-DD, 포함). 생략하면 시작 날짜 하루만 조회합니다."},
+				"topic": {Type: jsonschema.String, Enum: util.ConversationTopics, Description: "이 주제의 대화만 조회합니다. 생략하면 모든 주제를 조회합니다."},
+			},
+			Required: []string{"from"},
+		},
+		Execute: func(ctx context.Context, userID, arguments string) (string, error) {
+			var args conversationsByDateArgs
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			from, err := time.ParseInLocation("2006-01-02", args.From, time.Local)
+			if err != nil {
+				return "", fmt.Errorf("invalid from date %q", args.From)
+			}
+			to := from
+			if args.To != "" {
+				if to, err = time.ParseInLocation("2006-01-02", args.To, time.Local); err != nil || to.Before(from) {
+					return "", fmt.Errorf("invalid to date %q", args.To)
+				}
+			}
+
+			if args.Topic != "" && !util.IsConversationTopic(args.Topic) {
+				return "", fmt.Errorf("unknown topic %q", args.Topic)
+			}
+
+			results, err := store.ListConversationsByUser(ctx, userID)
+			if err != nil {
+				return "", err
+			}
+			if args.Topic != "" {
+				results = client.FilterByTopic(results, args.Topic)
+			}
+
+			// Chat turns only, most recent first
+			matched := []datedConversation{}
+			sort.SliceStable(results, func(i, j int) bool {
+				return results[i].Timestamp.After(results[j].Timestamp)
+			})
+			for _, result := range results {
+				if result.Timestamp.IsZero() || result.Timestamp.Before(from) || !result.Timestamp.Before(to.AddDate(0, 0, 1)) {
+					continue
+				}
+				if result.Metadata != nil && result.Metadata.Type != util.ConversationTypeChat {
+					continue
+				}
+				conversation := datedConversation{Date: result.Timestamp.Format("2006-01-02 15:04")}
+				for _, message := range result.Messages {
+					conversation.Messages = append(conversation.Messages, fmt.Sprintf("%s: %s", message.Role, textutil.Truncate(message.Content, util.MaxChatToolMessageRunes)))
+				}
+				matched = append(matched, conversation)
+				if len(matched) == util.MaxChatToolConversations {
+					break
+				}
+			}
+			return marshalToolResult(map[string]any{"conversations": matched})
+		},
+	}
+}
+
+// marshalToolResult encodes a tool result as JSON for the model
+func marshalToolResult(result any) (string, error) {
+	content, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+
+	"llm/internal/pii"
+	"llm/internal/util"
+)
+
+func TestToolResultRedaction(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.Register(ChatTool{
+		Name: "recall",
+		Execute: func(ctx context.Context, userID, arguments string) (string, error) {
+			return `{"messages": ["user: 딸 번호는 010-1234-5678이야"]}`, nil
+		},
+	})
+	call := openai.ToolCall{Function: openai.FunctionCall{Name: "recall", Arguments: "{}"}}
+
+	tests := []struct {
+		name   string
+		redact bool
+		want   string
+	}{
+		{name: "without redaction", want: `{"messages": ["user: 딸 번호는 010-1234-5678이야"]}`},
+		{name: "masked into the turn's mask", redact: true, want: `{"messages": ["user: 딸 번호는 [PHONE_1]이야"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := util.WithUserID(context.Background(), "user-1")
+			mask := pii.NewMask()
+			if tt.redact {
+				ctx = WithToolRedaction(ctx, pii.NewScrubber(util.PIIKinds), mask)
+			}
+
+			result, err := registry.execute(ctx, call)
+			if err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("execute() = %s, want %s", result, tt.want)
+			}
+			if restored := mask.Restore("[PHONE_1]"); tt.redact && restored != "010-1234-5678" {
+				t.Errorf("Restore() = %s, want the masked phone number", restored)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+
+	"llm/internal/util"
+)
+
+// difficultyLevels orders the difficulty labels from easiest to hardest
+var difficultyLevels = []string{util.DifficultyEasy, util.DifficultyMedium, util.DifficultyHard}
+
+// calibrateDifficulty moves a heuristic difficulty label one step toward the difficulty the
+// question's topic, or failing that its question type, has shown in recent results. Labels
+// stay unchanged until there are MinCalibrationSamples results to go on.
+func (gs *GameService) calibrateDifficulty(ctx context.Context, difficulty, questionType, topic string) string {
+	accuracy, samples := gs.quizHistoryService.TopicAccuracy(topic)
+	source := "topic"
+	if samples < util.MinCalibrationSamples {
+		accuracy, samples = gs.quizHistoryService.TypeAccuracy(questionType)
+		source = "question type"
+	}
+	if samples < util.MinCalibrationSamples {
+		return difficulty
+	}
+
+	observed := util.DifficultyMedium
+	if accuracy >= util.EasyAccuracyThreshold {
+		observed = util.DifficultyEasy
+	} else if accuracy < util.HardAccuracyThreshold {
+		observed = util.DifficultyHard
+	}
+
+	calibrated := shiftDifficulty(difficulty, difficultyRank(observed)-difficultyRank(difficulty))
+	if calibrated != difficulty {
+		gs.logger.WithContext(ctx).KeyValue("Difficulty", difficulty, "Calibrated", calibrated, "Accuracy", accuracy, "Samples", samples, "By", source)
+	}
+	return calibrated
+}
+
+// difficultyRank returns the position of a difficulty label, treating unknown labels as medium
+func difficultyRank(difficulty string) int {
+	for i, level := range difficultyLevels {
+		if level == difficulty {
+			return i
+		}
+	}
+	return 1
+}
+
+// raiseToFloor returns floor when difficulty is easier than it. An empty floor
+// leaves difficulty as is.
+func raiseToFloor(difficulty, floor string) string {
+	if floor != "" && difficultyRank(difficulty) < difficultyRank(floor) {
+		return floor
+	}
+	return difficulty
+}
+
+// shiftDifficulty moves a difficulty label at most one step in the direction of delta
+func shiftDifficulty(difficulty string, delta int) string {
+	rank := difficultyRank(difficulty)
+	if delta > 0 {
+		rank++
+	} else if delta < 0 {
+		rank--
+	}
+	return difficultyLevels[min(max(rank, 0), len(difficultyLevels)-1)]
+}
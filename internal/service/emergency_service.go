@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/client"
+	"llm/internal/config"
+	"llm/internal/emergency"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+	"llm/internal/webhook"
+)
+
+// EmergencyService detects distress phrases in chat messages, alerts the
+// user's emergency contacts, and records every incident
+type EmergencyService struct {
+	ragClient      client.RAGStore
+	webhookService *WebhookService
+	phrases        []emergency.Phrase
+	enabled        bool
+	notifier       *emergency.Notifier
+	incidents      *emergency.IncidentLog
+	logger         *util.Logger
+}
+
+// NewEmergencyService creates a new emergency service
+func NewEmergencyService(cfg *config.Config, ragClient client.RAGStore, webhookService *WebhookService) *EmergencyService {
+	es := &EmergencyService{
+		ragClient:      ragClient,
+		webhookService: webhookService,
+		phrases:        emergency.DefaultPhrases,
+		enabled:        cfg.EmergencyDetectionEnabled,
+		notifier:       emergency.NewNotifier(cfg.EmergencyWebhookURL, cfg.EmergencySMSURL, cfg.EmergencyNotifyTimeout),
+		logger:         util.NewLogger("EmergencyService"),
+	}
+
+	if cfg.EmergencyDetectionEnabled {
+		incidents, err := emergency.NewIncidentLog(cfg.EmergencyIncidentDir)
+		if err != nil {
+			es.logger.Warn("Emergency incident log disabled", err)
+		} else {
+			es.incidents = incidents
+		}
+		if !es.notifier.Enabled() {
+			es.logger.Warn("Direct emergency alerts disabled", fmt.Errorf("neither EMERGENCY_WEBHOOK_URL nor EMERGENCY_SMS_URL is set"))
+		}
+	}
+
+	return es
+}
+
+// Detect checks a chat message for distress phrases. On a match it returns the
+// incident summary immediately and alerts caregivers in the background, so the
+// chat reply is never delayed by contact lookup or delivery.
+func (es *EmergencyService) Detect(ctx context.Context, userID, message string) *models.EmergencyInfo {
+	if !es.enabled {
+		return nil
+	}
+
+	matches := emergency.Detect(es.phrases, message)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	logger := es.logger.WithContext(ctx)
+
+	matchedText := make([]string, len(matches))
+	for i, match := range matches {
+		matchedText[i] = match.Text
+	}
+
+	incident := emergency.Incident{
+		ID:         uuid.New().String(),
+		RequestID:  util.RequestIDFromContext(ctx),
+		UserID:     userID,
+		Signals:    emergency.Signals(matches),
+		Matches:    matchedText,
+		Message:    message,
+		DetectedAt: time.Now(),
+	}
+
+	logger.Section("Emergency Detected")
+	logger.KeyValue("Incident", incident.ID, "Signals", strings.Join(incident.Signals, ","))
+	for _, signal := range incident.Signals {
+		metrics.EmergencyIncidents.Add(signal, 1)
+	}
+
+	go es.alert(context.WithoutCancel(ctx), incident)
+
+	return &models.EmergencyInfo{
+		IncidentID: incident.ID,
+		Signals:    incident.Signals,
+	}
+}
+
+// alert looks up the user's emergency contacts, notifies them, and records the incident
+func (es *EmergencyService) alert(ctx context.Context, incident emergency.Incident) {
+	logger := es.logger.WithContext(ctx)
+
+	logger.Start("Async: Emergency Alert")
+
+	incident.Contacts = es.fetchContacts(ctx, incident.UserID)
+	logger.Info("Found %d emergency contacts", len(incident.Contacts))
+
+	if es.notifier.Enabled() {
+		smsText := fmt.Sprintf("[긴급] 대화 중 위급 상황으로 보이는 말씀이 있었습니다: \"%s\" 바로 연락해 확인해 주세요.", incident.Message)
+		incident.Notifications = es.notifier.Notify(ctx, incident, smsText)
+	}
+
+	for _, notification := range incident.Notifications {
+		if !notification.Success {
+			metrics.EmergencyNotifyFailures.Add(notification.Channel, 1)
+			logger.Warn("Emergency notification failed", fmt.Errorf("%s %s: %s", notification.Channel, notification.To, notification.Error))
+		}
+	}
+
+	es.webhookService.Publish(ctx, webhook.EventEmergencyDetected, incident.UserID, incident)
+
+	if es.incidents != nil {
+		if err := es.incidents.Write(incident); err != nil {
+			logger.Warn("Failed to write emergency incident", err)
+		}
+	}
+
+	logger.Success("Emergency incident recorded")
+	logger.End("Async: Emergency Alert")
+}
+
+func (es *EmergencyService) fetchContacts(ctx context.Context, userID string) []emergency.Contact {
+	contacts := []emergency.Contact{}
+
+	profile, err := es.ragClient.GetPersonalInfoByUser(ctx, userID)
+	if err != nil {
+		es.logger.WithContext(ctx).Warn("Failed to fetch emergency contacts", err)
+		return contacts
+	}
+
+	for _, item := range profile.Items {
+		if item.Category == util.CategoryEmergency {
+			contacts = append(contacts, emergency.ParseContact(item.Content))
+		}
+	}
+	return contacts
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/prompts"
+	"llm/internal/util"
+)
+
+// ExperimentService tracks per-user usage and outcome metrics by prompt/model/query strategy cohort
+type ExperimentService struct {
+	cfg              *config.Config
+	variants         []config.PromptVariant
+	totalWeight      int
+	queryVariants    []config.PromptVariant
+	queryTotalWeight int
+	cohorts          map[string]*cohortStats
+	mutex            sync.RWMutex
+	logger           *util.Logger
+}
+
+// Cohort identifies the prompt/model/query strategy combination a user is served with
+type Cohort struct {
+	PromptVersion string
+	Model         string
+	QueryStrategy string
+}
+
+// Key returns the cohort's identifier used in metrics and results
+func (c Cohort) Key() string {
+	return c.PromptVersion + "/" + c.Model + "/" + c.QueryStrategy
+}
+
+type cohortStats struct {
+	cohort Cohort
+	users  map[string]*userStats
+}
+
+type userStats struct {
+	chatTurns          int
+	questionsGenerated int
+	evaluations        int
+	scoreSum           int
+	quizAttempts       int
+	quizCorrect        int
+	retentionSum       float32
+	lastSeen           time.Time
+}
+
+// NewExperimentService creates a new experiment service
+func NewExperimentService(cfg *config.Config) *ExperimentService {
+	es := &ExperimentService{
+		cfg:     cfg,
+		cohorts: make(map[string]*cohortStats),
+		logger:  util.NewLogger("ExperimentService"),
+	}
+
+	for _, variant := range cfg.PromptVariants {
+		if !prompts.IsPromptVariant(variant.Name) {
+			es.logger.Warn("Ignoring unknown prompt variant", fmt.Errorf("%s", variant.Name))
+			continue
+		}
+		es.variants = append(es.variants, variant)
+		es.totalWeight += variant.Weight
+	}
+
+	for _, variant := range cfg.QueryStrategyVariants {
+		if !isQueryStrategy(variant.Name) {
+			es.logger.Warn("Ignoring unknown query strategy variant", fmt.Errorf("%s", variant.Name))
+			continue
+		}
+		es.queryVariants = append(es.queryVariants, variant)
+		es.queryTotalWeight += variant.Weight
+	}
+
+	return es
+}
+
+// CohortFor returns the cohort the user's requests are served with
+func (es *ExperimentService) CohortFor(userID string) Cohort {
+	settings := es.cfg.Runtime.Get()
+
+	// Without query strategy variants every user gets the configured strategy
+	queryStrategy := settings.ContextQueryStrategy
+	if !isQueryStrategy(queryStrategy) {
+		queryStrategy = util.QueryStrategyRaw
+	}
+
+	return Cohort{
+		PromptVersion: es.assignVariant(es.variants, es.totalWeight, es.cfg.PromptExperimentSalt, userID, prompts.PromptVersion),
+		Model:         settings.OpenAIModel,
+		QueryStrategy: es.assignVariant(es.queryVariants, es.queryTotalWeight, es.cfg.PromptExperimentSalt+":query", userID, queryStrategy),
+	}
+}
+
+// assignVariant deterministically buckets a user into a variant by traffic weight,
+// so the same user always sees the same variant while the configuration is unchanged.
+// Prompt and query strategy experiments use different salts so their buckets are independent.
+func (es *ExperimentService) assignVariant(variants []config.PromptVariant, totalWeight int, salt, userID, fallback string) string {
+	if totalWeight == 0 {
+		return fallback
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(salt + ":" + userID))
+	bucket := int(h.Sum32() % uint32(totalWeight))
+
+	for _, variant := range variants {
+		if bucket < variant.Weight {
+			return variant.Name
+		}
+		bucket -= variant.Weight
+	}
+	return fallback
+}
+
+// isQueryStrategy reports whether name is a supported retrieval query strategy
+func isQueryStrategy(name string) bool {
+	switch name {
+	case util.QueryStrategyRaw, util.QueryStrategyLLM, util.QueryStrategyHybrid:
+		return true
+	}
+	return false
+}
+
+// RecordChat records a chat turn for the user's cohort
+func (es *ExperimentService) RecordChat(cohort Cohort, userID string) {
+	es.update(cohort, userID, func(u *userStats) {
+		u.chatTurns++
+	})
+}
+
+// RecordEvaluation records a conversation quality score for the user's cohort
+func (es *ExperimentService) RecordEvaluation(cohort Cohort, userID string, score int) {
+	es.update(cohort, userID, func(u *userStats) {
+		u.evaluations++
+		u.scoreSum += score
+	})
+}
+
+// RecordQuestion records a generated game question for the user's cohort
+func (es *ExperimentService) RecordQuestion(cohort Cohort, userID string) {
+	es.update(cohort, userID, func(u *userStats) {
+		u.questionsGenerated++
+	})
+}
+
+// RecordQuizResult records a game result for the user's cohort
+func (es *ExperimentService) RecordQuizResult(cohort Cohort, userID string, isCorrect bool, retentionScore float32) {
+	es.update(cohort, userID, func(u *userStats) {
+		u.quizAttempts++
+		if isCorrect {
+			u.quizCorrect++
+		}
+		u.retentionSum += retentionScore
+	})
+}
+
+// Results joins usage, evaluation scores, and quiz accuracy per cohort
+func (es *ExperimentService) Results() *models.ExperimentResultsResponse {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+
+	results := []models.ExperimentCohortResult{}
+	for key, stats := range es.cohorts {
+		result := models.ExperimentCohortResult{
+			Cohort:        key,
+			PromptVersion: stats.cohort.PromptVersion,
+			Model:         stats.cohort.Model,
+			QueryStrategy: stats.cohort.QueryStrategy,
+			Users:         len(stats.users),
+		}
+
+		scoreSum, retentionSum := 0, float32(0)
+		for _, u := range stats.users {
+			result.ChatTurns += u.chatTurns
+			result.QuestionsGenerated += u.questionsGenerated
+			result.Evaluations += u.evaluations
+			result.QuizAttempts += u.quizAttempts
+			result.QuizCorrect += u.quizCorrect
+			scoreSum += u.scoreSum
+			retentionSum += u.retentionSum
+		}
+
+		if result.Evaluations > 0 {
+			result.AvgConversationScore = float32(scoreSum) / float32(result.Evaluations)
+		}
+		if result.QuizAttempts > 0 {
+			result.QuizAccuracy = float32(result.QuizCorrect) / float32(result.QuizAttempts)
+			result.AvgRetentionScore = retentionSum / float32(result.QuizAttempts)
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Cohort < results[j].Cohort
+	})
+
+	return &models.ExperimentResultsResponse{
+		Variants:              toExperimentVariants(es.variants),
+		QueryStrategyVariants: toExperimentVariants(es.queryVariants),
+		Cohorts:               results,
+		GeneratedAt:           time.Now(),
+	}
+}
+
+func (es *ExperimentService) update(cohort Cohort, userID string, apply func(*userStats)) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	key := cohort.Key()
+	stats, exists := es.cohorts[key]
+	if !exists {
+		stats = &cohortStats{cohort: cohort, users: make(map[string]*userStats)}
+		es.cohorts[key] = stats
+	}
+
+	u, exists := stats.users[userID]
+	if !exists {
+		u = &userStats{}
+		stats.users[userID] = u
+	}
+	apply(u)
+	u.lastSeen = time.Now()
+}
+
+// CompactUsage removes per-user usage rows not updated since before and drops
+// cohorts left without users. It returns how many rows were removed and the
+// approximate bytes they held.
+func (es *ExperimentService) CompactUsage(before time.Time) (int, int64) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for key, stats := range es.cohorts {
+		for userID, u := range stats.users {
+			if u.lastSeen.Before(before) {
+				delete(stats.users, userID)
+				removed++
+				reclaimed += int64(unsafe.Sizeof(*u)) + int64(len(userID))
+			}
+		}
+		if len(stats.users) == 0 {
+			delete(es.cohorts, key)
+			reclaimed += int64(unsafe.Sizeof(*stats)) + int64(len(key))
+		}
+	}
+	return removed, reclaimed
+}
+
+func toExperimentVariants(configured []config.PromptVariant) []models.ExperimentVariant {
+	variants := []models.ExperimentVariant{}
+	for _, variant := range configured {
+		variants = append(variants, models.ExperimentVariant{Name: variant.Name, Weight: variant.Weight})
+	}
+	return variants
+}
+
+// DeleteUser removes the user's usage rows from every cohort and returns how many there were
+func (es *ExperimentService) DeleteUser(userID string) int {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	removed := 0
+	for key, stats := range es.cohorts {
+		if _, ok := stats.users[userID]; !ok {
+			continue
+		}
+		delete(stats.users, userID)
+		removed++
+		if len(stats.users) == 0 {
+			delete(es.cohorts, key)
+		}
+	}
+	return removed
+}
@@ -2,129 +2,703 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
 	"llm/internal/client"
 	"llm/internal/config"
 	"llm/internal/models"
+	"llm/internal/prompts"
+	"llm/internal/textutil"
 	"llm/internal/util"
+	"llm/internal/webhook"
 )
 
 // GameService handles game question generation and result evaluation
 type GameService struct {
-	ragClient     *client.RAGClient
-	openaiService *OpenAIService
-	cfg           *config.Config
-	questionCache map[string]*models.StoredQuestion
-	cacheMutex    sync.RWMutex
-	logger        *util.Logger
+	ragClient          client.RAGStore
+	openaiService      LLMService
+	experimentService  *ExperimentService
+	quizHistoryService *QuizHistoryService
+	webhookService     *WebhookService
+	userService        *UserService
+	cfg                *config.Config
+	questionCache      map[string]*models.StoredQuestion
+	cacheMutex         sync.RWMutex
+	logger             *util.Logger
 }
 
 // NewGameService creates a new game service
-func NewGameService(cfg *config.Config, ragClient *client.RAGClient, openaiService *OpenAIService) *GameService {
-	gs := &GameService{
-		ragClient:     ragClient,
-		openaiService: openaiService,
-		cfg:           cfg,
-		questionCache: make(map[string]*models.StoredQuestion),
-		logger:        util.NewLogger("GameService"),
+func NewGameService(cfg *config.Config, ragClient client.RAGStore, openaiService LLMService, experimentService *ExperimentService, quizHistoryService *QuizHistoryService, webhookService *WebhookService, userService *UserService) *GameService {
+	return &GameService{
+		ragClient:          ragClient,
+		openaiService:      openaiService,
+		experimentService:  experimentService,
+		quizHistoryService: quizHistoryService,
+		webhookService:     webhookService,
+		userService:        userService,
+		cfg:                cfg,
+		questionCache:      make(map[string]*models.StoredQuestion),
+		logger:             util.NewLogger("GameService"),
 	}
-
-	go gs.cleanupCacheRoutine()
-	return gs
 }
 
 // GenerateQuestion generates a question based on user's conversation history
-func (gs *GameService) GenerateQuestion(ctx context.Context, req *models.GameQuestionRequest) (interface{}, error) {
-	gs.logger.Start("Generate Question")
+func (gs *GameService) GenerateQuestion(ctx context.Context, req *models.GameQuestionRequest) (*models.GameQuestionResponse, error) {
+	return gs.generateQuestion(ctx, req, "")
+}
 
-	// Fetch latest 20 conversations
-	searchResults, err := gs.ragClient.SearchConversations(ctx, "conversation", 20)
+// PreviewQuestion generates a question for supervised sessions. The question
+// stays pending, and results for it are refused, until a caregiver approves it.
+// Results for a rejected question are refused until it expires.
+func (gs *GameService) PreviewQuestion(ctx context.Context, req *models.GameQuestionRequest) (*models.SupervisedQuestionResponse, error) {
+	question, err := gs.generateQuestion(ctx, req, util.ReviewStatusPending)
 	if err != nil {
-		gs.logger.Error("Failed to search conversations", err)
-		gs.logger.End("Generate Question")
+		return nil, err
+	}
+
+	return &models.SupervisedQuestionResponse{
+		QuestionID: question.QuestionID,
+		Status:     util.ReviewStatusPending,
+		Question:   question,
+	}, nil
+}
+
+// ReviewQuestion approves a previewed question, applying any difficulty/topic
+// relabelling, or rejects it so it can never be answered
+func (gs *GameService) ReviewQuestion(ctx context.Context, qID string, req *models.QuestionReviewRequest) (*models.SupervisedQuestionResponse, error) {
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Review Question")
+
+	gs.cacheMutex.Lock()
+	defer gs.cacheMutex.Unlock()
+
+	stored, exists := gs.questionCache[qID]
+	if !exists || time.Now().After(stored.ExpiresAt) {
+		logger.End("Review Question")
+		return nil, fmt.Errorf("question_not_found: %s", qID)
+	}
+	if stored.ReviewStatus != util.ReviewStatusPending {
+		logger.End("Review Question")
+		return nil, fmt.Errorf("invalid_review: question %s is not awaiting review", qID)
+	}
+
+	// A rejected question stays cached until it expires so results for it keep being refused
+	if req.Action == util.ReviewActionReject {
+		stored.ReviewStatus = util.ReviewStatusRejected
+		logger.KeyValue("Question", qID, "Action", req.Action, "Reason", req.Reason)
+		logger.Success("Question rejected")
+		logger.End("Review Question")
+		return &models.SupervisedQuestionResponse{
+			QuestionID: qID,
+			Status:     util.ReviewStatusRejected,
+		}, nil
+	}
+
+	if req.Difficulty != "" {
+		stored.Difficulty = req.Difficulty
+	}
+	if req.Topic != "" {
+		stored.Topic = req.Topic
+	}
+	stored.Payload.Difficulty, stored.Payload.Metadata.Topic = stored.Difficulty, stored.Topic
+	stored.ReviewStatus = util.ReviewStatusApproved
+
+	logger.KeyValue("Question", qID, "Difficulty", stored.Difficulty, "Topic", stored.Topic)
+	logger.Success("Question approved")
+	logger.End("Review Question")
+
+	return &models.SupervisedQuestionResponse{
+		QuestionID: qID,
+		Status:     util.ReviewStatusApproved,
+		Question:   stored.Payload,
+	}, nil
+}
+
+// GetQuestionLineage returns what a cached question was generated from: its source
+// conversations, the prompt and raw model output, and the validation results
+func (gs *GameService) GetQuestionLineage(ctx context.Context, qID string) (*models.QuestionLineageResponse, error) {
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Get Question Lineage")
+
+	stored := gs.getCachedQuestion(qID)
+	if stored == nil {
+		logger.End("Get Question Lineage")
+		return nil, fmt.Errorf("question_not_found: %s", qID)
+	}
+
+	sources := stored.SourceConversations
+	if sources == nil {
+		sources = []models.RAGConversationSearchResult{}
+	}
+
+	logger.KeyValue("Question", qID, "Sources", len(sources), "Generation recorded", stored.Generation != nil)
+	logger.End("Get Question Lineage")
+
+	return &models.QuestionLineageResponse{
+		QuestionID:          stored.QuestionID,
+		UserID:              stored.UserID,
+		ReviewStatus:        stored.ReviewStatus,
+		Question:            stored.Payload,
+		SourceConversations: sources,
+		SourceContent:       stored.SourceContent,
+		Generation:          stored.Generation,
+		Validation:          stored.Validation,
+		GeneratedAt:         stored.GeneratedAt,
+		ExpiresAt:           stored.ExpiresAt,
+	}, nil
+}
+
+func (gs *GameService) generateQuestion(ctx context.Context, req *models.GameQuestionRequest, reviewStatus string) (*models.GameQuestionResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Generate Question")
+
+	if err := gs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Generate Question")
+		return nil, err
+	}
+	from, to, err := windowBounds(req.TimeWindow)
+	if err != nil {
+		logger.Error("Rejected time window", err)
+		logger.End("Generate Question")
+		return nil, err
+	}
+	floor := gs.difficultyFloor(req)
+
+	// Photo questions are based on the photo, not on conversation history
+	if req.QuestionType == util.QuestionTypePhoto {
+		response, sourceContent, err := gs.generatePhotoQuestion(WithGenerationOverrides(ctx, req.GenerationOverrides), req)
+		if err != nil {
+			logger.End("Generate Question")
+			return nil, err
+		}
+		if req.DifficultyHint == "" {
+			response.Difficulty = gs.calibrateDifficulty(ctx, response.Difficulty, response.QuestionType, response.Metadata.Topic)
+		}
+		response.Difficulty = raiseToFloor(response.Difficulty, floor)
+		gs.cacheQuestion(req.UserID, response, nil, sourceContent, reviewStatus)
+		cohort := gs.experimentService.CohortFor(req.UserID)
+		cohort.Model = gs.openaiService.EffectiveModel(util.LLMTaskQuestion, req.GenerationOverrides)
+		gs.experimentService.RecordQuestion(cohort, req.UserID)
+
+		logger.Success("Photo question generated and cached")
+		logger.End("Generate Question")
+		return response, nil
+	}
+
+	// Fetch latest conversations, from the requested period if any
+	searchResults, err := gs.searchConversations(ctx, "conversation", gs.questionCandidateLimit(), from, to)
+	if err != nil {
+		logger.Error("Failed to search conversations", err)
+		logger.End("Generate Question")
 		return nil, fmt.Errorf("insufficient conversation history: %w", err)
 	}
 
 	// Check if we have enough conversations
-	if len(searchResults) < 5 {
-		gs.logger.Error("Insufficient conversations", fmt.Errorf("need at least 5, got %d", len(searchResults)))
-		gs.logger.End("Generate Question")
-		return nil, fmt.Errorf("insufficient_data: need at least 5 conversations, got %d", len(searchResults))
+	minConversations := gs.cfg.MinConversationsForGame
+	if len(searchResults) < minConversations {
+		insufficient := fmt.Errorf("insufficient_data: need at least %d conversations, got %d", minConversations, len(searchResults))
+		if !req.AllowProfileFallback {
+			logger.Error("Insufficient conversations", fmt.Errorf("need at least %d, got %d", minConversations, len(searchResults)))
+			logger.End("Generate Question")
+			return nil, insufficient
+		}
+
+		logger.Info("Insufficient conversations (%d), falling back to personal info", len(searchResults))
+		response, sourceContent, err := gs.generateProfileQuestion(WithGenerationOverrides(ctx, req.GenerationOverrides), req)
+		if err != nil {
+			logger.End("Generate Question")
+			if strings.HasPrefix(err.Error(), "insufficient_data:") {
+				return nil, insufficient
+			}
+			return nil, err
+		}
+		response.Difficulty = raiseToFloor(response.Difficulty, floor)
+		gs.cacheQuestion(req.UserID, response, nil, sourceContent, reviewStatus)
+		cohort := gs.experimentService.CohortFor(req.UserID)
+		cohort.Model = gs.openaiService.EffectiveModel(util.LLMTaskQuestion, req.GenerationOverrides)
+		gs.experimentService.RecordQuestion(cohort, req.UserID)
+
+		logger.Success("Profile fallback question generated and cached")
+		logger.End("Generate Question")
+		return response, nil
 	}
 
 	// Determine difficulty and select conversation
-	difficulty := gs.determineDifficulty(req.DifficultyHint, searchResults)
+	difficulty := raiseToFloor(gs.determineDifficulty(req.DifficultyHint, searchResults), floor)
 	selectedConv := gs.selectConversation(searchResults, difficulty)
 	topic := gs.extractTopic(selectedConv)
 
-	gs.logger.KeyValue("Difficulty", difficulty, "Topic", topic)
+	logger.KeyValue("Difficulty", difficulty, "Topic", topic)
 
 	// Generate question based on type
-	var response interface{}
+	genCtx := WithGenerationOverrides(ctx, req.GenerationOverrides)
+	var response *models.GameQuestionResponse
+	sources := []models.RAGConversationSearchResult{selectedConv}
+	sourceContent := gs.extractConversationContent(selectedConv)
 	switch req.QuestionType {
 	case util.QuestionTypeFillInBlank:
-		response, err = gs.generateFillInTheBlankQuestion(ctx, selectedConv, topic)
+		response, err = gs.generateFillInTheBlankQuestion(genCtx, selectedConv, topic)
 	case util.QuestionTypeMultipleChoice:
-		response, err = gs.generateMultipleChoiceQuestion(ctx, selectedConv, topic)
+		response, err = gs.generateMultipleChoiceQuestion(genCtx, selectedConv, topic)
+	case util.QuestionTypeIntegrative:
+		related := gs.selectRelatedConversations(selectedConv, searchResults)
+		if len(related) < util.MinIntegrativeConversations {
+			logger.Error("Insufficient related conversations", fmt.Errorf("need at least %d, got %d", util.MinIntegrativeConversations, len(related)))
+			logger.End("Generate Question")
+			return nil, fmt.Errorf("insufficient_data: need at least %d related conversations, got %d", util.MinIntegrativeConversations, len(related))
+		}
+		contents := make([]string, len(related))
+		for i, conv := range related {
+			contents[i] = gs.extractConversationContent(conv)
+		}
+		sources, sourceContent = related, strings.Join(contents, "\n\n")
+		response, err = gs.generateIntegrativeQuestion(genCtx, related, topic)
 	default:
-		gs.logger.Error("Invalid question type", fmt.Errorf(req.QuestionType))
-		gs.logger.End("Generate Question")
+		logger.Error("Invalid question type", fmt.Errorf("%s", req.QuestionType))
+		logger.End("Generate Question")
 		return nil, fmt.Errorf("invalid_question_type: %s", req.QuestionType)
 	}
 
 	if err != nil {
-		gs.logger.End("Generate Question")
+		logger.End("Generate Question")
 		return nil, err
 	}
+	if req.DifficultyHint == "" {
+		response.Difficulty = gs.calibrateDifficulty(ctx, response.Difficulty, response.QuestionType, response.Metadata.Topic)
+	}
+	response.Difficulty = raiseToFloor(response.Difficulty, floor)
 
 	// Cache the question
-	gs.cacheQuestion(response)
+	gs.cacheQuestion(req.UserID, response, sources, sourceContent, reviewStatus)
+	cohort := gs.experimentService.CohortFor(req.UserID)
+	cohort.Model = gs.openaiService.EffectiveModel(util.LLMTaskQuestion, req.GenerationOverrides)
+	gs.experimentService.RecordQuestion(cohort, req.UserID)
 
-	gs.logger.Success("Question generated and cached")
-	gs.logger.End("Generate Question")
+	logger.Success("Question generated and cached")
+	logger.End("Generate Question")
 	return response, nil
 }
 
+// DryRunQuestion assembles the prompt a question request would be generated with, from the
+// same conversation selection, without calling the LLM or caching a question. Photo questions
+// need the caregiver's description, since captioning the photo would call the vision model.
+func (gs *GameService) DryRunQuestion(ctx context.Context, req *models.GameQuestionRequest) (*models.QuestionPromptPreviewResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Dry Run Question")
+
+	if err := gs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Dry Run Question")
+		return nil, err
+	}
+
+	resp := &models.QuestionPromptPreviewResponse{
+		Model:        gs.openaiService.EffectiveModel(util.LLMTaskQuestion, req.GenerationOverrides),
+		QuestionType: req.QuestionType,
+	}
+
+	if req.QuestionType == util.QuestionTypePhoto {
+		description := textutil.Normalize(req.ImageDescription)
+		if req.ImageURL == "" || description == "" {
+			err := fmt.Errorf("invalid_photo: image_url and image_description are required for a photo question dry run")
+			logger.Error("Missing photo", err)
+			logger.End("Dry Run Question")
+			return nil, err
+		}
+
+		resp.Messages = toPromptMessages(questionMessages(util.QuestionTypePhoto, []string{description}, ""))
+		resp.Difficulty = req.DifficultyHint
+		if resp.Difficulty == "" {
+			resp.Difficulty = util.DifficultyMedium
+		}
+		resp.Topic = textutil.Truncate(textutil.FirstSentence(description), textutil.MaxTopicRunes)
+
+		logger.Success("Photo question prompt assembled")
+		logger.End("Dry Run Question")
+		return resp, nil
+	}
+
+	from, to, err := windowBounds(req.TimeWindow)
+	if err != nil {
+		logger.Error("Rejected time window", err)
+		logger.End("Dry Run Question")
+		return nil, err
+	}
+
+	searchResults, err := gs.searchConversations(ctx, "conversation", gs.questionCandidateLimit(), from, to)
+	if err != nil {
+		logger.Error("Failed to search conversations", err)
+		logger.End("Dry Run Question")
+		return nil, fmt.Errorf("insufficient conversation history: %w", err)
+	}
+
+	minConversations := gs.cfg.MinConversationsForGame
+	if len(searchResults) < minConversations {
+		logger.Error("Insufficient conversations", fmt.Errorf("need at least %d, got %d", minConversations, len(searchResults)))
+		logger.End("Dry Run Question")
+		return nil, fmt.Errorf("insufficient_data: need at least %d conversations, got %d", minConversations, len(searchResults))
+	}
+
+	floor := gs.difficultyFloor(req)
+	difficulty := raiseToFloor(gs.determineDifficulty(req.DifficultyHint, searchResults), floor)
+	selectedConv := gs.selectConversation(searchResults, difficulty)
+	resp.Topic = gs.extractTopic(selectedConv)
+
+	sources := []models.RAGConversationSearchResult{selectedConv}
+	switch req.QuestionType {
+	case util.QuestionTypeFillInBlank, util.QuestionTypeMultipleChoice:
+		resp.Difficulty = gs.determineDifficultyFromConversation(selectedConv)
+	case util.QuestionTypeIntegrative:
+		sources = gs.selectRelatedConversations(selectedConv, searchResults)
+		if len(sources) < util.MinIntegrativeConversations {
+			logger.Error("Insufficient related conversations", fmt.Errorf("need at least %d, got %d", util.MinIntegrativeConversations, len(sources)))
+			logger.End("Dry Run Question")
+			return nil, fmt.Errorf("insufficient_data: need at least %d related conversations, got %d", util.MinIntegrativeConversations, len(sources))
+		}
+		resp.Difficulty = util.DifficultyHard
+	default:
+		logger.Error("Invalid question type", fmt.Errorf("%s", req.QuestionType))
+		logger.End("Dry Run Question")
+		return nil, fmt.Errorf("invalid_question_type: %s", req.QuestionType)
+	}
+	resp.Difficulty = raiseToFloor(resp.Difficulty, floor)
+
+	contents := make([]string, len(sources))
+	for i, conv := range sources {
+		contents[i] = gs.extractConversationContent(conv)
+		resp.BasedOnConversations = append(resp.BasedOnConversations, conv.ConversationID)
+	}
+	resp.Messages = toPromptMessages(questionMessages(req.QuestionType, contents, resp.Topic))
+
+	logger.KeyValue("Difficulty", resp.Difficulty, "Topic", resp.Topic)
+	logger.Success("Question prompt assembled")
+	logger.End("Dry Run Question")
+	return resp, nil
+}
+
+// GenerateQuestionSet generates a cohesive themed set of questions from conversations matching the theme
+func (gs *GameService) GenerateQuestionSet(ctx context.Context, req *models.QuestionSetRequest) (*models.QuestionSetResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Generate Question Set")
+
+	if err := gs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Generate Question Set")
+		return nil, err
+	}
+
+	theme, ok := quizThemes[req.Theme]
+	if !ok {
+		logger.Error("Invalid theme", fmt.Errorf("%s", req.Theme))
+		logger.End("Generate Question Set")
+		return nil, fmt.Errorf("invalid_theme: %s", req.Theme)
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = util.DefaultQuestionSetSize
+	}
+
+	convs, err := gs.fetchThemeConversations(ctx, theme)
+	if err != nil {
+		logger.Error("Failed to search conversations", err)
+		logger.End("Generate Question Set")
+		return nil, fmt.Errorf("insufficient conversation history: %w", err)
+	}
+
+	if len(convs) == 0 {
+		logger.Error("No conversations match theme", fmt.Errorf("%s", theme.name))
+		logger.End("Generate Question Set")
+		return nil, fmt.Errorf("insufficient_data: no conversations found for theme %s", theme.name)
+	}
+
+	logger.KeyValue("Theme", theme.name, "Conversations", len(convs), "Count", count)
+	floor := gs.userService.Preferences(req.UserID).QuizDifficultyFloor
+
+	contents := make([]string, 0, len(convs))
+	conversationIDs := make([]string, 0, len(convs))
+	for _, conv := range convs {
+		contents = append(contents, gs.extractConversationContent(conv))
+		conversationIDs = append(conversationIDs, conv.ConversationID)
+	}
+
+	genCtx := WithGenerationOverrides(ctx, req.GenerationOverrides)
+	set, err := gs.openaiService.GenerateThemedQuestionSet(genCtx, theme.description, contents, count)
+	if err != nil {
+		logger.End("Generate Question Set")
+		return nil, err
+	}
+
+	cohort := gs.experimentService.CohortFor(req.UserID)
+	cohort.Model = gs.openaiService.EffectiveModel(util.LLMTaskQuestion, req.GenerationOverrides)
+
+	questions := []models.GameQuestionResponse{}
+	for _, item := range set.Questions {
+		if len(questions) >= count {
+			break
+		}
+
+		// Fall back to the first conversation when the model cites an unknown source
+		source := convs[0]
+		if item.Source >= 1 && item.Source <= len(convs) {
+			source = convs[item.Source-1]
+		}
+
+		options := make([]models.QuestionOption, len(item.Options))
+		for i, opt := range item.Options {
+			options[i] = models.QuestionOption{ID: opt.ID, Text: opt.Text}
+		}
+
+		question := models.GameQuestionResponse{
+			QuestionID:          uuid.New().String(),
+			QuestionType:        util.QuestionTypeMultipleChoice,
+			Question:            item.Text,
+			Options:             options,
+			CorrectAnswer:       item.CorrectAnswer,
+			BasedOnConversation: source.ConversationID,
+			Difficulty:          gs.determineDifficultyFromConversation(source),
+			Metadata: models.QuestionMetadata{
+				Topic:                 theme.name,
+				MemoryScore:           source.Score,
+				DaysSinceConversation: int(time.Since(source.Timestamp).Hours() / 24),
+			},
+			Generation: set.Generation,
+		}
+
+		// Drop questions failing quality control rather than regenerating the whole set
+		sourceContent := gs.extractConversationContent(source)
+		if failed := gs.failedChecks(&question, sourceContent); len(failed) > 0 {
+			logger.Warn("Dropping question that failed quality checks", fmt.Errorf("%s", strings.Join(failed, ", ")))
+			continue
+		}
+		shuffleOptions(&question)
+		question.Difficulty = raiseToFloor(gs.calibrateDifficulty(ctx, question.Difficulty, question.QuestionType, question.Metadata.Topic), floor)
+
+		gs.cacheQuestion(req.UserID, &question, []models.RAGConversationSearchResult{source}, sourceContent, "")
+		gs.experimentService.RecordQuestion(cohort, req.UserID)
+		questions = append(questions, question)
+	}
+
+	if len(questions) == 0 {
+		err := fmt.Errorf("question_quality: no question in the set passed quality checks")
+		logger.Error("Empty question set", err)
+		logger.End("Generate Question Set")
+		return nil, err
+	}
+
+	logger.Success(fmt.Sprintf("Question set generated with %d questions", len(questions)))
+	logger.End("Generate Question Set")
+
+	return &models.QuestionSetResponse{
+		SetID:                uuid.New().String(),
+		Theme:                theme.name,
+		Intro:                set.Intro,
+		Questions:            questions,
+		BasedOnConversations: conversationIDs,
+		CreatedAt:            time.Now(),
+	}, nil
+}
+
+// GenerateRecap builds "오늘의 회상" recap cards from the user's most recent
+// conversations, favouring topics of recently missed quiz questions
+func (gs *GameService) GenerateRecap(ctx context.Context, req *models.RecapRequest) (*models.RecapResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Generate Recap")
+
+	if err := gs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Generate Recap")
+		return nil, err
+	}
+
+	from, to, err := windowBounds(req.TimeWindow)
+	if err != nil {
+		logger.Error("Rejected time window", err)
+		logger.End("Generate Recap")
+		return nil, err
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = util.DefaultRecapCardCount
+	}
+
+	searchResults, err := gs.searchConversations(ctx, "conversation", util.RecapSearchResultLimit, from, to)
+	if err != nil {
+		logger.Error("Failed to search conversations", err)
+		logger.End("Generate Recap")
+		return nil, fmt.Errorf("insufficient conversation history: %w", err)
+	}
+
+	if len(searchResults) == 0 {
+		logger.Error("No conversations", fmt.Errorf("no conversations found"))
+		logger.End("Generate Recap")
+		return nil, fmt.Errorf("insufficient_data: no conversations found")
+	}
+
+	// Most recent conversations first
+	sort.SliceStable(searchResults, func(i, j int) bool {
+		return searchResults[i].Timestamp.After(searchResults[j].Timestamp)
+	})
+	if len(searchResults) > util.MaxRecapConversations {
+		searchResults = searchResults[:util.MaxRecapConversations]
+	}
+
+	// Missed topics only steer the cards, so a failed lookup is not fatal
+	missedTopics := []string{}
+	attempts, err := gs.ragClient.GetIncorrectQuizAttempts(ctx, req.UserID, util.MaxRecapMissedTopics)
+	if err != nil {
+		logger.Warn("Failed to fetch incorrect attempts, building recap without missed topics", err)
+	} else if attempts != nil {
+		for _, attempt := range attempts.Items {
+			missedTopics = append(missedTopics, fmt.Sprintf("%s (문제: %s)", attempt.Quiz.Topic, attempt.Quiz.Question))
+		}
+	}
+
+	logger.KeyValue("Conversations", len(searchResults), "Missed Topics", len(missedTopics), "Count", count)
+
+	contents := make([]string, 0, len(searchResults))
+	conversationIDs := make([]string, 0, len(searchResults))
+	for _, conv := range searchResults {
+		contents = append(contents, gs.extractConversationContent(conv))
+		conversationIDs = append(conversationIDs, conv.ConversationID)
+	}
+
+	genCtx := WithGenerationOverrides(ctx, req.GenerationOverrides)
+	set, err := gs.openaiService.GenerateRecapCards(genCtx, contents, missedTopics, count)
+	if err != nil {
+		logger.End("Generate Recap")
+		return nil, err
+	}
+
+	// Cards must cite a real conversation, so incomplete or unsourced cards are dropped
+	cards := []models.RecapCard{}
+	for _, card := range set.Cards {
+		if len(cards) >= count {
+			break
+		}
+		if card.Title == "" || card.Body == "" || card.Source < 1 || card.Source > len(searchResults) {
+			continue
+		}
+		cards = append(cards, models.RecapCard{
+			Title:          card.Title,
+			Body:           card.Body,
+			ConversationID: searchResults[card.Source-1].ConversationID,
+			MissedTopic:    card.MissedTopic,
+		})
+	}
+
+	if len(cards) == 0 {
+		logger.Error("Empty recap", fmt.Errorf("no complete cards in response"))
+		logger.End("Generate Recap")
+		return nil, fmt.Errorf("incomplete recap response from openai")
+	}
+
+	logger.Success(fmt.Sprintf("Recap generated with %d cards", len(cards)))
+	logger.End("Generate Recap")
+
+	return &models.RecapResponse{
+		RecapID:              uuid.New().String(),
+		Cards:                cards,
+		BasedOnConversations: conversationIDs,
+		MissedTopics:         missedTopics,
+		CreatedAt:            time.Now(),
+	}, nil
+}
+
 // EvaluateGameResult evaluates a game result and stores the evaluation
 func (gs *GameService) EvaluateGameResult(ctx context.Context, req *models.GameResultRequest) (*models.GameResultResponse, error) {
-	gs.logger.Start("Evaluate Game Result")
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
 
-	// Calculate retention score
-	retentionScore := gs.calculateRetentionScore(req)
-	confidence := gs.determineConfidence(retentionScore)
-	recommendation := gs.getRecommendation(retentionScore)
+	logger.Start("Evaluate Game Result")
 
-	gs.logger.KeyValue("Retention Score", retentionScore, "Confidence", confidence)
+	if err := checkFeature(gs.cfg, util.FeatureEvaluation); err != nil {
+		logger.Error("Feature disabled", err)
+		logger.End("Evaluate Game Result")
+		return nil, err
+	}
 
-	// Get topic from cached question
-	topic := util.DifficultyEasy // Default
+	// Grade, label and date the result from the cached question when it is still cached
+	topic := util.DefaultTopic
 	cachedQuestion := gs.getCachedQuestion(req.QuestionID)
+	if cachedQuestion != nil && cachedQuestion.UserID != req.UserID {
+		logger.Warn("Ignoring cached question", fmt.Errorf("question %s belongs to another user", req.QuestionID))
+		cachedQuestion = nil
+	}
+	if cachedQuestion != nil && cachedQuestion.ReviewStatus == util.ReviewStatusPending {
+		logger.Error("Question not approved", fmt.Errorf("question %s is pending review", req.QuestionID))
+		logger.End("Evaluate Game Result")
+		return nil, fmt.Errorf("question_pending_review: %s", req.QuestionID)
+	}
+	if cachedQuestion != nil && cachedQuestion.ReviewStatus == util.ReviewStatusRejected {
+		logger.Error("Question rejected", fmt.Errorf("question %s was rejected in review", req.QuestionID))
+		logger.End("Evaluate Game Result")
+		return nil, fmt.Errorf("question_rejected: %s", req.QuestionID)
+	}
 	if cachedQuestion != nil && cachedQuestion.Topic != "" {
 		topic = cachedQuestion.Topic
 	}
+	if cachedQuestion != nil {
+		// Answers matching an option are graded against the stored correct answer
+		// rather than trusting the client's flag
+		if selected, isCorrect := gradeAnswer(cachedQuestion, req.UserAnswer); selected != "" && isCorrect != req.IsCorrect {
+			logger.Warn("Overriding reported correctness", fmt.Errorf("answer %q to question %s graded %t, reported %t", req.UserAnswer, req.QuestionID, isCorrect, req.IsCorrect))
+			graded := *req
+			graded.IsCorrect = isCorrect
+			req = &graded
+		}
+	}
+
+	// Calculate retention score
+	evaluation := gs.evaluateMemory(ctx, req, cachedQuestion, topic)
+	retentionScore := evaluation.RetentionScore
+
+	logger.KeyValue("Strategy", evaluation.Strategy, "Retention Score", retentionScore, "Confidence", evaluation.Confidence)
+
+	if cachedQuestion != nil {
+		gs.quizHistoryService.RecordAttempt(req.UserID, gs.factID(cachedQuestion), topic, req.GameSessionID, req.IsCorrect, retentionScore, evaluation.Confidence)
+		gs.quizHistoryService.RecordQuestionResult(cachedQuestion.QuestionType, topic, req.IsCorrect)
+	}
+	gs.notifyCaregivers(ctx, req, topic, retentionScore)
+
+	cohort := gs.experimentService.CohortFor(req.UserID)
+	gs.experimentService.RecordQuizResult(cohort, req.UserID, req.IsCorrect, retentionScore)
 
 	// Save evaluation asynchronously
-	go gs.saveEvaluation(context.Background(), req, topic, retentionScore)
+	go gs.saveEvaluation(context.WithoutCancel(ctx), req, topic, retentionScore, cohort)
 
 	// Suggest next difficulty
-	nextDifficulty := gs.suggestNextDifficulty(retentionScore)
+	nextDifficulty := gs.suggestNextDifficulty(req.UserID, retentionScore)
 
-	gs.logger.Success("Game result evaluated")
-	gs.logger.End("Evaluate Game Result")
+	logger.Success("Game result evaluated")
+	logger.End("Evaluate Game Result")
 
 	return &models.GameResultResponse{
-		ResultID: uuid.New().String(),
-		MemoryEvaluation: models.MemoryEvaluation{
-			Topic:          topic,
-			RetentionScore: retentionScore,
-			Confidence:     confidence,
-			Recommendation: recommendation,
-		},
+		ResultID:         uuid.New().String(),
+		MemoryEvaluation: evaluation,
 		NextQuestionSuggestion: models.NextQuestionSuggestion{
 			Difficulty:      nextDifficulty,
 			TopicPreference: "새로운 주제 추천",
@@ -133,24 +707,155 @@ func (gs *GameService) EvaluateGameResult(ctx context.Context, req *models.GameR
 	}, nil
 }
 
+// AnswerQuestion grades the user's answer to a generated question, records the
+// result like EvaluateGameResult, and explains the correct answer with reference
+// to the original conversation
+func (gs *GameService) AnswerQuestion(ctx context.Context, qID string, req *models.QuestionAnswerRequest) (*models.QuestionAnswerResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Answer Question")
+
+	cachedQuestion := gs.getCachedQuestion(qID)
+	if cachedQuestion == nil || cachedQuestion.UserID != req.UserID {
+		logger.Error("Question not found", fmt.Errorf("question %s not found for user", qID))
+		logger.End("Answer Question")
+		return nil, fmt.Errorf("question_not_found: %s", qID)
+	}
+
+	selected, isCorrect := gradeAnswer(cachedQuestion, req.Answer)
+	logger.KeyValue("Question", qID, "Selected", selected, "Correct", isCorrect)
+
+	result, err := gs.EvaluateGameResult(ctx, &models.GameResultRequest{
+		UserID:         req.UserID,
+		QuestionID:     qID,
+		UserAnswer:     req.Answer,
+		IsCorrect:      isCorrect,
+		ResponseTimeMs: req.ResponseTimeMs,
+		GameSessionID:  req.GameSessionID,
+	})
+	if err != nil {
+		logger.End("Answer Question")
+		return nil, err
+	}
+
+	correctText := optionText(cachedQuestion.Options, cachedQuestion.CorrectAnswer)
+
+	// The grade is already recorded, so a failed explanation falls back to a fixed gentle message
+	explanation, err := gs.openaiService.ExplainAnswer(ctx, cachedQuestion, req.Answer, isCorrect)
+	if err != nil {
+		logger.Warn("Failed to generate explanation, using fallback", err)
+		explanation = prompts.AnswerExplanationFallback(isCorrect, correctText)
+	}
+
+	logger.Success("Answer graded")
+	logger.End("Answer Question")
+
+	return &models.QuestionAnswerResponse{
+		QuestionID:        qID,
+		IsCorrect:         isCorrect,
+		SelectedOption:    selected,
+		CorrectAnswer:     cachedQuestion.CorrectAnswer,
+		CorrectAnswerText: correctText,
+		Explanation:       explanation,
+		Result:            *result,
+	}, nil
+}
+
+// GenerateOrientationQuestions builds reality-orientation questions about today's
+// date, weekday and season, and a nearby holiday, from the calendar alone. They
+// need no conversation history, so they work for users who have barely chatted.
+func (gs *GameService) GenerateOrientationQuestions(ctx context.Context, req *models.OrientationQuestionRequest) *models.OrientationQuestionResponse {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := gs.logger.WithContext(ctx)
+
+	now := time.Now()
+	generated := orientationQuestions(now)
+	if req.Count > 0 && req.Count < len(generated) {
+		generated = generated[:req.Count]
+	}
+
+	questions := make([]models.GameQuestionResponse, 0, len(generated))
+	for _, generatedQuestion := range generated {
+		shuffleOptions(generatedQuestion.question)
+		gs.cacheQuestion(req.UserID, generatedQuestion.question, nil, generatedQuestion.fact, "")
+		questions = append(questions, *generatedQuestion.question)
+	}
+
+	logger.KeyValue("Orientation questions", len(questions))
+	return &models.OrientationQuestionResponse{
+		Date:      now.Format("2006-01-02"),
+		Questions: questions,
+	}
+}
+
+// QuestionCacheStats returns how many questions are cached, including expired
+// ones not yet compacted, and for how many users
+func (gs *GameService) QuestionCacheStats() (int, int) {
+	gs.cacheMutex.RLock()
+	defer gs.cacheMutex.RUnlock()
+
+	users := map[string]bool{}
+	for _, stored := range gs.questionCache {
+		users[stored.UserID] = true
+	}
+	return len(gs.questionCache), len(users)
+}
+
+// EvictQuestions removes the cached question with the ID key or, when there is
+// none, the cached questions of the user with the ID key, and returns how many
+// were removed
+func (gs *GameService) EvictQuestions(key string) int {
+	gs.cacheMutex.Lock()
+	_, exists := gs.questionCache[key]
+	if exists {
+		delete(gs.questionCache, key)
+	}
+	gs.cacheMutex.Unlock()
+
+	if exists {
+		return 1
+	}
+	return gs.DeleteUserQuestions(key)
+}
+
+// DeleteUserQuestions removes the user's cached questions, including those
+// awaiting review, and returns how many there were
+func (gs *GameService) DeleteUserQuestions(userID string) int {
+	gs.cacheMutex.Lock()
+	defer gs.cacheMutex.Unlock()
+
+	removed := 0
+	for qID, stored := range gs.questionCache {
+		if stored.UserID == userID {
+			delete(gs.questionCache, qID)
+			removed++
+		}
+	}
+	return removed
+}
+
 // ============================================================================
 // Helper Methods - Question Generation
 // ============================================================================
 
-func (gs *GameService) generateFillInTheBlankQuestion(ctx context.Context, conv models.RAGConversationSearchResult, topic string) (*models.FillInTheBlankQuestionResponse, error) {
+func (gs *GameService) generateFillInTheBlankQuestion(ctx context.Context, conv models.RAGConversationSearchResult, topic string) (*models.GameQuestionResponse, error) {
 	conversationContent := gs.extractConversationContent(conv)
-	baseQuestion, err := gs.openaiService.GenerateFillInTheBlankQuestion(ctx, conversationContent, topic)
+	baseQuestion, err := gs.generateVerified(ctx, conversationContent, func() (*models.GameQuestionResponse, error) {
+		return gs.openaiService.GenerateFillInTheBlankQuestion(ctx, conversationContent, topic)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.FillInTheBlankQuestionResponse{
+	return &models.GameQuestionResponse{
 		QuestionID:          uuid.New().String(),
 		QuestionType:        util.QuestionTypeFillInBlank,
 		Question:            baseQuestion.Question,
 		Options:             baseQuestion.Options,
 		CorrectAnswer:       baseQuestion.CorrectAnswer,
 		BasedOnConversation: conv.ConversationID,
+		Generation:          baseQuestion.Generation,
 		Difficulty:          gs.determineDifficultyFromConversation(conv),
 		Metadata: models.QuestionMetadata{
 			Topic:                 topic,
@@ -160,20 +865,23 @@ func (gs *GameService) generateFillInTheBlankQuestion(ctx context.Context, conv
 	}, nil
 }
 
-func (gs *GameService) generateMultipleChoiceQuestion(ctx context.Context, conv models.RAGConversationSearchResult, topic string) (*models.MultipleChoiceQuestionResponse, error) {
+func (gs *GameService) generateMultipleChoiceQuestion(ctx context.Context, conv models.RAGConversationSearchResult, topic string) (*models.GameQuestionResponse, error) {
 	conversationContent := gs.extractConversationContent(conv)
-	baseQuestion, err := gs.openaiService.GenerateMultipleChoiceQuestion(ctx, conversationContent, topic)
+	baseQuestion, err := gs.generateVerified(ctx, conversationContent, func() (*models.GameQuestionResponse, error) {
+		return gs.openaiService.GenerateMultipleChoiceQuestion(ctx, conversationContent, topic)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.MultipleChoiceQuestionResponse{
+	return &models.GameQuestionResponse{
 		QuestionID:          uuid.New().String(),
 		QuestionType:        util.QuestionTypeMultipleChoice,
 		Question:            baseQuestion.Question,
 		Options:             baseQuestion.Options,
 		CorrectAnswer:       baseQuestion.CorrectAnswer,
 		BasedOnConversation: conv.ConversationID,
+		Generation:          baseQuestion.Generation,
 		Difficulty:          gs.determineDifficultyFromConversation(conv),
 		Metadata: models.QuestionMetadata{
 			Topic:                 topic,
@@ -183,6 +891,240 @@ func (gs *GameService) generateMultipleChoiceQuestion(ctx context.Context, conv
 	}, nil
 }
 
+// generateIntegrativeQuestion builds a question spanning several related conversations.
+// These questions are always hard and are dated by their oldest source conversation.
+func (gs *GameService) generateIntegrativeQuestion(ctx context.Context, convs []models.RAGConversationSearchResult, topic string) (*models.GameQuestionResponse, error) {
+	contents := make([]string, 0, len(convs))
+	conversationIDs := make([]string, 0, len(convs))
+	oldest := convs[0]
+	for _, conv := range convs {
+		contents = append(contents, gs.extractConversationContent(conv))
+		conversationIDs = append(conversationIDs, conv.ConversationID)
+		if conv.Timestamp.Before(oldest.Timestamp) {
+			oldest = conv
+		}
+	}
+
+	baseQuestion, err := gs.generateVerified(ctx, strings.Join(contents, "\n\n"), func() (*models.GameQuestionResponse, error) {
+		return gs.openaiService.GenerateIntegrativeQuestion(ctx, contents, topic)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GameQuestionResponse{
+		QuestionID:           uuid.New().String(),
+		QuestionType:         util.QuestionTypeIntegrative,
+		Question:             baseQuestion.Question,
+		Options:              baseQuestion.Options,
+		CorrectAnswer:        baseQuestion.CorrectAnswer,
+		BasedOnConversations: conversationIDs,
+		Generation:           baseQuestion.Generation,
+		Difficulty:           util.DifficultyHard,
+		Metadata: models.QuestionMetadata{
+			Topic:                 topic,
+			MemoryScore:           convs[0].Score,
+			DaysSinceConversation: int(time.Since(oldest.Timestamp).Hours() / 24),
+		},
+	}, nil
+}
+
+// generatePhotoQuestion builds a recognition question about the people and places in a
+// photo. The caregiver's description is used when given, otherwise the photo is captioned
+// by the vision model. It returns the question and the description it was based on.
+func (gs *GameService) generatePhotoQuestion(ctx context.Context, req *models.GameQuestionRequest) (*models.GameQuestionResponse, string, error) {
+	logger := gs.logger.WithContext(ctx)
+
+	if req.ImageURL == "" {
+		logger.Error("Missing photo", fmt.Errorf("image_url is required"))
+		return nil, "", fmt.Errorf("invalid_photo: image_url is required for photo questions")
+	}
+
+	description := textutil.Normalize(req.ImageDescription)
+	if description == "" {
+		caption, err := gs.openaiService.DescribePhoto(ctx, req.ImageURL)
+		if err != nil {
+			logger.Error("Failed to describe photo", err)
+			return nil, "", err
+		}
+		description = caption
+	}
+
+	difficulty := req.DifficultyHint
+	if difficulty == "" {
+		difficulty = util.DifficultyMedium
+	}
+	topic := textutil.Truncate(textutil.FirstSentence(description), textutil.MaxTopicRunes)
+	logger.KeyValue("Difficulty", difficulty, "Topic", topic)
+
+	baseQuestion, err := gs.generateVerified(ctx, description, func() (*models.GameQuestionResponse, error) {
+		return gs.openaiService.GeneratePhotoQuestion(ctx, description)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &models.GameQuestionResponse{
+		QuestionID:    uuid.New().String(),
+		QuestionType:  util.QuestionTypePhoto,
+		Question:      baseQuestion.Question,
+		Options:       baseQuestion.Options,
+		CorrectAnswer: baseQuestion.CorrectAnswer,
+		ImageURL:      req.ImageURL,
+		Generation:    baseQuestion.Generation,
+		Difficulty:    difficulty,
+		Metadata: models.QuestionMetadata{
+			Topic: topic,
+		},
+	}, description, nil
+}
+
+// generateProfileQuestion builds a question about one of the user's personal info facts,
+// for users with too few conversations. High-importance facts are preferred. It returns
+// the question and the fact it was based on.
+func (gs *GameService) generateProfileQuestion(ctx context.Context, req *models.GameQuestionRequest) (*models.GameQuestionResponse, string, error) {
+	profile, err := gs.ragClient.GetPersonalInfoByUser(ctx, req.UserID)
+	if err != nil {
+		gs.logger.WithContext(ctx).Error("Failed to get personal info", err)
+		return nil, "", fmt.Errorf("insufficient_data: personal info unavailable: %w", err)
+	}
+
+	var facts, important []models.PersonalInfoResponse
+	if profile != nil {
+		for _, item := range profile.Items {
+			if item.Category == util.CategoryCorrection || item.Category == util.CategoryAvoidTopic || item.Category == util.CategoryCareMode || item.Category == util.CategoryEmergency {
+				continue
+			}
+			facts = append(facts, item)
+			if item.Importance == "high" {
+				important = append(important, item)
+			}
+		}
+	}
+	if len(important) > 0 {
+		facts = important
+	}
+	if len(facts) == 0 {
+		return nil, "", fmt.Errorf("insufficient_data: no personal info to ask about")
+	}
+
+	fact := facts[rand.Intn(len(facts))]
+	content := textutil.Normalize(fact.Content)
+	topic := textutil.Truncate(textutil.FirstSentence(content), textutil.MaxTopicRunes)
+
+	questionType, generate := util.QuestionTypeMultipleChoice, gs.openaiService.GenerateMultipleChoiceQuestion
+	if req.QuestionType == util.QuestionTypeFillInBlank {
+		questionType, generate = util.QuestionTypeFillInBlank, gs.openaiService.GenerateFillInTheBlankQuestion
+	}
+
+	baseQuestion, err := gs.generateVerified(ctx, content, func() (*models.GameQuestionResponse, error) {
+		return generate(ctx, content, topic)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	difficulty := req.DifficultyHint
+	if difficulty == "" {
+		difficulty = util.DifficultyEasy
+	}
+
+	return &models.GameQuestionResponse{
+		QuestionID:          uuid.New().String(),
+		QuestionType:        questionType,
+		Question:            baseQuestion.Question,
+		Options:             baseQuestion.Options,
+		CorrectAnswer:       baseQuestion.CorrectAnswer,
+		BasedOnPersonalInfo: fact.ID,
+		Generation:          baseQuestion.Generation,
+		Difficulty:          difficulty,
+		Metadata: models.QuestionMetadata{
+			Topic:       topic,
+			MemoryScore: 1,
+		},
+	}, content, nil
+}
+
+// searchConversations searches the user's conversations, limited to those saved
+// at or after from and before to unless both are zero
+func (gs *GameService) searchConversations(ctx context.Context, query string, limit int, from, to time.Time) ([]models.RAGConversationSearchResult, error) {
+	if from.IsZero() && to.IsZero() {
+		return gs.ragClient.SearchConversations(ctx, query, limit)
+	}
+
+	page, err := gs.ragClient.SearchConversationsPage(ctx, models.RAGSearchPageRequest{
+		Query: query,
+		Limit: limit,
+		From:  from,
+		To:    to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
+
+// windowBounds returns the bounds of a requested time window, zero where open
+func windowBounds(window models.TimeWindow) (time.Time, time.Time, error) {
+	var from, to time.Time
+	if window.From != nil {
+		from = *window.From
+	}
+	if window.To != nil {
+		to = *window.To
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid_window: from must be before to")
+	}
+	return from, to, nil
+}
+
+// fetchThemeConversations searches RAG with each of the theme's queries and keeps the distinct
+// conversations inside the theme's date window, if any, that mention a theme keyword, oldest first.
+// Individual query failures are tolerated as long as one query succeeds.
+func (gs *GameService) fetchThemeConversations(ctx context.Context, theme quizTheme) ([]models.RAGConversationSearchResult, error) {
+	logger := gs.logger.WithContext(ctx)
+
+	var from, to time.Time
+	if theme.window != nil {
+		from, to = theme.window(time.Now())
+	}
+	seen := make(map[string]bool)
+	var matched []models.RAGConversationSearchResult
+	var lastErr error
+	succeeded := 0
+
+	for _, query := range theme.queries {
+		results, err := gs.searchConversations(ctx, query, 20, from, to)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Theme query %q failed", query), err)
+			lastErr = err
+			continue
+		}
+		succeeded++
+
+		for _, conv := range results {
+			if seen[conv.ConversationID] || !theme.matches(conv, from, to) {
+				continue
+			}
+			seen[conv.ConversationID] = true
+			matched = append(matched, conv)
+		}
+	}
+
+	if succeeded == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Timestamp.Before(matched[j].Timestamp)
+	})
+	if len(matched) > util.MaxQuestionSetConversations {
+		matched = matched[len(matched)-util.MaxQuestionSetConversations:]
+	}
+	return matched, nil
+}
+
 func (gs *GameService) determineDifficulty(hint string, searchResults []models.RAGConversationSearchResult) string {
 	if hint != "" && (hint == util.DifficultyEasy || hint == util.DifficultyMedium || hint == util.DifficultyHard) {
 		return hint
@@ -226,15 +1168,79 @@ func (gs *GameService) selectConversation(searchResults []models.RAGConversation
 	return searchResults[0]
 }
 
+// selectRelatedConversations returns the anchor conversation followed by the conversations
+// sharing the most keywords with it (e.g. the same weekly activity), up to the integrative limit.
+// Conversations sharing fewer than two keywords with the anchor are not considered related.
+func (gs *GameService) selectRelatedConversations(anchor models.RAGConversationSearchResult, searchResults []models.RAGConversationSearchResult) []models.RAGConversationSearchResult {
+	type candidate struct {
+		conv   models.RAGConversationSearchResult
+		shared int
+	}
+
+	anchorKeywords := conversationKeywords(anchor)
+	var candidates []candidate
+	for _, conv := range searchResults {
+		if conv.ConversationID == anchor.ConversationID {
+			continue
+		}
+		if shared := sharedKeywords(anchorKeywords, conversationKeywords(conv)); shared >= 2 {
+			candidates = append(candidates, candidate{conv: conv, shared: shared})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].shared > candidates[j].shared
+	})
+
+	related := []models.RAGConversationSearchResult{anchor}
+	for _, c := range candidates {
+		if len(related) >= util.MaxIntegrativeConversations {
+			break
+		}
+		related = append(related, c.conv)
+	}
+	return related
+}
+
+// conversationKeywords returns the distinct words of two or more characters in a conversation
+func conversationKeywords(conv models.RAGConversationSearchResult) map[string]bool {
+	keywords := make(map[string]bool)
+	for _, msg := range conv.Messages {
+		for _, word := range strings.FieldsFunc(strings.ToLower(msg.Content), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			if utf8.RuneCountInString(word) >= 2 {
+				keywords[word] = true
+			}
+		}
+	}
+	return keywords
+}
+
+// sharedKeywords counts keywords of a that appear in b. Korean particles are attached to
+// words ("산책을", "산책은"), so a word also matches when one is a prefix of the other.
+func sharedKeywords(a, b map[string]bool) int {
+	shared := 0
+	for wordA := range a {
+		if b[wordA] {
+			shared++
+			continue
+		}
+		for wordB := range b {
+			if strings.HasPrefix(wordA, wordB) || strings.HasPrefix(wordB, wordA) {
+				shared++
+				break
+			}
+		}
+	}
+	return shared
+}
+
 func (gs *GameService) extractTopic(conv models.RAGConversationSearchResult) string {
 	if len(conv.Messages) > 0 {
-		content := conv.Messages[0].Content
-		if len(content) > 50 {
-			return content[:50]
-		}
-		return content
+		return textutil.Truncate(textutil.FirstSentence(conv.Messages[0].Content), textutil.MaxTopicRunes)
 	}
-	return "일반"
+	return util.DefaultTopic
 }
 
 func (gs *GameService) extractConversationContent(conv models.RAGConversationSearchResult) string {
@@ -252,8 +1258,50 @@ func (gs *GameService) extractConversationContent(conv models.RAGConversationSea
 // Helper Methods - Evaluation
 // ============================================================================
 
-func (gs *GameService) calculateRetentionScore(req *models.GameResultRequest) float32 {
-	weights := gs.cfg.MemoryEvaluationWeights
+// evaluateMemory scores a game result with the configured strategy. The llm strategy needs
+// the question text, so results for expired questions, and failed model calls, use the formula.
+func (gs *GameService) evaluateMemory(ctx context.Context, req *models.GameResultRequest, cachedQuestion *models.StoredQuestion, topic string) models.MemoryEvaluation {
+	if gs.cfg.MemoryEvaluationStrategy == util.EvaluationStrategyLLM && cachedQuestion != nil {
+		evaluation, err := gs.openaiService.EvaluateMemory(ctx, cachedQuestion.Question, req.UserAnswer, req.IsCorrect, req.ResponseTimeMs, topic)
+		if err == nil {
+			evaluation.Topic = topic
+			evaluation.Strategy = util.EvaluationStrategyLLM
+			if evaluation.Confidence == "" {
+				evaluation.Confidence = gs.determineConfidence(evaluation.RetentionScore)
+			}
+			if evaluation.Recommendation == "" {
+				evaluation.Recommendation = gs.getRecommendation(evaluation.RetentionScore)
+			}
+			return *evaluation
+		}
+		gs.logger.WithContext(ctx).Warn("Failed to evaluate memory with the model, using formula", err)
+	}
+
+	retentionScore, breakdown := gs.calculateRetentionScore(req, cachedQuestion, time.Now())
+	return models.MemoryEvaluation{
+		Topic:          topic,
+		RetentionScore: retentionScore,
+		ScoreBreakdown: breakdown,
+		Confidence:     gs.determineConfidence(retentionScore),
+		Recommendation: gs.getRecommendation(retentionScore),
+		Strategy:       util.EvaluationStrategyFormula,
+	}
+}
+
+// calculateRetentionScore scores a result from weighted correctness, speed and recency,
+// using the weight set for the question's difficulty when one is configured. The question
+// is nil when it has expired from the cache.
+func (gs *GameService) calculateRetentionScore(req *models.GameResultRequest, question *models.StoredQuestion, now time.Time) (float32, models.RetentionScoreBreakdown) {
+	difficulty, sourceTime := "", time.Time{}
+	if question != nil {
+		difficulty, sourceTime = question.Difficulty, question.SourceTimestamp
+	}
+
+	settings := gs.cfg.Runtime.Get()
+	weights, ok := settings.MemoryEvaluationDifficultyWeights[difficulty]
+	if !ok {
+		weights = settings.MemoryEvaluationWeights
+	}
 
 	// Correct answer score (50% weight)
 	correctScore := float32(0.0)
@@ -270,15 +1318,49 @@ func (gs *GameService) calculateRetentionScore(req *models.GameResultRequest) fl
 	}
 
 	// Recency score (20% weight)
-	recencyScore := float32(1.0)
+	recencyScore := gs.recencyScore(sourceTime, now)
 
-	return weights[0]*correctScore + weights[1]*timeScore + weights[2]*recencyScore
+	breakdown := models.RetentionScoreBreakdown{
+		Correctness: scoreComponent(correctScore, weights[0]),
+		Speed:       scoreComponent(timeScore, weights[1]),
+		Recency:     scoreComponent(recencyScore, weights[2]),
+	}
+	score := breakdown.Correctness.Contribution + breakdown.Speed.Contribution + breakdown.Recency.Contribution
+
+	return score, breakdown
+}
+
+// recencyScore decays with the age of the question's source conversation along the
+// configured curve. Results without a dated source (photo or expired questions) score 1.
+func (gs *GameService) recencyScore(sourceTime, now time.Time) float32 {
+	if sourceTime.IsZero() {
+		return 1.0
+	}
+	days := math.Max(0, now.Sub(sourceTime).Hours()/24)
+
+	switch gs.cfg.RecencyDecayCurve {
+	case util.RecencyDecayLinear:
+		return float32(math.Max(0, 1-days/gs.cfg.RecencyDecayDays))
+	case util.RecencyDecayNone:
+		return 1.0
+	default:
+		return float32(math.Pow(0.5, days/gs.cfg.RecencyDecayDays))
+	}
+}
+
+func scoreComponent(score, weight float32) models.ScoreComponent {
+	return models.ScoreComponent{
+		Score:        score,
+		Weight:       weight,
+		Contribution: score * weight,
+	}
 }
 
 func (gs *GameService) determineConfidence(score float32) string {
-	if score >= 0.8 {
+	settings := gs.cfg.Runtime.Get()
+	if score >= settings.ConfidenceHighThreshold {
 		return util.ConfidenceHigh
-	} else if score >= 0.5 {
+	} else if score >= settings.ConfidenceMediumThreshold {
 		return util.ConfidenceMedium
 	}
 	return util.ConfidenceLow
@@ -295,33 +1377,116 @@ func (gs *GameService) getRecommendation(score float32) string {
 	return "이 주제는 잘 기억하지 못하고 있습니다. 자주 복습해주세요."
 }
 
-func (gs *GameService) suggestNextDifficulty(score float32) string {
+// suggestNextDifficulty suggests a difficulty from the result's retention score, moved one
+// step harder or easier when the user's recent attempts are mostly correct or mostly not,
+// and never easier than the user's preferred floor
+func (gs *GameService) suggestNextDifficulty(userID string, score float32) string {
+	suggested := util.DifficultyEasy
 	if score >= 0.8 {
-		return util.DifficultyHard
+		suggested = util.DifficultyHard
 	} else if score >= 0.5 {
-		return util.DifficultyMedium
+		suggested = util.DifficultyMedium
+	}
+
+	accuracy, attempts := gs.quizHistoryService.RecentAccuracy(userID, util.RecentPerformanceAttempts)
+	if attempts >= util.MinRecentAttempts {
+		if accuracy >= util.EasyAccuracyThreshold {
+			suggested = shiftDifficulty(suggested, 1)
+		} else if accuracy < util.HardAccuracyThreshold {
+			suggested = shiftDifficulty(suggested, -1)
+		}
+	}
+	return raiseToFloor(suggested, gs.userService.Preferences(userID).QuizDifficultyFloor)
+}
+
+// difficultyFloor returns the easiest difficulty the user prefers to be asked,
+// or none when the request names a difficulty itself
+func (gs *GameService) difficultyFloor(req *models.GameQuestionRequest) string {
+	if req.DifficultyHint != "" {
+		return ""
 	}
-	return util.DifficultyEasy
+	return gs.userService.Preferences(req.UserID).QuizDifficultyFloor
 }
 
-func (gs *GameService) cacheQuestion(q interface{}) {
+// cacheQuestion stores a generated question with its lineage: the source conversations,
+// the recorded generation and the results of validating it against its source
+func (gs *GameService) cacheQuestion(userID string, q *models.GameQuestionResponse, sources []models.RAGConversationSearchResult, sourceContent string, reviewStatus string) {
+	now := time.Now()
+	validation := validateQuestion(q, sourceContent)
+
+	var sourceTime time.Time
+	for _, conv := range sources {
+		if sourceTime.IsZero() || conv.Timestamp.Before(sourceTime) {
+			sourceTime = conv.Timestamp
+		}
+	}
+
 	gs.cacheMutex.Lock()
 	defer gs.cacheMutex.Unlock()
 
-	var qID string
-	switch v := q.(type) {
-	case *models.FillInTheBlankQuestionResponse:
-		qID = v.QuestionID
-	case *models.MultipleChoiceQuestionResponse:
-		qID = v.QuestionID
-	default:
-		return
+	gs.questionCache[q.QuestionID] = &models.StoredQuestion{
+		QuestionID:           q.QuestionID,
+		UserID:               userID,
+		QuestionType:         q.QuestionType,
+		Question:             q.Question,
+		Options:              q.Options,
+		CorrectAnswer:        q.CorrectAnswer,
+		BasedOnConversation:  q.BasedOnConversation,
+		BasedOnConversations: q.BasedOnConversations,
+		ImageURL:             q.ImageURL,
+		Difficulty:           q.Difficulty,
+		Topic:                q.Metadata.Topic,
+		ReviewStatus:         reviewStatus,
+		Payload:              q,
+		SourceContent:        sourceContent,
+		SourceConversations:  sources,
+		SourceTimestamp:      sourceTime,
+		Generation:           q.Generation,
+		Validation:           validation,
+		GeneratedAt:          now,
+		ExpiresAt:            now.Add(gs.cfg.Runtime.Get().QuestionCacheTTL),
 	}
+}
 
-	gs.questionCache[qID] = &models.StoredQuestion{
-		QuestionID: qID,
-		ExpiresAt:  time.Now().Add(24 * time.Hour),
+// questionCandidateLimit is how many recent conversations a question is chosen from.
+// It never drops below the configured minimum, which could otherwise never be met.
+func (gs *GameService) questionCandidateLimit() int {
+	return max(util.QuestionCandidateConversations, gs.cfg.MinConversationsForGame)
+}
+
+// gradeAnswer matches the answer to an option by ID ("b", "B)") or by its text
+// and reports whether that option is the correct one
+func gradeAnswer(q *models.StoredQuestion, answer string) (string, bool) {
+	answer = textutil.Normalize(answer)
+	id := strings.TrimRight(answer, ").")
+	for _, opt := range q.Options {
+		if strings.EqualFold(id, opt.ID) || strings.EqualFold(answer, textutil.Normalize(opt.Text)) {
+			return opt.ID, strings.EqualFold(opt.ID, q.CorrectAnswer)
+		}
+	}
+	return "", false
+}
+
+// optionText returns the text of the option with the given ID
+func optionText(options []models.QuestionOption, id string) string {
+	for _, opt := range options {
+		if strings.EqualFold(opt.ID, id) {
+			return opt.Text
+		}
+	}
+	return id
+}
+
+// factID identifies the remembered fact a cached question tests, so repeated
+// questions about the same conversation or photo are tracked together
+func (gs *GameService) factID(q *models.StoredQuestion) string {
+	if len(q.BasedOnConversations) > 0 {
+		return strings.Join(q.BasedOnConversations, "+")
+	}
+	if q.ImageURL != "" {
+		return "photo:" + q.ImageURL
 	}
+	return q.BasedOnConversation
 }
 
 func (gs *GameService) getCachedQuestion(qID string) *models.StoredQuestion {
@@ -332,11 +1497,40 @@ func (gs *GameService) getCachedQuestion(qID string) *models.StoredQuestion {
 	if !exists || time.Now().After(q.ExpiresAt) {
 		return nil
 	}
-	return q
+	// Return a copy since reviews update cached questions in place
+	copied := *q
+	return &copied
 }
 
-func (gs *GameService) saveEvaluation(ctx context.Context, req *models.GameResultRequest, topic string, retentionScore float32) {
-	gs.logger.Start("Async: Save Evaluation")
+// notifyCaregivers publishes webhook events when a result crosses below the
+// retention alert threshold or completes a run of incorrect answers
+func (gs *GameService) notifyCaregivers(ctx context.Context, req *models.GameResultRequest, topic string, retentionScore float32) {
+	trend := gs.quizHistoryService.TrackResult(req.UserID, req.IsCorrect, retentionScore)
+
+	threshold := gs.cfg.RetentionAlertThreshold
+	if trend.HasPrevious && trend.PreviousScore >= threshold && retentionScore < threshold {
+		gs.webhookService.Publish(ctx, webhook.EventRetentionDropped, req.UserID, models.RetentionDroppedEvent{
+			QuestionID:     req.QuestionID,
+			Topic:          topic,
+			RetentionScore: retentionScore,
+			PreviousScore:  trend.PreviousScore,
+			Threshold:      threshold,
+		})
+	}
+
+	if gs.cfg.IncorrectStreakAlertCount > 0 && trend.IncorrectStreak == gs.cfg.IncorrectStreakAlertCount {
+		gs.webhookService.Publish(ctx, webhook.EventIncorrectStreak, req.UserID, models.IncorrectStreakEvent{
+			QuestionID: req.QuestionID,
+			Topic:      topic,
+			Streak:     trend.IncorrectStreak,
+		})
+	}
+}
+
+func (gs *GameService) saveEvaluation(ctx context.Context, req *models.GameResultRequest, topic string, retentionScore float32, cohort Cohort) {
+	logger := gs.logger.WithContext(ctx)
+
+	logger.Start("Async: Save Evaluation")
 
 	saveReq := &models.RAGConversationSaveRequest{
 		ConversationID: fmt.Sprintf("memory_eval_%s", uuid.New().String()),
@@ -347,34 +1541,39 @@ func (gs *GameService) saveEvaluation(ctx context.Context, req *models.GameResul
 			},
 		},
 		Metadata: &models.RAGMetadata{
-			Type:           "memory_evaluation",
+			Type:           util.ConversationTypeMemoryEvaluation,
 			RetentionScore: retentionScore,
 			QuestionID:     req.QuestionID,
+			PromptVariant:  cohort.PromptVersion,
+			Model:          cohort.Model,
 		},
 	}
 
 	_, err := gs.ragClient.SaveConversation(ctx, saveReq)
 	if err != nil {
-		gs.logger.Warn("Failed to save evaluation", err)
+		logger.Warn("Failed to save evaluation", err)
 	} else {
-		gs.logger.Success("Evaluation saved")
+		logger.Success("Evaluation saved")
 	}
 
-	gs.logger.End("Async: Save Evaluation")
+	logger.End("Async: Save Evaluation")
 }
 
-func (gs *GameService) cleanupCacheRoutine() {
-	ticker := time.NewTicker(time.Minute)
-	defer ticker.Stop()
+// CompactQuestionCache removes expired questions and returns how many were
+// removed and the approximate bytes they held
+func (gs *GameService) CompactQuestionCache(now time.Time) (int, int64) {
+	gs.cacheMutex.Lock()
+	defer gs.cacheMutex.Unlock()
 
-	for range ticker.C {
-		gs.cacheMutex.Lock()
-		now := time.Now()
-		for qID, q := range gs.questionCache {
-			if now.After(q.ExpiresAt) {
-				delete(gs.questionCache, qID)
+	removed, reclaimed := 0, int64(0)
+	for qID, q := range gs.questionCache {
+		if now.After(q.ExpiresAt) {
+			if data, err := json.Marshal(q); err == nil {
+				reclaimed += int64(len(data))
 			}
+			delete(gs.questionCache, qID)
+			removed++
 		}
-		gs.cacheMutex.Unlock()
 	}
+	return removed, reclaimed
 }
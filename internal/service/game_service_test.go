@@ -0,0 +1,202 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"llm/internal/client/ragtest"
+	"llm/internal/config"
+	"llm/internal/fakellm"
+	"llm/internal/models"
+	"llm/internal/service"
+	"llm/internal/testutil"
+	"llm/internal/util"
+)
+
+func newTestGameService(t *testing.T, cfg *config.Config, store *ragtest.Fake) *service.GameService {
+	t.Helper()
+
+	userService, err := service.NewUserService(cfg)
+	if err != nil {
+		t.Fatalf("NewUserService() error = %v", err)
+	}
+	return service.NewGameService(cfg, store, fakellm.New(), service.NewExperimentService(cfg), service.NewQuizHistoryService(), service.NewWebhookService(cfg), userService)
+}
+
+// seedConversations gives the user count conversations, one a day up to yesterday
+func seedConversations(store *ragtest.Fake, userID string, count int) {
+	for i := 0; i < count; i++ {
+		store.AddConversation(userID, models.RAGConversationSearchResult{
+			ConversationID: fmt.Sprintf("conv-%d", i),
+			Timestamp:      time.Now().AddDate(0, 0, -count+i),
+			Messages: []models.RAGMessage{
+				{Role: "user", Content: "지난 추석에 큰딸이랑 고향집에 다녀왔어."},
+				{Role: "assistant", Content: "고향집에서 즐거우셨겠어요."},
+			},
+		})
+	}
+}
+
+func TestGenerateQuestionMinConversations(t *testing.T) {
+	tests := []struct {
+		name             string
+		minConversations string
+		conversations    int
+		wantErr          string
+	}{
+		{name: "one below the minimum", minConversations: "3", conversations: 2, wantErr: "insufficient_data: need at least 3 conversations, got 2"},
+		{name: "at the minimum", minConversations: "3", conversations: 3},
+		{name: "default minimum", minConversations: "", conversations: 4, wantErr: "insufficient_data: need at least 5 conversations, got 4"},
+		{name: "minimum above the candidate limit", minConversations: "25", conversations: 25},
+		{name: "no conversations", minConversations: "1", conversations: 0, wantErr: "insufficient_data: need at least 1 conversations, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			if tt.minConversations != "" {
+				env["MIN_CONVERSATIONS_FOR_GAME"] = tt.minConversations
+			}
+			cfg := testutil.LoadConfig(t, env)
+			store := ragtest.NewFake()
+			seedConversations(store, "user-1", tt.conversations)
+			gs := newTestGameService(t, cfg, store)
+
+			req := &models.GameQuestionRequest{UserID: "user-1", QuestionType: util.QuestionTypeMultipleChoice}
+			question, err := gs.GenerateQuestion(context.Background(), req)
+			_, dryRunErr := gs.DryRunQuestion(context.Background(), req)
+
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("GenerateQuestion() error = %v, want %q", err, tt.wantErr)
+				}
+				if dryRunErr == nil || dryRunErr.Error() != tt.wantErr {
+					t.Errorf("DryRunQuestion() error = %v, want %q", dryRunErr, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GenerateQuestion() error = %v", err)
+			}
+			if dryRunErr != nil {
+				t.Errorf("DryRunQuestion() error = %v", dryRunErr)
+			}
+			if question.QuestionID == "" {
+				t.Error("GenerateQuestion() returned a question without an ID")
+			}
+		})
+	}
+}
+
+func TestQuestionCacheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		elapsed time.Duration
+		wantTTL time.Duration
+		removed int
+	}{
+		{name: "default TTL keeps a day-old question", elapsed: 23 * time.Hour, wantTTL: 24 * time.Hour, removed: 0},
+		{name: "default TTL expires after a day", elapsed: 25 * time.Hour, wantTTL: 24 * time.Hour, removed: 1},
+		{name: "configured TTL keeps a fresh question", ttl: "60", elapsed: 30 * time.Second, wantTTL: time.Minute, removed: 0},
+		{name: "configured TTL expires", ttl: "60", elapsed: 2 * time.Minute, wantTTL: time.Minute, removed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			if tt.ttl != "" {
+				env["QUESTION_CACHE_TTL"] = tt.ttl
+			}
+			cfg := testutil.LoadConfig(t, env)
+			store := ragtest.NewFake()
+			seedConversations(store, "user-1", cfg.MinConversationsForGame)
+			gs := newTestGameService(t, cfg, store)
+
+			question, err := gs.GenerateQuestion(context.Background(), &models.GameQuestionRequest{UserID: "user-1", QuestionType: util.QuestionTypeFillInBlank})
+			if err != nil {
+				t.Fatalf("GenerateQuestion() error = %v", err)
+			}
+			lineage, err := gs.GetQuestionLineage(context.Background(), question.QuestionID)
+			if err != nil {
+				t.Fatalf("GetQuestionLineage() error = %v", err)
+			}
+			if ttl := lineage.ExpiresAt.Sub(lineage.GeneratedAt); ttl != tt.wantTTL {
+				t.Errorf("question expires after %v, want %v", ttl, tt.wantTTL)
+			}
+
+			if removed, _ := gs.CompactQuestionCache(lineage.GeneratedAt.Add(tt.elapsed)); removed != tt.removed {
+				t.Errorf("CompactQuestionCache() removed %d, want %d", removed, tt.removed)
+			}
+		})
+	}
+}
+
+func TestConfigRejectsInvalidGameSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "zero minimum conversations", env: map[string]string{"MIN_CONVERSATIONS_FOR_GAME": "0"}, wantErr: "MIN_CONVERSATIONS_FOR_GAME"},
+		{name: "zero cache TTL", env: map[string]string{"QUESTION_CACHE_TTL": "0"}, wantErr: "QUESTION_CACHE_TTL"},
+		{name: "negative cache TTL", env: map[string]string{"QUESTION_CACHE_TTL": "-5"}, wantErr: "QUESTION_CACHE_TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FAKE_LLM_ENABLED", "true")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if _, err := config.Load(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("config.Load() error = %v, want one naming %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnswerReviewedQuestion(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string // review action, empty to leave the question pending
+		wantErr string
+	}{
+		{name: "pending question", wantErr: "question_pending_review:"},
+		{name: "approved question", action: util.ReviewActionApprove},
+		{name: "rejected question", action: util.ReviewActionReject, wantErr: "question_rejected:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.LoadConfig(t, nil)
+			store := ragtest.NewFake()
+			seedConversations(store, "user-1", cfg.MinConversationsForGame)
+			gs := newTestGameService(t, cfg, store)
+
+			preview, err := gs.PreviewQuestion(context.Background(), &models.GameQuestionRequest{UserID: "user-1", QuestionType: util.QuestionTypeMultipleChoice})
+			if err != nil {
+				t.Fatalf("PreviewQuestion() error = %v", err)
+			}
+			if tt.action != "" {
+				if _, err := gs.ReviewQuestion(context.Background(), preview.QuestionID, &models.QuestionReviewRequest{Action: tt.action}); err != nil {
+					t.Fatalf("ReviewQuestion() error = %v", err)
+				}
+			}
+
+			_, err = gs.AnswerQuestion(context.Background(), preview.QuestionID, &models.QuestionAnswerRequest{UserID: "user-1", Answer: preview.Question.CorrectAnswer})
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("AnswerQuestion() error = %v, want prefix %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("AnswerQuestion() error = %v", err)
+			}
+		})
+	}
+}
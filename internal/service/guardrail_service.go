@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/guardrail"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// GuardrailService checks user input and assistant output for elder-safety risks
+// with custom Korean rules and the OpenAI moderation API, and audits every intervention
+type GuardrailService struct {
+	openaiService     LLMService
+	rules             []guardrail.Rule
+	enabled           bool
+	moderationEnabled bool
+	audit             *guardrail.AuditLog
+	logger            *util.Logger
+}
+
+// Verdict is the outcome of a guardrail check
+type Verdict struct {
+	Stage       string
+	Action      string // guardrail.ActionAllow, ActionRewrite, or ActionBlock
+	Categories  []string
+	Replacement string // safe reply to send instead, set unless the action is allow
+}
+
+// NewGuardrailService creates a new guardrail service
+func NewGuardrailService(cfg *config.Config, openaiService LLMService) *GuardrailService {
+	gr := &GuardrailService{
+		openaiService:     openaiService,
+		rules:             guardrail.DefaultRules,
+		enabled:           cfg.GuardrailsEnabled,
+		moderationEnabled: cfg.GuardrailModerationEnabled,
+		logger:            util.NewLogger("GuardrailService"),
+	}
+
+	if cfg.GuardrailsEnabled {
+		audit, err := guardrail.NewAuditLog(cfg.GuardrailAuditDir)
+		if err != nil {
+			gr.logger.Warn("Guardrail audit log disabled", err)
+		} else {
+			gr.audit = audit
+		}
+	}
+
+	return gr
+}
+
+// CheckInput checks a user message before it reaches the model
+func (gr *GuardrailService) CheckInput(ctx context.Context, userID, text string) Verdict {
+	return gr.check(ctx, guardrail.StageInput, userID, text)
+}
+
+// CheckOutput checks an assistant response before it is returned
+func (gr *GuardrailService) CheckOutput(ctx context.Context, userID, text string) Verdict {
+	return gr.check(ctx, guardrail.StageOutput, userID, text)
+}
+
+func (gr *GuardrailService) check(ctx context.Context, stage, userID, text string) Verdict {
+	verdict := Verdict{Stage: stage, Action: guardrail.ActionAllow}
+	if !gr.enabled {
+		return verdict
+	}
+
+	logger := gr.logger.WithContext(ctx)
+
+	// Custom rules
+	matches := guardrail.Evaluate(gr.rules, stage, text)
+	matchedText := []string{}
+	for _, match := range matches {
+		verdict.escalate(match.Rule.Action, match.Rule.Category)
+		matchedText = append(matchedText, match.Text)
+	}
+
+	// Moderation API; failures fall back to the custom rules alone
+	moderated := false
+	var flagged []string
+	if gr.moderationEnabled {
+		var err error
+		flagged, err = gr.openaiService.Moderate(ctx, text)
+		if err != nil {
+			logger.Warn("Moderation check failed, using custom rules only", err)
+		}
+		for _, category := range flagged {
+			verdict.escalate(guardrail.ActionBlock, guardrail.ModerationCategory(category))
+		}
+		moderated = len(flagged) > 0
+	}
+
+	if verdict.Action == guardrail.ActionAllow {
+		return verdict
+	}
+
+	verdict.Replacement = guardrail.SafeResponse(verdict.Categories[0])
+
+	// Emit the safety event
+	logger.Section("Guardrail Triggered")
+	logger.KeyValue("Stage", stage, "Action", verdict.Action, "Categories", verdict.Categories)
+	for _, category := range verdict.Categories {
+		metrics.GuardrailEvents.Add(fmt.Sprintf("%s.%s.%s", stage, category, verdict.Action), 1)
+	}
+
+	// The audit log keeps a hash and a masked excerpt rather than the text itself
+	if gr.audit != nil {
+		hash, excerpt := guardrail.RedactForAudit(text)
+		event := guardrail.Event{
+			RequestID:  util.RequestIDFromContext(ctx),
+			UserID:     userID,
+			Stage:      stage,
+			Action:     verdict.Action,
+			Categories: verdict.Categories,
+			Matches:    guardrail.RedactMatches(matchedText),
+			Moderated:  moderated,
+			Flagged:    flagged,
+			Hash:       hash,
+			Excerpt:    excerpt,
+			CreatedAt:  time.Now(),
+		}
+		if err := gr.audit.Write(event); err != nil {
+			logger.Warn("Failed to write guardrail audit event", err)
+		}
+	}
+
+	return verdict
+}
+
+// info converts the verdict for API responses
+func (v Verdict) info() *models.GuardrailInfo {
+	return &models.GuardrailInfo{
+		Stage:      v.Stage,
+		Action:     v.Action,
+		Categories: v.Categories,
+	}
+}
+
+// escalate raises the verdict to action if it is more severe and records the category.
+// The category of the most severe action is kept first so it picks the safe reply.
+func (v *Verdict) escalate(action, category string) {
+	others := []string{}
+	for _, existing := range v.Categories {
+		if existing != category {
+			others = append(others, existing)
+		}
+	}
+
+	if guardrail.Severity(action) > guardrail.Severity(v.Action) {
+		v.Action = action
+		v.Categories = append([]string{category}, others...)
+		return
+	}
+	if len(others) == len(v.Categories) {
+		v.Categories = append(v.Categories, category)
+	}
+}
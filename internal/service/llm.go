@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"llm/internal/models"
+	"llm/internal/prompts"
+)
+
+// LLMService is the language model API the services depend on. OpenAIService
+// implements it; package fakellm provides a scripted fake for tests and
+// running without an API key.
+type LLMService interface {
+	ValidateOverrides(overrides models.GenerationOverrides) error
+	EffectiveModel(task string, overrides models.GenerationOverrides) string
+
+	GenerateChatResponse(ctx context.Context, userMessage string, contextMessages []string) (string, error)
+	GenerateChatResponseWithProfile(ctx context.Context, userMessage string, data prompts.ChatPromptData) (string, error)
+	GenerateSmallTalkResponse(ctx context.Context, userMessage string, language string) (string, error)
+	SummarizeConversations(ctx context.Context, contextMessages []string) (string, error)
+	GenerateSearchQuery(ctx context.Context, userMessage string, profileInfo *models.PersonalInfoListResponse) (string, error)
+	RerankContext(ctx context.Context, userMessage string, candidates []string) ([]string, error)
+	SuggestFollowUps(ctx context.Context, userMessage, assistantResponse string, language string) ([]string, error)
+
+	GenerateFillInTheBlankQuestion(ctx context.Context, conversationContent string, topic string) (*models.GameQuestionResponse, error)
+	GenerateMultipleChoiceQuestion(ctx context.Context, conversationContent string, topic string) (*models.GameQuestionResponse, error)
+	GenerateIntegrativeQuestion(ctx context.Context, conversationContents []string, topic string) (*models.GameQuestionResponse, error)
+	DescribePhoto(ctx context.Context, imageURL string) (string, error)
+	GeneratePhotoQuestion(ctx context.Context, description string) (*models.GameQuestionResponse, error)
+	GenerateThemedQuestionSet(ctx context.Context, themeDescription string, conversationContents []string, count int) (*QuestionSet, error)
+	GenerateRecapCards(ctx context.Context, conversationContents []string, missedTopics []string, count int) (*RecapSet, error)
+
+	EvaluateUserResponseQuality(ctx context.Context, userMessage string, contextMessages []string, profileInfo *models.PersonalInfoListResponse) (int, error)
+	AnalyzeSentiment(ctx context.Context, userMessage, assistantResponse string) (*models.Sentiment, error)
+	ClassifyTopics(ctx context.Context, userMessage, assistantResponse string) ([]string, error)
+	ExplainAnswer(ctx context.Context, q *models.StoredQuestion, userAnswer string, isCorrect bool) (string, error)
+	EvaluateMemory(ctx context.Context, question string, userAnswer string, isCorrect bool, responseTimeMs int64, topic string) (*models.MemoryEvaluation, error)
+	Moderate(ctx context.Context, text string) ([]string, error)
+
+	AnalyzeDomains(ctx context.Context, conversationHistory []string, incorrectQuizzes []string) ([]models.DomainScore, error)
+	GenerateAnalysisReport(ctx context.Context, domains []models.DomainScore, consolidation *models.ConsolidationMetric) (string, error)
+	GenerateReportFromDomainScores(ctx context.Context, familyScore int, familyInsights []string, lifeEventsScore int, lifeEventsInsights []string, careerScore int, careerInsights []string, hobbiesScore int, hobbiesInsights []string) (string, error)
+
+	UnavailableModels() []models.ModelWarning
+}
+
+var _ LLMService = (*OpenAIService)(nil)
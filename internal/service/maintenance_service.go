@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"llm/internal/client"
+	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// MaintenanceTask compacts one local store. It is given the run time and
+// returns how many items it removed and the approximate bytes they held.
+type MaintenanceTask struct {
+	Name string
+	Run  func(now time.Time) (int, int64)
+}
+
+// MaintenanceService runs compaction tasks over the in-memory stores nightly
+// and on demand, keeping the report of the last run
+type MaintenanceService struct {
+	cfg        *config.Config
+	tasks      []MaintenanceTask
+	lastReport *models.MaintenanceReport
+	runMutex   sync.Mutex
+	mutex      sync.RWMutex
+	logger     *util.Logger
+}
+
+// NewMaintenanceService creates a maintenance service with the standard tasks.
+// The nightly run starts only when MAINTENANCE_ENABLED is set. ragCache is nil
+// when the RAG read cache is disabled.
+func NewMaintenanceService(cfg *config.Config, chatService *ChatService, gameService *GameService, experimentService *ExperimentService, quizHistoryService *QuizHistoryService, moodService *MoodService, planService *PlanService, reminderService *ReminderService, assessmentService *AssessmentService, ragCache *client.CachedStore) *MaintenanceService {
+	ms := &MaintenanceService{
+		cfg:    cfg,
+		logger: util.NewLogger("MaintenanceService"),
+	}
+
+	ms.tasks = []MaintenanceTask{
+		{Name: util.MaintenanceTaskExpiredQuestions, Run: gameService.CompactQuestionCache},
+		{Name: util.MaintenanceTaskUsage, Run: func(now time.Time) (int, int64) {
+			return experimentService.CompactUsage(now.AddDate(0, 0, -cfg.UsageRetentionDays))
+		}},
+		{Name: util.MaintenanceTaskQuizHistory, Run: func(now time.Time) (int, int64) {
+			return quizHistoryService.CompactHistory(now.AddDate(0, 0, -cfg.QuizHistoryRetentionDays))
+		}},
+		// Mood entries outside the longest timeline window can no longer be viewed
+		{Name: util.MaintenanceTaskMood, Run: func(now time.Time) (int, int64) {
+			return moodService.CompactEntries(now.AddDate(0, 0, -util.MaxMoodTimelineDays))
+		}},
+		{Name: util.MaintenanceTaskSemanticCache, Run: chatService.CompactSemanticCache},
+		{Name: util.MaintenanceTaskGoalCoverage, Run: func(now time.Time) (int, int64) {
+			return planService.CompactCoverage(now.AddDate(0, 0, -util.GoalCoverageDays))
+		}},
+		{Name: util.MaintenanceTaskReminders, Run: func(now time.Time) (int, int64) {
+			return reminderService.CompactReminders(now.AddDate(0, 0, -util.ReminderRetentionDays))
+		}},
+		{Name: util.MaintenanceTaskAssessments, Run: func(now time.Time) (int, int64) {
+			return assessmentService.CompactAssessments(now.AddDate(0, 0, -util.AssessmentRetentionDays))
+		}},
+	}
+	if ragCache != nil {
+		ms.tasks = append(ms.tasks, MaintenanceTask{Name: util.MaintenanceTaskRAGCache, Run: ragCache.Compact})
+	}
+
+	if cfg.MaintenanceEnabled {
+		go ms.maintenanceRoutine()
+	}
+	return ms
+}
+
+// Report returns the most recent maintenance run, or nil when none has run yet
+func (ms *MaintenanceService) Report() *models.MaintenanceReport {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	return ms.lastReport
+}
+
+// Run compacts every store now. Runs never overlap; a run requested while
+// another is in progress waits for it to finish.
+func (ms *MaintenanceService) Run(ctx context.Context) *models.MaintenanceReport {
+	logger := ms.logger.WithContext(ctx)
+
+	ms.runMutex.Lock()
+	defer ms.runMutex.Unlock()
+
+	logger.Start("Maintenance")
+
+	report := &models.MaintenanceReport{
+		Tasks:     []models.MaintenanceTaskResult{},
+		StartedAt: time.Now(),
+	}
+
+	for _, task := range ms.tasks {
+		started := time.Now()
+		removed, reclaimed := task.Run(started)
+
+		report.Tasks = append(report.Tasks, models.MaintenanceTaskResult{
+			Name:           task.Name,
+			Removed:        removed,
+			ReclaimedBytes: reclaimed,
+			DurationMs:     time.Since(started).Milliseconds(),
+		})
+		report.TotalRemoved += removed
+		report.TotalReclaimedBytes += reclaimed
+
+		metrics.MaintenanceRemoved.Add(task.Name, int64(removed))
+		metrics.MaintenanceReclaimedBytes.Add(task.Name, reclaimed)
+		logger.KeyValue("Task", task.Name, "Removed", removed, "Reclaimed Bytes", reclaimed)
+	}
+
+	report.FinishedAt = time.Now()
+	if ms.cfg.MaintenanceEnabled {
+		next := nextMaintenanceRun(report.FinishedAt, ms.cfg.MaintenanceHour)
+		report.NextRunAt = &next
+	}
+	metrics.MaintenanceRuns.Add(1)
+
+	ms.mutex.Lock()
+	ms.lastReport = report
+	ms.mutex.Unlock()
+
+	logger.Success(fmt.Sprintf("Removed %d items, reclaimed ~%d bytes", report.TotalRemoved, report.TotalReclaimedBytes))
+	logger.End("Maintenance")
+	return report
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (ms *MaintenanceService) maintenanceRoutine() {
+	for {
+		timer := time.NewTimer(time.Until(nextMaintenanceRun(time.Now(), ms.cfg.MaintenanceHour)))
+		<-timer.C
+		ms.Run(context.Background())
+	}
+}
+
+// nextMaintenanceRun returns the next time after now at the given local hour
+func nextMaintenanceRun(now time.Time, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+
+	"llm/internal/metrics"
+	"llm/internal/models"
+)
+
+// modelRecheckInterval is how long calls for an unavailable model go straight to the
+// fallback model before the model is tried again
+const modelRecheckInterval = time.Hour
+
+// unavailableModel records a model the provider rejected as deprecated or not found
+type unavailableModel struct {
+	err        string
+	detectedAt time.Time
+	lastSeenAt time.Time
+}
+
+// isModelUnavailableError reports whether err is the provider rejecting the requested
+// model as deprecated, decommissioned or unknown, as opposed to a transient failure
+func isModelUnavailableError(err error) bool {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if code, ok := apiErr.Code.(string); ok && (code == "model_not_found" || code == "model_decommissioned") {
+		return true
+	}
+	if apiErr.HTTPStatusCode != http.StatusNotFound && apiErr.HTTPStatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	if !strings.Contains(message, "model") {
+		return false
+	}
+	for _, phrase := range []string{"deprecated", "decommissioned", "does not exist", "not found"} {
+		if strings.Contains(message, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// modelForCall returns the model to send a request for model to. Calls for a model
+// found unavailable go to the fallback model until the recheck interval has passed.
+func (os *OpenAIService) modelForCall(model string) string {
+	if os.fallbackModel == "" {
+		return model
+	}
+
+	os.unavailableMutex.Lock()
+	defer os.unavailableMutex.Unlock()
+
+	entry, ok := os.unavailableModels[model]
+	if !ok || time.Since(entry.lastSeenAt) >= modelRecheckInterval {
+		return model
+	}
+	return os.fallbackModel
+}
+
+// markModelUnavailable records that the provider rejected model. The first rejection
+// is logged as a warning, since every later call for the model is degraded.
+func (os *OpenAIService) markModelUnavailable(ctx context.Context, model string, err error) {
+	metrics.ModelUnavailableErrors.Add(model, 1)
+
+	now := time.Now()
+	os.unavailableMutex.Lock()
+	entry, seen := os.unavailableModels[model]
+	if !seen {
+		entry = &unavailableModel{detectedAt: now}
+		os.unavailableModels[model] = entry
+	}
+	entry.err = err.Error()
+	entry.lastSeenAt = now
+	os.unavailableMutex.Unlock()
+
+	if !seen {
+		replacement := os.fallbackModel
+		if replacement == "" {
+			replacement = "none configured, set OPENAI_FALLBACK_MODEL"
+		}
+		os.logger.WithContext(ctx).Warn(fmt.Sprintf("Model %s is deprecated or unavailable (fallback: %s)", model, replacement), err)
+	}
+}
+
+// markModelAvailable forgets an earlier rejection once the model serves a call again
+func (os *OpenAIService) markModelAvailable(model string) {
+	os.unavailableMutex.Lock()
+	defer os.unavailableMutex.Unlock()
+
+	delete(os.unavailableModels, model)
+}
+
+// UnavailableModels returns the models the provider has rejected as deprecated or not
+// found, with the model their calls fall back to, for the health check
+func (os *OpenAIService) UnavailableModels() []models.ModelWarning {
+	os.unavailableMutex.Lock()
+	defer os.unavailableMutex.Unlock()
+
+	warnings := make([]models.ModelWarning, 0, len(os.unavailableModels))
+	for model, entry := range os.unavailableModels {
+		warnings = append(warnings, models.ModelWarning{
+			Model:       model,
+			Replacement: os.fallbackModel,
+			Error:       entry.err,
+			DetectedAt:  entry.detectedAt,
+			LastSeenAt:  entry.lastSeenAt,
+		})
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Model < warnings[j].Model })
+	return warnings
+}
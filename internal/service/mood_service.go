@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// MoodService keeps the sentiment of recent chat turns per user and builds
+// mood timelines for the caregiver dashboard
+type MoodService struct {
+	entries map[string][]models.MoodEntry
+	mutex   sync.RWMutex
+	logger  *util.Logger
+}
+
+// NewMoodService creates a new mood service
+func NewMoodService() *MoodService {
+	return &MoodService{
+		entries: make(map[string][]models.MoodEntry),
+		logger:  util.NewLogger("MoodService"),
+	}
+}
+
+// Record stores the sentiment of a chat turn, keeping the most recent entries per user
+func (ms *MoodService) Record(userID, conversationID string, sentiment *models.Sentiment) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	entries := append(ms.entries[userID], models.MoodEntry{
+		ConversationID: conversationID,
+		MoodScore:      sentiment.MoodScore,
+		Emotion:        sentiment.Emotion,
+		CreatedAt:      time.Now(),
+	})
+	if len(entries) > util.MaxMoodEntriesPerUser {
+		entries = entries[len(entries)-util.MaxMoodEntriesPerUser:]
+	}
+	ms.entries[userID] = entries
+}
+
+// Timeline returns the user's mood over the last days, with a daily summary
+// (oldest day first) and every scored turn in the window
+func (ms *MoodService) Timeline(ctx context.Context, req *models.MoodTimelineRequest) *models.MoodTimelineResponse {
+	logger := ms.logger.WithContext(ctx)
+
+	days := req.Days
+	if days == 0 {
+		days = util.DefaultMoodTimelineDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	ms.mutex.RLock()
+	entries := []models.MoodEntry{}
+	for _, entry := range ms.entries[req.UserID] {
+		if entry.CreatedAt.After(since) {
+			entries = append(entries, entry)
+		}
+	}
+	ms.mutex.RUnlock()
+
+	resp := &models.MoodTimelineResponse{
+		UserID:   req.UserID,
+		Days:     days,
+		Daily:    []models.DailyMood{},
+		Entries:  entries,
+		Emotions: make(map[string]int),
+	}
+
+	type dayStats struct {
+		sum      int
+		turns    int
+		emotions map[string]int
+	}
+	byDay := make(map[string]*dayStats)
+	total := 0
+	for _, entry := range entries {
+		date := entry.CreatedAt.Format("2006-01-02")
+		stats, ok := byDay[date]
+		if !ok {
+			stats = &dayStats{emotions: make(map[string]int)}
+			byDay[date] = stats
+		}
+		stats.sum += entry.MoodScore
+		stats.turns++
+		stats.emotions[entry.Emotion]++
+		resp.Emotions[entry.Emotion]++
+		total += entry.MoodScore
+	}
+
+	for date, stats := range byDay {
+		resp.Daily = append(resp.Daily, models.DailyMood{
+			Date:            date,
+			AverageScore:    float32(stats.sum) / float32(stats.turns),
+			DominantEmotion: dominantEmotion(stats.emotions),
+			Turns:           stats.turns,
+		})
+	}
+	sort.Slice(resp.Daily, func(i, j int) bool {
+		return resp.Daily[i].Date < resp.Daily[j].Date
+	})
+
+	if len(entries) > 0 {
+		average := float32(total) / float32(len(entries))
+		resp.AverageScore = &average
+	}
+
+	logger.KeyValue("Mood Entries", len(entries), "Days", days)
+	return resp
+}
+
+// CompactEntries removes mood entries recorded before the cutoff and users left
+// without entries. It returns how many entries were removed and the approximate
+// bytes they held.
+func (ms *MoodService) CompactEntries(before time.Time) (int, int64) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for userID, entries := range ms.entries {
+		// Entries are appended in time order, so the old ones form a prefix
+		cut := sort.Search(len(entries), func(i int) bool {
+			return !entries[i].CreatedAt.Before(before)
+		})
+		for _, entry := range entries[:cut] {
+			reclaimed += int64(unsafe.Sizeof(entry)) + int64(len(entry.ConversationID)+len(entry.Emotion))
+		}
+		removed += cut
+
+		if cut == len(entries) {
+			delete(ms.entries, userID)
+		} else if cut > 0 {
+			ms.entries[userID] = append([]models.MoodEntry(nil), entries[cut:]...)
+		}
+	}
+	return removed, reclaimed
+}
+
+// dominantEmotion returns the most frequent emotion, breaking ties alphabetically
+func dominantEmotion(counts map[string]int) string {
+	dominant, best := "", 0
+	for emotion, count := range counts {
+		if count > best || (count == best && emotion < dominant) {
+			dominant, best = emotion, count
+		}
+	}
+	return dominant
+}
+
+// Entries returns all of the user's mood entries, oldest first
+func (ms *MoodService) Entries(userID string) []models.MoodEntry {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	return append([]models.MoodEntry{}, ms.entries[userID]...)
+}
+
+// DeleteUser removes all of the user's mood entries and returns how many there were
+func (ms *MoodService) DeleteUser(userID string) int {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	removed := len(ms.entries[userID])
+	delete(ms.entries, userID)
+	return removed
+}
@@ -1,38 +1,239 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 
+	"llm/internal/chaos"
 	"llm/internal/config"
+	"llm/internal/httptransport"
+	"llm/internal/metrics"
 	"llm/internal/models"
 	"llm/internal/prompts"
+	"llm/internal/textutil"
 	"llm/internal/util"
 )
 
-// OpenAIService handles all interactions with OpenAI API
+// OpenAIService handles all interactions with OpenAI API, or another provider
+// serving the OpenAI API such as Ollama
 type OpenAIService struct {
-	client      *openai.Client
-	model       string
-	temperature float32
-	maxTokens   int
-	logger      *util.Logger
+	client         atomic.Pointer[openai.Client] // rebuilt when the API key rotates
+	apiKey         atomic.Pointer[string]        // for the endpoints called directly
+	baseURL        string                        // empty for the OpenAI API
+	httpClient     *http.Client
+	runtime        *config.Runtime
+	summaryModel   string
+	visionModel    string
+	taskModels     map[string]string // LLM task -> model; tasks without one use the main model
+	taskGeneration map[string]config.TaskGeneration
+	modelAllowlist map[string]bool
+	modelMap       map[string]string
+	moderation     bool          // the provider offers the moderation API
+	fallbackModel  string        // replacement for models the provider rejects as deprecated or not found
+	tools          *ToolRegistry // tools offered to the chat model, nil or empty for none
+	logger         *util.Logger
+
+	contextWindowOverride int    // context window for every model; 0 uses the known model windows
+	contextOverflow       string // trim or reject prompts that exceed the context window
+
+	unavailableModels map[string]*unavailableModel
+	unavailableMutex  sync.Mutex
 }
 
-// NewOpenAIService creates a new OpenAI service instance
-func NewOpenAIService(cfg *config.Config) *OpenAIService {
-	return &OpenAIService{
-		client:      openai.NewClient(cfg.OpenAIAPIKey),
-		model:       cfg.OpenAIModel,
-		temperature: cfg.OpenAITemperature,
-		maxTokens:   cfg.OpenAIMaxTokens,
-		logger:      util.NewLogger("OpenAIService"),
+// NewOpenAIService creates a new OpenAI service instance. Chat responses may call
+// the tools in the registry, which may be nil.
+func NewOpenAIService(cfg *config.Config, tools *ToolRegistry) *OpenAIService {
+	modelAllowlist := make(map[string]bool)
+	for _, model := range cfg.OpenAIModelAllowlist {
+		modelAllowlist[model] = true
+	}
+
+	os := &OpenAIService{
+		baseURL: strings.TrimRight(cfg.OpenAIBaseURL, "/"),
+		httpClient: &http.Client{
+			Transport: chaos.WrapTransport(cfg, chaos.TargetOpenAI, httptransport.OpenAI(cfg)),
+		},
+		runtime:      cfg.Runtime,
+		summaryModel: cfg.OpenAISummaryModel,
+		visionModel:  cfg.OpenAIVisionModel,
+		taskModels: map[string]string{
+			util.LLMTaskChat:       cfg.OpenAIChatModel,
+			util.LLMTaskEvaluation: cfg.OpenAIEvaluationModel,
+			util.LLMTaskQuestion:   cfg.OpenAIQuestionModel,
+			util.LLMTaskVision:     cfg.OpenAIVisionModel,
+			util.LLMTaskAnalysis:   cfg.OpenAIAnalysisModel,
+		},
+		taskGeneration: cfg.OpenAITaskGeneration,
+		modelAllowlist: modelAllowlist,
+		modelMap:       cfg.OpenAIModelMap,
+		moderation:     cfg.LLMProvider == config.LLMProviderOpenAI,
+		fallbackModel:  cfg.OpenAIFallbackModel,
+		tools:          tools,
+		logger:         util.NewLogger("OpenAIService"),
+
+		contextWindowOverride: cfg.OpenAIContextWindow,
+		contextOverflow:       cfg.ContextOverflowMode,
+
+		unavailableModels: make(map[string]*unavailableModel),
+	}
+	os.SetAPIKey(cfg.OpenAIAPIKey)
+	return os
+}
+
+// SetAPIKey rebuilds the client with a rotated API key. Calls already in flight
+// finish with the previous client.
+func (os *OpenAIService) SetAPIKey(apiKey string) {
+	os.apiKey.Store(&apiKey)
+	openaiConfig := openai.DefaultConfig(apiKey)
+	if os.baseURL != "" {
+		openaiConfig.BaseURL = os.baseURL
+	}
+	openaiConfig.HTTPClient = os.httpClient
+	os.client.Store(openai.NewClientWithConfig(openaiConfig))
+}
+
+type generationOverridesKey struct{}
+
+// WithGenerationOverrides returns a copy of ctx whose main-model calls use the given overrides.
+// Calls made with other contexts (evaluation, summarization) keep the server settings.
+func WithGenerationOverrides(ctx context.Context, overrides models.GenerationOverrides) context.Context {
+	return context.WithValue(ctx, generationOverridesKey{}, overrides)
+}
+
+// ValidateOverrides checks per-request overrides against the model allowlist.
+// The currently configured main and task models are always allowed.
+func (os *OpenAIService) ValidateOverrides(overrides models.GenerationOverrides) error {
+	if overrides.Model == "" || overrides.Model == os.runtime.Get().OpenAIModel {
+		return nil
+	}
+	for _, model := range os.taskModels {
+		if overrides.Model == model {
+			return nil
+		}
+	}
+	if !os.modelAllowlist[overrides.Model] {
+		return fmt.Errorf("invalid_override: model %q is not allowed", overrides.Model)
+	}
+	return nil
+}
+
+// EffectiveModel returns the model a request for the task with the given overrides is served with
+func (os *OpenAIService) EffectiveModel(task string, overrides models.GenerationOverrides) string {
+	if overrides.Model != "" {
+		return overrides.Model
+	}
+	return os.taskModel(task)
+}
+
+// taskModel returns the model configured for the task, or the main model
+func (os *OpenAIService) taskModel(task string) string {
+	if model := os.taskModels[task]; model != "" {
+		return model
+	}
+	return os.runtime.Get().OpenAIModel
+}
+
+// chatMessages assembles the chat prompt for a turn. When a previous summary is set it
+// replaces the raw context messages, which are otherwise added for the latest three.
+func chatMessages(userMessage string, data prompts.ChatPromptData) []openai.ChatCompletionMessage {
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.ChatSystemPrompt(data)},
+	}
+
+	// Add raw context only when no summary is available
+	contextMessages := data.ContextMessages
+	if data.PreviousSummary == "" && len(contextMessages) > 0 {
+		contextLimit := 3
+		if len(contextMessages) < contextLimit {
+			contextLimit = len(contextMessages)
+		}
+
+		contextStr := "최근 대화 이력:\n"
+		for i := 0; i < contextLimit; i++ {
+			contextStr += fmt.Sprintf("- %s\n", contextMessages[i])
+		}
+
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleAssistant,
+			Content: contextStr,
+		})
+	}
+
+	// Add user message, with the attached photos as image parts
+	if len(data.ImageURLs) == 0 {
+		return append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleUser,
+			Content: userMessage,
+		})
+	}
+
+	parts := []openai.ChatMessagePart{{Type: openai.ChatMessagePartTypeText, Text: userMessage}}
+	for _, imageURL := range data.ImageURLs {
+		parts = append(parts, openai.ChatMessagePart{
+			Type:     openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{URL: imageURL, Detail: openai.ImageURLDetailAuto},
+		})
+	}
+	return append(messages, openai.ChatCompletionMessage{
+		Role:         openai.ChatMessageRoleUser,
+		MultiContent: parts,
+	})
+}
+
+// smallTalkMessages assembles the minimal prompt used for small talk
+func smallTalkMessages(userMessage string, language string) []openai.ChatCompletionMessage {
+	return []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.SmallTalkSystemPrompt(language)},
+		{Role: openai.ChatMessageRoleUser, Content: userMessage},
 	}
 }
 
+// questionMessages assembles the question generation prompt for a question type.
+// Photo questions take the photo description as their only content and no topic.
+func questionMessages(questionType string, contents []string, topic string) []openai.ChatCompletionMessage {
+	var systemPrompt, userPrompt string
+	switch questionType {
+	case util.QuestionTypeMultipleChoice:
+		systemPrompt = prompts.MultipleChoiceQuestionSystemPrompt()
+		userPrompt = prompts.MultipleChoiceQuestionUserPrompt(contents[0], topic)
+	case util.QuestionTypeIntegrative:
+		systemPrompt = prompts.IntegrativeQuestionSystemPrompt()
+		userPrompt = prompts.IntegrativeQuestionUserPrompt(contents, topic)
+	case util.QuestionTypePhoto:
+		systemPrompt = prompts.PhotoQuestionSystemPrompt()
+		userPrompt = prompts.PhotoQuestionUserPrompt(contents[0])
+	default:
+		systemPrompt = prompts.FillInTheBlankQuestionSystemPrompt()
+		userPrompt = prompts.FillInTheBlankQuestionUserPrompt(contents[0], topic)
+	}
+
+	return []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+	}
+}
+
+// toPromptMessages converts assembled prompt messages for dry-run responses
+func toPromptMessages(messages []openai.ChatCompletionMessage) []models.PromptMessage {
+	result := make([]models.PromptMessage, len(messages))
+	for i, msg := range messages {
+		result[i] = models.PromptMessage{Role: msg.Role, Content: msg.Content}
+	}
+	return result
+}
+
 // GenerateChatResponse generates a simple chat response
 func (os *OpenAIService) GenerateChatResponse(ctx context.Context, userMessage string, contextMessages []string) (string, error) {
 	messages := []openai.ChatCompletionMessage{
@@ -56,7 +257,7 @@ func (os *OpenAIService) GenerateChatResponse(ctx context.Context, userMessage s
 		Content: userMessage,
 	})
 
-	content, err := os.callOpenAI(ctx, messages)
+	content, err := os.callOpenAI(ctx, util.LLMTaskChat, messages)
 	if err != nil {
 		return "", err
 	}
@@ -65,82 +266,218 @@ func (os *OpenAIService) GenerateChatResponse(ctx context.Context, userMessage s
 }
 
 // GenerateChatResponseWithProfile generates a response with user profile and incorrect attempts
-func (os *OpenAIService) GenerateChatResponseWithProfile(ctx context.Context, userMessage string, contextMessages []string, profileInfo *models.PersonalInfoListResponse, incorrectAttempts *models.IncorrectQuizAttemptsResponse) (string, error) {
-	os.logger.Start("Chat Response Generation")
+// When a previous summary is set it replaces the raw context messages in the prompt.
+func (os *OpenAIService) GenerateChatResponseWithProfile(ctx context.Context, userMessage string, data prompts.ChatPromptData) (string, error) {
+	logger := os.logger.WithContext(ctx)
 
-	systemPrompt := prompts.ChatSystemPrompt(profileInfo, incorrectAttempts)
-	os.logger.Section("System Prompt")
-	os.logger.Info(systemPrompt)
+	logger.Start("Chat Response Generation")
 
-	// Build messages
-	messages := []openai.ChatCompletionMessage{
-		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+	messages := chatMessages(userMessage, data)
+	logger.Verbose("System Prompt", messages[0].Content)
+	if len(messages) > 2 {
+		logger.Verbose("Context", messages[1].Content)
 	}
 
-	// Add context
-	if len(contextMessages) > 0 {
-		contextLimit := 3
-		if len(contextMessages) < contextLimit {
-			contextLimit = len(contextMessages)
-		}
+	// Turns with photos need an image-capable model
+	task := util.LLMTaskChat
+	if len(data.ImageURLs) > 0 {
+		task = util.LLMTaskVision
+	}
 
-		contextStr := "최근 대화 이력:\n"
-		for i := 0; i < contextLimit; i++ {
-			contextStr += fmt.Sprintf("- %s\n", contextMessages[i])
-		}
+	logger.Section("Calling OpenAI")
+	content, err := os.callOpenAIWithTools(ctx, task, messages)
+	if err != nil {
+		logger.Error("Failed to generate response", err)
+		logger.End("Chat Response Generation")
+		return "", err
+	}
 
-		os.logger.Section("Context")
-		os.logger.Info(contextStr)
+	logger.Success("Response generated")
+	logger.End("Chat Response Generation")
+	return content, nil
+}
 
-		messages = append(messages, openai.ChatCompletionMessage{
-			Role:    openai.ChatMessageRoleAssistant,
-			Content: contextStr,
-		})
+// GenerateSmallTalkResponse answers an acknowledgement, thanks or greeting with a
+// minimal prompt and no retrieved context
+func (os *OpenAIService) GenerateSmallTalkResponse(ctx context.Context, userMessage string, language string) (string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Small Talk Response")
+
+	messages := smallTalkMessages(userMessage, language)
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskChat, messages)
+	if err != nil {
+		logger.Error("Failed to generate small talk response", err)
+		logger.End("Small Talk Response")
+		return "", err
 	}
 
-	// Add user message
-	messages = append(messages, openai.ChatCompletionMessage{
-		Role:    openai.ChatMessageRoleUser,
-		Content: userMessage,
-	})
+	logger.Success("Response generated")
+	logger.End("Small Talk Response")
+	return content, nil
+}
 
-	os.logger.Section("Calling OpenAI")
-	content, err := os.callOpenAI(ctx, messages)
+// SummarizeConversations compresses retrieved conversation messages into a short recap
+// using the cheaper summary model
+func (os *OpenAIService) SummarizeConversations(ctx context.Context, contextMessages []string) (string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Conversation Summary")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.ConversationSummarySystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.ConversationSummaryUserPrompt(contextMessages)},
+	}
+
+	content, err := os.callOpenAIWithModel(ctx, os.summaryModel, messages)
 	if err != nil {
-		os.logger.Error("Failed to generate response", err)
-		os.logger.End("Chat Response Generation")
+		logger.Error("Failed to summarize conversations", err)
+		logger.End("Conversation Summary")
 		return "", err
 	}
 
-	os.logger.Success("Response generated")
-	os.logger.End("Chat Response Generation")
+	logger.Verbose("Summary", content)
+	logger.End("Conversation Summary")
 	return content, nil
 }
 
-// GenerateFillInTheBlankQuestion generates a fill-in-the-blank question
-func (os *OpenAIService) GenerateFillInTheBlankQuestion(ctx context.Context, conversationContent string, topic string) (*models.FillInTheBlankQuestionResponse, error) {
-	os.logger.Start("Fill-in-the-blank Question Generation")
+// GenerateSearchQuery rewrites the user message into a conversation search query
+// using the cheaper summary model
+func (os *OpenAIService) GenerateSearchQuery(ctx context.Context, userMessage string, profileInfo *models.PersonalInfoListResponse) (string, error) {
+	logger := os.logger.WithContext(ctx)
 
-	systemPrompt := prompts.FillInTheBlankQuestionSystemPrompt()
-	userPrompt := prompts.FillInTheBlankQuestionUserPrompt(conversationContent, topic)
+	logger.Start("Search Query Generation")
 
 	messages := []openai.ChatCompletionMessage{
-		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
-		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.SearchQuerySystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.SearchQueryUserPrompt(userMessage, profileInfo)},
+	}
+
+	content, err := os.callOpenAIWithModel(ctx, os.summaryModel, messages)
+	if err != nil {
+		logger.Error("Failed to generate search query", err)
+		logger.End("Search Query Generation")
+		return "", err
+	}
+
+	query := strings.Trim(strings.TrimSpace(content), "\"")
+	if query == "" {
+		logger.End("Search Query Generation")
+		return "", fmt.Errorf("empty search query")
+	}
+
+	logger.Info("Search query: %s", query)
+	logger.End("Search Query Generation")
+	return query, nil
+}
+
+// RerankContext asks the model which retrieved context messages are relevant to the user message.
+// It returns the relevant subset ordered by relevance.
+func (os *OpenAIService) RerankContext(ctx context.Context, userMessage string, candidates []string) ([]string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Context Rerank")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.ContextRerankSystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.ContextRerankUserPrompt(userMessage, candidates)},
+	}
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskChat, messages)
+	if err != nil {
+		logger.Error("Failed to rerank context", err)
+		logger.End("Context Rerank")
+		return nil, err
+	}
+
+	var rerankResult struct {
+		RelevantIndices []int `json:"relevant_indices"`
+	}
+
+	if err := json.Unmarshal([]byte(content), &rerankResult); err != nil {
+		logger.Error("Failed to parse rerank response", err)
+		logger.End("Context Rerank")
+		return nil, fmt.Errorf("failed to parse rerank response: %w", err)
+	}
+
+	relevant := []string{}
+	for _, idx := range rerankResult.RelevantIndices {
+		if idx >= 0 && idx < len(candidates) {
+			relevant = append(relevant, candidates[idx])
+		}
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	logger.KeyValue("Candidates", len(candidates), "Relevant", len(relevant))
+	logger.End("Context Rerank")
+	return relevant, nil
+}
+
+// SuggestFollowUps proposes lines to say if the user goes silent after a reply.
+// It uses the summary model since it is a second call on top of the reply.
+func (os *OpenAIService) SuggestFollowUps(ctx context.Context, userMessage, assistantResponse string, language string) ([]string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Follow-up Suggestions")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.FollowUpSuggestionSystemPrompt(language)},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.FollowUpSuggestionUserPrompt(userMessage, assistantResponse)},
+	}
+
+	content, err := os.callOpenAIWithModel(ctx, os.summaryModel, messages)
+	if err != nil {
+		logger.Error("Failed to suggest follow-ups", err)
+		logger.End("Follow-up Suggestions")
+		return nil, err
+	}
+
+	var result struct {
+		Suggestions []string `json:"suggestions"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		logger.Error("Failed to parse follow-up response", err)
+		logger.End("Follow-up Suggestions")
+		return nil, fmt.Errorf("failed to parse follow-up response: %w", err)
+	}
+
+	// Blank and repeated suggestions are dropped
+	suggestions := []string{}
+	for _, suggestion := range result.Suggestions {
+		suggestion = strings.TrimSpace(suggestion)
+		if suggestion != "" && !slices.Contains(suggestions, suggestion) {
+			suggestions = append(suggestions, suggestion)
+		}
+		if len(suggestions) == util.MaxFollowUpSuggestions {
+			break
+		}
+	}
+
+	logger.KeyValue("Suggestions", len(suggestions))
+	logger.End("Follow-up Suggestions")
+	return suggestions, nil
+}
+
+// GenerateFillInTheBlankQuestion generates a fill-in-the-blank question
+func (os *OpenAIService) GenerateFillInTheBlankQuestion(ctx context.Context, conversationContent string, topic string) (*models.GameQuestionResponse, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Fill-in-the-blank Question Generation")
+
+	messages := questionMessages(util.QuestionTypeFillInBlank, []string{conversationContent}, topic)
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
 	if err != nil {
-		os.logger.Error("Failed to generate question", err)
-		os.logger.End("Fill-in-the-blank Question Generation")
+		logger.Error("Failed to generate question", err)
+		logger.End("Fill-in-the-blank Question Generation")
 		return nil, err
 	}
 
 	// Parse response
 	questionData, err := os.parseQuestionResponse(content)
 	if err != nil {
-		os.logger.Error("Failed to parse response", err)
-		os.logger.End("Fill-in-the-blank Question Generation")
+		logger.Error("Failed to parse response", err)
+		logger.End("Fill-in-the-blank Question Generation")
 		return nil, err
 	}
 
@@ -150,41 +487,79 @@ func (os *OpenAIService) GenerateFillInTheBlankQuestion(ctx context.Context, con
 		options[i] = models.QuestionOption{ID: opt.ID, Text: opt.Text}
 	}
 
-	response := &models.FillInTheBlankQuestionResponse{
+	response := &models.GameQuestionResponse{
 		Question:      questionData.Text,
 		Options:       options,
 		CorrectAnswer: questionData.CorrectAnswer,
+		Generation:    os.questionGeneration(ctx, messages, content),
 	}
 
-	os.logger.Success("Question generated")
-	os.logger.End("Fill-in-the-blank Question Generation")
+	logger.Success("Question generated")
+	logger.End("Fill-in-the-blank Question Generation")
 	return response, nil
 }
 
 // GenerateMultipleChoiceQuestion generates a multiple choice question
-func (os *OpenAIService) GenerateMultipleChoiceQuestion(ctx context.Context, conversationContent string, topic string) (*models.MultipleChoiceQuestionResponse, error) {
-	os.logger.Start("Multiple Choice Question Generation")
+func (os *OpenAIService) GenerateMultipleChoiceQuestion(ctx context.Context, conversationContent string, topic string) (*models.GameQuestionResponse, error) {
+	logger := os.logger.WithContext(ctx)
 
-	systemPrompt := prompts.MultipleChoiceQuestionSystemPrompt()
-	userPrompt := prompts.MultipleChoiceQuestionUserPrompt(conversationContent, topic)
+	logger.Start("Multiple Choice Question Generation")
 
-	messages := []openai.ChatCompletionMessage{
-		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
-		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+	messages := questionMessages(util.QuestionTypeMultipleChoice, []string{conversationContent}, topic)
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
+	if err != nil {
+		logger.Error("Failed to generate question", err)
+		logger.End("Multiple Choice Question Generation")
+		return nil, err
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	// Parse response
+	questionData, err := os.parseQuestionResponse(content)
 	if err != nil {
-		os.logger.Error("Failed to generate question", err)
-		os.logger.End("Multiple Choice Question Generation")
+		logger.Error("Failed to parse response", err)
+		logger.End("Multiple Choice Question Generation")
+		return nil, err
+	}
+
+	// Convert options to models.QuestionOption
+	options := make([]models.QuestionOption, len(questionData.Options))
+	for i, opt := range questionData.Options {
+		options[i] = models.QuestionOption{ID: opt.ID, Text: opt.Text}
+	}
+
+	response := &models.GameQuestionResponse{
+		Question:      questionData.Text,
+		Options:       options,
+		CorrectAnswer: questionData.CorrectAnswer,
+		Generation:    os.questionGeneration(ctx, messages, content),
+	}
+
+	logger.Success("Question generated")
+	logger.End("Multiple Choice Question Generation")
+	return response, nil
+}
+
+// GenerateIntegrativeQuestion generates a multiple choice question that requires combining several conversations
+func (os *OpenAIService) GenerateIntegrativeQuestion(ctx context.Context, conversationContents []string, topic string) (*models.GameQuestionResponse, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Integrative Question Generation")
+
+	messages := questionMessages(util.QuestionTypeIntegrative, conversationContents, topic)
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
+	if err != nil {
+		logger.Error("Failed to generate question", err)
+		logger.End("Integrative Question Generation")
 		return nil, err
 	}
 
 	// Parse response
 	questionData, err := os.parseQuestionResponse(content)
 	if err != nil {
-		os.logger.Error("Failed to parse response", err)
-		os.logger.End("Multiple Choice Question Generation")
+		logger.Error("Failed to parse response", err)
+		logger.End("Integrative Question Generation")
 		return nil, err
 	}
 
@@ -194,20 +569,146 @@ func (os *OpenAIService) GenerateMultipleChoiceQuestion(ctx context.Context, con
 		options[i] = models.QuestionOption{ID: opt.ID, Text: opt.Text}
 	}
 
-	response := &models.MultipleChoiceQuestionResponse{
+	response := &models.GameQuestionResponse{
 		Question:      questionData.Text,
 		Options:       options,
 		CorrectAnswer: questionData.CorrectAnswer,
+		Generation:    os.questionGeneration(ctx, messages, content),
 	}
 
-	os.logger.Success("Question generated")
-	os.logger.End("Multiple Choice Question Generation")
+	logger.Success("Question generated")
+	logger.End("Integrative Question Generation")
 	return response, nil
 }
 
+// DescribePhoto captions a photo with the vision model, describing the people and places in it
+func (os *OpenAIService) DescribePhoto(ctx context.Context, imageURL string) (string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Photo Caption")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.PhotoCaptionSystemPrompt()},
+		{
+			Role: openai.ChatMessageRoleUser,
+			MultiContent: []openai.ChatMessagePart{
+				{Type: openai.ChatMessagePartTypeText, Text: "이 사진을 설명해 주세요."},
+				{Type: openai.ChatMessagePartTypeImageURL, ImageURL: &openai.ChatMessageImageURL{URL: imageURL, Detail: openai.ImageURLDetailAuto}},
+			},
+		},
+	}
+
+	content, err := os.callOpenAIWithModel(ctx, os.visionModel, messages)
+	if err != nil {
+		logger.Error("Failed to caption photo", err)
+		logger.End("Photo Caption")
+		return "", err
+	}
+
+	description := strings.TrimSpace(content)
+	if description == "" {
+		logger.End("Photo Caption")
+		return "", fmt.Errorf("empty photo description")
+	}
+
+	logger.Verbose("Photo Description", description)
+	logger.End("Photo Caption")
+	return description, nil
+}
+
+// GeneratePhotoQuestion generates a multiple choice question about the people and places in a photo
+func (os *OpenAIService) GeneratePhotoQuestion(ctx context.Context, description string) (*models.GameQuestionResponse, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Photo Question Generation")
+
+	messages := questionMessages(util.QuestionTypePhoto, []string{description}, "")
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
+	if err != nil {
+		logger.Error("Failed to generate question", err)
+		logger.End("Photo Question Generation")
+		return nil, err
+	}
+
+	questionData, err := os.parseQuestionResponse(content)
+	if err != nil {
+		logger.Error("Failed to parse response", err)
+		logger.End("Photo Question Generation")
+		return nil, err
+	}
+
+	options := make([]models.QuestionOption, len(questionData.Options))
+	for i, opt := range questionData.Options {
+		options[i] = models.QuestionOption{ID: opt.ID, Text: opt.Text}
+	}
+
+	response := &models.GameQuestionResponse{
+		Question:      questionData.Text,
+		Options:       options,
+		CorrectAnswer: questionData.CorrectAnswer,
+		Generation:    os.questionGeneration(ctx, messages, content),
+	}
+
+	logger.Success("Question generated")
+	logger.End("Photo Question Generation")
+	return response, nil
+}
+
+// GenerateThemedQuestionSet generates an intro line and a set of multiple choice questions for a theme
+func (os *OpenAIService) GenerateThemedQuestionSet(ctx context.Context, themeDescription string, conversationContents []string, count int) (*QuestionSet, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Themed Question Set Generation")
+
+	systemPrompt := prompts.ThemedQuestionSetSystemPrompt()
+	userPrompt := prompts.ThemedQuestionSetUserPrompt(themeDescription, conversationContents, count)
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
+	}
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
+	if err != nil {
+		logger.Error("Failed to generate question set", err)
+		logger.End("Themed Question Set Generation")
+		return nil, err
+	}
+
+	var set QuestionSet
+	if err := json.Unmarshal([]byte(content), &set); err != nil {
+		logger.Error("Failed to parse response", err)
+		logger.End("Themed Question Set Generation")
+		return nil, fmt.Errorf("failed to parse question set response json: %w", err)
+	}
+
+	// Drop incomplete questions rather than failing the whole set
+	complete := set.Questions[:0]
+	for _, q := range set.Questions {
+		if q.Text != "" && len(q.Options) > 0 && q.CorrectAnswer != "" {
+			complete = append(complete, q)
+		}
+	}
+	set.Questions = complete
+	set.Generation = os.questionGeneration(ctx, messages, content)
+
+	if len(set.Questions) == 0 {
+		logger.Error("Empty question set", fmt.Errorf("no complete questions in response"))
+		logger.End("Themed Question Set Generation")
+		return nil, fmt.Errorf("incomplete question set response from openai")
+	}
+
+	logger.Info("Generated %d questions", len(set.Questions))
+	logger.End("Themed Question Set Generation")
+	return &set, nil
+}
+
 // EvaluateUserResponseQuality evaluates the quality of a user's response
 func (os *OpenAIService) EvaluateUserResponseQuality(ctx context.Context, userMessage string, contextMessages []string, profileInfo *models.PersonalInfoListResponse) (int, error) {
-	os.logger.Start("User Response Quality Evaluation")
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("User Response Quality Evaluation")
 
 	systemPrompt := prompts.UserResponseEvaluationSystemPrompt()
 	userPrompt := prompts.UserResponseEvaluationUserPrompt(userMessage, contextMessages, profileInfo)
@@ -217,10 +718,10 @@ func (os *OpenAIService) EvaluateUserResponseQuality(ctx context.Context, userMe
 		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	content, err := os.callOpenAI(ctx, util.LLMTaskEvaluation, messages)
 	if err != nil {
-		os.logger.Error("Failed to evaluate response", err)
-		os.logger.End("User Response Quality Evaluation")
+		logger.Error("Failed to evaluate response", err)
+		logger.End("User Response Quality Evaluation")
 		return util.DefaultResponseScore, err
 	}
 
@@ -231,8 +732,8 @@ func (os *OpenAIService) EvaluateUserResponseQuality(ctx context.Context, userMe
 	}
 
 	if err := json.Unmarshal([]byte(content), &evalResult); err != nil {
-		os.logger.Warn("Failed to parse evaluation response, using default score", err)
-		os.logger.End("User Response Quality Evaluation")
+		logger.Warn("Failed to parse evaluation response, using default score", err)
+		logger.End("User Response Quality Evaluation")
 		return util.DefaultResponseScore, nil
 	}
 
@@ -243,14 +744,136 @@ func (os *OpenAIService) EvaluateUserResponseQuality(ctx context.Context, userMe
 		evalResult.Score = util.MaxScore
 	}
 
-	os.logger.KeyValue("Score", evalResult.Score, "Reasoning", evalResult.Reasoning)
-	os.logger.End("User Response Quality Evaluation")
+	logger.KeyValue("Score", evalResult.Score, "Reasoning", evalResult.Reasoning)
+	logger.End("User Response Quality Evaluation")
 	return evalResult.Score, nil
 }
 
+// AnalyzeSentiment scores the emotional state of a chat turn's user message.
+// It uses the summary model since it runs on every turn.
+func (os *OpenAIService) AnalyzeSentiment(ctx context.Context, userMessage, assistantResponse string) (*models.Sentiment, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Sentiment Analysis")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.SentimentAnalysisSystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.SentimentAnalysisUserPrompt(userMessage, assistantResponse)},
+	}
+
+	content, err := os.callOpenAIWithModel(ctx, os.summaryModel, messages)
+	if err != nil {
+		logger.Error("Failed to analyze sentiment", err)
+		logger.End("Sentiment Analysis")
+		return nil, err
+	}
+
+	var result struct {
+		MoodScore int    `json:"mood_score"`
+		Emotion   string `json:"emotion"`
+		Reasoning string `json:"reasoning"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		logger.Error("Failed to parse sentiment response", err)
+		logger.End("Sentiment Analysis")
+		return nil, fmt.Errorf("failed to parse sentiment response: %w", err)
+	}
+
+	if result.MoodScore < util.MinScore {
+		result.MoodScore = util.MinScore
+	} else if result.MoodScore > util.MaxScore {
+		result.MoodScore = util.MaxScore
+	}
+	if !util.IsEmotion(result.Emotion) {
+		result.Emotion = util.EmotionNeutral
+	}
+
+	logger.KeyValue("Mood Score", result.MoodScore, "Emotion", result.Emotion, "Reasoning", result.Reasoning)
+	logger.End("Sentiment Analysis")
+
+	return &models.Sentiment{MoodScore: result.MoodScore, Emotion: result.Emotion}, nil
+}
+
+// ClassifyTopics tags a chat turn with the conversation topics its user message
+// covers, most prominent first. It uses the summary model since it runs on every turn.
+func (os *OpenAIService) ClassifyTopics(ctx context.Context, userMessage, assistantResponse string) ([]string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Topic Classification")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.TopicClassificationSystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.TopicClassificationUserPrompt(userMessage, assistantResponse)},
+	}
+
+	content, err := os.callOpenAIWithModel(ctx, os.summaryModel, messages)
+	if err != nil {
+		logger.Error("Failed to classify topics", err)
+		logger.End("Topic Classification")
+		return nil, err
+	}
+
+	var result struct {
+		Topics []string `json:"topics"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		logger.Error("Failed to parse topic response", err)
+		logger.End("Topic Classification")
+		return nil, fmt.Errorf("failed to parse topic response: %w", err)
+	}
+
+	// Unknown and repeated topics are dropped
+	topics := []string{}
+	for _, topic := range result.Topics {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if util.IsConversationTopic(topic) && !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+		if len(topics) == util.MaxConversationTopics {
+			break
+		}
+	}
+
+	logger.KeyValue("Topics", strings.Join(topics, ", "))
+	logger.End("Topic Classification")
+	return topics, nil
+}
+
+// ExplainAnswer writes a gentle explanation of a quiz question's correct answer
+// that refers back to the conversation the question was generated from
+func (os *OpenAIService) ExplainAnswer(ctx context.Context, q *models.StoredQuestion, userAnswer string, isCorrect bool) (string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Answer Explanation")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.AnswerExplanationSystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.AnswerExplanationUserPrompt(q.Question, q.Options, q.CorrectAnswer, userAnswer, isCorrect, q.SourceContent)},
+	}
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
+	if err != nil {
+		logger.Error("Failed to generate answer explanation", err)
+		logger.End("Answer Explanation")
+		return "", err
+	}
+
+	explanation := strings.TrimSpace(content)
+	if explanation == "" {
+		logger.End("Answer Explanation")
+		return "", fmt.Errorf("empty answer explanation")
+	}
+
+	logger.Verbose("Explanation", explanation)
+	logger.End("Answer Explanation")
+	return explanation, nil
+}
+
 // EvaluateMemory evaluates user's memory based on game result
 func (os *OpenAIService) EvaluateMemory(ctx context.Context, question string, userAnswer string, isCorrect bool, responseTimeMs int64, topic string) (*models.MemoryEvaluation, error) {
-	os.logger.Start("Memory Evaluation")
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Memory Evaluation")
 
 	systemPrompt := prompts.MemoryEvaluationSystemPrompt()
 	userPrompt := prompts.MemoryEvaluationUserPrompt(question, userAnswer, isCorrect, responseTimeMs, topic)
@@ -260,53 +883,277 @@ func (os *OpenAIService) EvaluateMemory(ctx context.Context, question string, us
 		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	content, err := os.callOpenAI(ctx, util.LLMTaskEvaluation, messages)
 	if err != nil {
-		os.logger.Error("Failed to evaluate memory", err)
-		os.logger.End("Memory Evaluation")
+		logger.Error("Failed to evaluate memory", err)
+		logger.End("Memory Evaluation")
 		return nil, err
 	}
 
-	os.logger.Info("Memory evaluation completed")
-	os.logger.End("Memory Evaluation")
-
-	// Parse response
-	var evalResult struct {
-		RetentionScore float32 `json:"retention_score"`
-		Confidence     string  `json:"confidence"`
-		Recommendation string  `json:"recommendation"`
+	// Parse response; the score is clamped to 0-1
+	evalResult, err := util.ParseMemoryEvaluationResponse(content)
+	if err != nil {
+		logger.Error("Failed to parse memory evaluation", err)
+		logger.End("Memory Evaluation")
+		return nil, err
 	}
 
-	if err := json.Unmarshal([]byte(content), &evalResult); err != nil {
-		return nil, fmt.Errorf("failed to parse memory evaluation: %w", err)
+	// A confidence outside the known labels is left for the caller to derive from the score
+	confidence := strings.ToLower(strings.TrimSpace(evalResult.Confidence))
+	switch confidence {
+	case util.ConfidenceHigh, util.ConfidenceMedium, util.ConfidenceLow:
+	default:
+		logger.Warn("Ignoring unknown confidence label", fmt.Errorf("%q", evalResult.Confidence))
+		confidence = ""
 	}
 
+	logger.KeyValue("Retention Score", evalResult.RetentionScore, "Confidence", confidence)
+	logger.Success("Memory evaluation completed")
+	logger.End("Memory Evaluation")
+
 	return &models.MemoryEvaluation{
 		Topic:          topic,
 		RetentionScore: evalResult.RetentionScore,
-		Confidence:     evalResult.Confidence,
-		Recommendation: evalResult.Recommendation,
+		Confidence:     confidence,
+		Recommendation: strings.TrimSpace(evalResult.Recommendation),
 	}, nil
 }
 
-// callOpenAI makes a call to OpenAI API with given messages
-func (os *OpenAIService) callOpenAI(ctx context.Context, messages []openai.ChatCompletionMessage) (string, error) {
-	resp, err := os.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model:       os.model,
+// moderationModel reports every moderation category, including harassment and the
+// self-harm subcategories the go-openai result type leaves out
+const moderationModel = "omni-moderation-latest"
+
+// Moderate runs text through the OpenAI moderation API and returns the flagged category names.
+// The endpoint is called directly so every category the API reports is returned.
+// Providers without a moderation API flag nothing.
+func (os *OpenAIService) Moderate(ctx context.Context, text string) ([]string, error) {
+	if !os.moderation {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model": moderationModel,
+		"input": text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal moderation request: %w", err)
+	}
+
+	baseURL := os.baseURL
+	if baseURL == "" {
+		baseURL = openai.DefaultConfig("").BaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+*os.apiKey.Load())
+
+	resp, err := os.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai moderation call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read moderation response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai moderation call failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(respBody), textutil.MaxErrorBodyRunes))
+	}
+
+	var apiResp struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal moderation response: %w", err)
+	}
+
+	var flagged []string
+	for _, result := range apiResp.Results {
+		if !result.Flagged {
+			continue
+		}
+		for name, hit := range result.Categories {
+			if hit {
+				flagged = append(flagged, name)
+			}
+		}
+	}
+	sort.Strings(flagged)
+	return flagged, nil
+}
+
+// questionGeneration records the prompt and raw output a question was generated from
+func (os *OpenAIService) questionGeneration(ctx context.Context, messages []openai.ChatCompletionMessage, content string) *models.QuestionGeneration {
+	overrides, _ := ctx.Value(generationOverridesKey{}).(models.GenerationOverrides)
+	return &models.QuestionGeneration{
+		Model:     os.EffectiveModel(util.LLMTaskQuestion, overrides),
+		Prompt:    toPromptMessages(messages),
+		RawOutput: content,
+	}
+}
+
+// callOpenAI makes a call to OpenAI API with given messages, using the task's model
+func (os *OpenAIService) callOpenAI(ctx context.Context, task string, messages []openai.ChatCompletionMessage) (string, error) {
+	return os.createChatCompletion(ctx, os.taskRequest(ctx, task, messages))
+}
+
+// callOpenAIWithTools makes a call to OpenAI API offering the registered tools. The tools
+// the model calls are executed and their results sent back until it answers; after
+// MaxChatToolRounds rounds it must answer without them.
+func (os *OpenAIService) callOpenAIWithTools(ctx context.Context, task string, messages []openai.ChatCompletionMessage) (string, error) {
+	if os.tools.Len() == 0 {
+		return os.callOpenAI(ctx, task, messages)
+	}
+	logger := os.logger.WithContext(ctx)
+
+	// Date arguments such as "last Monday" are resolved against today
+	now := time.Now()
+	messages = append([]openai.ChatCompletionMessage(nil), messages...)
+	messages[0].Content += fmt.Sprintf("\n\n오늘 날짜: %s (%s)", now.Format("2006-01-02"), koreanWeekdays[now.Weekday()])
+
+	req := os.taskRequest(ctx, task, messages)
+	req.Tools = os.tools.definitions()
+	for round := 1; ; round++ {
+		if round > util.MaxChatToolRounds {
+			req.ToolChoice = "none"
+		}
+
+		message, err := os.createChatCompletionMessage(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		if len(message.ToolCalls) == 0 {
+			return message.Content, nil
+		}
+
+		req.Messages = append(req.Messages, message)
+		for _, call := range message.ToolCalls {
+			result, err := os.tools.execute(ctx, call)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Tool %s failed", call.Function.Name), err)
+			} else {
+				logger.KeyValue("Tool", call.Function.Name, "Round", round)
+			}
+			req.Messages = append(req.Messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    result,
+				ToolCallID: call.ID,
+			})
+		}
+	}
+}
+
+// taskRequest builds a chat completion request for the task: its model and generation
+// settings, then the per-request overrides carried by ctx (validated by the caller)
+func (os *OpenAIService) taskRequest(ctx context.Context, task string, messages []openai.ChatCompletionMessage) openai.ChatCompletionRequest {
+	settings := os.runtime.Get()
+	req := openai.ChatCompletionRequest{
+		Model:       os.taskModel(task),
 		Messages:    messages,
-		Temperature: os.temperature,
-		MaxTokens:   os.maxTokens,
+		Temperature: settings.OpenAITemperature,
+		MaxTokens:   settings.OpenAIMaxTokens,
+	}
+
+	if generation, ok := os.taskGeneration[task]; ok {
+		if generation.MaxTokens > 0 {
+			req.MaxTokens = generation.MaxTokens
+		}
+		if generation.Temperature != nil {
+			req.Temperature = *generation.Temperature
+		}
+	}
+	if overrides, ok := ctx.Value(generationOverridesKey{}).(models.GenerationOverrides); ok {
+		if overrides.Model != "" {
+			req.Model = overrides.Model
+		}
+		if overrides.Temperature != nil {
+			req.Temperature = *overrides.Temperature
+		}
+		if overrides.MaxTokens != nil {
+			req.MaxTokens = *overrides.MaxTokens
+		}
+	}
+	return req
+}
+
+// callOpenAIWithModel makes a call to OpenAI API using a specific model
+func (os *OpenAIService) callOpenAIWithModel(ctx context.Context, model string, messages []openai.ChatCompletionMessage) (string, error) {
+	settings := os.runtime.Get()
+	return os.createChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:       model,
+		Messages:    messages,
+		Temperature: settings.OpenAITemperature,
+		MaxTokens:   settings.OpenAIMaxTokens,
 	})
+}
 
+// createChatCompletion sends a chat completion request and returns the first choice's content
+func (os *OpenAIService) createChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (string, error) {
+	message, err := os.createChatCompletionMessage(ctx, req)
 	if err != nil {
-		return "", fmt.Errorf("openai api call failed: %w", err)
+		return "", err
+	}
+	return message.Content, nil
+}
+
+// createChatCompletionMessage sends a chat completion request and returns the first choice's
+// message. When the provider rejects the model as deprecated or not found, the call is retried
+// with the fallback model, which later calls for the model then use directly.
+func (os *OpenAIService) createChatCompletionMessage(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionMessage, error) {
+	requested := req.Model
+	req.Model = os.modelForCall(requested)
+
+	message, err := os.sendChatCompletion(ctx, req)
+	if err == nil {
+		if req.Model == requested {
+			os.markModelAvailable(requested)
+		}
+		return message, nil
+	}
+	if !isModelUnavailableError(err) {
+		return openai.ChatCompletionMessage{}, err
+	}
+
+	os.markModelUnavailable(ctx, req.Model, err)
+	if os.fallbackModel == "" || req.Model == os.fallbackModel {
+		return openai.ChatCompletionMessage{}, err
 	}
 
+	metrics.ModelFallbacks.Add(requested, 1)
+	req.Model = os.fallbackModel
+	return os.sendChatCompletion(ctx, req)
+}
+
+// sendChatCompletion makes a single chat completion call once the prompt passes the token
+// preflight. The model is translated through the provider's model map, if any.
+func (os *OpenAIService) sendChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionMessage, error) {
+	if err := os.preflightTokens(ctx, &req); err != nil {
+		return openai.ChatCompletionMessage{}, err
+	}
+
+	if mapped, ok := os.modelMap[req.Model]; ok {
+		req.Model = mapped
+	}
+
+	resp, err := os.client.Load().CreateChatCompletion(ctx, req)
+
+	if err != nil {
+		return openai.ChatCompletionMessage{}, fmt.Errorf("openai api call failed: %w", err)
+	}
+	util.RequestStatsFromContext(ctx).AddTokens(resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from openai")
+		return openai.ChatCompletionMessage{}, fmt.Errorf("no response from openai")
 	}
 
-	return resp.Choices[0].Message.Content, nil
+	return resp.Choices[0].Message, nil
 }
 
 // parseQuestionResponse parses OpenAI's question response JSON
@@ -334,9 +1181,68 @@ type Question struct {
 	CorrectAnswer string `json:"correct_answer"`
 }
 
+// RecapSet represents a parsed recap cards response
+type RecapSet struct {
+	Cards []RecapSetCard `json:"cards"`
+}
+
+// RecapSetCard is a recap card with the 1-based index of the conversation it is based on
+type RecapSetCard struct {
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	Source      int    `json:"source"`
+	MissedTopic bool   `json:"missed_topic"`
+}
+
+// QuestionSet represents a parsed themed question set response
+type QuestionSet struct {
+	Intro     string            `json:"intro"`
+	Questions []QuestionSetItem `json:"questions"`
+
+	Generation *models.QuestionGeneration `json:"-"` // shared by every question in the set
+}
+
+// QuestionSetItem is a question in a set, with the 1-based index of the conversation it is based on
+type QuestionSetItem struct {
+	Question
+	Source int `json:"source"`
+}
+
+// GenerateRecapCards generates short recap cards from conversations, favouring missed quiz topics
+func (os *OpenAIService) GenerateRecapCards(ctx context.Context, conversationContents []string, missedTopics []string, count int) (*RecapSet, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Recap Card Generation")
+
+	messages := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: prompts.RecapCardsSystemPrompt()},
+		{Role: openai.ChatMessageRoleUser, Content: prompts.RecapCardsUserPrompt(conversationContents, missedTopics, count)},
+	}
+
+	content, err := os.callOpenAI(ctx, util.LLMTaskQuestion, messages)
+	if err != nil {
+		logger.Error("Failed to generate recap cards", err)
+		logger.End("Recap Card Generation")
+		return nil, err
+	}
+
+	var set RecapSet
+	if err := json.Unmarshal([]byte(content), &set); err != nil {
+		logger.Error("Failed to parse response", err)
+		logger.End("Recap Card Generation")
+		return nil, fmt.Errorf("failed to parse recap response json: %w", err)
+	}
+
+	logger.Info("Generated %d recap cards", len(set.Cards))
+	logger.End("Recap Card Generation")
+	return &set, nil
+}
+
 // AnalyzeDomains analyzes user conversation and incorrect quizzes across 4 domains
 func (os *OpenAIService) AnalyzeDomains(ctx context.Context, conversationHistory []string, incorrectQuizzes []string) ([]models.DomainScore, error) {
-	os.logger.Start("Domain Analysis")
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Domain Analysis")
 
 	systemPrompt := prompts.DomainAnalysisSystemPrompt()
 	userPrompt := prompts.DomainAnalysisUserPrompt(conversationHistory, incorrectQuizzes)
@@ -346,10 +1252,10 @@ func (os *OpenAIService) AnalyzeDomains(ctx context.Context, conversationHistory
 		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	content, err := os.callOpenAI(ctx, util.LLMTaskAnalysis, messages)
 	if err != nil {
-		os.logger.Error("Failed to analyze domains", err)
-		os.logger.End("Domain Analysis")
+		logger.Error("Failed to analyze domains", err)
+		logger.End("Domain Analysis")
 		return nil, fmt.Errorf("failed to analyze domains: %w", err)
 	}
 
@@ -374,8 +1280,8 @@ func (os *OpenAIService) AnalyzeDomains(ctx context.Context, conversationHistory
 	}
 
 	if err := json.Unmarshal([]byte(content), &analysisResult); err != nil {
-		os.logger.Error("Failed to parse domain analysis response", err)
-		os.logger.End("Domain Analysis")
+		logger.Error("Failed to parse domain analysis response", err)
+		logger.End("Domain Analysis")
 		return nil, fmt.Errorf("failed to parse domain analysis: %w", err)
 	}
 
@@ -403,16 +1309,19 @@ func (os *OpenAIService) AnalyzeDomains(ctx context.Context, conversationHistory
 		},
 	}
 
-	os.logger.Success("Domain analysis completed")
-	os.logger.KeyValue("Family", analysisResult.Family.Score, "Life Events", analysisResult.LifeEvents.Score, "Career", analysisResult.Career.Score, "Hobbies", analysisResult.Hobbies.Score)
-	os.logger.End("Domain Analysis")
+	logger.Success("Domain analysis completed")
+	logger.KeyValue("Family", analysisResult.Family.Score, "Life Events", analysisResult.LifeEvents.Score, "Career", analysisResult.Career.Score, "Hobbies", analysisResult.Hobbies.Score)
+	logger.End("Domain Analysis")
 
 	return domains, nil
 }
 
-// GenerateAnalysisReport generates a professional markdown report based on domain analysis
-func (os *OpenAIService) GenerateAnalysisReport(ctx context.Context, domains []models.DomainScore) (string, error) {
-	os.logger.Start("Generate Analysis Report")
+// GenerateAnalysisReport generates a professional markdown report based on domain analysis.
+// When consolidation is non-nil, the report also covers long-term memory consolidation.
+func (os *OpenAIService) GenerateAnalysisReport(ctx context.Context, domains []models.DomainScore, consolidation *models.ConsolidationMetric) (string, error) {
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Generate Analysis Report")
 
 	systemPrompt := prompts.AnalysisReportSystemPrompt()
 
@@ -445,28 +1354,41 @@ func (os *OpenAIService) GenerateAnalysisReport(ctx context.Context, domains []m
 		careerScore, careerInsights,
 		hobbiesScore, hobbiesInsights,
 	)
+	if consolidation != nil {
+		userPrompt += prompts.MemoryConsolidationSection(
+			consolidation.Score,
+			consolidation.TrackedFacts,
+			consolidation.ConsolidatedFacts,
+			consolidation.ImmediateOnlyFacts,
+			consolidation.NotRecalledFacts,
+			consolidation.ConsolidatedTopics,
+			consolidation.ImmediateTopics,
+		)
+	}
 
 	messages := []openai.ChatCompletionMessage{
 		{Role: openai.ChatMessageRoleSystem, Content: systemPrompt},
 		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	content, err := os.callOpenAI(ctx, util.LLMTaskAnalysis, messages)
 	if err != nil {
-		os.logger.Error("Failed to generate report", err)
-		os.logger.End("Generate Analysis Report")
+		logger.Error("Failed to generate report", err)
+		logger.End("Generate Analysis Report")
 		return "", fmt.Errorf("failed to generate report: %w", err)
 	}
 
-	os.logger.Success("Report generated successfully")
-	os.logger.End("Generate Analysis Report")
+	logger.Success("Report generated successfully")
+	logger.End("Generate Analysis Report")
 
 	return content, nil
 }
 
 // GenerateReportFromDomainScores generates a report from already-analyzed domain scores
 func (os *OpenAIService) GenerateReportFromDomainScores(ctx context.Context, familyScore int, familyInsights []string, lifeEventsScore int, lifeEventsInsights []string, careerScore int, careerInsights []string, hobbiesScore int, hobbiesInsights []string) (string, error) {
-	os.logger.Start("Generate Report from Domain Scores")
+	logger := os.logger.WithContext(ctx)
+
+	logger.Start("Generate Report from Domain Scores")
 
 	systemPrompt := prompts.AnalysisReportSystemPrompt()
 
@@ -482,15 +1404,15 @@ func (os *OpenAIService) GenerateReportFromDomainScores(ctx context.Context, fam
 		{Role: openai.ChatMessageRoleUser, Content: userPrompt},
 	}
 
-	content, err := os.callOpenAI(ctx, messages)
+	content, err := os.callOpenAI(ctx, util.LLMTaskAnalysis, messages)
 	if err != nil {
-		os.logger.Error("Failed to generate report", err)
-		os.logger.End("Generate Report from Domain Scores")
+		logger.Error("Failed to generate report", err)
+		logger.End("Generate Report from Domain Scores")
 		return "", fmt.Errorf("failed to generate report: %w", err)
 	}
 
-	os.logger.Success("Report generated successfully")
-	os.logger.End("Generate Report from Domain Scores")
+	logger.Success("Report generated successfully")
+	logger.End("Generate Report from Domain Scores")
 
 	return content, nil
 }
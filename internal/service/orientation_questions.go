@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// seollalDates lists Seollal (lunar January 1) in the solar calendar
+var seollalDates = []time.Time{
+	time.Date(2024, time.February, 10, 0, 0, 0, 0, time.Local),
+	time.Date(2025, time.January, 29, 0, 0, 0, 0, time.Local),
+	time.Date(2026, time.February, 17, 0, 0, 0, 0, time.Local),
+	time.Date(2027, time.February, 7, 0, 0, 0, 0, time.Local),
+	time.Date(2028, time.January, 27, 0, 0, 0, 0, time.Local),
+}
+
+// chuseokDates lists Chuseok (lunar August 15) in the solar calendar
+var chuseokDates = []time.Time{
+	time.Date(2024, time.September, 17, 0, 0, 0, 0, time.Local),
+	time.Date(2025, time.October, 6, 0, 0, 0, 0, time.Local),
+	time.Date(2026, time.September, 25, 0, 0, 0, 0, time.Local),
+	time.Date(2027, time.September, 15, 0, 0, 0, 0, time.Local),
+	time.Date(2028, time.October, 3, 0, 0, 0, 0, time.Local),
+}
+
+// solarHolidays are holidays on the same solar date every year
+var solarHolidays = []struct {
+	name  string
+	month time.Month
+	day   int
+}{
+	{"신정", time.January, 1},
+	{"삼일절", time.March, 1},
+	{"어린이날", time.May, 5},
+	{"어버이날", time.May, 8},
+	{"광복절", time.August, 15},
+	{"개천절", time.October, 3},
+	{"한글날", time.October, 9},
+	{"크리스마스", time.December, 25},
+}
+
+var koreanWeekdays = [...]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"}
+
+var koreanSeasons = [...]string{"봄", "여름", "가을", "겨울"}
+
+type orientationHoliday struct {
+	name string
+	date time.Time
+}
+
+// orientationQuestion is a generated question with the fact it is checked against
+type orientationQuestion struct {
+	question *models.GameQuestionResponse
+	fact     string
+}
+
+// orientationQuestions builds reality-orientation questions about the date,
+// weekday and season of now, and about a holiday close to it if there is one.
+// They are built from the calendar alone and need no conversation history.
+func orientationQuestions(now time.Time) []orientationQuestion {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	questions := []orientationQuestion{
+		{
+			question: orientationQuestionFor("오늘은 몇 월 며칠일까요?", "오늘 날짜", util.DifficultyMedium, monthDay(today),
+				monthDay(today.AddDate(0, 0, -1)), monthDay(today.AddDate(0, 0, 1)), monthDay(today.AddDate(0, -1, 0))),
+			fact: fmt.Sprintf("오늘은 %d년 %s입니다.", today.Year(), monthDay(today)),
+		},
+		{
+			question: orientationQuestionFor("오늘은 무슨 요일일까요?", "오늘 요일", util.DifficultyEasy, koreanWeekdays[today.Weekday()],
+				koreanWeekdays[(today.Weekday()+1)%7], koreanWeekdays[(today.Weekday()+3)%7], koreanWeekdays[(today.Weekday()+5)%7]),
+			fact: fmt.Sprintf("오늘은 %s입니다.", koreanWeekdays[today.Weekday()]),
+		},
+	}
+
+	season := seasonOf(today.Month())
+	questions = append(questions, orientationQuestion{
+		question: orientationQuestionFor("지금은 어느 계절일까요?", "계절", util.DifficultyEasy, koreanSeasons[season],
+			koreanSeasons[(season+1)%4], koreanSeasons[(season+2)%4], koreanSeasons[(season+3)%4]),
+		fact: fmt.Sprintf("지금은 %s입니다.", koreanSeasons[season]),
+	})
+
+	if holiday, ok := nearestHoliday(today); ok {
+		text := "오늘은 무슨 날일까요?"
+		if holiday.date.After(today) {
+			text = "얼마 뒤에 다가오는 날은 무슨 날일까요?"
+		} else if holiday.date.Before(today) {
+			text = "며칠 전에 지나간 날은 무슨 날일까요?"
+		}
+
+		distractors := make([]string, 0, util.QuestionOptionCount-1)
+		for _, name := range holidayNames() {
+			if name != holiday.name && len(distractors) < util.QuestionOptionCount-1 {
+				distractors = append(distractors, name)
+			}
+		}
+		questions = append(questions, orientationQuestion{
+			question: orientationQuestionFor(text, "명절과 기념일", util.DifficultyMedium, holiday.name, distractors...),
+			fact:     fmt.Sprintf("%s 날짜는 %s입니다.", holiday.name, monthDay(holiday.date)),
+		})
+	}
+
+	return questions
+}
+
+// orientationQuestionFor builds an unshuffled question with the answer as option A
+func orientationQuestionFor(text, topic, difficulty, answer string, distractors ...string) *models.GameQuestionResponse {
+	options := []models.QuestionOption{{ID: "A", Text: answer}}
+	for i, distractor := range distractors {
+		options = append(options, models.QuestionOption{ID: string(rune('B' + i)), Text: distractor})
+	}
+
+	return &models.GameQuestionResponse{
+		QuestionID:    uuid.New().String(),
+		QuestionType:  util.QuestionTypeOrientation,
+		Question:      text,
+		Options:       options,
+		CorrectAnswer: "A",
+		Difficulty:    difficulty,
+		Metadata: models.QuestionMetadata{
+			Topic:       topic,
+			MemoryScore: 1,
+		},
+	}
+}
+
+// nearestHoliday returns the holiday closest to today from OrientationHolidayLookback
+// before it to OrientationHolidayLookahead after it
+func nearestHoliday(today time.Time) (orientationHoliday, bool) {
+	var nearest orientationHoliday
+	found := false
+	for _, holiday := range holidaysAround(today) {
+		offset := holiday.date.Sub(today)
+		if offset < -util.OrientationHolidayLookback || offset > util.OrientationHolidayLookahead {
+			continue
+		}
+		if !found || offset.Abs() < nearest.date.Sub(today).Abs() {
+			nearest, found = holiday, true
+		}
+	}
+	return nearest, found
+}
+
+// holidaysAround lists the holidays of today's year and the years either side
+func holidaysAround(today time.Time) []orientationHoliday {
+	var holidays []orientationHoliday
+	for year := today.Year() - 1; year <= today.Year()+1; year++ {
+		for _, h := range solarHolidays {
+			holidays = append(holidays, orientationHoliday{name: h.name, date: time.Date(year, h.month, h.day, 0, 0, 0, 0, today.Location())})
+		}
+	}
+	for _, date := range seollalDates {
+		holidays = append(holidays, orientationHoliday{name: "설날", date: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, today.Location())})
+	}
+	for _, date := range chuseokDates {
+		holidays = append(holidays, orientationHoliday{name: "추석", date: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, today.Location())})
+	}
+	return holidays
+}
+
+// holidayNames returns the distinct holiday names, lunar holidays first
+func holidayNames() []string {
+	names := []string{"설날", "추석"}
+	for _, h := range solarHolidays {
+		names = append(names, h.name)
+	}
+	return names
+}
+
+// seasonOf returns the index into koreanSeasons of the month's season
+func seasonOf(month time.Month) int {
+	switch month {
+	case time.March, time.April, time.May:
+		return 0
+	case time.June, time.July, time.August:
+		return 1
+	case time.September, time.October, time.November:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func monthDay(t time.Time) string {
+	return fmt.Sprintf("%d월 %d일", t.Month(), t.Day())
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// goalKeywords are words in a user's message that show a chat turn touched a goal
+var goalKeywords = map[string][]string{
+	util.GoalFamilyReminiscence: {"가족", "아들", "딸", "손주", "손자", "손녀", "남편", "아내", "영감", "며느리", "사위", "형제", "자매", "동생", "어머니", "아버지", "엄마", "아빠"},
+	util.GoalDateOrientation:    {"오늘", "날짜", "요일", "며칠", "몇 월", "계절", "봄", "여름", "가을", "겨울", "명절", "설날", "추석"},
+	util.GoalNameRecall:         {"이름", "성함", "불러", "부르"},
+}
+
+// weekdayKeys maps weekdays to the day keys goals are scheduled with
+var weekdayKeys = map[time.Weekday]string{
+	time.Monday:    "mon",
+	time.Tuesday:   "tue",
+	time.Wednesday: "wed",
+	time.Thursday:  "thu",
+	time.Friday:    "fri",
+	time.Saturday:  "sat",
+	time.Sunday:    "sun",
+}
+
+// PlanService keeps caregiver-defined weekly cognitive exercise plans per user,
+// picks the goal a chat turn should steer towards, and tracks daily coverage
+type PlanService struct {
+	plans    map[string]*models.CarePlanResponse
+	coverage map[string]map[string]map[string]*goalCounts // user ID -> date -> goal kind -> counts
+	mutex    sync.RWMutex
+	logger   *util.Logger
+}
+
+type goalCounts struct {
+	prompted int
+	engaged  int
+}
+
+// NewPlanService creates a new plan service
+func NewPlanService() *PlanService {
+	return &PlanService{
+		plans:    make(map[string]*models.CarePlanResponse),
+		coverage: make(map[string]map[string]map[string]*goalCounts),
+		logger:   util.NewLogger("PlanService"),
+	}
+}
+
+// SetPlan replaces the user's plan. Coverage already recorded is kept.
+func (ps *PlanService) SetPlan(ctx context.Context, userID string, req *models.CarePlanRequest) (*models.CarePlanResponse, error) {
+	logger := ps.logger.WithContext(ctx)
+
+	goals := make([]models.CarePlanGoal, 0, len(req.Goals))
+	seen := make(map[string]bool)
+	for _, goal := range req.Goals {
+		if seen[goal.Kind] {
+			return nil, fmt.Errorf("invalid_plan: goal %s is listed more than once", goal.Kind)
+		}
+		seen[goal.Kind] = true
+
+		if goal.TargetTurns == 0 {
+			goal.TargetTurns = util.DefaultGoalTargetTurns
+		}
+		goal.Note = strings.TrimSpace(goal.Note)
+		goals = append(goals, goal)
+	}
+
+	plan := &models.CarePlanResponse{
+		UserID:    userID,
+		Goals:     goals,
+		UpdatedAt: time.Now(),
+	}
+
+	ps.mutex.Lock()
+	ps.plans[userID] = plan
+	ps.mutex.Unlock()
+
+	logger.KeyValue("User", userID, "Goals", len(goals))
+	return plan, nil
+}
+
+// Plan returns the user's plan, or nil when none is set
+func (ps *PlanService) Plan(userID string) *models.CarePlanResponse {
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+
+	return ps.plans[userID]
+}
+
+// ActiveGoal returns the first goal scheduled for the day of now whose target
+// the user has not yet reached that day, or nil when every goal is met or none
+// is scheduled
+func (ps *PlanService) ActiveGoal(userID string, now time.Time) *models.CarePlanGoal {
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+
+	plan := ps.plans[userID]
+	if plan == nil {
+		return nil
+	}
+
+	day := ps.coverage[userID][now.Format("2006-01-02")]
+	for i, goal := range plan.Goals {
+		if !scheduledOn(goal, now.Weekday()) {
+			continue
+		}
+		if counts := day[goal.Kind]; counts != nil && counts.engaged >= goal.TargetTurns {
+			continue
+		}
+		return &plan.Goals[i]
+	}
+	return nil
+}
+
+// RecordTurn records a chat turn against the user's plan: the active goal, if
+// any, as prompted, and every goal scheduled that day whose keywords the
+// message contains as engaged
+func (ps *PlanService) RecordTurn(userID string, active *models.CarePlanGoal, message string, now time.Time) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	plan := ps.plans[userID]
+	if plan == nil {
+		return
+	}
+
+	date := now.Format("2006-01-02")
+	for _, goal := range plan.Goals {
+		if !scheduledOn(goal, now.Weekday()) {
+			continue
+		}
+
+		prompted := active != nil && active.Kind == goal.Kind
+		engaged := mentionsGoal(goal.Kind, message)
+		if !prompted && !engaged {
+			continue
+		}
+
+		counts := ps.counts(userID, date, goal.Kind)
+		if prompted {
+			counts.prompted++
+		}
+		if engaged {
+			counts.engaged++
+		}
+	}
+}
+
+// DailySummary reports the coverage of each of the plan's goals on the date (YYYY-MM-DD)
+func (ps *PlanService) DailySummary(ctx context.Context, userID, date string) (*models.GoalSummaryResponse, error) {
+	logger := ps.logger.WithContext(ctx)
+
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid_date: %s", date)
+	}
+
+	ps.mutex.RLock()
+	defer ps.mutex.RUnlock()
+
+	plan := ps.plans[userID]
+	if plan == nil {
+		return nil, fmt.Errorf("plan_not_found: user %s has no care plan", userID)
+	}
+
+	resp := &models.GoalSummaryResponse{
+		UserID: userID,
+		Date:   date,
+		Goals:  []models.GoalCoverage{},
+	}
+
+	scheduled := 0
+	total := float32(0)
+	for _, goal := range plan.Goals {
+		coverage := models.GoalCoverage{
+			Kind:        goal.Kind,
+			Scheduled:   scheduledOn(goal, day.Weekday()),
+			TargetTurns: goal.TargetTurns,
+		}
+		if counts := ps.coverage[userID][date][goal.Kind]; counts != nil {
+			coverage.PromptedTurns = counts.prompted
+			coverage.EngagedTurns = counts.engaged
+		}
+		coverage.Coverage = min(float32(coverage.EngagedTurns)/float32(goal.TargetTurns), 1)
+		coverage.Met = coverage.EngagedTurns >= goal.TargetTurns
+
+		if coverage.Scheduled {
+			scheduled++
+			total += coverage.Coverage
+		}
+		resp.Goals = append(resp.Goals, coverage)
+	}
+	if scheduled > 0 {
+		resp.Coverage = total / float32(scheduled)
+	}
+
+	logger.KeyValue("User", userID, "Date", date, "Coverage", resp.Coverage)
+	return resp, nil
+}
+
+// CompactCoverage removes coverage recorded for days before the cutoff and
+// returns how many goal-days were removed and the approximate bytes they held
+func (ps *PlanService) CompactCoverage(before time.Time) (int, int64) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	cutoff := before.Format("2006-01-02")
+	removed, reclaimed := 0, int64(0)
+	for userID, days := range ps.coverage {
+		for date, goals := range days {
+			if date >= cutoff {
+				continue
+			}
+			for kind := range goals {
+				reclaimed += int64(len(date) + len(kind) + 16)
+			}
+			removed += len(goals)
+			delete(days, date)
+		}
+		if len(days) == 0 {
+			delete(ps.coverage, userID)
+		}
+	}
+	return removed, reclaimed
+}
+
+// DeleteUser removes the user's plan and coverage and returns how many items there were
+func (ps *PlanService) DeleteUser(userID string) int {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	removed := 0
+	if _, ok := ps.plans[userID]; ok {
+		removed++
+	}
+	for _, goals := range ps.coverage[userID] {
+		removed += len(goals)
+	}
+	delete(ps.plans, userID)
+	delete(ps.coverage, userID)
+	return removed
+}
+
+// counts returns the counters for the user's goal on the date, creating them.
+// The caller must hold the write lock.
+func (ps *PlanService) counts(userID, date, kind string) *goalCounts {
+	days := ps.coverage[userID]
+	if days == nil {
+		days = make(map[string]map[string]*goalCounts)
+		ps.coverage[userID] = days
+	}
+	goals := days[date]
+	if goals == nil {
+		goals = make(map[string]*goalCounts)
+		days[date] = goals
+	}
+	counts := goals[kind]
+	if counts == nil {
+		counts = &goalCounts{}
+		goals[kind] = counts
+	}
+	return counts
+}
+
+// scheduledOn reports whether the goal is scheduled for the weekday; goals without days run every day
+func scheduledOn(goal models.CarePlanGoal, weekday time.Weekday) bool {
+	if len(goal.Days) == 0 {
+		return true
+	}
+	for _, day := range goal.Days {
+		if day == weekdayKeys[weekday] {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsGoal reports whether the message contains one of the goal's keywords
+func mentionsGoal(kind, message string) bool {
+	for _, keyword := range goalKeywords[kind] {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
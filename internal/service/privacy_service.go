@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"llm/internal/client"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// PrivacyService exports and erases everything stored about a user, both on
+// the RAG server and in the in-process stores of the other services
+type PrivacyService struct {
+	ragClient          client.RAGStore
+	chatService        *ChatService
+	gameService        *GameService
+	analysisService    *AnalysisService
+	experimentService  *ExperimentService
+	quizHistoryService *QuizHistoryService
+	moodService        *MoodService
+	planService        *PlanService
+	reminderService    *ReminderService
+	assessmentService  *AssessmentService
+	userService        *UserService
+	logger             *util.Logger
+}
+
+// NewPrivacyService creates a new privacy service
+func NewPrivacyService(ragClient client.RAGStore, chatService *ChatService, gameService *GameService, analysisService *AnalysisService, experimentService *ExperimentService, quizHistoryService *QuizHistoryService, moodService *MoodService, planService *PlanService, reminderService *ReminderService, assessmentService *AssessmentService, userService *UserService) *PrivacyService {
+	return &PrivacyService{
+		ragClient:          ragClient,
+		chatService:        chatService,
+		gameService:        gameService,
+		analysisService:    analysisService,
+		experimentService:  experimentService,
+		quizHistoryService: quizHistoryService,
+		moodService:        moodService,
+		planService:        planService,
+		reminderService:    reminderService,
+		assessmentService:  assessmentService,
+		userService:        userService,
+		logger:             util.NewLogger("PrivacyService"),
+	}
+}
+
+// ExportUserData assembles the user's conversations, personal info, quiz
+// attempts, mood entries and analysis reports. It fails rather than return a
+// partial archive when the RAG server cannot be read.
+func (ps *PrivacyService) ExportUserData(ctx context.Context, userID string) (*models.UserDataExport, error) {
+	ctx = util.WithUserID(ctx, userID)
+	logger := ps.logger.WithContext(ctx)
+
+	logger.Start("Export User Data")
+
+	conversations, err := ps.ragClient.ListConversationsByUser(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list conversations", err)
+		logger.End("Export User Data")
+		return nil, fmt.Errorf("rag_unavailable: failed to list conversations: %w", err)
+	}
+
+	personalInfo, err := ps.ragClient.GetPersonalInfoByUser(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get personal info", err)
+		logger.End("Export User Data")
+		return nil, fmt.Errorf("rag_unavailable: failed to get personal info: %w", err)
+	}
+
+	incorrectAttempts, err := ps.ragClient.GetIncorrectQuizAttempts(ctx, userID, util.ExportIncorrectAttemptsLimit)
+	if err != nil {
+		logger.Error("Failed to get incorrect quiz attempts", err)
+		logger.End("Export User Data")
+		return nil, fmt.Errorf("rag_unavailable: failed to get incorrect quiz attempts: %w", err)
+	}
+
+	export := &models.UserDataExport{
+		UserID:                userID,
+		ExportedAt:            time.Now(),
+		Conversations:         conversations,
+		PersonalInfo:          []models.PersonalInfoResponse{},
+		IncorrectQuizAttempts: []models.IncorrectQuizAttempt{},
+		QuizHistory:           ps.quizHistoryService.Attempts(userID),
+		MoodEntries:           ps.moodService.Entries(userID),
+		AnalysisReports:       ps.analysisService.Reports(userID),
+		CarePlan:              ps.planService.Plan(userID),
+		Reminders:             ps.reminderService.List(userID, true),
+		Assessments:           ps.assessmentService.List(userID),
+	}
+	if user, err := ps.userService.Get(userID); err == nil {
+		export.User = user
+	}
+	if export.Conversations == nil {
+		export.Conversations = []models.RAGConversationSearchResult{}
+	}
+	if personalInfo != nil && personalInfo.Items != nil {
+		export.PersonalInfo = personalInfo.Items
+	}
+	if incorrectAttempts != nil && incorrectAttempts.Items != nil {
+		export.IncorrectQuizAttempts = incorrectAttempts.Items
+	}
+
+	logger.KeyValue("Conversations", len(export.Conversations), "Personal Info", len(export.PersonalInfo), "Quiz Attempts", len(export.QuizHistory))
+	logger.Success("User data exported")
+	logger.End("Export User Data")
+
+	return export, nil
+}
+
+// DeleteUserData erases the user from every in-process store, then from the
+// RAG server. Local data is removed even when the RAG erasure fails, and a
+// retry is safe.
+func (ps *PrivacyService) DeleteUserData(ctx context.Context, userID string) (*models.UserDeletionResponse, error) {
+	ctx = util.WithUserID(ctx, userID)
+	logger := ps.logger.WithContext(ctx)
+
+	logger.Start("Delete User Data")
+
+	resp := &models.UserDeletionResponse{
+		UserID: userID,
+		LocalRemoved: map[string]int{
+			"semantic_cache":   ps.chatService.DeleteUserCache(userID),
+			"question_cache":   ps.gameService.DeleteUserQuestions(userID),
+			"analysis_reports": ps.analysisService.DeleteUser(userID),
+			"experiment_usage": ps.experimentService.DeleteUser(userID),
+			"quiz_history":     ps.quizHistoryService.DeleteUser(userID),
+			"mood_entries":     ps.moodService.DeleteUser(userID),
+			"care_plan":        ps.planService.DeleteUser(userID),
+			"reminders":        ps.reminderService.DeleteUser(userID),
+			"assessments":      ps.assessmentService.DeleteUser(userID),
+			"user_registry":    ps.userService.DeleteUser(userID),
+		},
+	}
+
+	if err := ps.ragClient.DeleteUserData(ctx, userID); err != nil {
+		logger.Error("Failed to delete user data on RAG server", err)
+		logger.End("Delete User Data")
+		return nil, fmt.Errorf("rag_unavailable: failed to delete user data: %w", err)
+	}
+	resp.RAGDeleted = true
+	resp.DeletedAt = time.Now()
+
+	logger.Success("User data deleted")
+	logger.End("Delete User Data")
+
+	return resp, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/textutil"
+	"llm/internal/util"
+)
+
+// generateVerified calls generate until its question passes the quality checks against the
+// source it was generated from, up to MaxQuestionGenerationAttempts times, and shuffles the
+// options of the question that passes
+func (gs *GameService) generateVerified(ctx context.Context, sourceContent string, generate func() (*models.GameQuestionResponse, error)) (*models.GameQuestionResponse, error) {
+	logger := gs.logger.WithContext(ctx)
+
+	var failed []string
+	for attempt := 1; attempt <= util.MaxQuestionGenerationAttempts; attempt++ {
+		question, err := generate()
+		if err != nil {
+			return nil, err
+		}
+
+		failed = gs.failedChecks(question, sourceContent)
+		if len(failed) == 0 {
+			shuffleOptions(question)
+			return question, nil
+		}
+		logger.Warn(fmt.Sprintf("Question failed quality checks (attempt %d/%d)", attempt, util.MaxQuestionGenerationAttempts), fmt.Errorf("%s", strings.Join(failed, ", ")))
+	}
+
+	return nil, fmt.Errorf("question_quality: no question passed quality checks in %d attempts, last failed %s", util.MaxQuestionGenerationAttempts, strings.Join(failed, ", "))
+}
+
+// failedChecks returns the names of the quality checks a question fails, counting each failure
+func (gs *GameService) failedChecks(q *models.GameQuestionResponse, sourceContent string) []string {
+	var failed []string
+	for _, check := range validateQuestion(q, sourceContent) {
+		if !check.Passed {
+			failed = append(failed, check.Name)
+			metrics.QuestionQualityFailures.Add(check.Name, 1)
+		}
+	}
+	return failed
+}
+
+// validateQuestion checks a generated question's options and that its correct answer is
+// grounded in the source it was generated from
+func validateQuestion(q *models.GameQuestionResponse, sourceContent string) []models.QuestionValidationCheck {
+	checks := []models.QuestionValidationCheck{
+		{Name: util.QuestionCheckOptionCount, Passed: len(q.Options) == util.QuestionOptionCount},
+	}
+	if !checks[0].Passed {
+		checks[0].Detail = fmt.Sprintf("%d options, want %d", len(q.Options), util.QuestionOptionCount)
+	}
+
+	answerText, answerFound := "", false
+	seen := make(map[string]bool, len(q.Options))
+	duplicate := ""
+	for _, opt := range q.Options {
+		if strings.EqualFold(opt.ID, q.CorrectAnswer) {
+			answerText, answerFound = opt.Text, true
+		}
+		key := strings.ToLower(textutil.Normalize(opt.Text))
+		if seen[key] && duplicate == "" {
+			duplicate = opt.Text
+		}
+		seen[key] = true
+	}
+
+	correct := models.QuestionValidationCheck{Name: util.QuestionCheckCorrectAnswer, Passed: answerFound}
+	if !answerFound {
+		correct.Detail = fmt.Sprintf("correct answer %q matches no option", q.CorrectAnswer)
+	}
+	distinct := models.QuestionValidationCheck{Name: util.QuestionCheckDistinctOptions, Passed: duplicate == ""}
+	if duplicate != "" {
+		distinct.Detail = fmt.Sprintf("option %q appears more than once", duplicate)
+	}
+
+	grounded := models.QuestionValidationCheck{Name: util.QuestionCheckAnswerInSource}
+	coverage := answerCoverage(answerText, sourceContent)
+	grounded.Passed = coverage >= util.MinAnswerSourceCoverage
+	if !grounded.Passed {
+		grounded.Detail = fmt.Sprintf("%.0f%% of the correct answer text found in the source, want %.0f%%", coverage*100, util.MinAnswerSourceCoverage*100)
+	}
+
+	return append(checks, correct, distinct, grounded)
+}
+
+// answerCoverage returns the share (0-1) of the answer's character bigrams that appear in
+// the source. Unlike a substring match it accepts answers the model inflected or reworded
+// slightly ("할머니께서" for "할머니가"), while an answer absent from the source scores near 0.
+func answerCoverage(answer, source string) float64 {
+	answerGrams := textutil.NGramVector(answer)
+	if len(answerGrams) == 0 {
+		return 0
+	}
+	sourceGrams := textutil.NGramVector(source)
+
+	var found, total float64
+	for gram, count := range answerGrams {
+		total += count
+		if sourceGrams[gram] > 0 {
+			found += count
+		}
+	}
+	return found / total
+}
+
+// shuffleOptions randomizes the option order, since models tend to put the correct answer
+// first. Option IDs keep their order (A-D) and the correct answer follows its option.
+func shuffleOptions(q *models.GameQuestionResponse) {
+	ids := make([]string, len(q.Options))
+	for i, opt := range q.Options {
+		ids[i] = opt.ID
+	}
+
+	rand.Shuffle(len(q.Options), func(i, j int) {
+		q.Options[i], q.Options[j] = q.Options[j], q.Options[i]
+	})
+
+	correct := q.CorrectAnswer
+	for i := range q.Options {
+		if strings.EqualFold(q.Options[i].ID, q.CorrectAnswer) {
+			correct = ids[i]
+		}
+		q.Options[i].ID = ids[i]
+	}
+	q.CorrectAnswer = correct
+}
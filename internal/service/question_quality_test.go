@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+func questionWithOptions(correct string, texts ...string) *models.GameQuestionResponse {
+	q := &models.GameQuestionResponse{CorrectAnswer: correct}
+	for i, text := range texts {
+		q.Options = append(q.Options, models.QuestionOption{ID: string(rune('A' + i)), Text: text})
+	}
+	return q
+}
+
+func TestValidateQuestion(t *testing.T) {
+	source := "추석에 큰딸이랑 고향집에 다녀왔어. 할머니가 송편을 빚어 주셨지."
+
+	tests := []struct {
+		name       string
+		question   *models.GameQuestionResponse
+		wantFailed []string
+	}{
+		{
+			name:     "answer quoted from the source",
+			question: questionWithOptions("A", "고향집", "바닷가", "병원", "서울"),
+		},
+		{
+			name:     "inflected answer",
+			question: questionWithOptions("B", "바닷가", "할머니께서", "이웃", "친구"),
+		},
+		{
+			name:     "answer spaced differently",
+			question: questionWithOptions("C", "병원", "시장", "고향 집", "서울"),
+		},
+		{
+			name:       "answer absent from the source",
+			question:   questionWithOptions("A", "떡국", "송편", "냉면", "팥죽"),
+			wantFailed: []string{util.QuestionCheckAnswerInSource},
+		},
+		{
+			name:       "three options",
+			question:   questionWithOptions("A", "고향집", "바닷가", "병원"),
+			wantFailed: []string{util.QuestionCheckOptionCount},
+		},
+		{
+			name:       "duplicate options",
+			question:   questionWithOptions("A", "송편", "바닷가", "Bada", "bada"),
+			wantFailed: []string{util.QuestionCheckDistinctOptions},
+		},
+		{
+			name:       "correct answer matches no option",
+			question:   questionWithOptions("E", "고향집", "바닷가", "병원", "서울"),
+			wantFailed: []string{util.QuestionCheckCorrectAnswer, util.QuestionCheckAnswerInSource},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failed []string
+			for _, check := range validateQuestion(tt.question, source) {
+				if !check.Passed {
+					failed = append(failed, check.Name)
+				}
+			}
+			if !equalStrings(failed, tt.wantFailed) {
+				t.Errorf("failed checks = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestShuffleOptionsKeepsCorrectAnswer(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		q := questionWithOptions("B", "바닷가", "고향집", "병원", "서울")
+		shuffleOptions(q)
+
+		for j, opt := range q.Options {
+			if want := string(rune('A' + j)); opt.ID != want {
+				t.Fatalf("option %d has ID %s, want %s", j, opt.ID, want)
+			}
+			if opt.ID == q.CorrectAnswer && opt.Text != "고향집" {
+				t.Fatalf("correct answer %s is %q, want 고향집", q.CorrectAnswer, opt.Text)
+			}
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// QuizHistoryService keeps per-user quiz attempts grouped by fact so that
+// recall can be compared across separate game sessions
+type QuizHistoryService struct {
+	users    map[string]map[string]*factHistory
+	trends   map[string]*QuizTrend
+	accuracy map[string][]bool // latest results across users, keyed by accuracyKey
+	mutex    sync.RWMutex
+	logger   *util.Logger
+}
+
+type factHistory struct {
+	topic    string
+	attempts []quizAttempt
+}
+
+type quizAttempt struct {
+	sessionID      string
+	isCorrect      bool
+	retentionScore float32
+	confidence     string
+	at             time.Time
+}
+
+// RecallOutcome pairs an evaluated attempt with whether the same fact was
+// recalled the next time it was quizzed in a later session
+type RecallOutcome struct {
+	RetentionScore float32
+	Confidence     string
+	LaterCorrect   bool
+}
+
+// QuizTrend is a user's most recent result and current run of incorrect answers
+type QuizTrend struct {
+	PreviousScore   float32
+	HasPrevious     bool
+	LastScore       float32
+	IncorrectStreak int
+}
+
+// NewQuizHistoryService creates a new quiz history service
+func NewQuizHistoryService() *QuizHistoryService {
+	return &QuizHistoryService{
+		users:    make(map[string]map[string]*factHistory),
+		trends:   make(map[string]*QuizTrend),
+		accuracy: make(map[string][]bool),
+		logger:   util.NewLogger("QuizHistoryService"),
+	}
+}
+
+// RecordAttempt stores a quiz attempt for the fact a question was built from,
+// along with the retention score and confidence label it was given.
+// Attempts without a game session are grouped by calendar day instead.
+func (qs *QuizHistoryService) RecordAttempt(userID, factID, topic, sessionID string, isCorrect bool, retentionScore float32, confidence string) {
+	if userID == "" || factID == "" {
+		return
+	}
+
+	now := time.Now()
+	if sessionID == "" {
+		sessionID = now.Format("2006-01-02")
+	}
+
+	qs.mutex.Lock()
+	defer qs.mutex.Unlock()
+
+	facts, ok := qs.users[userID]
+	if !ok {
+		facts = make(map[string]*factHistory)
+		qs.users[userID] = facts
+	}
+	fact, ok := facts[factID]
+	if !ok {
+		fact = &factHistory{topic: topic}
+		facts[factID] = fact
+	}
+	fact.attempts = append(fact.attempts, quizAttempt{
+		sessionID:      sessionID,
+		isCorrect:      isCorrect,
+		retentionScore: retentionScore,
+		confidence:     confidence,
+		at:             now,
+	})
+}
+
+// TrackResult updates the user's trend with an evaluated result, whatever the
+// question, and returns the updated trend
+func (qs *QuizHistoryService) TrackResult(userID string, isCorrect bool, retentionScore float32) QuizTrend {
+	qs.mutex.Lock()
+	defer qs.mutex.Unlock()
+
+	trend, ok := qs.trends[userID]
+	if !ok {
+		trend = &QuizTrend{}
+		qs.trends[userID] = trend
+	} else {
+		trend.PreviousScore = trend.LastScore
+		trend.HasPrevious = true
+	}
+	trend.LastScore = retentionScore
+
+	if isCorrect {
+		trend.IncorrectStreak = 0
+	} else {
+		trend.IncorrectStreak++
+	}
+	return *trend
+}
+
+// RecordQuestionResult adds a result to the accuracy of its question type and topic,
+// keeping the latest CalibrationWindow results of each
+func (qs *QuizHistoryService) RecordQuestionResult(questionType, topic string, isCorrect bool) {
+	qs.mutex.Lock()
+	defer qs.mutex.Unlock()
+
+	for _, key := range []string{accuracyKey("type", questionType), accuracyKey("topic", topic)} {
+		results := append(qs.accuracy[key], isCorrect)
+		if len(results) > util.CalibrationWindow {
+			results = results[len(results)-util.CalibrationWindow:]
+		}
+		qs.accuracy[key] = results
+	}
+}
+
+// TypeAccuracy returns the share of recent results for a question type that were correct,
+// and how many results it is based on
+func (qs *QuizHistoryService) TypeAccuracy(questionType string) (float64, int) {
+	return qs.accuracyOf(accuracyKey("type", questionType))
+}
+
+// TopicAccuracy returns the share of recent results for a topic that were correct,
+// and how many results it is based on
+func (qs *QuizHistoryService) TopicAccuracy(topic string) (float64, int) {
+	return qs.accuracyOf(accuracyKey("topic", topic))
+}
+
+// RecentAccuracy returns the share of a user's latest n attempts that were correct,
+// and how many attempts it is based on
+func (qs *QuizHistoryService) RecentAccuracy(userID string, n int) (float64, int) {
+	qs.mutex.RLock()
+	var attempts []quizAttempt
+	for _, fact := range qs.users[userID] {
+		attempts = append(attempts, fact.attempts...)
+	}
+	qs.mutex.RUnlock()
+
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].at.After(attempts[j].at) })
+	if len(attempts) > n {
+		attempts = attempts[:n]
+	}
+	if len(attempts) == 0 {
+		return 0, 0
+	}
+
+	correct := 0
+	for _, attempt := range attempts {
+		if attempt.isCorrect {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(attempts)), len(attempts)
+}
+
+func (qs *QuizHistoryService) accuracyOf(key string) (float64, int) {
+	qs.mutex.RLock()
+	defer qs.mutex.RUnlock()
+
+	results := qs.accuracy[key]
+	if len(results) == 0 {
+		return 0, 0
+	}
+	correct := 0
+	for _, isCorrect := range results {
+		if isCorrect {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(results)), len(results)
+}
+
+func accuracyKey(kind, value string) string {
+	return kind + ":" + value
+}
+
+// Consolidation classifies each quizzed fact by whether it was recalled in
+// more than one session. The score is the share of facts quizzed in at least
+// two sessions that were recalled in at least two of them. Returns nil when
+// the user has no quiz history yet.
+func (qs *QuizHistoryService) Consolidation(userID string) *models.ConsolidationMetric {
+	qs.mutex.RLock()
+	defer qs.mutex.RUnlock()
+
+	facts := qs.users[userID]
+	if len(facts) == 0 {
+		return nil
+	}
+
+	metric := &models.ConsolidationMetric{
+		TrackedFacts:       len(facts),
+		ConsolidatedTopics: []string{},
+		ImmediateTopics:    []string{},
+	}
+	eligible := 0
+	consolidatedTopics := make(map[string]bool)
+	immediateTopics := make(map[string]bool)
+
+	for _, fact := range facts {
+		sessions := make(map[string]bool)
+		correctSessions := make(map[string]bool)
+		for _, attempt := range fact.attempts {
+			sessions[attempt.sessionID] = true
+			if attempt.isCorrect {
+				correctSessions[attempt.sessionID] = true
+			}
+		}
+
+		if len(sessions) >= util.MinConsolidationSessions {
+			eligible++
+		}
+
+		switch {
+		case len(correctSessions) >= util.MinConsolidationSessions:
+			metric.ConsolidatedFacts++
+			consolidatedTopics[fact.topic] = true
+		case len(correctSessions) == 1:
+			metric.ImmediateOnlyFacts++
+			immediateTopics[fact.topic] = true
+		default:
+			metric.NotRecalledFacts++
+		}
+	}
+
+	if eligible > 0 {
+		metric.Score = metric.ConsolidatedFacts * 100 / eligible
+	}
+	for topic := range consolidatedTopics {
+		if topic != "" {
+			metric.ConsolidatedTopics = append(metric.ConsolidatedTopics, topic)
+		}
+	}
+	for topic := range immediateTopics {
+		if topic != "" && !consolidatedTopics[topic] {
+			metric.ImmediateTopics = append(metric.ImmediateTopics, topic)
+		}
+	}
+	sort.Strings(metric.ConsolidatedTopics)
+	sort.Strings(metric.ImmediateTopics)
+
+	return metric
+}
+
+// RecallOutcomes returns, across all users, every attempt that was followed by
+// another attempt on the same fact in a different session
+func (qs *QuizHistoryService) RecallOutcomes() []RecallOutcome {
+	qs.mutex.RLock()
+	defer qs.mutex.RUnlock()
+
+	outcomes := []RecallOutcome{}
+	for _, facts := range qs.users {
+		for _, fact := range facts {
+			for i, attempt := range fact.attempts {
+				for _, later := range fact.attempts[i+1:] {
+					if later.sessionID == attempt.sessionID {
+						continue
+					}
+					outcomes = append(outcomes, RecallOutcome{
+						RetentionScore: attempt.retentionScore,
+						Confidence:     attempt.confidence,
+						LaterCorrect:   later.isCorrect,
+					})
+					break
+				}
+			}
+		}
+	}
+	return outcomes
+}
+
+// CompactHistory removes quiz attempts made before the cutoff, along with facts
+// and users left without attempts. It returns how many attempts were removed and
+// the approximate bytes they held.
+func (qs *QuizHistoryService) CompactHistory(before time.Time) (int, int64) {
+	qs.mutex.Lock()
+	defer qs.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for userID, facts := range qs.users {
+		for factID, fact := range facts {
+			kept := fact.attempts[:0]
+			for _, attempt := range fact.attempts {
+				if attempt.at.Before(before) {
+					removed++
+					reclaimed += int64(unsafe.Sizeof(attempt)) + int64(len(attempt.sessionID)+len(attempt.confidence))
+					continue
+				}
+				kept = append(kept, attempt)
+			}
+			fact.attempts = kept
+			if len(kept) == 0 {
+				delete(facts, factID)
+				reclaimed += int64(len(factID) + len(fact.topic))
+			}
+		}
+		if len(facts) == 0 {
+			delete(qs.users, userID)
+			if trend, ok := qs.trends[userID]; ok {
+				delete(qs.trends, userID)
+				reclaimed += int64(unsafe.Sizeof(*trend))
+			}
+		}
+	}
+	return removed, reclaimed
+}
+
+// Attempts returns every quiz attempt recorded for the user, oldest first
+func (qs *QuizHistoryService) Attempts(userID string) []models.QuizHistoryEntry {
+	qs.mutex.RLock()
+	defer qs.mutex.RUnlock()
+
+	entries := []models.QuizHistoryEntry{}
+	for factID, fact := range qs.users[userID] {
+		for _, attempt := range fact.attempts {
+			entries = append(entries, models.QuizHistoryEntry{
+				FactID:         factID,
+				Topic:          fact.topic,
+				SessionID:      attempt.sessionID,
+				IsCorrect:      attempt.isCorrect,
+				RetentionScore: attempt.retentionScore,
+				Confidence:     attempt.confidence,
+				AttemptedAt:    attempt.at,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].AttemptedAt.Before(entries[j].AttemptedAt)
+	})
+	return entries
+}
+
+// DeleteUser removes the user's quiz attempts and trend and returns how many
+// attempts there were. Accuracy by question type and topic is not per user and is kept.
+func (qs *QuizHistoryService) DeleteUser(userID string) int {
+	qs.mutex.Lock()
+	defer qs.mutex.Unlock()
+
+	removed := 0
+	for _, fact := range qs.users[userID] {
+		removed += len(fact.attempts)
+	}
+	delete(qs.users, userID)
+	delete(qs.trends, userID)
+	return removed
+}
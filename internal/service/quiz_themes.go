@@ -0,0 +1,67 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// quizTheme describes how conversations are retrieved and filtered for a themed question set.
+// The RAG server has no tag filter, so themes search with their queries, limited to the
+// theme's date window, and keep the results that mention a keyword. Each result's timestamp is
+// checked against the window again locally. A theme without a window matches on its
+// keywords across all dates.
+type quizTheme struct {
+	name        string
+	description string   // theme description given to the model for the intro and questions
+	queries     []string // RAG search queries standing in for tags
+	keywords    []string // a conversation must mention at least one keyword
+	window      func(now time.Time) (from, to time.Time)
+}
+
+var quizThemes = map[string]quizTheme{
+	util.ThemeChuseok: {
+		name:        util.ThemeChuseok,
+		description: "추석 명절 (가족 모임, 차례, 송편, 성묘, 귀성길)",
+		queries:     []string{"추석", "명절 가족 모임", "송편"},
+		// Without a date window these must name Chuseok itself; 명절 or 차례 alone also match
+		// Seollal and everyday talk
+		keywords: []string{"추석", "한가위", "송편", "차례상", "성묘"},
+	},
+	util.ThemeBirthday: {
+		name:        util.ThemeBirthday,
+		description: "생일과 생신 (축하, 선물, 케이크, 함께한 사람)",
+		queries:     []string{"생일", "생신 축하"},
+		keywords:    []string{"생일", "생신", "케이크", "미역국", "선물"},
+		window: func(now time.Time) (time.Time, time.Time) {
+			return now.AddDate(-1, 0, 0), now
+		},
+	},
+	util.ThemeFamilyVisit: {
+		name:        util.ThemeFamilyVisit,
+		description: "최근 가족 방문 (누가 왔는지, 함께 한 일, 나눈 이야기)",
+		queries:     []string{"가족 방문", "자녀 손주 방문"},
+		keywords:    []string{"방문", "놀러", "찾아", "아들", "딸", "손주", "손자", "손녀", "며느리", "사위"},
+		window: func(now time.Time) (time.Time, time.Time) {
+			return now.AddDate(0, 0, -30), now
+		},
+	},
+}
+
+// matches reports whether a conversation falls in the theme's window and mentions one of its keywords.
+// Zero bounds are open.
+func (t quizTheme) matches(conv models.RAGConversationSearchResult, from, to time.Time) bool {
+	if (!from.IsZero() && conv.Timestamp.Before(from)) || (!to.IsZero() && conv.Timestamp.After(to)) {
+		return false
+	}
+	for _, msg := range conv.Messages {
+		for _, keyword := range t.keywords {
+			if strings.Contains(msg.Content, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
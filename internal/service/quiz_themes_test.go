@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+func TestQuizThemeMatches(t *testing.T) {
+	now := time.Date(2031, time.March, 2, 12, 0, 0, 0, time.UTC)
+	conversation := func(timestamp time.Time, content string) models.RAGConversationSearchResult {
+		return models.RAGConversationSearchResult{
+			Timestamp: timestamp,
+			Messages:  []models.RAGMessage{{Role: "user", Content: content}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		theme string
+		conv  models.RAGConversationSearchResult
+		want  bool
+	}{
+		{
+			name:  "chuseok before the hardcoded calendar",
+			theme: util.ThemeChuseok,
+			conv:  conversation(time.Date(2019, time.September, 13, 0, 0, 0, 0, time.UTC), "추석에 큰딸이 송편을 빚었어"),
+			want:  true,
+		},
+		{
+			name:  "chuseok after the hardcoded calendar",
+			theme: util.ThemeChuseok,
+			conv:  conversation(time.Date(2030, time.September, 12, 0, 0, 0, 0, time.UTC), "한가위라 성묘 다녀왔지"),
+			want:  true,
+		},
+		{
+			name:  "chuseok ignores seollal and everyday turns",
+			theme: util.ThemeChuseok,
+			conv:  conversation(now, "설 명절에 이제 내 차례라고 하더라"),
+			want:  false,
+		},
+		{
+			name:  "family visit inside its window",
+			theme: util.ThemeFamilyVisit,
+			conv:  conversation(now.AddDate(0, 0, -3), "손주가 놀러 왔어"),
+			want:  true,
+		},
+		{
+			name:  "family visit outside its window",
+			theme: util.ThemeFamilyVisit,
+			conv:  conversation(now.AddDate(0, -2, 0), "손주가 놀러 왔어"),
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := quizThemes[tt.theme]
+			var from, to time.Time
+			if theme.window != nil {
+				from, to = theme.window(now)
+			}
+			if got := theme.matches(tt.conv, from, to); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/models"
+	"llm/internal/textutil"
+	"llm/internal/util"
+)
+
+// ReminderService keeps medication and appointment reminders per user. Chat
+// brings due reminders up and acknowledges them when the user confirms.
+type ReminderService struct {
+	reminders map[string][]*models.Reminder // user ID -> reminders
+	mutex     sync.RWMutex
+	logger    *util.Logger
+}
+
+// NewReminderService creates a new reminder service
+func NewReminderService() *ReminderService {
+	return &ReminderService{
+		reminders: make(map[string][]*models.Reminder),
+		logger:    util.NewLogger("ReminderService"),
+	}
+}
+
+// Create stores a reminder
+func (rs *ReminderService) Create(ctx context.Context, req *models.ReminderRequest) *models.Reminder {
+	logger := rs.logger.WithContext(ctx)
+
+	reminder := &models.Reminder{
+		ID:        uuid.New().String(),
+		UserID:    req.UserID,
+		Kind:      req.Kind,
+		Title:     textutil.Normalize(req.Title),
+		Note:      textutil.Normalize(req.Note),
+		DueAt:     req.DueAt,
+		CreatedAt: time.Now(),
+	}
+
+	rs.mutex.Lock()
+	rs.reminders[req.UserID] = append(rs.reminders[req.UserID], reminder)
+	rs.mutex.Unlock()
+
+	logger.KeyValue("Reminder", reminder.ID, "Kind", reminder.Kind, "Due", reminder.DueAt.Format(time.RFC3339))
+	created := *reminder
+	return &created
+}
+
+// List returns the user's reminders, soonest first. Acknowledged reminders are
+// left out unless includeAcknowledged is set.
+func (rs *ReminderService) List(userID string, includeAcknowledged bool) []models.Reminder {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+
+	reminders := []models.Reminder{}
+	for _, reminder := range rs.reminders[userID] {
+		if reminder.AcknowledgedAt != nil && !includeAcknowledged {
+			continue
+		}
+		reminders = append(reminders, *reminder)
+	}
+	sort.SliceStable(reminders, func(i, j int) bool {
+		return reminders[i].DueAt.Before(reminders[j].DueAt)
+	})
+	return reminders
+}
+
+// Acknowledge marks the user's reminder as confirmed
+func (rs *ReminderService) Acknowledge(ctx context.Context, userID, reminderID string) (*models.Reminder, error) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	for _, reminder := range rs.reminders[userID] {
+		if reminder.ID != reminderID {
+			continue
+		}
+		if reminder.AcknowledgedAt == nil {
+			now := time.Now()
+			reminder.AcknowledgedAt = &now
+			rs.logger.WithContext(ctx).KeyValue("Reminder", reminderID, "Acknowledged", true)
+		}
+		acknowledged := *reminder
+		return &acknowledged, nil
+	}
+	return nil, fmt.Errorf("reminder_not_found: %s", reminderID)
+}
+
+// Due returns up to MaxRemindersPerPrompt unacknowledged reminders due around
+// now that were not brought up within the repeat interval, soonest first
+func (rs *ReminderService) Due(userID string, now time.Time) []models.Reminder {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+
+	due := []models.Reminder{}
+	for _, reminder := range rs.reminders[userID] {
+		if reminder.AcknowledgedAt != nil || reminder.DueAt.Before(now.Add(-util.ReminderLookback)) || reminder.DueAt.After(now.Add(util.ReminderLookahead)) {
+			continue
+		}
+		if reminder.MentionedAt != nil && now.Sub(*reminder.MentionedAt) < util.ReminderRepeatInterval {
+			continue
+		}
+		due = append(due, *reminder)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].DueAt.Before(due[j].DueAt)
+	})
+	if len(due) > util.MaxRemindersPerPrompt {
+		due = due[:util.MaxRemindersPerPrompt]
+	}
+	return due
+}
+
+// MarkMentioned records that a chat reply brought the reminders up, which
+// starts their confirm window and holds them back for the repeat interval
+func (rs *ReminderService) MarkMentioned(userID string, reminders []models.Reminder, now time.Time) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	for _, reminder := range rs.reminders[userID] {
+		for _, mentioned := range reminders {
+			if reminder.ID == mentioned.ID {
+				at := now
+				reminder.MentionedAt = &at
+			}
+		}
+	}
+}
+
+// AcknowledgeConfirmed marks the reminders brought up within the confirm
+// window as acknowledged when message confirms them, and returns their IDs
+func (rs *ReminderService) AcknowledgeConfirmed(ctx context.Context, userID, message string, now time.Time) []string {
+	if !util.IsConfirmation(message) {
+		return nil
+	}
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	var acknowledged []string
+	for _, reminder := range rs.reminders[userID] {
+		if reminder.AcknowledgedAt != nil || reminder.MentionedAt == nil || now.Sub(*reminder.MentionedAt) > util.ReminderConfirmWindow {
+			continue
+		}
+		at := now
+		reminder.AcknowledgedAt = &at
+		acknowledged = append(acknowledged, reminder.ID)
+	}
+
+	if len(acknowledged) > 0 {
+		rs.logger.WithContext(ctx).Info("Reminders confirmed in chat: %s", strings.Join(acknowledged, ", "))
+	}
+	return acknowledged
+}
+
+// CompactReminders removes reminders due before the cutoff and returns how
+// many were removed and the approximate bytes they held
+func (rs *ReminderService) CompactReminders(before time.Time) (int, int64) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for userID, reminders := range rs.reminders {
+		kept := reminders[:0]
+		for _, reminder := range reminders {
+			if reminder.DueAt.Before(before) {
+				removed++
+				reclaimed += int64(len(reminder.ID) + len(reminder.Kind) + len(reminder.Title) + len(reminder.Note))
+				continue
+			}
+			kept = append(kept, reminder)
+		}
+
+		if len(kept) == 0 {
+			delete(rs.reminders, userID)
+		} else {
+			rs.reminders[userID] = kept
+		}
+	}
+	return removed, reclaimed
+}
+
+// DeleteUser removes all of the user's reminders and returns how many there were
+func (rs *ReminderService) DeleteUser(userID string) int {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	removed := len(rs.reminders[userID])
+	delete(rs.reminders, userID)
+	return removed
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"llm/internal/client"
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/prompts"
+	"llm/internal/textutil"
+	"llm/internal/util"
+)
+
+// ReplayService re-runs stored chat turns through the current prompt and model
+// configuration and compares the replies and evaluation scores with the stored
+// ones, for regression testing prompt changes. Nothing is saved.
+type ReplayService struct {
+	cfg               *config.Config
+	ragClient         client.RAGStore
+	openaiService     LLMService
+	experimentService *ExperimentService
+	logger            *util.Logger
+}
+
+// NewReplayService creates a new replay service
+func NewReplayService(cfg *config.Config, ragClient client.RAGStore, openaiService LLMService, experimentService *ExperimentService) *ReplayService {
+	return &ReplayService{
+		cfg:               cfg,
+		ragClient:         ragClient,
+		openaiService:     openaiService,
+		experimentService: experimentService,
+		logger:            util.NewLogger("ReplayService"),
+	}
+}
+
+// Replay re-runs the user's stored chat turns, newest first. Each turn is answered
+// with the user's profile and an extractive summary of the user messages of the
+// turns before it; retrieval is not re-run, since it would find the turn itself.
+func (rs *ReplayService) Replay(ctx context.Context, req *models.ReplayRequest) (*models.ReplayResponse, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := rs.logger.WithContext(ctx)
+
+	logger.Start("Replay")
+
+	if err := rs.openaiService.ValidateOverrides(req.GenerationOverrides); err != nil {
+		logger.Error("Rejected generation overrides", err)
+		logger.End("Replay")
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = util.DefaultReplayLimit
+	}
+	if limit > util.MaxReplayLimit {
+		limit = util.MaxReplayLimit
+	}
+
+	conversations, err := rs.ragClient.ListConversationsByUser(ctx, req.UserID)
+	if err != nil {
+		logger.Error("Failed to list conversations", err)
+		logger.End("Replay")
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	// Chat turns in the window, newest first
+	var from, to time.Time
+	if req.From != nil {
+		from = *req.From
+	}
+	if req.To != nil {
+		to = *req.To
+	}
+	turns := []models.RAGConversationSearchResult{}
+	for _, conv := range client.FilterByTime(conversations, from, to) {
+		if isChatTurn(conv.Metadata) && firstUserMessage(conv) != "" {
+			turns = append(turns, conv)
+		}
+	}
+	sort.SliceStable(turns, func(i, j int) bool {
+		return turns[i].Timestamp.After(turns[j].Timestamp)
+	})
+
+	profile, err := rs.ragClient.GetPersonalInfoByUser(ctx, req.UserID)
+	if err != nil {
+		logger.Warn("Failed to fetch profile, replaying without it", err)
+		profile = nil
+	}
+
+	cohort := rs.experimentService.CohortFor(req.UserID)
+	evaluate := !rs.cfg.Runtime.Get().FeatureDisabled(util.FeatureEvaluation)
+
+	resp := &models.ReplayResponse{
+		UserID:        req.UserID,
+		PromptVariant: cohort.PromptVersion,
+		Model:         rs.openaiService.EffectiveModel(util.LLMTaskChat, req.GenerationOverrides),
+		ScoreVersion:  prompts.PromptVersion,
+		Results:       []models.ReplayResult{},
+		StartedAt:     time.Now(),
+	}
+
+	genCtx := WithGenerationOverrides(ctx, req.GenerationOverrides)
+	similaritySum, scoreDeltaSum, scored := 0.0, 0, 0
+	for i, conv := range turns {
+		if i == limit {
+			break
+		}
+
+		result := models.ReplayResult{
+			ConversationID:   conv.ConversationID,
+			Timestamp:        conv.Timestamp,
+			Message:          firstUserMessage(conv),
+			OriginalResponse: firstAssistantMessage(conv),
+		}
+		if conv.Metadata != nil && conv.Metadata.ScoreVersion != "" {
+			score := conv.Metadata.ConversationScore
+			result.OriginalScore = &score
+			result.OriginalScoreVersion = conv.Metadata.ScoreVersion
+		}
+
+		contextMessages := []string{}
+		for _, older := range turns[i+1:] {
+			if len(contextMessages) == util.ReplayContextUserMessages {
+				break
+			}
+			contextMessages = append(contextMessages, firstUserMessage(older))
+		}
+
+		data := prompts.ChatPromptData{
+			ContextMessages: contextMessages,
+			ProfileInfo:     profile,
+			Now:             conv.Timestamp,
+			Language:        rs.cfg.ChatLanguage,
+			Variant:         cohort.PromptVersion,
+		}
+		if len(contextMessages) > 0 {
+			data.PreviousSummary = prompts.ExtractiveSummary(contextMessages, 3)
+		}
+
+		replayed, err := rs.openaiService.GenerateChatResponseWithProfile(genCtx, result.Message, data)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Failed to replay conversation %s", conv.ConversationID), err)
+			result.Error = err.Error()
+			resp.Failed++
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+		result.ReplayedResponse = replayed
+		result.Similarity = textutil.Similarity(textutil.NGramVector(result.OriginalResponse), textutil.NGramVector(replayed))
+		result.Changed = result.Similarity < util.ReplayChangedBelow
+
+		if evaluate {
+			score, err := rs.openaiService.EvaluateUserResponseQuality(ctx, result.Message, contextMessages, profile)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Failed to evaluate conversation %s", conv.ConversationID), err)
+			} else {
+				result.ReplayedScore = &score
+				if result.OriginalScore != nil {
+					delta := score - *result.OriginalScore
+					result.ScoreDelta = &delta
+					scoreDeltaSum += delta
+					scored++
+				}
+			}
+		}
+
+		resp.Replayed++
+		if result.Changed {
+			resp.Changed++
+		}
+		similaritySum += result.Similarity
+		resp.Results = append(resp.Results, result)
+	}
+
+	if resp.Replayed > 0 {
+		resp.AverageSimilarity = similaritySum / float64(resp.Replayed)
+	}
+	if scored > 0 {
+		average := float64(scoreDeltaSum) / float64(scored)
+		resp.AverageScoreDelta = &average
+	}
+	resp.FinishedAt = time.Now()
+
+	logger.KeyValue("Replayed", resp.Replayed, "Changed", resp.Changed, "Failed", resp.Failed)
+	logger.End("Replay")
+	return resp, nil
+}
+
+// firstAssistantMessage returns the assistant's reply in a stored conversation
+func firstAssistantMessage(conv models.RAGConversationSearchResult) string {
+	for _, msg := range conv.Messages {
+		if msg.Role == "assistant" {
+			return msg.Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/textutil"
+)
+
+// SemanticCache keeps recent chat replies per user so a near-identical repeated
+// question ("오늘 며칠이에요?" asked again a few minutes later) is answered without
+// another LLM call. A cached reply is reused only when the messages are similar
+// enough, the retrieved context has the same fingerprint, and the reply is still
+// within the freshness window.
+type SemanticCache struct {
+	ttl        time.Duration
+	threshold  float64
+	maxEntries int
+	entries    map[string][]semanticCacheEntry // user ID -> entries, oldest first
+	mutex      sync.RWMutex
+}
+
+type semanticCacheEntry struct {
+	vector      map[string]float64
+	fingerprint string
+	response    string
+	createdAt   time.Time
+}
+
+// NewSemanticCache creates a semantic cache from the SEMANTIC_CACHE_* settings
+func NewSemanticCache(cfg *config.Config) *SemanticCache {
+	return &SemanticCache{
+		ttl:        cfg.SemanticCacheTTL,
+		threshold:  cfg.SemanticCacheThreshold,
+		maxEntries: cfg.SemanticCacheMaxEntries,
+		entries:    make(map[string][]semanticCacheEntry),
+	}
+}
+
+// Lookup returns the fresh reply whose message is most similar to message among
+// the user's entries with the same context fingerprint
+func (sc *SemanticCache) Lookup(userID, message, fingerprint string, now time.Time) (string, bool) {
+	vector := textutil.NGramVector(message)
+
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	best, bestScore := "", 0.0
+	for _, entry := range sc.entries[userID] {
+		if entry.fingerprint != fingerprint || now.Sub(entry.createdAt) > sc.ttl {
+			continue
+		}
+		if score := textutil.Similarity(vector, entry.vector); score >= sc.threshold && score > bestScore {
+			best, bestScore = entry.response, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// Store caches a reply, dropping the user's expired entries and the oldest
+// ones beyond the per-user limit
+func (sc *SemanticCache) Store(userID, message, fingerprint, response string, now time.Time) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	entries := sc.fresh(sc.entries[userID], now)
+	entries = append(entries, semanticCacheEntry{
+		vector:      textutil.NGramVector(message),
+		fingerprint: fingerprint,
+		response:    response,
+		createdAt:   now,
+	})
+	if len(entries) > sc.maxEntries {
+		entries = entries[len(entries)-sc.maxEntries:]
+	}
+	sc.entries[userID] = entries
+}
+
+// Compact removes expired entries and returns how many were removed and the
+// approximate bytes their replies held
+func (sc *SemanticCache) Compact(now time.Time) (int, int64) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	removed, reclaimed := 0, int64(0)
+	for userID, entries := range sc.entries {
+		kept := sc.fresh(entries, now)
+		for _, entry := range entries[:len(entries)-len(kept)] {
+			reclaimed += int64(len(entry.response))
+		}
+		removed += len(entries) - len(kept)
+
+		if len(kept) == 0 {
+			delete(sc.entries, userID)
+		} else {
+			sc.entries[userID] = kept
+		}
+	}
+	return removed, reclaimed
+}
+
+// Stats returns how many replies are cached and for how many users
+func (sc *SemanticCache) Stats() (int, int) {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+
+	entries := 0
+	for _, userEntries := range sc.entries {
+		entries += len(userEntries)
+	}
+	return entries, len(sc.entries)
+}
+
+// DeleteUser removes the user's cached replies and returns how many there were
+func (sc *SemanticCache) DeleteUser(userID string) int {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+
+	removed := len(sc.entries[userID])
+	delete(sc.entries, userID)
+	return removed
+}
+
+// fresh returns the entries still within the freshness window. Entries are
+// kept oldest first, so the expired ones form a prefix.
+func (sc *SemanticCache) fresh(entries []semanticCacheEntry, now time.Time) []semanticCacheEntry {
+	for i, entry := range entries {
+		if now.Sub(entry.createdAt) <= sc.ttl {
+			return entries[i:]
+		}
+	}
+	return nil
+}
+
+// contextFingerprint hashes everything besides the message that shapes a chat
+// reply, so a cached reply is not reused once the user's memories, profile,
+// preferences or prompt variant have changed
+func contextFingerprint(cohort Cohort, prefs models.UserPreferences, contextMessages []string, profileRes profileResult, incorrectAttemptsRes incorrectAttemptsResult) string {
+	data, _ := json.Marshal(struct {
+		PromptVersion     string
+		Model             string
+		Language          string
+		Persona           string
+		ContextMessages   []string
+		Profile           any
+		IncorrectAttempts any
+	}{
+		PromptVersion:     cohort.PromptVersion,
+		Model:             cohort.Model,
+		Language:          prefs.Language,
+		Persona:           prefs.Persona,
+		ContextMessages:   contextMessages,
+		Profile:           profileRes.profile,
+		IncorrectAttempts: incorrectAttemptsRes.attempts,
+	})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
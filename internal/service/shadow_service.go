@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/prompts"
+	"llm/internal/shadow"
+	"llm/internal/util"
+)
+
+// ShadowService runs a candidate prompt variant or model alongside production for a
+// sampled share of chat turns. Both replies are logged for offline comparison; the
+// shadow reply is never returned to the user.
+type ShadowService struct {
+	openaiService LLMService
+	sampleRate    float64
+	promptVariant string
+	model         string
+	log           *shadow.Log
+	logger        *util.Logger
+}
+
+// NewShadowService creates a new shadow service. It is disabled when the sample
+// rate is 0, the candidate prompt variant is unknown, or the log cannot be opened.
+func NewShadowService(cfg *config.Config, openaiService LLMService) *ShadowService {
+	ss := &ShadowService{
+		openaiService: openaiService,
+		promptVariant: cfg.ShadowPromptVariant,
+		model:         cfg.ShadowModel,
+		logger:        util.NewLogger("ShadowService"),
+	}
+	if cfg.ShadowSampleRate == 0 {
+		return ss
+	}
+
+	if ss.promptVariant != "" && !prompts.IsPromptVariant(ss.promptVariant) {
+		ss.logger.Warn("Shadow mode disabled", fmt.Errorf("unknown prompt variant %s", ss.promptVariant))
+		return ss
+	}
+	log, err := shadow.NewLog(cfg.ShadowLogDir)
+	if err != nil {
+		ss.logger.Warn("Shadow mode disabled", err)
+		return ss
+	}
+	ss.log = log
+	ss.sampleRate = cfg.ShadowSampleRate
+	return ss
+}
+
+type productionReply struct {
+	response string
+	err      error
+	latency  time.Duration
+}
+
+// Start samples the chat turn and, when sampled, generates the shadow reply for the
+// prompt data in the background. It returns the function to hand the production
+// reply to once generated, or nil when the turn is not sampled.
+func (ss *ShadowService) Start(ctx context.Context, req *models.ChatRequest, data prompts.ChatPromptData, productionModel string) func(response string, err error) {
+	if ss.sampleRate == 0 || rand.Float64() >= ss.sampleRate {
+		return nil
+	}
+
+	production := make(chan productionReply, 1)
+	started := time.Now()
+	go ss.run(context.WithoutCancel(ctx), req, data, productionModel, production)
+
+	return func(response string, err error) {
+		production <- productionReply{response: response, err: err, latency: time.Since(started)}
+	}
+}
+
+// run generates the shadow reply and logs it next to the production reply
+func (ss *ShadowService) run(ctx context.Context, req *models.ChatRequest, data prompts.ChatPromptData, productionModel string, production <-chan productionReply) {
+	logger := ss.logger.WithContext(ctx)
+
+	record := shadow.Record{
+		RequestID: util.RequestIDFromContext(ctx),
+		UserID:    req.UserID,
+		Message:   req.Message,
+		Production: shadow.Output{
+			PromptVariant: data.Variant,
+			Model:         productionModel,
+		},
+		Shadow: shadow.Output{
+			PromptVariant: data.Variant,
+			Model:         productionModel,
+		},
+		CreatedAt: time.Now(),
+	}
+
+	// The candidate keeps the request's other overrides, e.g. temperature
+	overrides := req.GenerationOverrides
+	if ss.promptVariant != "" {
+		data.Variant = ss.promptVariant
+		record.Shadow.PromptVariant = ss.promptVariant
+	}
+	if ss.model != "" {
+		overrides.Model = ss.model
+		record.Shadow.Model = ss.model
+	}
+
+	started := time.Now()
+	response, err := ss.openaiService.GenerateChatResponseWithProfile(WithGenerationOverrides(ctx, overrides), req.Message, data)
+	record.Shadow.LatencyMs = time.Since(started).Milliseconds()
+	record.Shadow.Response = response
+	if err != nil {
+		record.Shadow.Error = err.Error()
+	}
+
+	select {
+	case reply := <-production:
+		record.Production.Response = reply.response
+		record.Production.LatencyMs = reply.latency.Milliseconds()
+		if reply.err != nil {
+			record.Production.Error = reply.err.Error()
+		}
+	case <-time.After(util.ShadowProductionWait):
+		metrics.ShadowRuns.Add(metrics.ShadowTimeout, 1)
+		logger.Warn("Shadow run discarded", fmt.Errorf("no production reply within %s", util.ShadowProductionWait))
+		return
+	}
+
+	if err != nil {
+		metrics.ShadowRuns.Add(metrics.ShadowError, 1)
+	} else {
+		metrics.ShadowRuns.Add(metrics.ShadowOK, 1)
+	}
+	if err := ss.log.Write(record); err != nil {
+		logger.Warn("Failed to write shadow record", err)
+		return
+	}
+	logger.KeyValue("Shadow Variant", record.Shadow.PromptVariant, "Shadow Model", record.Shadow.Model, "Latency", record.Shadow.LatencyMs)
+}
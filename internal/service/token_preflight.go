@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai"
+
+	"llm/internal/metrics"
+	"llm/internal/util"
+)
+
+// Context windows of known models, in tokens. Dated snapshots (gpt-4o-2024-08-06)
+// match their base model; unknown models get defaultContextWindow.
+var modelContextWindows = map[string]int{
+	"gpt-4o":        128000,
+	"gpt-4o-mini":   128000,
+	"gpt-4-turbo":   128000,
+	"gpt-4":         8192,
+	"gpt-3.5-turbo": 16385,
+}
+
+const defaultContextWindow = 8192
+
+// Token estimate overheads, following OpenAI's chat format accounting
+const (
+	tokensPerMessage    = 4   // role and message delimiters
+	tokensReplyPriming  = 3   // every reply is primed with the assistant role
+	tokensPerImageInput = 765 // a 1024x1024 image at auto detail
+)
+
+// estimateTokens approximates the token count of text without a tokenizer. English
+// averages about four characters per token; Hangul and other non-ASCII text is closer
+// to one token per character, so it is counted per rune.
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// estimatePromptTokens approximates the prompt tokens of a chat completion request
+func estimatePromptTokens(messages []openai.ChatCompletionMessage) int {
+	total := tokensReplyPriming
+	for _, msg := range messages {
+		total += tokensPerMessage + estimateTokens(msg.Content)
+		for _, part := range msg.MultiContent {
+			if part.Type == openai.ChatMessagePartTypeImageURL {
+				total += tokensPerImageInput
+			} else {
+				total += estimateTokens(part.Text)
+			}
+		}
+	}
+	return total
+}
+
+// contextWindow returns the context window of the model a request is served with
+func (os *OpenAIService) contextWindow(model string) int {
+	if os.contextWindowOverride > 0 {
+		return os.contextWindowOverride
+	}
+	if mapped, ok := os.modelMap[model]; ok {
+		model = mapped
+	}
+
+	// The longest matching base name wins, so gpt-4o-mini-2024-07-18 is not read as gpt-4
+	window, matched := defaultContextWindow, ""
+	for name, size := range modelContextWindows {
+		if (model == name || strings.HasPrefix(model, name+"-")) && len(name) > len(matched) {
+			window, matched = size, name
+		}
+	}
+	return window
+}
+
+// preflightTokens checks that a request's estimated prompt plus its completion budget fits
+// the model's context window. In trim mode, context messages between the system prompt and
+// the final message are dropped oldest first until it fits; requests that still do not fit
+// are rejected with a context_too_large error before reaching the API.
+func (os *OpenAIService) preflightTokens(ctx context.Context, req *openai.ChatCompletionRequest) error {
+	window := os.contextWindow(req.Model)
+	budget := window - req.MaxTokens
+	estimate := estimatePromptTokens(req.Messages)
+	if estimate <= budget {
+		return nil
+	}
+
+	logger := os.logger.WithContext(ctx)
+
+	if os.contextOverflow == util.ContextOverflowTrim {
+		dropped := 0
+		for estimate > budget {
+			i := firstDroppableMessage(req.Messages)
+			if i < 0 {
+				break
+			}
+			req.Messages = append(req.Messages[:i:i], req.Messages[i+1:]...)
+			estimate = estimatePromptTokens(req.Messages)
+			dropped++
+		}
+		if estimate <= budget {
+			metrics.PromptPreflight.Add(util.ContextOverflowTrim, 1)
+			logger.Info("Trimmed %d context messages to fit %s (about %d of %d tokens)", dropped, req.Model, estimate+req.MaxTokens, window)
+			return nil
+		}
+	}
+
+	metrics.PromptPreflight.Add(util.ContextOverflowReject, 1)
+	err := fmt.Errorf("context_too_large: prompt needs about %d tokens plus %d for the response, model %s allows %d", estimate, req.MaxTokens, req.Model, window)
+	logger.Error("Rejected oversized prompt", err)
+	return err
+}
+
+// firstDroppableMessage returns the index of the oldest message that is neither a system
+// message nor the final message, or -1 when nothing can be dropped. Tool calls and their
+// results are kept, since the API rejects one without the other.
+func firstDroppableMessage(messages []openai.ChatCompletionMessage) int {
+	for i := 0; i < len(messages)-1; i++ {
+		if messages[i].Role != openai.ChatMessageRoleSystem && messages[i].Role != openai.ChatMessageRoleTool && len(messages[i].ToolCalls) == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// userLimiter bounds how many requests each user has in flight at once
+type userLimiter struct {
+	max   int
+	users map[string]*userSlots
+	mutex sync.Mutex
+}
+
+type userSlots struct {
+	sem  chan struct{}
+	refs int // requests holding or waiting for a slot; the entry is dropped at zero
+}
+
+func newUserLimiter(max int) *userLimiter {
+	return &userLimiter{
+		max:   max,
+		users: make(map[string]*userSlots),
+	}
+}
+
+// tryAcquire takes one of the user's slots if one is free
+func (l *userLimiter) tryAcquire(userID string) (func(), bool) {
+	slots := l.ref(userID)
+	select {
+	case slots.sem <- struct{}{}:
+		return l.releaser(userID, slots), true
+	default:
+		l.unref(userID, slots)
+		return nil, false
+	}
+}
+
+// acquire waits for one of the user's slots until ctx is done
+func (l *userLimiter) acquire(ctx context.Context, userID string) (func(), error) {
+	slots := l.ref(userID)
+	select {
+	case slots.sem <- struct{}{}:
+		return l.releaser(userID, slots), nil
+	case <-ctx.Done():
+		l.unref(userID, slots)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *userLimiter) ref(userID string) *userSlots {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	slots, ok := l.users[userID]
+	if !ok {
+		slots = &userSlots{sem: make(chan struct{}, l.max)}
+		l.users[userID] = slots
+	}
+	slots.refs++
+	return slots
+}
+
+func (l *userLimiter) unref(userID string, slots *userSlots) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	slots.refs--
+	if slots.refs == 0 {
+		delete(l.users, userID)
+	}
+}
+
+func (l *userLimiter) releaser(userID string, slots *userSlots) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-slots.sem
+			l.unref(userID, slots)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"llm/internal/config"
+	"llm/internal/models"
+	"llm/internal/util"
+)
+
+// preferredCallTimeLayout is the HH:MM format of a user's preferred call time
+const preferredCallTimeLayout = "15:04"
+
+// UserService is the registry of known users. It is kept in a small JSON file
+// rewritten on every change, so registrations survive restarts.
+type UserService struct {
+	path   string
+	users  map[string]*models.User
+	mutex  sync.RWMutex
+	logger *util.Logger
+}
+
+// NewUserService loads the registry from USER_REGISTRY_FILE. A missing file is
+// an empty registry; an unreadable one is an error, since starting empty would
+// reject every registered user.
+func NewUserService(cfg *config.Config) (*UserService, error) {
+	us := &UserService{
+		path:   cfg.UserRegistryFile,
+		users:  make(map[string]*models.User),
+		logger: util.NewLogger("UserService"),
+	}
+
+	data, err := os.ReadFile(us.path)
+	if os.IsNotExist(err) {
+		return us, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user registry: %w", err)
+	}
+
+	var users []*models.User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("failed to parse user registry %s: %w", us.path, err)
+	}
+	for _, user := range users {
+		us.users[user.UserID] = user
+	}
+	us.logger.Info("Loaded %d registered users", len(us.users))
+	return us, nil
+}
+
+// Create registers a user under the request's tenant
+func (us *UserService) Create(ctx context.Context, req *models.UserCreateRequest) (*models.User, error) {
+	ctx = util.WithUserID(ctx, req.UserID)
+	logger := us.logger.WithContext(ctx)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("invalid_user: name is required")
+	}
+	if req.BirthYear > time.Now().Year() {
+		return nil, fmt.Errorf("invalid_user: birth_year %d is in the future", req.BirthYear)
+	}
+	if req.PreferredCallTime != "" {
+		if _, err := time.Parse(preferredCallTimeLayout, req.PreferredCallTime); err != nil {
+			return nil, fmt.Errorf("invalid_user: preferred_call_time must be HH:MM")
+		}
+	}
+
+	user := &models.User{
+		UserID:            req.UserID,
+		Name:              name,
+		BirthYear:         req.BirthYear,
+		PreferredCallTime: req.PreferredCallTime,
+		Preferences:       req.Preferences,
+		TenantID:          util.TenantIDFromContext(ctx),
+		CreatedAt:         time.Now(),
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if _, exists := us.users[req.UserID]; exists {
+		return nil, fmt.Errorf("user_exists: %s", req.UserID)
+	}
+	us.users[req.UserID] = user
+	if err := us.save(); err != nil {
+		delete(us.users, req.UserID)
+		logger.Error("Failed to save user registry", err)
+		return nil, err
+	}
+
+	logger.Success("User registered")
+	copied := *user
+	return &copied, nil
+}
+
+// Get returns a registered user
+func (us *UserService) Get(userID string) (*models.User, error) {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	user, exists := us.users[userID]
+	if !exists {
+		return nil, fmt.Errorf("user_not_found: %s", userID)
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// Preferences returns the user's preferences, empty for an unregistered user
+func (us *UserService) Preferences(userID string) models.UserPreferences {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	if user, exists := us.users[userID]; exists {
+		return user.Preferences
+	}
+	return models.UserPreferences{}
+}
+
+// SetPreferences replaces a registered user's preferences
+func (us *UserService) SetPreferences(ctx context.Context, userID string, prefs models.UserPreferences) (*models.User, error) {
+	ctx = util.WithUserID(ctx, userID)
+	logger := us.logger.WithContext(ctx)
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	user, exists := us.users[userID]
+	if !exists {
+		return nil, fmt.Errorf("user_not_found: %s", userID)
+	}
+
+	previous, previousUpdatedAt := user.Preferences, user.UpdatedAt
+	now := time.Now()
+	user.Preferences = prefs
+	user.UpdatedAt = &now
+	if err := us.save(); err != nil {
+		user.Preferences, user.UpdatedAt = previous, previousUpdatedAt
+		logger.Error("Failed to save user registry", err)
+		return nil, err
+	}
+
+	logger.KeyValue("Language", prefs.Language, "Persona", prefs.Persona, "Voice Speed", prefs.VoiceSpeed, "Quiz Difficulty Floor", prefs.QuizDifficultyFloor)
+	copied := *user
+	return &copied, nil
+}
+
+// Exists reports whether a user is registered
+func (us *UserService) Exists(userID string) bool {
+	us.mutex.RLock()
+	defer us.mutex.RUnlock()
+
+	_, exists := us.users[userID]
+	return exists
+}
+
+// DeleteUser removes the user from the registry and returns how many entries
+// were removed
+func (us *UserService) DeleteUser(userID string) int {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	user, exists := us.users[userID]
+	if !exists {
+		return 0
+	}
+	delete(us.users, userID)
+	if err := us.save(); err != nil {
+		// Keep the entry so the registry in memory matches the file, and a retry can erase it
+		us.users[userID] = user
+		us.logger.Error("Failed to save user registry", err)
+		return 0
+	}
+	return 1
+}
+
+// save writes the registry atomically, so a crash never leaves a partial file.
+// The caller holds the write lock.
+func (us *UserService) save() error {
+	users := make([]*models.User, 0, len(us.users))
+	for _, user := range us.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].UserID < users[j].UserID })
+
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal user registry: %w", err)
+	}
+	if err := os.WriteFile(us.path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write user registry: %w", err)
+	}
+	if err := os.Rename(us.path+".tmp", us.path); err != nil {
+		return fmt.Errorf("failed to write user registry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"llm/internal/config"
+	"llm/internal/metrics"
+	"llm/internal/models"
+	"llm/internal/util"
+	"llm/internal/webhook"
+)
+
+// WebhookService keeps caregiver webhook subscriptions and delivers events to them
+type WebhookService struct {
+	subscriptions map[string]webhook.Subscription
+	sender        *webhook.Sender
+	mutex         sync.RWMutex
+	logger        *util.Logger
+}
+
+// NewWebhookService creates a new webhook service seeded with the configured webhooks.
+// Configured webhooks with unknown event types are skipped.
+func NewWebhookService(cfg *config.Config) *WebhookService {
+	ws := &WebhookService{
+		subscriptions: make(map[string]webhook.Subscription),
+		sender:        webhook.NewSender(cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.WebhookInitialBackoff),
+		logger:        util.NewLogger("WebhookService"),
+	}
+
+	for _, hook := range cfg.Webhooks {
+		if err := validateWebhookEvents(hook.Events); err != nil {
+			ws.logger.Warn("Skipping configured webhook "+hook.URL, err)
+			continue
+		}
+		sub := webhook.Subscription{
+			ID:        uuid.New().String(),
+			URL:       hook.URL,
+			Secret:    hook.Secret,
+			Events:    hook.Events,
+			Source:    webhook.SourceConfig,
+			CreatedAt: time.Now(),
+		}
+		ws.subscriptions[sub.ID] = sub
+	}
+
+	return ws
+}
+
+// Register adds a subscription through the API
+func (ws *WebhookService) Register(ctx context.Context, req *models.WebhookRegisterRequest) (*models.WebhookSubscriptionResponse, error) {
+	logger := ws.logger.WithContext(ctx)
+
+	logger.Start("Register Webhook")
+
+	if err := validateWebhookEvents(req.Events); err != nil {
+		logger.Error("Rejected webhook", err)
+		logger.End("Register Webhook")
+		return nil, err
+	}
+
+	sub := webhook.Subscription{
+		ID:        uuid.New().String(),
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Events:    req.Events,
+		Source:    webhook.SourceAPI,
+		CreatedAt: time.Now(),
+	}
+
+	ws.mutex.Lock()
+	ws.subscriptions[sub.ID] = sub
+	ws.mutex.Unlock()
+
+	logger.KeyValue("Subscription", sub.ID, "URL", sub.URL)
+	logger.Success("Webhook registered")
+	logger.End("Register Webhook")
+
+	return toWebhookSubscriptionResponse(sub), nil
+}
+
+// List returns every subscription, oldest first. Secrets are never returned.
+func (ws *WebhookService) List() *models.WebhookListResponse {
+	ws.mutex.RLock()
+	defer ws.mutex.RUnlock()
+
+	subscriptions := make([]models.WebhookSubscriptionResponse, 0, len(ws.subscriptions))
+	for _, sub := range ws.subscriptions {
+		subscriptions = append(subscriptions, *toWebhookSubscriptionResponse(sub))
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+
+	return &models.WebhookListResponse{
+		Subscriptions: subscriptions,
+		EventTypes:    webhook.EventTypes,
+	}
+}
+
+// Delete removes an API-registered subscription. Configured webhooks can only
+// be removed by changing WEBHOOKS.
+func (ws *WebhookService) Delete(ctx context.Context, id string) error {
+	logger := ws.logger.WithContext(ctx)
+
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	sub, exists := ws.subscriptions[id]
+	if !exists {
+		return fmt.Errorf("webhook_not_found: %s", id)
+	}
+	if sub.Source == webhook.SourceConfig {
+		return fmt.Errorf("invalid_webhook: subscription %s is configured through WEBHOOKS", id)
+	}
+
+	delete(ws.subscriptions, id)
+	logger.Info("Webhook %s deleted", id)
+	return nil
+}
+
+// Publish delivers an event to every matching subscription in the background
+func (ws *WebhookService) Publish(ctx context.Context, eventType, userID string, data interface{}) {
+	event := webhook.Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		UserID:     userID,
+		Data:       data,
+		OccurredAt: time.Now(),
+	}
+
+	ws.mutex.RLock()
+	var targets []webhook.Subscription
+	for _, sub := range ws.subscriptions {
+		if sub.Wants(eventType) {
+			targets = append(targets, sub)
+		}
+	}
+	ws.mutex.RUnlock()
+
+	if len(targets) == 0 {
+		return
+	}
+
+	deliverCtx := context.WithoutCancel(ctx)
+	for _, sub := range targets {
+		go ws.deliver(deliverCtx, sub, event)
+	}
+}
+
+func (ws *WebhookService) deliver(ctx context.Context, sub webhook.Subscription, event webhook.Event) {
+	logger := ws.logger.WithContext(ctx)
+
+	attempts, err := ws.sender.Deliver(ctx, sub, event)
+	if err != nil {
+		metrics.WebhookDeliveries.Add(event.Type+".failed", 1)
+		logger.Warn(fmt.Sprintf("Webhook %s to %s failed after %d attempts", event.Type, sub.URL, attempts), err)
+		return
+	}
+
+	metrics.WebhookDeliveries.Add(event.Type+".delivered", 1)
+	logger.Info("Webhook %s delivered to %s (attempts: %d)", event.Type, sub.URL, attempts)
+}
+
+func validateWebhookEvents(events []string) error {
+	for _, event := range events {
+		if !webhook.IsEventType(event) {
+			return fmt.Errorf("invalid_webhook: unknown event type %q", event)
+		}
+	}
+	return nil
+}
+
+func toWebhookSubscriptionResponse(sub webhook.Subscription) *models.WebhookSubscriptionResponse {
+	events := sub.Events
+	if len(events) == 0 {
+		events = webhook.EventTypes
+	}
+	return &models.WebhookSubscriptionResponse{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    events,
+		Source:    sub.Source,
+		CreatedAt: sub.CreatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+// Package shadow records chat replies generated by a candidate prompt or model
+// next to the production reply, for offline comparison.
+package shadow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Output is one side of a shadow comparison
+type Output struct {
+	PromptVariant string `json:"prompt_variant"` // empty for the control prompt
+	Model         string `json:"model"`
+	Response      string `json:"response,omitempty"`
+	Error         string `json:"error,omitempty"`
+	LatencyMs     int64  `json:"latency_ms"`
+}
+
+// Record is a chat turn answered by both production and the shadow variant
+type Record struct {
+	RequestID  string    `json:"request_id,omitempty"`
+	UserID     string    `json:"user_id"`
+	Message    string    `json:"message"`
+	Production Output    `json:"production"`
+	Shadow     Output    `json:"shadow"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Log appends shadow records to a daily JSONL file
+type Log struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewLog creates a shadow log writing into dir
+func NewLog(dir string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create shadow log directory: %w", err)
+	}
+	return &Log{dir: dir}, nil
+}
+
+// Write appends a record
+func (l *Log) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal shadow record: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	path := filepath.Join(l.dir, "shadow-"+record.CreatedAt.Format("2006-01-02")+".jsonl")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open shadow log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write shadow record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Package testutil holds helpers shared by tests across packages
+package testutil
+
+import (
+	"testing"
+
+	"llm/internal/config"
+)
+
+// LoadConfig loads the config for local mode with the fake LLM and the given
+// overrides, keeping every file the services write in a temporary directory
+func LoadConfig(t testing.TB, env map[string]string) *config.Config {
+	t.Helper()
+
+	t.Setenv("FAKE_LLM_ENABLED", "true")
+	t.Setenv("DATA_DIR", t.TempDir())
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	return cfg
+}
@@ -0,0 +1,229 @@
+// Package textutil provides Unicode-safe text helpers for mixed Korean,
+// English and numeric content. Lengths are counted in characters (runes)
+// rather than bytes so Hangul syllables are never split.
+package textutil
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Length limits, counted in characters
+const (
+	MaxTopicRunes      = 50  // topic labels derived from conversation text
+	MaxErrorBodyRunes  = 200 // upstream response bodies quoted in error messages
+	MaxPromptLineRunes = 300 // single history lines quoted in LLM prompts
+)
+
+// Truncate returns s cut to at most maxRunes characters without splitting a
+// multi-byte character, so the result is always valid UTF-8
+func Truncate(s string, maxRunes int) string {
+	if maxRunes <= 0 {
+		return ""
+	}
+	count := 0
+	for i := range s {
+		if count == maxRunes {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
+
+// Normalize converts s to NFC, drops zero-width characters, and collapses
+// runs of whitespace into single spaces. Some keyboards and clients (notably
+// on macOS/iOS) send Hangul decomposed into jamo, which looks identical but
+// does not match composed text in keyword rules or searches.
+func Normalize(s string) string {
+	s = norm.NFC.String(s)
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b', '\u200c', '\u200d', '\ufeff':
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// SplitSentences splits Korean or mixed-language text into sentences. A
+// sentence ends at a line break or at terminal punctuation (. ! ? … and their
+// full-width forms) followed by whitespace or the end of text, so decimals
+// ("3.5") and abbreviations inside a word stay intact. Punctuation runs such as
+// "?!" or "..." stay with their sentence. Empty sentences are dropped.
+func SplitSentences(s string) []string {
+	runes := []rune(s)
+	sentences := []string{}
+	start := 0
+
+	flush := func(end int) {
+		if sentence := strings.TrimSpace(string(runes[start:end])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\n' {
+			flush(i + 1)
+			continue
+		}
+		if !isTerminal(runes[i]) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && (isTerminal(runes[end]) || isClosing(runes[end])) {
+			end++
+		}
+		if end == len(runes) || unicode.IsSpace(runes[end]) {
+			flush(end)
+		}
+		i = end - 1
+	}
+	flush(len(runes))
+
+	return sentences
+}
+
+// FirstSentence returns the first sentence of s, or s trimmed when it has no sentence break
+func FirstSentence(s string) string {
+	if sentences := SplitSentences(s); len(sentences) > 0 {
+		return sentences[0]
+	}
+	return strings.TrimSpace(s)
+}
+
+// NGramVector returns the character bigram counts of s, lowercased with
+// whitespace, punctuation and symbols removed, for comparing short messages
+// with Similarity. Text of a single character yields that character alone.
+func NGramVector(s string) map[string]float64 {
+	runes := []rune(strings.ToLower(Normalize(s)))
+	kept := runes[:0]
+	for _, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			kept = append(kept, r)
+		}
+	}
+
+	vector := make(map[string]float64)
+	if len(kept) == 1 {
+		vector[string(kept)] = 1
+	}
+	for i := 0; i+1 < len(kept); i++ {
+		vector[string(kept[i:i+2])]++
+	}
+	return vector
+}
+
+// Similarity returns the cosine similarity (0-1) of two NGramVector vectors
+func Similarity(a, b map[string]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for gram, weight := range a {
+		dot += weight * b[gram]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// sinoDigits and sinoUnits are the Sino-Korean numerals
+var (
+	sinoDigits = map[rune]int{'영': 0, '공': 0, '일': 1, '이': 2, '삼': 3, '사': 4, '오': 5, '육': 6, '륙': 6, '칠': 7, '팔': 8, '구': 9}
+	sinoUnits  = map[rune]int{'십': 10, '백': 100, '천': 1000}
+)
+
+// numeralCounters may follow a Sino-Korean numeral inside a word ("오월", "삼번")
+const numeralCounters = "년월일요번시분살세개명"
+
+// Numbers returns the numbers written in s, in Arabic digits ("93") or
+// Sino-Korean numerals ("구십삼"). A numeral word only counts when it stands
+// alone or before a counter, so "오늘" is not read as 5. Since 일 and 이 are
+// also the day counter and a particle ("십육일", "구십삼이요"), a numeral
+// ending in one of them also yields its value without it.
+func Numbers(s string) []int {
+	runes := []rune(s)
+	numbers := []int{}
+	for i := 0; i < len(runes); {
+		start := i
+		if unicode.IsDigit(runes[i]) {
+			value := 0
+			for ; i < len(runes) && unicode.IsDigit(runes[i]); i++ {
+				value = value*10 + int(runes[i]-'0')
+			}
+			numbers = append(numbers, value)
+			continue
+		}
+		for ; i < len(runes) && isSinoNumeral(runes[i]); i++ {
+		}
+		if i == start {
+			i++
+			continue
+		}
+
+		run := runes[start:i]
+		if i < len(runes) && unicode.Is(unicode.Hangul, runes[i]) && !strings.ContainsRune(numeralCounters, runes[i]) {
+			continue
+		}
+		if value, ok := sinoValue(run); ok {
+			numbers = append(numbers, value)
+		}
+		if last := run[len(run)-1]; len(run) > 1 && (last == '일' || last == '이') {
+			if value, ok := sinoValue(run[:len(run)-1]); ok {
+				numbers = append(numbers, value)
+			}
+		}
+	}
+	return numbers
+}
+
+func isSinoNumeral(r rune) bool {
+	_, digit := sinoDigits[r]
+	_, unit := sinoUnits[r]
+	return digit || unit
+}
+
+// sinoValue parses a Sino-Korean numeral; two digits in a row are not a number
+func sinoValue(run []rune) (int, bool) {
+	total, current, afterDigit := 0, 0, false
+	for _, r := range run {
+		if digit, ok := sinoDigits[r]; ok {
+			if afterDigit {
+				return 0, false
+			}
+			current, afterDigit = digit, true
+			continue
+		}
+		if !afterDigit {
+			current = 1
+		}
+		total += current * sinoUnits[r]
+		current, afterDigit = 0, false
+	}
+	return total + current, true
+}
+
+func isTerminal(r rune) bool {
+	switch r {
+	case '.', '!', '?', '…', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+func isClosing(r rune) bool {
+	switch r {
+	case '"', '\'', ')', '”', '’', '」', '』':
+		return true
+	}
+	return false
+}
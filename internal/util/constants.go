@@ -1,21 +1,23 @@
 package util
 
+import "time"
+
 // Log message constants
 const (
-	LogStart    = "=== %s START ==="
-	LogEnd      = "=== %s END ===\n"
-	LogSection  = "--- %s ---"
-	LogError    = "ERROR: %v"
-	LogWarning  = "WARNING: %v"
+	LogStart   = "=== %s START ==="
+	LogEnd     = "=== %s END ===\n"
+	LogSection = "--- %s ---"
+	LogError   = "ERROR: %v"
+	LogWarning = "WARNING: %v"
 )
 
 // Service constants
 const (
-	MinRetentionScore       = 0.0
-	MaxRetentionScore       = 1.0
-	ResponseTimeThreshold   = 5000 // milliseconds
-	ConversationCacheTTL    = 1 // hour
-	QuestionCacheTTL        = 24 // hours
+	MinRetentionScore     = 0.0
+	MaxRetentionScore     = 1.0
+	ResponseTimeThreshold = 5000 // milliseconds
+	ConversationCacheTTL  = 1    // hour
+	QuestionCacheTTL      = 24   // hours
 )
 
 // Difficulty levels
@@ -25,6 +27,25 @@ const (
 	DifficultyHard   = "hard"
 )
 
+// Domain analysis input size: histories longer than the prompt holds are summarized in
+// batches (map) and the domains are analyzed over the batch summaries (reduce)
+const (
+	MaxAnalysisPromptMessages  = 20 // conversation lines the domain analysis prompt includes
+	AnalysisSummaryBatchSize   = 25 // messages summarized together
+	AnalysisSummaryConcurrency = 4  // batch summaries requested at once
+	AnalysisSearchPageSize     = 50 // conversations fetched per search page
+)
+
+// Difficulty calibration from observed accuracy
+const (
+	CalibrationWindow         = 200 // most recent results kept per question type and per topic
+	MinCalibrationSamples     = 20  // results needed before observed accuracy moves a difficulty label
+	EasyAccuracyThreshold     = 0.8 // questions answered correctly this often behave as easy
+	HardAccuracyThreshold     = 0.5 // questions answered correctly less often than this behave as hard
+	RecentPerformanceAttempts = 10  // latest attempts of a user considered when suggesting the next difficulty
+	MinRecentAttempts         = 5   // attempts needed before recent performance adjusts the suggestion
+)
+
 // Confidence levels
 const (
 	ConfidenceHigh   = "high"
@@ -34,13 +55,416 @@ const (
 
 // Question types
 const (
-	QuestionTypeFillInBlank  = "fill_in_blank"
+	QuestionTypeFillInBlank    = "fill_in_blank"
 	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeIntegrative    = "integrative" // synthesizes across several related conversations
+	QuestionTypePhoto          = "photo"       // recognition of people and places in a caregiver's photo
+	QuestionTypeOrientation    = "orientation" // reality orientation (date, weekday, season, holiday), needs no history
+)
+
+// Supervised question review
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+
+	ReviewActionApprove = "approve"
+	ReviewActionReject  = "reject"
+)
+
+// Conversation retrieval query strategies
+const (
+	QueryStrategyRaw    = "raw"    // the user's message as typed
+	QueryStrategyLLM    = "llm"    // a search query written by the summary model
+	QueryStrategyHybrid = "hybrid" // the message plus keywords from the user's profile
+)
+
+// Memory evaluation strategies for game results
+const (
+	EvaluationStrategyFormula = "formula" // weighted correctness, speed and recency
+	EvaluationStrategyLLM     = "llm"     // judged by the model, falling back to the formula on failure
+)
+
+// Question quality control
+const (
+	QuestionOptionCount           = 4 // options every generated question must have
+	MaxQuestionGenerationAttempts = 3 // generations tried before a question fails quality control
+
+	// MinAnswerSourceCoverage is the share of the correct answer's character bigrams that
+	// must appear in the source for the answer to count as grounded
+	MinAnswerSourceCoverage = 0.5
+)
+
+// DefaultTopic labels questions and results whose topic is unknown
+const DefaultTopic = "일반"
+
+// Subsystems with safe-mode kill switches, for switching expensive or risky work off during incidents
+const (
+	FeatureAnalysis    = "analysis"    // domain analysis and report generation
+	FeatureEvaluation  = "evaluation"  // game result evaluation and conversation quality/mood scoring
+	FeatureSuggestions = "suggestions" // follow-up suggestions generated with chat replies
+)
+
+// Recency decay curves for the retention formula's recency score
+const (
+	RecencyDecayExponential = "exponential" // halves every RECENCY_DECAY_DAYS
+	RecencyDecayLinear      = "linear"      // reaches zero after RECENCY_DECAY_DAYS
+	RecencyDecayNone        = "none"        // always 1
+)
+
+// Handling of prompts estimated to exceed the model's context window, also the keys of the preflight metric
+const (
+	ContextOverflowTrim   = "trim"   // drop older context messages until the prompt fits
+	ContextOverflowReject = "reject" // fail with context_too_large
+)
+
+// LLM tasks, each of which may be served by its own model
+const (
+	LLMTaskChat       = "chat"
+	LLMTaskEvaluation = "evaluation"
+	LLMTaskQuestion   = "question"
+	LLMTaskAnalysis   = "analysis"
+	LLMTaskVision     = "vision" // chat turns with photos attached
+)
+
+// ShadowProductionWait bounds how long a shadow run waits for the production reply
+// it is logged next to
+const ShadowProductionWait = 2 * time.Minute
+
+// Chat tools the model may call mid-conversation
+const (
+	ChatToolWeather             = "get_today_weather"
+	ChatToolNextReminder        = "get_next_reminder"
+	ChatToolConversationsByDate = "search_conversations_by_date"
+
+	MaxChatToolRounds        = 3   // model calls that may request tools before it must answer
+	MaxChatToolConversations = 5   // conversations a date search returns to the model
+	MaxChatToolMessageRunes  = 300 // each returned message is truncated to this length
+)
+
+// How a chat request is handled while the same user already has the maximum in flight
+const (
+	ChatOverlapQueue  = "queue"  // wait for an earlier request to finish, up to CHAT_USER_QUEUE_TIMEOUT
+	ChatOverlapReject = "reject" // fail at once with chat_in_progress
+)
+
+// MaxQueryProfileKeywords caps the profile keywords the hybrid strategy adds to a query
+const MaxQueryProfileKeywords = 8
+
+// Quiz pack themes
+const (
+	ThemeChuseok     = "chuseok"
+	ThemeBirthday    = "birthday"
+	ThemeFamilyVisit = "family_visit"
+)
+
+// Question set limits
+const (
+	DefaultQuestionSetSize      = 5
+	MaxQuestionSetConversations = 8
+)
+
+// Orientation questions ask about a holiday from this long before it until this long after
+const (
+	OrientationHolidayLookahead = 14 * 24 * time.Hour
+	OrientationHolidayLookback  = 3 * 24 * time.Hour
+)
+
+// Recap card limits
+const (
+	DefaultRecapCardCount  = 5
+	MaxRecapConversations  = 8
+	MaxRecapMissedTopics   = 5
+	RecapSearchResultLimit = 20
+)
+
+// Confidence calibration: later-recall rates a label should predict, and the
+// threshold search grid
+const (
+	CalibrationTargetHighRecall   = 0.8
+	CalibrationTargetMediumRecall = 0.5
+	CalibrationThresholdStep      = 0.05
+	CalibrationMinBucketSamples   = 5
+)
+
+// Score backfill: conversations re-scored per user, job states, and how many
+// per-conversation errors a job keeps for its report
+const (
+	DefaultBackfillConversationLimit = 100
+	MaxBackfillConversationLimit     = 500
+	MaxBackfillErrors                = 20
+	BackfillStatusRunning            = "running"
+	BackfillStatusCompleted          = "completed"
+)
+
+// Replay: stored chat turns re-run per request, and the reply similarity (0-1)
+// below which a replayed reply counts as changed
+const (
+	DefaultReplayLimit        = 10
+	MaxReplayLimit            = 50
+	ReplayChangedBelow        = 0.8
+	ReplayContextUserMessages = 3 // user messages of the turns before a replayed one used as its context
+)
+
+// Cache names for the admin cache endpoints
+const (
+	CacheQuestions         = "questions"          // generated questions awaiting answers, keyed by question or user ID
+	CacheProfiles          = "profiles"           // RAG personal info, keyed by user ID
+	CacheIncorrectAttempts = "incorrect_attempts" // RAG incorrect quiz attempts, keyed by user ID
+	CacheSemantic          = "semantic"           // chat replies reused for repeated questions, keyed by user ID
+)
+
+// Maintenance task names, also the keys of the maintenance metrics
+const (
+	MaintenanceTaskExpiredQuestions = "expired_questions"
+	MaintenanceTaskUsage            = "experiment_usage"
+	MaintenanceTaskQuizHistory      = "quiz_history"
+	MaintenanceTaskMood             = "mood_entries"
+	MaintenanceTaskSemanticCache    = "semantic_cache"
+	MaintenanceTaskRAGCache         = "rag_cache"
+	MaintenanceTaskGoalCoverage     = "goal_coverage"
+	MaintenanceTaskReminders        = "reminders"
+	MaintenanceTaskAssessments      = "assessments"
+)
+
+// Steps a prompt dry run leaves out because they call the LLM or act on the request
+const (
+	DryRunSkippedGuardrail       = "guardrail"
+	DryRunSkippedEmergency       = "emergency_detection"
+	DryRunSkippedQueryGeneration = "search_query_generation" // the hybrid query is used instead
+	DryRunSkippedRerank          = "rerank"
+	DryRunSkippedSummary         = "summary" // the extractive summary is used instead
+)
+
+// Checks run on generated questions and recorded for lineage lookups
+const (
+	QuestionCheckOptionCount     = "option_count" // exactly QuestionOptionCount options
+	QuestionCheckCorrectAnswer   = "correct_answer_in_options"
+	QuestionCheckDistinctOptions = "distinct_options"
+	QuestionCheckAnswerInSource  = "answer_in_source" // most of the correct option's text appears in the source
+)
+
+// Memory consolidation: a fact counts as consolidated once it is recalled
+// correctly in this many distinct game sessions
+const MinConsolidationSessions = 2
+
+// QuestionCandidateConversations is how many recent conversations a question is chosen from
+const QuestionCandidateConversations = 20
+
+// Integrative question source limits
+const (
+	MinIntegrativeConversations = 2
+	MaxIntegrativeConversations = 3
+)
+
+// Personal info categories
+const (
+	CategoryCorrection = "correction"
+	CategoryAvoidTopic = "avoid_topic"
+	CategoryCareMode   = "care_mode"
+	CategoryEmergency  = "emergency" // caregiver contacts alerted on emergencies
+	CategoryPinned     = "pinned"    // caregiver-pinned facts always included in chat context
+)
+
+// Conversation types saved to RAG
+const (
+	ConversationTypeChat             = "chat"              // chat turns
+	ConversationTypeMemoryEvaluation = "memory_evaluation" // game results
+)
+
+// RAGMetadataSchemaVersion is stamped on the metadata of every saved conversation.
+// Version 2 added topics, language and source_channel.
+const RAGMetadataSchemaVersion = 2
+
+// Channels a chat message reaches the server through, recorded with saved conversations
+const (
+	SourceChannelPhone = "phone" // voice call transcribed by the calling service
+	SourceChannelApp   = "app"   // typed in the family or user app
+	SourceChannelAPI   = "api"   // direct API call, the default
+)
+
+// MaxPinnedItems caps a user's pinned facts, which are never cut from the chat prompt
+const MaxPinnedItems = 10
+
+// Care modes (stored as care_mode personal info)
+const (
+	CareModeGriefSensitive = "grief_sensitive"
+)
+
+// Chat personas a user can prefer (stored in the user registry)
+const (
+	PersonaFriend     = "friend"     // warm companion, the default
+	PersonaGrandchild = "grandchild" // affectionate grandchild
+	PersonaCounselor  = "counselor"  // calm, attentive listener
+)
+
+// Emotions a chat turn can be labelled with by sentiment analysis
+const (
+	EmotionJoy        = "joy"
+	EmotionCalm       = "calm"
+	EmotionNeutral    = "neutral"
+	EmotionSadness    = "sadness"
+	EmotionLoneliness = "loneliness"
+	EmotionAnxiety    = "anxiety"
+	EmotionAnger      = "anger"
+)
+
+// IsEmotion reports whether emotion is a known emotion label
+func IsEmotion(emotion string) bool {
+	switch emotion {
+	case EmotionJoy, EmotionCalm, EmotionNeutral, EmotionSadness, EmotionLoneliness, EmotionAnxiety, EmotionAnger:
+		return true
+	}
+	return false
+}
+
+// Conversation topics a chat turn is tagged with when it is saved. Family,
+// life_events, career and hobbies match the domain analysis domains.
+const (
+	TopicFamily     = "family"
+	TopicHealth     = "health"
+	TopicHobbies    = "hobbies"
+	TopicCareer     = "career"
+	TopicLifeEvents = "life_events"
+	TopicDailyLife  = "daily_life"
+	TopicFood       = "food"
+	TopicPlaces     = "places"
+
+	MaxConversationTopics = 3 // topics kept per conversation, most prominent first
+)
+
+// MaxFollowUpSuggestions caps the follow-up lines returned with a chat reply
+const MaxFollowUpSuggestions = 3
+
+// ConversationTopics lists every topic a conversation can be tagged with
+var ConversationTopics = []string{TopicFamily, TopicHealth, TopicHobbies, TopicCareer, TopicLifeEvents, TopicDailyLife, TopicFood, TopicPlaces}
+
+// IsConversationTopic reports whether topic is a known conversation topic
+func IsConversationTopic(topic string) bool {
+	for _, known := range ConversationTopics {
+		if topic == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Care plan goal kinds: cognitive exercises a caregiver schedules for a user's chats
+const (
+	GoalFamilyReminiscence = "family_reminiscence"
+	GoalDateOrientation    = "date_orientation"
+	GoalNameRecall         = "name_recall"
+)
+
+// Care plan limits
+const (
+	DefaultGoalTargetTurns = 3
+	GoalCoverageDays       = 90 // days of goal coverage kept per user
+)
+
+// Reminder kinds
+const (
+	ReminderMedication  = "medication"
+	ReminderAppointment = "appointment"
+)
+
+// Reminder timing: a reminder is brought up in chats from ReminderLookahead
+// before it is due until ReminderLookback after, at most once per
+// ReminderRepeatInterval, and a confirmation counts for reminders brought up
+// within ReminderConfirmWindow
+const (
+	ReminderLookahead      = 6 * time.Hour
+	ReminderLookback       = 2 * time.Hour
+	ReminderRepeatInterval = 30 * time.Minute
+	ReminderConfirmWindow  = 15 * time.Minute
+	MaxRemindersPerPrompt  = 2
+	ReminderRetentionDays  = 30 // days past due a reminder is kept
+)
+
+// Cognitive assessment domains, scored per K-MMSE conventions
+const (
+	AssessmentOrientation  = "orientation"  // year, season, month, date, weekday: 1 point each
+	AssessmentRegistration = "registration" // repeating three words: 1 point each
+	AssessmentAttention    = "attention"    // five serial subtractions of 7 from 100: 1 point each
+	AssessmentRecall       = "recall"       // recalling the three words later: 1 point each
+)
+
+// Cognitive assessment statuses
+const (
+	AssessmentInProgress = "in_progress"
+	AssessmentCompleted  = "completed"
+	AssessmentAbandoned  = "abandoned" // no answer within AssessmentIdleTimeout
+)
+
+// Cognitive assessment limits
+const (
+	AssessmentIdleTimeout   = 30 * time.Minute
+	AssessmentRetentionDays = 365
+	SerialSubtractionStart  = 100
+	SerialSubtractionStep   = 7
+	SerialSubtractionCount  = 5
+	AssessmentWordsPerSet   = 3
+)
+
+// Cognitive timeline limits
+const (
+	DefaultCognitiveTimelineDays = 90
+	MaxCognitiveTimelineDays     = 365
+)
+
+// Mood timeline limits
+const (
+	DefaultMoodTimelineDays = 14
+	MaxMoodTimelineDays     = 90
+	MaxMoodEntriesPerUser   = 1000
 )
 
 // Response score defaults
 const (
 	DefaultResponseScore = 50
-	MinScore            = 0
-	MaxScore            = 100
-)
\ No newline at end of file
+	MinScore             = 0
+	MaxScore             = 100
+)
+
+// User data export and erasure
+const (
+	MaxAnalysisReportsPerUser    = 20
+	ExportIncorrectAttemptsLimit = 1000
+)
+
+// Personal identifier kinds masked by PII redaction
+const (
+	PIIKindPhone   = "phone"
+	PIIKindRRN     = "rrn" // resident registration number
+	PIIKindAddress = "address"
+)
+
+// PIIKinds lists every PII kind, the default for PII_REDACTION_KINDS
+var PIIKinds = []string{PIIKindPhone, PIIKindRRN, PIIKindAddress}
+
+// IsPIIKind reports whether kind is a known PII kind
+func IsPIIKind(kind string) bool {
+	for _, k := range PIIKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// RAG server endpoints, as named in RAG_ENDPOINT_TIMEOUTS
+const (
+	RAGEndpointSearch            = "search"
+	RAGEndpointSave              = "save"
+	RAGEndpointHealth            = "health"
+	RAGEndpointPersonalInfo      = "personal_info"
+	RAGEndpointIncorrectAttempts = "incorrect_attempts"
+	RAGEndpointUserConversations = "user_conversations"
+	RAGEndpointUserDelete        = "user_delete"
+)
+
+// RAGEndpoints lists every RAG endpoint with a configurable timeout
+var RAGEndpoints = []string{
+	RAGEndpointSearch, RAGEndpointSave, RAGEndpointHealth, RAGEndpointPersonalInfo,
+	RAGEndpointIncorrectAttempts, RAGEndpointUserConversations, RAGEndpointUserDelete,
+}
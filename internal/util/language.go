@@ -0,0 +1,54 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Supported response languages
+const (
+	LanguageKorean  = "ko"
+	LanguageEnglish = "en"
+)
+
+// DetectLanguage returns the dominant language of text by counting words.
+// Words are compared rather than letters because one Hangul syllable packs what
+// takes several Latin letters, so a Korean sentence with a few English names
+// ("삼성 Galaxy 스마트폰") would otherwise read as English. A word containing any
+// Hangul (e.g. "iPhone으로") counts as Korean. It returns an empty string when
+// the text has no letters (e.g. only numbers or emoji).
+func DetectLanguage(text string) string {
+	hangul, latin := 0, 0
+	for _, word := range strings.Fields(text) {
+		hasHangul, hasLatin := false, false
+		for _, r := range word {
+			switch {
+			case unicode.Is(unicode.Hangul, r):
+				hasHangul = true
+			case unicode.Is(unicode.Latin, r):
+				hasLatin = true
+			}
+		}
+		switch {
+		case hasHangul:
+			hangul++
+		case hasLatin:
+			latin++
+		}
+	}
+
+	if hangul == 0 && latin == 0 {
+		return ""
+	}
+	if hangul >= latin {
+		return LanguageKorean
+	}
+	return LanguageEnglish
+}
+
+// IsExpectedLanguage reports whether text is written in the expected language.
+// Texts without letters are accepted since their language cannot be judged.
+func IsExpectedLanguage(text string, expected string) bool {
+	detected := DetectLanguage(text)
+	return detected == "" || detected == expected
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+)
+
+// logSampling decides which requests get verbose logs (full prompts and context)
+type logSampling struct {
+	rate       float64
+	debugUsers map[string]bool
+	debugAll   bool
+}
+
+var currentLogSampling atomic.Pointer[logSampling]
+
+// ConfigureLogSampling sets which requests log verbose output: a sampled share
+// (rate, 0-1) of requests, every request acting on one of debugUsers, or every
+// request when debugAll is set. Other requests log only the size of verbose output.
+func ConfigureLogSampling(rate float64, debugUsers []string, debugAll bool) {
+	users := make(map[string]bool, len(debugUsers))
+	for _, userID := range debugUsers {
+		users[userID] = true
+	}
+	currentLogSampling.Store(&logSampling{rate: rate, debugUsers: users, debugAll: debugAll})
+}
+
+// verboseLogging reports whether a request is logged verbosely. Sampling hashes
+// the request ID, so a request's lines are either all verbose or all brief.
+func verboseLogging(requestID, userID string) bool {
+	sampling := currentLogSampling.Load()
+	if sampling == nil {
+		return true
+	}
+	if sampling.debugAll || (userID != "" && sampling.debugUsers[userID]) {
+		return true
+	}
+	if requestID == "" || sampling.rate <= 0 {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(requestID))
+	return float64(h.Sum32()%10000) < sampling.rate*10000
+}
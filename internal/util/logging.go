@@ -1,13 +1,19 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"unicode/utf8"
 )
 
 // Logger provides consistent logging across services
 type Logger struct {
-	prefix string
+	prefix    string
+	requestID string
+	tenantID  string
+	verbose   bool
 }
 
 // NewLogger creates a new logger with a prefix
@@ -15,51 +21,85 @@ func NewLogger(prefix string) *Logger {
 	return &Logger{prefix: prefix}
 }
 
+// WithContext returns a logger that tags every line with the request and tenant IDs carried
+// by ctx and writes Verbose output only when the request is sampled for it
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	requestID := RequestIDFromContext(ctx)
+	return &Logger{
+		prefix:    l.prefix,
+		requestID: requestID,
+		tenantID:  TenantIDFromContext(ctx),
+		verbose:   verboseLogging(requestID, UserIDFromContext(ctx)),
+	}
+}
+
 // Start logs the start of a process
 func (l *Logger) Start(name string) {
-	log.Printf("\n"+LogStart+"\n", l.prefix+": "+name)
+	l.printf("\n"+LogStart+"\n", l.prefix+": "+name)
 }
 
 // End logs the end of a process
 func (l *Logger) End(name string) {
-	log.Printf(LogEnd, l.prefix+": "+name)
+	l.printf(LogEnd, l.prefix+": "+name)
 }
 
 // Section logs a section header
 func (l *Logger) Section(name string) {
-	log.Printf("\n"+LogSection+"\n", name)
+	l.printf("\n"+LogSection+"\n", name)
 }
 
 // Error logs an error message
 func (l *Logger) Error(msg string, err error) {
-	log.Printf(LogError+": %s - %v\n", msg, err)
+	l.printf(LogError+"\n", fmt.Sprintf("%s - %v", msg, err))
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(msg string, err error) {
-	log.Printf(LogWarning+": %s - %v\n", msg, err)
+	l.printf(LogWarning+"\n", fmt.Sprintf("%s - %v", msg, err))
 }
 
 // Info logs an info message
 func (l *Logger) Info(format string, args ...interface{}) {
-	log.Printf(format+"\n", args...)
+	l.printf(format+"\n", args...)
+}
+
+// Verbose logs large output such as prompts under a section header when the
+// request is sampled for verbose logging, and only its size otherwise
+func (l *Logger) Verbose(label string, text string) {
+	if !l.verbose {
+		l.printf("%s: %d chars (not sampled)\n", label, utf8.RuneCountInString(text))
+		return
+	}
+	l.Section(label)
+	l.printf("%s\n", text)
 }
 
 // JSON logs data as formatted JSON
 func (l *Logger) JSON(label string, data interface{}) {
 	jsonBytes, _ := json.MarshalIndent(data, "", "  ")
-	log.Printf("%s:\n%s\n", label, string(jsonBytes))
+	l.printf("%s:\n%s\n", label, string(jsonBytes))
 }
 
 // Success logs a success message
 func (l *Logger) Success(msg string) {
-	log.Printf("✓ %s\n", msg)
+	l.printf("✓ %s\n", msg)
 }
 
 // KeyValue logs key-value pairs
 func (l *Logger) KeyValue(pairs ...interface{}) {
 	for i := 0; i < len(pairs)-1; i += 2 {
 		key, val := pairs[i], pairs[i+1]
-		log.Printf("%s: %v\n", key, val)
+		l.printf("%s: %v\n", key, val)
 	}
-}
\ No newline at end of file
+}
+
+// printf writes a log line, tagged with the request ID when one is set
+func (l *Logger) printf(format string, args ...interface{}) {
+	if l.tenantID != "" {
+		format = "[tenant_id=" + l.tenantID + "] " + format
+	}
+	if l.requestID != "" {
+		format = "[request_id=" + l.requestID + "] " + format
+	}
+	log.Printf(format, args...)
+}
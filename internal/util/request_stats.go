@@ -0,0 +1,83 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RequestStats accumulates the upstream calls and model token usage of one API
+// request for its access log line. A nil *RequestStats ignores updates, so
+// callers outside a request need no checks.
+type RequestStats struct {
+	mutex            sync.Mutex
+	upstreamMillis   map[string]int64
+	upstreamCalls    map[string]int
+	promptTokens     int
+	completionTokens int
+}
+
+type requestStatsKey struct{}
+
+// WithRequestStats returns a copy of ctx carrying new request stats, and the stats
+func WithRequestStats(ctx context.Context) (context.Context, *RequestStats) {
+	stats := &RequestStats{upstreamMillis: map[string]int64{}, upstreamCalls: map[string]int{}}
+	return context.WithValue(ctx, requestStatsKey{}, stats), stats
+}
+
+// RequestStatsFromContext returns the request stats carried by ctx, or nil
+func RequestStatsFromContext(ctx context.Context) *RequestStats {
+	if ctx == nil {
+		return nil
+	}
+	stats, _ := ctx.Value(requestStatsKey{}).(*RequestStats)
+	return stats
+}
+
+// AddUpstream records a call to an upstream target (e.g. openai, rag) and how long it took
+func (s *RequestStats) AddUpstream(target string, latency time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.upstreamMillis[target] += latency.Milliseconds()
+	s.upstreamCalls[target]++
+}
+
+// AddTokens records model token usage
+func (s *RequestStats) AddTokens(promptTokens, completionTokens int) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.promptTokens += promptTokens
+	s.completionTokens += completionTokens
+}
+
+// Upstream returns the summed latency in milliseconds and the call count per upstream target
+func (s *RequestStats) Upstream() (map[string]int64, map[string]int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	millis := make(map[string]int64, len(s.upstreamMillis))
+	for target, ms := range s.upstreamMillis {
+		millis[target] = ms
+	}
+	calls := make(map[string]int, len(s.upstreamCalls))
+	for target, n := range s.upstreamCalls {
+		calls[target] = n
+	}
+	return millis, calls
+}
+
+// Tokens returns the prompt and completion tokens used so far
+func (s *RequestStats) Tokens() (int, int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.promptTokens, s.completionTokens
+}
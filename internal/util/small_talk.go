@@ -0,0 +1,115 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxSmallTalkWords is the longest message, in words, treated as small talk
+const MaxSmallTalkWords = 3
+
+// smallTalkPhrases are acknowledgements, thanks and greetings that need no
+// memory context to answer, written without spaces or punctuation
+var smallTalkPhrases = map[string]bool{
+	// acknowledgements
+	"네": true, "예": true, "응": true, "어": true, "음": true, "으응": true, "네네": true,
+	"그래": true, "그래요": true, "그럼": true, "그럼요": true, "그렇지": true, "그렇죠": true,
+	"그렇구나": true, "그렇군요": true, "맞아": true, "맞아요": true, "좋아": true, "좋아요": true,
+	"알겠어": true, "알겠어요": true, "알겠습니다": true, "알았어": true, "알았어요": true,
+	"아니": true, "아니요": true, "아뇨": true, "글쎄": true, "글쎄요": true, "아하": true,
+	"하하": true, "ㅎㅎ": true, "ㅋㅋ": true,
+	// thanks
+	"고마워": true, "고마워요": true, "고맙습니다": true, "감사해요": true, "감사합니다": true,
+	// greetings
+	"안녕": true, "안녕하세요": true, "잘자": true, "잘자요": true, "수고했어요": true,
+	// English
+	"ok": true, "okay": true, "yes": true, "yeah": true, "no": true, "thanks": true,
+	"thankyou": true, "hi": true, "hello": true, "bye": true,
+}
+
+// confirmationPhrases are replies that confirm a reminder ("네, 약 먹었어요")
+var confirmationPhrases = map[string]bool{
+	"네": true, "예": true, "응": true, "그래": true, "그래요": true, "그럼": true, "그럼요": true,
+	"맞아": true, "맞아요": true, "알겠어": true, "알겠어요": true, "알겠습니다": true, "알았어": true, "알았어요": true,
+	"yes": true, "yeah": true, "ok": true, "okay": true,
+}
+
+// confirmationStems are verb stems of confirming replies ("먹었어요", "갈게요", "챙겼지")
+var confirmationStems = []string{"먹었", "먹을게", "갈게", "가요", "다녀올게", "챙겼", "챙길게", "했어", "했지"}
+
+// negationWords mark a reply as declining rather than confirming
+var negationWords = map[string]bool{
+	"아니": true, "아니요": true, "아뇨": true, "안": true, "못": true, "no": true, "not": true,
+}
+
+// uncertaintyStems mark a reply as unsure rather than confirming ("먹었는지 모르겠어")
+var uncertaintyStems = []string{"모르", "몰라", "몰랐", "글쎄", "아마", "헷갈", "maybe"}
+
+// questionEndings turn a confirming verb into a question or a doubt ("먹었나", "갔는지", "했니")
+var questionEndings = []string{"나", "나요", "니", "냐", "는지", "는지요", "까", "까요"}
+
+// IsConfirmation reports whether message confirms what the assistant just
+// brought up ("네", "약 먹었어요", "내일 병원 갈게요"). Any negation ("아니요",
+// "안 먹었어"), question ("약 먹었나?") or doubt ("먹었는지 모르겠어") makes it
+// not a confirmation.
+func IsConfirmation(message string) bool {
+	if strings.ContainsAny(message, "?？") {
+		return false
+	}
+	words := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	confirmed := false
+	for _, word := range words {
+		if negationWords[word] {
+			return false
+		}
+		for _, stem := range uncertaintyStems {
+			if strings.HasPrefix(word, stem) {
+				return false
+			}
+		}
+		if confirmationPhrases[word] {
+			confirmed = true
+			continue
+		}
+		for _, stem := range confirmationStems {
+			if !strings.HasPrefix(word, stem) {
+				continue
+			}
+			for _, ending := range questionEndings {
+				if strings.HasSuffix(word[len(stem):], ending) {
+					return false
+				}
+			}
+			confirmed = true
+			break
+		}
+	}
+	return confirmed
+}
+
+// IsSmallTalk reports whether message is only trivial acknowledgements, thanks or
+// greetings ("네", "고마워요", "네 네, 알겠어요!"). Punctuation, emoji and spacing
+// are ignored; any other word, or a message longer than MaxSmallTalkWords words,
+// is not small talk.
+func IsSmallTalk(message string) bool {
+	words := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 || len(words) > MaxSmallTalkWords {
+		return false
+	}
+
+	// Whole-message match handles phrases split by spacing ("잘 자요", "thank you")
+	if smallTalkPhrases[strings.Join(words, "")] {
+		return true
+	}
+	for _, word := range words {
+		if !smallTalkPhrases[word] {
+			return false
+		}
+	}
+	return true
+}
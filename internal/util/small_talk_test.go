@@ -0,0 +1,37 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestIsConfirmation(t *testing.T) {
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{message: "네", want: true},
+		{message: "네, 약 먹었어요", want: true},
+		{message: "내일 병원 갈게요", want: true},
+		{message: "어머니 모시고 다녀올게", want: true},
+		{message: "okay", want: true},
+		{message: "아니요", want: false},
+		{message: "안 먹었어", want: false},
+		{message: "약 먹었나?", want: false},
+		{message: "약 먹었나", want: false},
+		{message: "약 먹었는지 모르겠어", want: false},
+		{message: "내가 약을 먹었니", want: false},
+		{message: "병원에 갈까요", want: false},
+		{message: "네? 뭐라고요", want: false},
+		{message: "아마 먹었을 거야", want: false},
+		{message: "글쎄, 챙겼던가", want: false},
+		{message: "오늘 날씨 좋네", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := IsConfirmation(tt.message); got != tt.want {
+				t.Errorf("IsConfirmation(%q) = %t, want %t", tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package util
+
+import "context"
+
+type tenantIDKey struct{}
+
+// WithTenantID returns a copy of ctx carrying the tenant (care facility) the request was made for
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// TenantIDFromContext returns the tenant ID carried by ctx, or an empty string
+func TenantIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenantID, _ := ctx.Value(tenantIDKey{}).(string)
+	return tenantID
+}
@@ -0,0 +1,19 @@
+package util
+
+import "context"
+
+type userIDKey struct{}
+
+// WithUserID returns a copy of ctx carrying the user the request acts on
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the user ID carried by ctx, or an empty string
+func UserIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	userID, _ := ctx.Value(userIDKey{}).(string)
+	return userID
+}
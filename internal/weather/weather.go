@@ -0,0 +1,122 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Forecast is today's weather at the configured location
+type Forecast struct {
+	Date                     string  `json:"date"`
+	Condition                string  `json:"condition"` // Korean description, e.g. "맑음"
+	TemperatureMax           float64 `json:"temperature_max"`
+	TemperatureMin           float64 `json:"temperature_min"`
+	PrecipitationProbability int     `json:"precipitation_probability"` // percent
+}
+
+// Client fetches daily forecasts from an Open-Meteo compatible forecast API
+type Client struct {
+	apiURL     string
+	latitude   float64
+	longitude  float64
+	timezone   string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the forecast API at apiURL and the given location
+func NewClient(apiURL string, latitude, longitude float64, timezone string, timeout time.Duration) *Client {
+	return &Client{
+		apiURL:     apiURL,
+		latitude:   latitude,
+		longitude:  longitude,
+		timezone:   timezone,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type forecastResponse struct {
+	Daily struct {
+		Time                     []string  `json:"time"`
+		WeatherCode              []int     `json:"weather_code"`
+		TemperatureMax           []float64 `json:"temperature_2m_max"`
+		TemperatureMin           []float64 `json:"temperature_2m_min"`
+		PrecipitationProbability []int     `json:"precipitation_probability_max"`
+	} `json:"daily"`
+}
+
+// Today returns today's forecast
+func (c *Client) Today(ctx context.Context) (*Forecast, error) {
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(c.latitude, 'f', -1, 64))
+	query.Set("longitude", strconv.FormatFloat(c.longitude, 'f', -1, 64))
+	query.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max")
+	query.Set("timezone", c.timezone)
+	query.Set("forecast_days", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create forecast request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch forecast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("forecast api returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var forecast forecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&forecast); err != nil {
+		return nil, fmt.Errorf("failed to decode forecast: %w", err)
+	}
+
+	daily := forecast.Daily
+	if len(daily.Time) == 0 || len(daily.WeatherCode) == 0 || len(daily.TemperatureMax) == 0 || len(daily.TemperatureMin) == 0 {
+		return nil, fmt.Errorf("forecast api returned no daily forecast")
+	}
+
+	today := &Forecast{
+		Date:           daily.Time[0],
+		Condition:      Condition(daily.WeatherCode[0]),
+		TemperatureMax: daily.TemperatureMax[0],
+		TemperatureMin: daily.TemperatureMin[0],
+	}
+	if len(daily.PrecipitationProbability) > 0 {
+		today.PrecipitationProbability = daily.PrecipitationProbability[0]
+	}
+	return today, nil
+}
+
+// Condition describes a WMO weather interpretation code in Korean
+func Condition(code int) string {
+	switch {
+	case code == 0:
+		return "맑음"
+	case code <= 2:
+		return "구름 조금"
+	case code == 3:
+		return "흐림"
+	case code == 45 || code == 48:
+		return "안개"
+	case code >= 51 && code <= 57:
+		return "이슬비"
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return "비"
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return "눈"
+	case code >= 95:
+		return "뇌우"
+	default:
+		return "알 수 없음"
+	}
+}
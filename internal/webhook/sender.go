@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"llm/internal/textutil"
+)
+
+// Sender delivers signed events, retrying failures with exponential backoff
+type Sender struct {
+	httpClient     *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+// NewSender creates a sender
+func NewSender(timeout time.Duration, maxAttempts int, initialBackoff time.Duration) *Sender {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Sender{
+		httpClient:     &http.Client{Timeout: timeout},
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+	}
+}
+
+// Deliver posts event to the subscription until it succeeds, fails permanently,
+// or runs out of attempts. It returns the number of attempts made.
+func (s *Sender) Deliver(ctx context.Context, sub Subscription, event Event) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	backoff := s.initialBackoff
+	var lastErr error
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		retryable, err := s.post(ctx, sub, event, body)
+		if err == nil {
+			return attempt, nil
+		}
+		lastErr = err
+		if !retryable || attempt == s.maxAttempts {
+			return attempt, lastErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return s.maxAttempts, lastErr
+}
+
+// post makes one delivery attempt. Network errors, 429 and 5xx responses are retryable.
+func (s *Sender) post(ctx context.Context, sub Subscription, event Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, timestamp, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook delivery failed: status %d, body: %s", resp.StatusCode, textutil.Truncate(string(respBody), textutil.MaxErrorBodyRunes))
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Event types delivered to subscribers
+const (
+	EventAnalysisCompleted = "analysis.completed"
+	EventRetentionDropped  = "retention.dropped"
+	EventIncorrectStreak   = "quiz.incorrect_streak"
+	EventEmergencyDetected = "emergency.detected"
+)
+
+// EventTypes lists every event a subscription may select
+var EventTypes = []string{
+	EventAnalysisCompleted,
+	EventRetentionDropped,
+	EventIncorrectStreak,
+	EventEmergencyDetected,
+}
+
+// IsEventType reports whether name is a known event type
+func IsEventType(name string) bool {
+	for _, eventType := range EventTypes {
+		if eventType == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery headers. The signature is "sha256=" followed by the hex HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the subscription secret, so receivers can
+// reject both forged and replayed deliveries.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Subscription sources
+const (
+	SourceConfig = "config"
+	SourceAPI    = "api"
+)
+
+// Event is the JSON body posted to subscribers
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	UserID     string      `json:"user_id"`
+	Data       interface{} `json:"data"`
+	OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// Subscription is a registered endpoint and the events it receives
+type Subscription struct {
+	ID        string
+	URL       string
+	Secret    string
+	Events    []string // empty receives every event
+	Source    string
+	CreatedAt time.Time
+}
+
+// Wants reports whether the subscription receives eventType
+func (s Subscription) Wants(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, event := range s.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the signature header value for a delivery
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -9,33 +9,69 @@
 // @host refo-llm-hackerton.dsmhs.kr
 // @basePath /
 // @schemes https http
+// @securityDefinitions.apikey AdminAPIKey
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey TenantAPIKey
+// @in header
+// @name X-API-Key
 
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "llm/docs"
 	"llm/internal/api"
 	"llm/internal/client"
 	"llm/internal/config"
+	"llm/internal/embedding"
+	"llm/internal/fakellm"
+	"llm/internal/savequeue"
+	"llm/internal/secrets"
 	"llm/internal/service"
+	"llm/internal/util"
+	"llm/internal/weather"
 )
 
 func main() {
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	if *printConfig {
+		for _, setting := range cfg.EffectiveConfig() {
+			fmt.Printf("%-45s %-8s %s\n", setting.Key, setting.Source, setting.Value)
+		}
+		return
+	}
+
 	log.Printf("Starting LLM Server on port %d", cfg.Port)
 
+	// API keys may be references to a mounted file, Vault or AWS Secrets Manager
+	secretsCtx, secretsCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	secretManager, err := secrets.NewManager(secretsCtx, cfg)
+	secretsCancel()
+	if err != nil {
+		log.Fatalf("Failed to load secrets: %v", err)
+	}
+
+	// Full prompts are logged only for sampled requests and debug users, or for everything at debug level
+	settings := cfg.Runtime.Get()
+	util.ConfigureLogSampling(settings.LogSampleRate, settings.LogDebugUsers, settings.LogLevel == "debug")
+
 	// Initialize RAG client
 	ragClient := client.NewRAGClient(cfg)
 
@@ -49,16 +85,113 @@ func main() {
 		log.Println("RAG server is healthy")
 	}
 
-	// Initialize OpenAI service
-	openaiService := service.NewOpenAIService(cfg)
+	// Verify RAG API contract
+	if cfg.RAGContractCheck {
+		report, err := ragClient.VerifyContract(ctx, "contract-check", false)
+		if err != nil {
+			log.Printf("Warning: RAG contract check failed: %v", err)
+		} else if !report.OK() {
+			for _, violation := range report.Violations {
+				log.Printf("Warning: RAG contract violation [%s]: %s", violation.Endpoint, violation.Message)
+			}
+		} else {
+			log.Printf("RAG contract verified (%d endpoints)", len(report.Checked))
+		}
+	}
+
+	// Conversation saves are queued on disk and retried while the RAG server is unreachable
+	var ragStore client.RAGStore = ragClient
+	if cfg.SaveQueueEnabled {
+		saveQueue, err := savequeue.New(ragClient, cfg.SaveQueueDir, cfg.SaveQueueMaxAttempts, cfg.SaveQueueInitialBackoff)
+		if err != nil {
+			log.Fatalf("Failed to start save queue: %v", err)
+		}
+		ragStore = saveQueue
+	}
+
+	// Recently saved conversations stay searchable locally while the RAG server is unreachable
+	embedder := embedding.New(cfg)
+	secretManager.Secret(secrets.OpenAIAPIKey).OnChange(embedder.SetAPIKey)
+	if cfg.LocalIndexEnabled {
+		ragStore = client.NewBufferedStore(ragStore, embedder, cfg.LocalIndexMaxPerUser)
+	}
+
+	// Personal info and incorrect attempts are cached per user since every chat turn loads them
+	var ragCache *client.CachedStore
+	if cfg.ProfileCacheEnabled || cfg.IncorrectAttemptsCacheEnabled {
+		var profileTTL, attemptsTTL time.Duration
+		if cfg.ProfileCacheEnabled {
+			profileTTL = cfg.ProfileCacheTTL
+		}
+		if cfg.IncorrectAttemptsCacheEnabled {
+			attemptsTTL = cfg.IncorrectAttemptsCacheTTL
+		}
+		ragCache = client.NewCachedStore(ragStore, profileTTL, cfg.ProfileCacheMaxStale, attemptsTTL)
+		ragStore = ragCache
+	}
+
+	// Each care facility's conversations are tagged with its tenant ID
+	if len(cfg.Tenants) > 0 {
+		ragStore = client.NewTenantStore(ragStore)
+		log.Printf("Serving %d tenants", len(cfg.Tenants))
+	}
+
+	// Initialize LLM service. Chat tools are registered once the services they use exist.
+	chatTools := service.NewToolRegistry()
+	var openaiService service.LLMService
+	if cfg.FakeLLMEnabled {
+		fake := fakellm.New()
+		if cfg.FakeLLMResponses != "" {
+			if fake, err = fakellm.Load(cfg.FakeLLMResponses); err != nil {
+				log.Fatalf("Failed to load fake LLM responses: %v", err)
+			}
+		}
+		openaiService = fake
+		log.Println("Using fake LLM with canned responses")
+	} else {
+		openaiClient := service.NewOpenAIService(cfg, chatTools)
+		secretManager.Secret(secrets.OpenAIAPIKey).OnChange(openaiClient.SetAPIKey)
+		openaiService = openaiClient
+		if cfg.LLMProvider != config.LLMProviderOpenAI {
+			log.Printf("Using %s LLM provider at %s (moderation API disabled)", cfg.LLMProvider, cfg.OpenAIBaseURL)
+		}
+	}
 
 	// Initialize services
-	chatService := service.NewChatService(cfg, ragClient, openaiService)
-	gameService := service.NewGameService(cfg, ragClient, openaiService)
-	analysisService := service.NewAnalysisService(ragClient, openaiService)
+	experimentService := service.NewExperimentService(cfg)
+	guardrailService := service.NewGuardrailService(cfg, openaiService)
+	webhookService := service.NewWebhookService(cfg)
+	emergencyService := service.NewEmergencyService(cfg, ragStore, webhookService)
+	moodService := service.NewMoodService()
+	planService := service.NewPlanService()
+	reminderService := service.NewReminderService()
+	assessmentService := service.NewAssessmentService()
+	userService, err := service.NewUserService(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load user registry: %v", err)
+	}
+	if cfg.ChatToolsEnabled {
+		chatTools.Register(service.NextReminderTool(reminderService))
+		chatTools.Register(service.ConversationsByDateTool(ragStore))
+		if cfg.WeatherAPIURL != "" {
+			chatTools.Register(service.WeatherTool(weather.NewClient(cfg.WeatherAPIURL, cfg.WeatherLatitude, cfg.WeatherLongitude, cfg.WeatherTimezone, cfg.WeatherTimeout)))
+		}
+	}
+	shadowService := service.NewShadowService(cfg, openaiService)
+	chatService := service.NewChatService(cfg, ragStore, openaiService, experimentService, guardrailService, emergencyService, moodService, planService, reminderService, assessmentService, shadowService, userService)
+	quizHistoryService := service.NewQuizHistoryService()
+	gameService := service.NewGameService(cfg, ragStore, openaiService, experimentService, quizHistoryService, webhookService, userService)
+	analysisService := service.NewAnalysisService(cfg, ragStore, openaiService, quizHistoryService, assessmentService, webhookService)
+	adminService := service.NewAdminService(cfg)
+	calibrationService := service.NewCalibrationService(cfg, quizHistoryService)
+	backfillService := service.NewBackfillService(cfg, ragStore, openaiService)
+	replayService := service.NewReplayService(cfg, ragStore, openaiService, experimentService)
+	cacheService := service.NewCacheService(cfg, chatService, gameService, ragCache)
+	maintenanceService := service.NewMaintenanceService(cfg, chatService, gameService, experimentService, quizHistoryService, moodService, planService, reminderService, assessmentService, ragCache)
+	privacyService := service.NewPrivacyService(ragStore, chatService, gameService, analysisService, experimentService, quizHistoryService, moodService, planService, reminderService, assessmentService, userService)
 
 	// Setup router
-	router := api.Router(cfg, chatService, gameService, analysisService)
+	router := api.Router(cfg, chatService, gameService, analysisService, experimentService, adminService, calibrationService, webhookService, backfillService, maintenanceService, replayService, cacheService, privacyService, planService, reminderService, assessmentService, openaiService, secretManager, userService)
 
 	// Start server in a goroutine
 	addr := fmt.Sprintf(":%d", cfg.Port)
@@ -70,6 +203,21 @@ func main() {
 
 	log.Printf("LLM Server running on %s", addr)
 
+	// Reload configuration and re-read referenced secrets on SIGHUP; requests already running keep their settings
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	go func() {
+		for range reloadChan {
+			log.Println("Reloading configuration...")
+			if _, err := adminService.ReloadConfig(context.Background()); err != nil {
+				log.Printf("Warning: configuration reload failed: %v", err)
+			}
+			secretsCtx, secretsCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			secretManager.Refresh(secretsCtx)
+			secretsCancel()
+		}
+	}()
+
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)